@@ -0,0 +1,1057 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        v6.32.1
+// source: api/payment/v1/bulk_transfer.proto
+
+package paymentv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ExecutionMode int32
+
+const (
+	ExecutionMode_EXECUTION_MODE_UNSPECIFIED ExecutionMode = 0
+	// Every transfer is executed or none is. The default.
+	ExecutionMode_EXECUTION_MODE_ALL_OR_NOTHING ExecutionMode = 1
+	// Transfers are accepted in order while the balance covers them.
+	ExecutionMode_EXECUTION_MODE_PARTIAL ExecutionMode = 2
+)
+
+// Enum value maps for ExecutionMode.
+var (
+	ExecutionMode_name = map[int32]string{
+		0: "EXECUTION_MODE_UNSPECIFIED",
+		1: "EXECUTION_MODE_ALL_OR_NOTHING",
+		2: "EXECUTION_MODE_PARTIAL",
+	}
+	ExecutionMode_value = map[string]int32{
+		"EXECUTION_MODE_UNSPECIFIED":    0,
+		"EXECUTION_MODE_ALL_OR_NOTHING": 1,
+		"EXECUTION_MODE_PARTIAL":        2,
+	}
+)
+
+func (x ExecutionMode) Enum() *ExecutionMode {
+	p := new(ExecutionMode)
+	*p = x
+	return p
+}
+
+func (x ExecutionMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExecutionMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_payment_v1_bulk_transfer_proto_enumTypes[0].Descriptor()
+}
+
+func (ExecutionMode) Type() protoreflect.EnumType {
+	return &file_api_payment_v1_bulk_transfer_proto_enumTypes[0]
+}
+
+func (x ExecutionMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExecutionMode.Descriptor instead.
+func (ExecutionMode) EnumDescriptor() ([]byte, []int) {
+	return file_api_payment_v1_bulk_transfer_proto_rawDescGZIP(), []int{0}
+}
+
+type TransferStatus int32
+
+const (
+	TransferStatus_TRANSFER_STATUS_UNSPECIFIED TransferStatus = 0
+	TransferStatus_TRANSFER_STATUS_ACCEPTED    TransferStatus = 1
+	TransferStatus_TRANSFER_STATUS_REJECTED    TransferStatus = 2
+)
+
+// Enum value maps for TransferStatus.
+var (
+	TransferStatus_name = map[int32]string{
+		0: "TRANSFER_STATUS_UNSPECIFIED",
+		1: "TRANSFER_STATUS_ACCEPTED",
+		2: "TRANSFER_STATUS_REJECTED",
+	}
+	TransferStatus_value = map[string]int32{
+		"TRANSFER_STATUS_UNSPECIFIED": 0,
+		"TRANSFER_STATUS_ACCEPTED":    1,
+		"TRANSFER_STATUS_REJECTED":    2,
+	}
+)
+
+func (x TransferStatus) Enum() *TransferStatus {
+	p := new(TransferStatus)
+	*p = x
+	return p
+}
+
+func (x TransferStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TransferStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_payment_v1_bulk_transfer_proto_enumTypes[1].Descriptor()
+}
+
+func (TransferStatus) Type() protoreflect.EnumType {
+	return &file_api_payment_v1_bulk_transfer_proto_enumTypes[1]
+}
+
+func (x TransferStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TransferStatus.Descriptor instead.
+func (TransferStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_payment_v1_bulk_transfer_proto_rawDescGZIP(), []int{1}
+}
+
+// Review names the check that held a bulk transfer for a manual review.
+type Review int32
+
+const (
+	Review_REVIEW_UNSPECIFIED Review = 0
+	Review_REVIEW_SANCTIONS   Review = 1
+	Review_REVIEW_FRAUD       Review = 2
+)
+
+// Enum value maps for Review.
+var (
+	Review_name = map[int32]string{
+		0: "REVIEW_UNSPECIFIED",
+		1: "REVIEW_SANCTIONS",
+		2: "REVIEW_FRAUD",
+	}
+	Review_value = map[string]int32{
+		"REVIEW_UNSPECIFIED": 0,
+		"REVIEW_SANCTIONS":   1,
+		"REVIEW_FRAUD":       2,
+	}
+)
+
+func (x Review) Enum() *Review {
+	p := new(Review)
+	*p = x
+	return p
+}
+
+func (x Review) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Review) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_payment_v1_bulk_transfer_proto_enumTypes[2].Descriptor()
+}
+
+func (Review) Type() protoreflect.EnumType {
+	return &file_api_payment_v1_bulk_transfer_proto_enumTypes[2]
+}
+
+func (x Review) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Review.Descriptor instead.
+func (Review) EnumDescriptor() ([]byte, []int) {
+	return file_api_payment_v1_bulk_transfer_proto_rawDescGZIP(), []int{2}
+}
+
+type CreditTransfer struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	AmountCents      int64                  `protobuf:"varint,1,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	Currency         string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	CounterpartyName string                 `protobuf:"bytes,3,opt,name=counterparty_name,json=counterpartyName,proto3" json:"counterparty_name,omitempty"`
+	CounterpartyBic  string                 `protobuf:"bytes,4,opt,name=counterparty_bic,json=counterpartyBic,proto3" json:"counterparty_bic,omitempty"`
+	CounterpartyIban string                 `protobuf:"bytes,5,opt,name=counterparty_iban,json=counterpartyIban,proto3" json:"counterparty_iban,omitempty"`
+	Description      string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	// Name the account of the organization that pays the transfer, both or
+	// neither. The organization account pays when they are empty.
+	DebtorIban    string `protobuf:"bytes,7,opt,name=debtor_iban,json=debtorIban,proto3" json:"debtor_iban,omitempty"`
+	DebtorBic     string `protobuf:"bytes,8,opt,name=debtor_bic,json=debtorBic,proto3" json:"debtor_bic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreditTransfer) Reset() {
+	*x = CreditTransfer{}
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreditTransfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreditTransfer) ProtoMessage() {}
+
+func (x *CreditTransfer) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreditTransfer.ProtoReflect.Descriptor instead.
+func (*CreditTransfer) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_bulk_transfer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreditTransfer) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+func (x *CreditTransfer) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreditTransfer) GetCounterpartyName() string {
+	if x != nil {
+		return x.CounterpartyName
+	}
+	return ""
+}
+
+func (x *CreditTransfer) GetCounterpartyBic() string {
+	if x != nil {
+		return x.CounterpartyBic
+	}
+	return ""
+}
+
+func (x *CreditTransfer) GetCounterpartyIban() string {
+	if x != nil {
+		return x.CounterpartyIban
+	}
+	return ""
+}
+
+func (x *CreditTransfer) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreditTransfer) GetDebtorIban() string {
+	if x != nil {
+		return x.DebtorIban
+	}
+	return ""
+}
+
+func (x *CreditTransfer) GetDebtorBic() string {
+	if x != nil {
+		return x.DebtorBic
+	}
+	return ""
+}
+
+type SubmitBulkTransferRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	OrganizationBic  string                 `protobuf:"bytes,1,opt,name=organization_bic,json=organizationBic,proto3" json:"organization_bic,omitempty"`
+	OrganizationIban string                 `protobuf:"bytes,2,opt,name=organization_iban,json=organizationIban,proto3" json:"organization_iban,omitempty"`
+	ExecutionMode    ExecutionMode          `protobuf:"varint,3,opt,name=execution_mode,json=executionMode,proto3,enum=payment.v1.ExecutionMode" json:"execution_mode,omitempty"`
+	CreditTransfers  []*CreditTransfer      `protobuf:"bytes,4,rep,name=credit_transfers,json=creditTransfers,proto3" json:"credit_transfers,omitempty"`
+	// Pending hold of the organization account that the bulk transfer captures.
+	HoldId        int64 `protobuf:"varint,5,opt,name=hold_id,json=holdId,proto3" json:"hold_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitBulkTransferRequest) Reset() {
+	*x = SubmitBulkTransferRequest{}
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitBulkTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitBulkTransferRequest) ProtoMessage() {}
+
+func (x *SubmitBulkTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitBulkTransferRequest.ProtoReflect.Descriptor instead.
+func (*SubmitBulkTransferRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_bulk_transfer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubmitBulkTransferRequest) GetOrganizationBic() string {
+	if x != nil {
+		return x.OrganizationBic
+	}
+	return ""
+}
+
+func (x *SubmitBulkTransferRequest) GetOrganizationIban() string {
+	if x != nil {
+		return x.OrganizationIban
+	}
+	return ""
+}
+
+func (x *SubmitBulkTransferRequest) GetExecutionMode() ExecutionMode {
+	if x != nil {
+		return x.ExecutionMode
+	}
+	return ExecutionMode_EXECUTION_MODE_UNSPECIFIED
+}
+
+func (x *SubmitBulkTransferRequest) GetCreditTransfers() []*CreditTransfer {
+	if x != nil {
+		return x.CreditTransfers
+	}
+	return nil
+}
+
+func (x *SubmitBulkTransferRequest) GetHoldId() int64 {
+	if x != nil {
+		return x.HoldId
+	}
+	return 0
+}
+
+type TransferResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the transfer in credit_transfers.
+	Index  int32          `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Status TransferStatus `protobuf:"varint,2,opt,name=status,proto3,enum=payment.v1.TransferStatus" json:"status,omitempty"`
+	// Why the transfer was rejected, e.g. insufficient_funds.
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Charged on top of the amount. Only set for accepted transfers.
+	FeeCents      int64 `protobuf:"varint,4,opt,name=fee_cents,json=feeCents,proto3" json:"fee_cents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferResult) Reset() {
+	*x = TransferResult{}
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferResult) ProtoMessage() {}
+
+func (x *TransferResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferResult.ProtoReflect.Descriptor instead.
+func (*TransferResult) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_bulk_transfer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TransferResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *TransferResult) GetStatus() TransferStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TransferStatus_TRANSFER_STATUS_UNSPECIFIED
+}
+
+func (x *TransferResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *TransferResult) GetFeeCents() int64 {
+	if x != nil {
+		return x.FeeCents
+	}
+	return 0
+}
+
+type SubmitBulkTransferResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	BulkTransferId int64                  `protobuf:"varint,1,opt,name=bulk_transfer_id,json=bulkTransferId,proto3" json:"bulk_transfer_id,omitempty"`
+	ExecutionMode  ExecutionMode          `protobuf:"varint,2,opt,name=execution_mode,json=executionMode,proto3,enum=payment.v1.ExecutionMode" json:"execution_mode,omitempty"`
+	Transfers      []*TransferResult      `protobuf:"bytes,3,rep,name=transfers,proto3" json:"transfers,omitempty"`
+	// Total of the fees of the accepted transfers.
+	FeeCents int64 `protobuf:"varint,4,opt,name=fee_cents,json=feeCents,proto3" json:"fee_cents,omitempty"`
+	// Set instead of the other fields when the bulk transfer is held for a
+	// manual review. It executes once an operator releases it.
+	HeldBulkTransferId int64  `protobuf:"varint,5,opt,name=held_bulk_transfer_id,json=heldBulkTransferId,proto3" json:"held_bulk_transfer_id,omitempty"`
+	Review             Review `protobuf:"varint,6,opt,name=review,proto3,enum=payment.v1.Review" json:"review,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SubmitBulkTransferResponse) Reset() {
+	*x = SubmitBulkTransferResponse{}
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitBulkTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitBulkTransferResponse) ProtoMessage() {}
+
+func (x *SubmitBulkTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitBulkTransferResponse.ProtoReflect.Descriptor instead.
+func (*SubmitBulkTransferResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_bulk_transfer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SubmitBulkTransferResponse) GetBulkTransferId() int64 {
+	if x != nil {
+		return x.BulkTransferId
+	}
+	return 0
+}
+
+func (x *SubmitBulkTransferResponse) GetExecutionMode() ExecutionMode {
+	if x != nil {
+		return x.ExecutionMode
+	}
+	return ExecutionMode_EXECUTION_MODE_UNSPECIFIED
+}
+
+func (x *SubmitBulkTransferResponse) GetTransfers() []*TransferResult {
+	if x != nil {
+		return x.Transfers
+	}
+	return nil
+}
+
+func (x *SubmitBulkTransferResponse) GetFeeCents() int64 {
+	if x != nil {
+		return x.FeeCents
+	}
+	return 0
+}
+
+func (x *SubmitBulkTransferResponse) GetHeldBulkTransferId() int64 {
+	if x != nil {
+		return x.HeldBulkTransferId
+	}
+	return 0
+}
+
+func (x *SubmitBulkTransferResponse) GetReview() Review {
+	if x != nil {
+		return x.Review
+	}
+	return Review_REVIEW_UNSPECIFIED
+}
+
+type GetBulkTransferRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	BulkTransferId int64                  `protobuf:"varint,1,opt,name=bulk_transfer_id,json=bulkTransferId,proto3" json:"bulk_transfer_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetBulkTransferRequest) Reset() {
+	*x = GetBulkTransferRequest{}
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBulkTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBulkTransferRequest) ProtoMessage() {}
+
+func (x *GetBulkTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBulkTransferRequest.ProtoReflect.Descriptor instead.
+func (*GetBulkTransferRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_bulk_transfer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetBulkTransferRequest) GetBulkTransferId() int64 {
+	if x != nil {
+		return x.BulkTransferId
+	}
+	return 0
+}
+
+type BulkTransfer struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrganizationBic  string                 `protobuf:"bytes,2,opt,name=organization_bic,json=organizationBic,proto3" json:"organization_bic,omitempty"`
+	OrganizationIban string                 `protobuf:"bytes,3,opt,name=organization_iban,json=organizationIban,proto3" json:"organization_iban,omitempty"`
+	ExecutionMode    ExecutionMode          `protobuf:"varint,4,opt,name=execution_mode,json=executionMode,proto3,enum=payment.v1.ExecutionMode" json:"execution_mode,omitempty"`
+	TotalAmountCents int64                  `protobuf:"varint,5,opt,name=total_amount_cents,json=totalAmountCents,proto3" json:"total_amount_cents,omitempty"`
+	TransferCount    int32                  `protobuf:"varint,6,opt,name=transfer_count,json=transferCount,proto3" json:"transfer_count,omitempty"`
+	RequestId        string                 `protobuf:"bytes,7,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	CreateTime       *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *BulkTransfer) Reset() {
+	*x = BulkTransfer{}
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkTransfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkTransfer) ProtoMessage() {}
+
+func (x *BulkTransfer) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkTransfer.ProtoReflect.Descriptor instead.
+func (*BulkTransfer) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_bulk_transfer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BulkTransfer) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BulkTransfer) GetOrganizationBic() string {
+	if x != nil {
+		return x.OrganizationBic
+	}
+	return ""
+}
+
+func (x *BulkTransfer) GetOrganizationIban() string {
+	if x != nil {
+		return x.OrganizationIban
+	}
+	return ""
+}
+
+func (x *BulkTransfer) GetExecutionMode() ExecutionMode {
+	if x != nil {
+		return x.ExecutionMode
+	}
+	return ExecutionMode_EXECUTION_MODE_UNSPECIFIED
+}
+
+func (x *BulkTransfer) GetTotalAmountCents() int64 {
+	if x != nil {
+		return x.TotalAmountCents
+	}
+	return 0
+}
+
+func (x *BulkTransfer) GetTransferCount() int32 {
+	if x != nil {
+		return x.TransferCount
+	}
+	return 0
+}
+
+func (x *BulkTransfer) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *BulkTransfer) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+type ListTransactionsRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	OrganizationBic  string                 `protobuf:"bytes,1,opt,name=organization_bic,json=organizationBic,proto3" json:"organization_bic,omitempty"`
+	OrganizationIban string                 `protobuf:"bytes,2,opt,name=organization_iban,json=organizationIban,proto3" json:"organization_iban,omitempty"`
+	// Only lists the transactions of this bulk transfer when set.
+	BulkTransferId int64 `protobuf:"varint,3,opt,name=bulk_transfer_id,json=bulkTransferId,proto3" json:"bulk_transfer_id,omitempty"`
+	// Defaults to 100, at most 1000.
+	PageSize      int32  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsRequest) Reset() {
+	*x = ListTransactionsRequest{}
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsRequest) ProtoMessage() {}
+
+func (x *ListTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*ListTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_bulk_transfer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListTransactionsRequest) GetOrganizationBic() string {
+	if x != nil {
+		return x.OrganizationBic
+	}
+	return ""
+}
+
+func (x *ListTransactionsRequest) GetOrganizationIban() string {
+	if x != nil {
+		return x.OrganizationIban
+	}
+	return ""
+}
+
+func (x *ListTransactionsRequest) GetBulkTransferId() int64 {
+	if x != nil {
+		return x.BulkTransferId
+	}
+	return 0
+}
+
+func (x *ListTransactionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTransactionsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type Transaction struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	BulkTransferId   int64                  `protobuf:"varint,2,opt,name=bulk_transfer_id,json=bulkTransferId,proto3" json:"bulk_transfer_id,omitempty"`
+	CounterpartyName string                 `protobuf:"bytes,3,opt,name=counterparty_name,json=counterpartyName,proto3" json:"counterparty_name,omitempty"`
+	CounterpartyBic  string                 `protobuf:"bytes,4,opt,name=counterparty_bic,json=counterpartyBic,proto3" json:"counterparty_bic,omitempty"`
+	CounterpartyIban string                 `protobuf:"bytes,5,opt,name=counterparty_iban,json=counterpartyIban,proto3" json:"counterparty_iban,omitempty"`
+	// Negative for debits.
+	AmountCents int64  `protobuf:"varint,6,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	Currency    string `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	Description string `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`
+	// One of transfer, credit or fee.
+	Kind          string `protobuf:"bytes,9,opt,name=kind,proto3" json:"kind,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_bulk_transfer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Transaction) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Transaction) GetBulkTransferId() int64 {
+	if x != nil {
+		return x.BulkTransferId
+	}
+	return 0
+}
+
+func (x *Transaction) GetCounterpartyName() string {
+	if x != nil {
+		return x.CounterpartyName
+	}
+	return ""
+}
+
+func (x *Transaction) GetCounterpartyBic() string {
+	if x != nil {
+		return x.CounterpartyBic
+	}
+	return ""
+}
+
+func (x *Transaction) GetCounterpartyIban() string {
+	if x != nil {
+		return x.CounterpartyIban
+	}
+	return ""
+}
+
+func (x *Transaction) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+func (x *Transaction) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Transaction) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Transaction) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+type ListTransactionsResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Transactions []*Transaction         `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsResponse) Reset() {
+	*x = ListTransactionsResponse{}
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsResponse) ProtoMessage() {}
+
+func (x *ListTransactionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_bulk_transfer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsResponse.ProtoReflect.Descriptor instead.
+func (*ListTransactionsResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_bulk_transfer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListTransactionsResponse) GetTransactions() []*Transaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+func (x *ListTransactionsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+var File_api_payment_v1_bulk_transfer_proto protoreflect.FileDescriptor
+
+const file_api_payment_v1_bulk_transfer_proto_rawDesc = "" +
+	"\n" +
+	"\"api/payment/v1/bulk_transfer.proto\x12\n" +
+	"payment.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb6\x02\n" +
+	"\x0eCreditTransfer\x12!\n" +
+	"\famount_cents\x18\x01 \x01(\x03R\vamountCents\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12+\n" +
+	"\x11counterparty_name\x18\x03 \x01(\tR\x10counterpartyName\x12)\n" +
+	"\x10counterparty_bic\x18\x04 \x01(\tR\x0fcounterpartyBic\x12+\n" +
+	"\x11counterparty_iban\x18\x05 \x01(\tR\x10counterpartyIban\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vdebtor_iban\x18\a \x01(\tR\n" +
+	"debtorIban\x12\x1d\n" +
+	"\n" +
+	"debtor_bic\x18\b \x01(\tR\tdebtorBic\"\x95\x02\n" +
+	"\x19SubmitBulkTransferRequest\x12)\n" +
+	"\x10organization_bic\x18\x01 \x01(\tR\x0forganizationBic\x12+\n" +
+	"\x11organization_iban\x18\x02 \x01(\tR\x10organizationIban\x12@\n" +
+	"\x0eexecution_mode\x18\x03 \x01(\x0e2\x19.payment.v1.ExecutionModeR\rexecutionMode\x12E\n" +
+	"\x10credit_transfers\x18\x04 \x03(\v2\x1a.payment.v1.CreditTransferR\x0fcreditTransfers\x12\x17\n" +
+	"\ahold_id\x18\x05 \x01(\x03R\x06holdId\"\x8f\x01\n" +
+	"\x0eTransferResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x122\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1a.payment.v1.TransferStatusR\x06status\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1b\n" +
+	"\tfee_cents\x18\x04 \x01(\x03R\bfeeCents\"\xbe\x02\n" +
+	"\x1aSubmitBulkTransferResponse\x12(\n" +
+	"\x10bulk_transfer_id\x18\x01 \x01(\x03R\x0ebulkTransferId\x12@\n" +
+	"\x0eexecution_mode\x18\x02 \x01(\x0e2\x19.payment.v1.ExecutionModeR\rexecutionMode\x128\n" +
+	"\ttransfers\x18\x03 \x03(\v2\x1a.payment.v1.TransferResultR\ttransfers\x12\x1b\n" +
+	"\tfee_cents\x18\x04 \x01(\x03R\bfeeCents\x121\n" +
+	"\x15held_bulk_transfer_id\x18\x05 \x01(\x03R\x12heldBulkTransferId\x12*\n" +
+	"\x06review\x18\x06 \x01(\x0e2\x12.payment.v1.ReviewR\x06review\"B\n" +
+	"\x16GetBulkTransferRequest\x12(\n" +
+	"\x10bulk_transfer_id\x18\x01 \x01(\x03R\x0ebulkTransferId\"\xe9\x02\n" +
+	"\fBulkTransfer\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12)\n" +
+	"\x10organization_bic\x18\x02 \x01(\tR\x0forganizationBic\x12+\n" +
+	"\x11organization_iban\x18\x03 \x01(\tR\x10organizationIban\x12@\n" +
+	"\x0eexecution_mode\x18\x04 \x01(\x0e2\x19.payment.v1.ExecutionModeR\rexecutionMode\x12,\n" +
+	"\x12total_amount_cents\x18\x05 \x01(\x03R\x10totalAmountCents\x12%\n" +
+	"\x0etransfer_count\x18\x06 \x01(\x05R\rtransferCount\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\a \x01(\tR\trequestId\x12;\n" +
+	"\vcreate_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"createTime\"\xd7\x01\n" +
+	"\x17ListTransactionsRequest\x12)\n" +
+	"\x10organization_bic\x18\x01 \x01(\tR\x0forganizationBic\x12+\n" +
+	"\x11organization_iban\x18\x02 \x01(\tR\x10organizationIban\x12(\n" +
+	"\x10bulk_transfer_id\x18\x03 \x01(\x03R\x0ebulkTransferId\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\"\xc1\x02\n" +
+	"\vTransaction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12(\n" +
+	"\x10bulk_transfer_id\x18\x02 \x01(\x03R\x0ebulkTransferId\x12+\n" +
+	"\x11counterparty_name\x18\x03 \x01(\tR\x10counterpartyName\x12)\n" +
+	"\x10counterparty_bic\x18\x04 \x01(\tR\x0fcounterpartyBic\x12+\n" +
+	"\x11counterparty_iban\x18\x05 \x01(\tR\x10counterpartyIban\x12!\n" +
+	"\famount_cents\x18\x06 \x01(\x03R\vamountCents\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\x12 \n" +
+	"\vdescription\x18\b \x01(\tR\vdescription\x12\x12\n" +
+	"\x04kind\x18\t \x01(\tR\x04kind\"\x7f\n" +
+	"\x18ListTransactionsResponse\x12;\n" +
+	"\ftransactions\x18\x01 \x03(\v2\x17.payment.v1.TransactionR\ftransactions\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken*n\n" +
+	"\rExecutionMode\x12\x1e\n" +
+	"\x1aEXECUTION_MODE_UNSPECIFIED\x10\x00\x12!\n" +
+	"\x1dEXECUTION_MODE_ALL_OR_NOTHING\x10\x01\x12\x1a\n" +
+	"\x16EXECUTION_MODE_PARTIAL\x10\x02*m\n" +
+	"\x0eTransferStatus\x12\x1f\n" +
+	"\x1bTRANSFER_STATUS_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18TRANSFER_STATUS_ACCEPTED\x10\x01\x12\x1c\n" +
+	"\x18TRANSFER_STATUS_REJECTED\x10\x02*H\n" +
+	"\x06Review\x12\x16\n" +
+	"\x12REVIEW_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10REVIEW_SANCTIONS\x10\x01\x12\x10\n" +
+	"\fREVIEW_FRAUD\x10\x022\xaa\x02\n" +
+	"\x13BulkTransferService\x12c\n" +
+	"\x12SubmitBulkTransfer\x12%.payment.v1.SubmitBulkTransferRequest\x1a&.payment.v1.SubmitBulkTransferResponse\x12O\n" +
+	"\x0fGetBulkTransfer\x12\".payment.v1.GetBulkTransferRequest\x1a\x18.payment.v1.BulkTransfer\x12]\n" +
+	"\x10ListTransactions\x12#.payment.v1.ListTransactionsRequest\x1a$.payment.v1.ListTransactionsResponseB\"Z payment/api/payment/v1;paymentv1b\x06proto3"
+
+var (
+	file_api_payment_v1_bulk_transfer_proto_rawDescOnce sync.Once
+	file_api_payment_v1_bulk_transfer_proto_rawDescData []byte
+)
+
+func file_api_payment_v1_bulk_transfer_proto_rawDescGZIP() []byte {
+	file_api_payment_v1_bulk_transfer_proto_rawDescOnce.Do(func() {
+		file_api_payment_v1_bulk_transfer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_payment_v1_bulk_transfer_proto_rawDesc), len(file_api_payment_v1_bulk_transfer_proto_rawDesc)))
+	})
+	return file_api_payment_v1_bulk_transfer_proto_rawDescData
+}
+
+var file_api_payment_v1_bulk_transfer_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_api_payment_v1_bulk_transfer_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_api_payment_v1_bulk_transfer_proto_goTypes = []any{
+	(ExecutionMode)(0),                 // 0: payment.v1.ExecutionMode
+	(TransferStatus)(0),                // 1: payment.v1.TransferStatus
+	(Review)(0),                        // 2: payment.v1.Review
+	(*CreditTransfer)(nil),             // 3: payment.v1.CreditTransfer
+	(*SubmitBulkTransferRequest)(nil),  // 4: payment.v1.SubmitBulkTransferRequest
+	(*TransferResult)(nil),             // 5: payment.v1.TransferResult
+	(*SubmitBulkTransferResponse)(nil), // 6: payment.v1.SubmitBulkTransferResponse
+	(*GetBulkTransferRequest)(nil),     // 7: payment.v1.GetBulkTransferRequest
+	(*BulkTransfer)(nil),               // 8: payment.v1.BulkTransfer
+	(*ListTransactionsRequest)(nil),    // 9: payment.v1.ListTransactionsRequest
+	(*Transaction)(nil),                // 10: payment.v1.Transaction
+	(*ListTransactionsResponse)(nil),   // 11: payment.v1.ListTransactionsResponse
+	(*timestamppb.Timestamp)(nil),      // 12: google.protobuf.Timestamp
+}
+var file_api_payment_v1_bulk_transfer_proto_depIdxs = []int32{
+	0,  // 0: payment.v1.SubmitBulkTransferRequest.execution_mode:type_name -> payment.v1.ExecutionMode
+	3,  // 1: payment.v1.SubmitBulkTransferRequest.credit_transfers:type_name -> payment.v1.CreditTransfer
+	1,  // 2: payment.v1.TransferResult.status:type_name -> payment.v1.TransferStatus
+	0,  // 3: payment.v1.SubmitBulkTransferResponse.execution_mode:type_name -> payment.v1.ExecutionMode
+	5,  // 4: payment.v1.SubmitBulkTransferResponse.transfers:type_name -> payment.v1.TransferResult
+	2,  // 5: payment.v1.SubmitBulkTransferResponse.review:type_name -> payment.v1.Review
+	0,  // 6: payment.v1.BulkTransfer.execution_mode:type_name -> payment.v1.ExecutionMode
+	12, // 7: payment.v1.BulkTransfer.create_time:type_name -> google.protobuf.Timestamp
+	10, // 8: payment.v1.ListTransactionsResponse.transactions:type_name -> payment.v1.Transaction
+	4,  // 9: payment.v1.BulkTransferService.SubmitBulkTransfer:input_type -> payment.v1.SubmitBulkTransferRequest
+	7,  // 10: payment.v1.BulkTransferService.GetBulkTransfer:input_type -> payment.v1.GetBulkTransferRequest
+	9,  // 11: payment.v1.BulkTransferService.ListTransactions:input_type -> payment.v1.ListTransactionsRequest
+	6,  // 12: payment.v1.BulkTransferService.SubmitBulkTransfer:output_type -> payment.v1.SubmitBulkTransferResponse
+	8,  // 13: payment.v1.BulkTransferService.GetBulkTransfer:output_type -> payment.v1.BulkTransfer
+	11, // 14: payment.v1.BulkTransferService.ListTransactions:output_type -> payment.v1.ListTransactionsResponse
+	12, // [12:15] is the sub-list for method output_type
+	9,  // [9:12] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_api_payment_v1_bulk_transfer_proto_init() }
+func file_api_payment_v1_bulk_transfer_proto_init() {
+	if File_api_payment_v1_bulk_transfer_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_payment_v1_bulk_transfer_proto_rawDesc), len(file_api_payment_v1_bulk_transfer_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_payment_v1_bulk_transfer_proto_goTypes,
+		DependencyIndexes: file_api_payment_v1_bulk_transfer_proto_depIdxs,
+		EnumInfos:         file_api_payment_v1_bulk_transfer_proto_enumTypes,
+		MessageInfos:      file_api_payment_v1_bulk_transfer_proto_msgTypes,
+	}.Build()
+	File_api_payment_v1_bulk_transfer_proto = out.File
+	file_api_payment_v1_bulk_transfer_proto_goTypes = nil
+	file_api_payment_v1_bulk_transfer_proto_depIdxs = nil
+}
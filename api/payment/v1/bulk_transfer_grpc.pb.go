@@ -0,0 +1,215 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: api/payment/v1/bulk_transfer.proto
+
+package paymentv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BulkTransferService_SubmitBulkTransfer_FullMethodName = "/payment.v1.BulkTransferService/SubmitBulkTransfer"
+	BulkTransferService_GetBulkTransfer_FullMethodName    = "/payment.v1.BulkTransferService/GetBulkTransfer"
+	BulkTransferService_ListTransactions_FullMethodName   = "/payment.v1.BulkTransferService/ListTransactions"
+)
+
+// BulkTransferServiceClient is the client API for BulkTransferService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BulkTransferService is the gRPC counterpart of the HTTP API for internal
+// services. Domain errors are returned with a google.rpc.ErrorInfo detail whose
+// reason is stable, e.g. INSUFFICIENT_FUNDS.
+type BulkTransferServiceClient interface {
+	// SubmitBulkTransfer executes a bulk transfer, like POST /transfers/bulk, or
+	// holds it for a manual review.
+	SubmitBulkTransfer(ctx context.Context, in *SubmitBulkTransferRequest, opts ...grpc.CallOption) (*SubmitBulkTransferResponse, error)
+	// GetBulkTransfer returns an executed bulk transfer of the organization the
+	// caller's API key is bound to.
+	GetBulkTransfer(ctx context.Context, in *GetBulkTransferRequest, opts ...grpc.CallOption) (*BulkTransfer, error)
+	// ListTransactions pages through the transactions of an account, oldest first.
+	ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error)
+}
+
+type bulkTransferServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBulkTransferServiceClient(cc grpc.ClientConnInterface) BulkTransferServiceClient {
+	return &bulkTransferServiceClient{cc}
+}
+
+func (c *bulkTransferServiceClient) SubmitBulkTransfer(ctx context.Context, in *SubmitBulkTransferRequest, opts ...grpc.CallOption) (*SubmitBulkTransferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitBulkTransferResponse)
+	err := c.cc.Invoke(ctx, BulkTransferService_SubmitBulkTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulkTransferServiceClient) GetBulkTransfer(ctx context.Context, in *GetBulkTransferRequest, opts ...grpc.CallOption) (*BulkTransfer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BulkTransfer)
+	err := c.cc.Invoke(ctx, BulkTransferService_GetBulkTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bulkTransferServiceClient) ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTransactionsResponse)
+	err := c.cc.Invoke(ctx, BulkTransferService_ListTransactions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BulkTransferServiceServer is the server API for BulkTransferService service.
+// All implementations must embed UnimplementedBulkTransferServiceServer
+// for forward compatibility.
+//
+// BulkTransferService is the gRPC counterpart of the HTTP API for internal
+// services. Domain errors are returned with a google.rpc.ErrorInfo detail whose
+// reason is stable, e.g. INSUFFICIENT_FUNDS.
+type BulkTransferServiceServer interface {
+	// SubmitBulkTransfer executes a bulk transfer, like POST /transfers/bulk, or
+	// holds it for a manual review.
+	SubmitBulkTransfer(context.Context, *SubmitBulkTransferRequest) (*SubmitBulkTransferResponse, error)
+	// GetBulkTransfer returns an executed bulk transfer of the organization the
+	// caller's API key is bound to.
+	GetBulkTransfer(context.Context, *GetBulkTransferRequest) (*BulkTransfer, error)
+	// ListTransactions pages through the transactions of an account, oldest first.
+	ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error)
+	mustEmbedUnimplementedBulkTransferServiceServer()
+}
+
+// UnimplementedBulkTransferServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBulkTransferServiceServer struct{}
+
+func (UnimplementedBulkTransferServiceServer) SubmitBulkTransfer(context.Context, *SubmitBulkTransferRequest) (*SubmitBulkTransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitBulkTransfer not implemented")
+}
+func (UnimplementedBulkTransferServiceServer) GetBulkTransfer(context.Context, *GetBulkTransferRequest) (*BulkTransfer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBulkTransfer not implemented")
+}
+func (UnimplementedBulkTransferServiceServer) ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransactions not implemented")
+}
+func (UnimplementedBulkTransferServiceServer) mustEmbedUnimplementedBulkTransferServiceServer() {}
+func (UnimplementedBulkTransferServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeBulkTransferServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BulkTransferServiceServer will
+// result in compilation errors.
+type UnsafeBulkTransferServiceServer interface {
+	mustEmbedUnimplementedBulkTransferServiceServer()
+}
+
+func RegisterBulkTransferServiceServer(s grpc.ServiceRegistrar, srv BulkTransferServiceServer) {
+	// If the following call pancis, it indicates UnimplementedBulkTransferServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BulkTransferService_ServiceDesc, srv)
+}
+
+func _BulkTransferService_SubmitBulkTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitBulkTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulkTransferServiceServer).SubmitBulkTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BulkTransferService_SubmitBulkTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulkTransferServiceServer).SubmitBulkTransfer(ctx, req.(*SubmitBulkTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulkTransferService_GetBulkTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBulkTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulkTransferServiceServer).GetBulkTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BulkTransferService_GetBulkTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulkTransferServiceServer).GetBulkTransfer(ctx, req.(*GetBulkTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BulkTransferService_ListTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BulkTransferServiceServer).ListTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BulkTransferService_ListTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BulkTransferServiceServer).ListTransactions(ctx, req.(*ListTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BulkTransferService_ServiceDesc is the grpc.ServiceDesc for BulkTransferService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BulkTransferService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "payment.v1.BulkTransferService",
+	HandlerType: (*BulkTransferServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitBulkTransfer",
+			Handler:    _BulkTransferService_SubmitBulkTransfer_Handler,
+		},
+		{
+			MethodName: "GetBulkTransfer",
+			Handler:    _BulkTransferService_GetBulkTransfer_Handler,
+		},
+		{
+			MethodName: "ListTransactions",
+			Handler:    _BulkTransferService_ListTransactions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/payment/v1/bulk_transfer.proto",
+}
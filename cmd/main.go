@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -9,9 +11,19 @@ import (
 	"time"
 
 	"payment/config"
+	"payment/internal/admission"
 	"payment/internal/core"
+	"payment/internal/fraudrules"
+	"payment/internal/grpc"
 	"payment/internal/http"
+	"payment/internal/memory"
+	"payment/internal/metrics"
+	"payment/internal/pricing"
+	"payment/internal/ratelimit"
+	"payment/internal/reconciliation"
+	"payment/internal/sanctions"
 	"payment/internal/sqlite"
+	"payment/internal/telemetry"
 )
 
 func main() {
@@ -26,28 +38,85 @@ func main() {
 		os.Exit(1)
 	}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	logger := slog.New(telemetry.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.Level(cfg.LogLevel),
-	}))
+	})))
 	slog.SetDefault(logger)
 
+	shutdownTracing, err := telemetry.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+
 	logger.InfoContext(ctx, "Starting application")
 
-	dbClient, err := sqlite.NewClient(cfg.Database)
+	store, err := openStore(ctx, cfg)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to open account repository", "repository", cfg.Repository, "error", err)
+		os.Exit(1)
+	}
+	if cfg.Repository == repositoryMemory {
+		logger.WarnContext(ctx, "Accounts are kept in memory and lost on shutdown")
+	}
+
+	screener, err := sanctions.NewScreener(cfg.Sanctions)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load sanctions list", "error", err)
+		os.Exit(1)
+	}
+	if screener.Size() == 0 {
+		logger.InfoContext(ctx, "Sanctions list is empty, screening lets every transfer through")
+	}
+
+	fraudEngine := core.NewFraudEngine(nil)
+	fraudRulesWatcher := fraudrules.NewWatcher(cfg.FraudRules, fraudEngine, logger)
+	if err = fraudRulesWatcher.Load(); err != nil {
+		slog.ErrorContext(ctx, "failed to load fraud rules", "error", err)
+		os.Exit(1)
+	}
+
+	watchCtx, stopWatching := context.WithCancel(ctx)
+	defer stopWatching()
+	go fraudRulesWatcher.Run(watchCtx)
+
+	pricingPlans, err := pricing.Load(cfg.Pricing)
 	if err != nil {
-		slog.ErrorContext(ctx, "failed to create db client", "error", err)
+		slog.ErrorContext(ctx, "failed to load pricing plans", "error", err)
 		os.Exit(1)
 	}
 
-	accountRepository := sqlite.NewAccountStore(dbClient.DB())
-	service := core.NewService(accountRepository)
-	httpServer := http.NewServer(service, logger, cfg.HTTP)
+	// Both transports go through the same admission, so that the batch size
+	// and the daily quota hold whichever one a client uses.
+	rateLimiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), cfg.RateLimit)
+	accountRepository := telemetry.NewTracingRepository(store.writer)
+	service := core.NewService(accountRepository, screener, fraudEngine, pricingPlans).
+		WithAdmission(admission.New(rateLimiter, logger, cfg.Admission))
+
+	// Staged bulk transfers do not outlive their upload: those found at
+	// startup were abandoned when the previous run stopped.
+	purged, err := service.PurgeStagedBulkTransfers(ctx, time.Now())
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to purge staged bulk transfers", "error", err)
+	} else if purged > 0 {
+		logger.InfoContext(ctx, "Purged abandoned staged bulk transfers", "count", purged)
+	}
+
+	reconciler := core.NewReconciler(telemetry.NewTracingRepository(store.reader), accountRepository)
+	go reconciliation.NewJob(reconciler, logger, cfg.Reconciliation).Run(watchCtx)
+	httpServer := http.NewServer(service, rateLimiter, store.health, logger, cfg.Admission.MaxBatchSize, cfg.HTTP)
+	grpcServer := grpc.NewServer(service, rateLimiter, logger, cfg.GRPC)
 
 	if err = httpServer.Start(ctx); err != nil {
 		slog.ErrorContext(ctx, "failed to start http server", "error", err)
 		os.Exit(1)
 	}
 
+	if err = grpcServer.Start(ctx); err != nil {
+		slog.ErrorContext(ctx, "failed to start grpc server", "error", err)
+		os.Exit(1)
+	}
+
 	logger.InfoContext(ctx, "Application started successfully")
 
 	<-stop
@@ -61,9 +130,84 @@ func main() {
 		logger.ErrorContext(ctx, "Error stopping HTTP server", "error", err)
 	}
 
-	if err = dbClient.Close(); err != nil {
+	if err = grpcServer.Stop(shutdownCtx); err != nil {
+		logger.ErrorContext(ctx, "Error stopping gRPC server", "error", err)
+	}
+
+	if err = store.close(); err != nil {
 		logger.ErrorContext(ctx, "Error closing database", "error", err)
 	}
 
+	if err = shutdownTracing(shutdownCtx); err != nil {
+		logger.ErrorContext(ctx, "Error flushing traces", "error", err)
+	}
+
 	logger.InfoContext(ctx, "Application shutdown complete")
 }
+
+const (
+	repositorySQLite = "sqlite"
+	repositoryMemory = "memory"
+)
+
+// store is the account repository selected by ACCOUNT_REPOSITORY.
+type store struct {
+	writer core.AccountRepository
+	// reader serves reconciliation, which must never hold the write lock.
+	reader core.AccountRepository
+	health http.HealthChecker
+	close  func() error
+}
+
+func openStore(ctx context.Context, cfg config.Config) (store, error) {
+	switch cfg.Repository {
+	case repositorySQLite:
+		return openSQLiteStore(ctx, cfg.Database)
+	case repositoryMemory:
+		// Transactions of the memory store read a consistent copy, so
+		// reconciliation can share it.
+		accountStore := memory.NewAccountStore()
+		return store{
+			writer: accountStore,
+			reader: accountStore,
+			health: accountStore,
+			close:  func() error { return nil },
+		}, nil
+	default:
+		return store{}, fmt.Errorf("unknown repository %q, expected %q or %q", cfg.Repository, repositorySQLite, repositoryMemory)
+	}
+}
+
+func openSQLiteStore(ctx context.Context, cfg sqlite.Config) (store, error) {
+	dbClient, err := sqlite.NewClient(cfg)
+	if err != nil {
+		return store{}, fmt.Errorf("failed to create db client: %w", err)
+	}
+
+	if err = dbClient.Migrate(ctx); err != nil {
+		dbClient.Close()
+		return store{}, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	if err = metrics.RegisterDBStats(dbClient.DB(), "sqlite"); err != nil {
+		dbClient.Close()
+		return store{}, fmt.Errorf("failed to register database metrics: %w", err)
+	}
+
+	// Reconciliation reads through its own read-only connection so that it
+	// never holds the write lock.
+	readOnlyClient, err := sqlite.NewReadOnlyClient(cfg)
+	if err != nil {
+		dbClient.Close()
+		return store{}, fmt.Errorf("failed to create read-only db client: %w", err)
+	}
+
+	return store{
+		writer: sqlite.NewAccountStore(dbClient.DB()).WithRetryPolicy(cfg.RetryPolicy()),
+		reader: sqlite.NewAccountStore(readOnlyClient.DB()).WithRetryPolicy(cfg.RetryPolicy()),
+		health: dbClient,
+		close: func() error {
+			return errors.Join(readOnlyClient.Close(), dbClient.Close())
+		},
+	}, nil
+}
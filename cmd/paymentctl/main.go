@@ -0,0 +1,524 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"payment/config"
+	"payment/internal/core"
+	"payment/internal/fraudrules"
+	"payment/internal/http"
+	"payment/internal/pricing"
+	"payment/internal/reconciliation"
+	"payment/internal/sanctions"
+	"payment/internal/sqlite"
+)
+
+const usage = `usage: paymentctl <command> [flags]
+
+commands:
+  accounts create     -name NAME -iban IBAN -bic BIC [-balance AMOUNT] [-organization-id ID]
+  accounts list
+  accounts freeze     -iban IBAN -bic BIC [-reason TEXT]
+  accounts unfreeze   -iban IBAN -bic BIC [-reason TEXT]
+  accounts credit     -iban IBAN -bic BIC -amount AMOUNT
+  accounts overdraft  -iban IBAN -bic BIC -limit AMOUNT [-reason TEXT]
+  accounts plan       -iban IBAN -bic BIC -plan NAME [-reason TEXT]
+  holds place         -iban IBAN -bic BIC -amount AMOUNT -ttl DURATION [-reason TEXT]
+  holds release       -iban IBAN -bic BIC -id ID [-reason TEXT]
+  holds list          -iban IBAN -bic BIC
+  batches list        -iban IBAN -bic BIC
+  batches show        -id ID
+  batches held        lists the bulk transfers held for a manual review
+  batches release     -id ID [-reason TEXT]
+  batches reject      -id ID [-reason TEXT]
+  transactions list   -iban IBAN -bic BIC [-batch ID] [-after ID] [-limit N]
+  reconcile           checks every balance against its ledger, exits 1 on discrepancies
+
+Every command accepts -format table|json|csv; redirect the output to export it.
+The database is configured like the service, through DATABASE_PATH, the
+pricing plans through PRICING_PLANS_PATH, and the sanctions list and fraud
+rules that released bulk transfers go through like the service's.
+`
+
+// paymentctl is the operations CLI. It goes through core.Service, so that
+// changes are made in transactions and recorded in the audit log like the
+// ones made by the API.
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// app holds what the commands work with.
+type app struct {
+	service    core.Service
+	reconciler reconciliation.Job
+}
+
+type command func(ctx context.Context, app app, args []string, w io.Writer) error
+
+var commands = map[string]command{
+	"accounts create":    createAccount,
+	"accounts list":      listAccounts,
+	"accounts freeze":    setAccountFrozen("accounts freeze", true),
+	"accounts unfreeze":  setAccountFrozen("accounts unfreeze", false),
+	"accounts credit":    creditAccount,
+	"accounts overdraft": setOverdraftLimit,
+	"accounts plan":      setPricingPlan,
+	"holds place":        placeHold,
+	"holds release":      releaseHold,
+	"holds list":         listHolds,
+	"batches list":       listBatches,
+	"batches show":       showBatch,
+	"batches held":       listHeldBatches,
+	"batches release":    releaseHeldBatch,
+	"batches reject":     rejectHeldBatch,
+	"transactions list":  listTransactions,
+	"reconcile":          reconcile,
+}
+
+func run(ctx context.Context, args []string, w io.Writer) error {
+	cmd, args, err := lookup(args)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	dbClient, err := sqlite.NewClient(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to create db client: %w", err)
+	}
+	defer dbClient.Close()
+
+	// The service owns the migrations; an older schema would be missing
+	// columns.
+	if err = dbClient.CheckSchema(ctx); err != nil {
+		return fmt.Errorf("database is not ready, start the service to migrate it: %w", err)
+	}
+
+	readOnlyClient, err := sqlite.NewReadOnlyClient(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to create read-only db client: %w", err)
+	}
+	defer readOnlyClient.Close()
+
+	// Plans are only needed to check the names given to accounts.
+	pricingPlans, err := pricing.Load(cfg.Pricing)
+	if err != nil {
+		return fmt.Errorf("failed to load pricing plans: %w", err)
+	}
+
+	accountStore := sqlite.NewAccountStore(dbClient.DB()).WithRetryPolicy(cfg.Database.RetryPolicy())
+	reconciler := core.NewReconciler(sqlite.NewAccountStore(readOnlyClient.DB()).WithRetryPolicy(cfg.Database.RetryPolicy()), accountStore)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	// A released bulk transfer is screened and scored again: its approval only
+	// covers the review it was held for.
+	screener, err := sanctions.NewScreener(cfg.Sanctions)
+	if err != nil {
+		return fmt.Errorf("failed to load sanctions list: %w", err)
+	}
+
+	fraudEngine := core.NewFraudEngine(nil)
+	if err = fraudrules.NewWatcher(cfg.FraudRules, fraudEngine, logger).Load(); err != nil {
+		return fmt.Errorf("failed to load fraud rules: %w", err)
+	}
+
+	return cmd(core.WithActor(ctx, actor()), app{
+		service:    core.NewService(accountStore, screener, fraudEngine, pricingPlans),
+		reconciler: reconciliation.NewJob(reconciler, logger, cfg.Reconciliation),
+	}, args, w)
+}
+
+// lookup finds the command named by the first one or two arguments and
+// returns the arguments that follow.
+func lookup(args []string) (command, []string, error) {
+	if len(args) >= 2 {
+		if cmd, ok := commands[args[0]+" "+args[1]]; ok {
+			return cmd, args[2:], nil
+		}
+	}
+
+	if len(args) >= 1 {
+		if cmd, ok := commands[args[0]]; ok {
+			return cmd, args[1:], nil
+		}
+
+		return nil, nil, fmt.Errorf("unknown command %q\n\n%s", strings.Join(args[:min(len(args), 2)], " "), usage)
+	}
+
+	return nil, nil, errors.New(usage)
+}
+
+// actor identifies the operator in the audit log.
+func actor() string {
+	current, err := user.Current()
+	if err != nil {
+		return "paymentctl"
+	}
+
+	return "paymentctl:" + current.Username
+}
+
+// flags parses the flags of a command. -format is added to every command.
+type flags struct {
+	*flag.FlagSet
+	format *string
+}
+
+func newFlags(name string) flags {
+	set := flag.NewFlagSet(name, flag.ContinueOnError)
+	return flags{FlagSet: set, format: set.String("format", formatTable, "output format: table, json or csv")}
+}
+
+func (f flags) parse(args []string, required ...string) error {
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+
+	for _, name := range required {
+		if f.Lookup(name).Value.String() == "" {
+			return fmt.Errorf("-%s is required", name)
+		}
+	}
+
+	return nil
+}
+
+func createAccount(ctx context.Context, app app, args []string, w io.Writer) error {
+	f := newFlags("accounts create")
+	name := f.String("name", "", "organization name")
+	iban := f.String("iban", "", "account IBAN")
+	bic := f.String("bic", "", "account BIC")
+	balance := f.String("balance", "0", "opening balance, e.g. 100.00")
+	organizationID := f.String("organization-id", "", "organization whose other accounts may pay from this one")
+	if err := f.parse(args, "name", "iban", "bic"); err != nil {
+		return err
+	}
+
+	balanceCents, err := http.ParseAmountToCents(*balance)
+	if err != nil {
+		return fmt.Errorf("invalid -balance: %w", err)
+	}
+
+	account, err := app.service.CreateAccount(ctx, core.Account{
+		OrganizationName: *name,
+		OrganizationID:   *organizationID,
+		BalanceCents:     balanceCents,
+		IBAN:             *iban,
+		BIC:              *bic,
+	})
+	if err != nil {
+		return err
+	}
+
+	return write(w, *f.format, accountsOutput([]core.Account{account}))
+}
+
+func listAccounts(ctx context.Context, app app, args []string, w io.Writer) error {
+	f := newFlags("accounts list")
+	if err := f.parse(args); err != nil {
+		return err
+	}
+
+	accounts, err := app.service.ListAccounts(ctx)
+	if err != nil {
+		return err
+	}
+
+	return write(w, *f.format, accountsOutput(accounts))
+}
+
+func setAccountFrozen(name string, frozen bool) command {
+	return func(ctx context.Context, app app, args []string, w io.Writer) error {
+		f := newFlags(name)
+		iban := f.String("iban", "", "account IBAN")
+		bic := f.String("bic", "", "account BIC")
+		reason := f.String("reason", "", "reason recorded in the audit log")
+		if err := f.parse(args, "iban", "bic"); err != nil {
+			return err
+		}
+
+		account, err := app.service.SetAccountFrozen(ctx, *iban, *bic, frozen, *reason)
+		if err != nil {
+			return err
+		}
+
+		return write(w, *f.format, accountsOutput([]core.Account{account}))
+	}
+}
+
+func creditAccount(ctx context.Context, app app, args []string, w io.Writer) error {
+	f := newFlags("accounts credit")
+	iban := f.String("iban", "", "account IBAN")
+	bic := f.String("bic", "", "account BIC")
+	amount := f.String("amount", "", "amount to credit, e.g. 100.00")
+	if err := f.parse(args, "iban", "bic", "amount"); err != nil {
+		return err
+	}
+
+	amountCents, err := http.ParseAmountToCents(*amount)
+	if err != nil {
+		return fmt.Errorf("invalid -amount: %w", err)
+	}
+
+	account, err := app.service.CreditAccount(ctx, *iban, *bic, amountCents)
+	if err != nil {
+		return err
+	}
+
+	return write(w, *f.format, accountsOutput([]core.Account{account}))
+}
+
+func setOverdraftLimit(ctx context.Context, app app, args []string, w io.Writer) error {
+	f := newFlags("accounts overdraft")
+	iban := f.String("iban", "", "account IBAN")
+	bic := f.String("bic", "", "account BIC")
+	limit := f.String("limit", "", "how far below zero the balance may go, e.g. 5000.00; 0 removes the overdraft")
+	reason := f.String("reason", "", "reason recorded in the audit log")
+	if err := f.parse(args, "iban", "bic", "limit"); err != nil {
+		return err
+	}
+
+	limitCents, err := http.ParseAmountToCents(*limit)
+	if err != nil {
+		return fmt.Errorf("invalid -limit: %w", err)
+	}
+
+	account, err := app.service.SetOverdraftLimit(ctx, *iban, *bic, limitCents, *reason)
+	if err != nil {
+		return err
+	}
+
+	return write(w, *f.format, accountsOutput([]core.Account{account}))
+}
+
+func setPricingPlan(ctx context.Context, app app, args []string, w io.Writer) error {
+	f := newFlags("accounts plan")
+	iban := f.String("iban", "", "account IBAN")
+	bic := f.String("bic", "", "account BIC")
+	plan := f.String("plan", "", "pricing plan of PRICING_PLANS_PATH; empty removes the account from any plan")
+	reason := f.String("reason", "", "reason recorded in the audit log")
+	if err := f.parse(args, "iban", "bic"); err != nil {
+		return err
+	}
+
+	account, err := app.service.SetPricingPlan(ctx, *iban, *bic, *plan, *reason)
+	if err != nil {
+		return err
+	}
+
+	return write(w, *f.format, accountsOutput([]core.Account{account}))
+}
+
+func placeHold(ctx context.Context, app app, args []string, w io.Writer) error {
+	f := newFlags("holds place")
+	iban := f.String("iban", "", "account IBAN")
+	bic := f.String("bic", "", "account BIC")
+	amount := f.String("amount", "", "amount to reserve, e.g. 100.00")
+	ttl := f.Duration("ttl", 0, "how long the hold lasts, e.g. 72h")
+	reason := f.String("reason", "", "reason recorded in the audit log")
+	if err := f.parse(args, "iban", "bic", "amount"); err != nil {
+		return err
+	}
+	if *ttl <= 0 {
+		return errors.New("-ttl is required")
+	}
+
+	amountCents, err := http.ParseAmountToCents(*amount)
+	if err != nil {
+		return fmt.Errorf("invalid -amount: %w", err)
+	}
+
+	hold, err := app.service.PlaceHold(ctx, *iban, *bic, amountCents, time.Now().Add(*ttl), *reason)
+	if err != nil {
+		return err
+	}
+
+	return write(w, *f.format, holdsOutput([]core.Hold{hold}))
+}
+
+func releaseHold(ctx context.Context, app app, args []string, w io.Writer) error {
+	f := newFlags("holds release")
+	iban := f.String("iban", "", "account IBAN")
+	bic := f.String("bic", "", "account BIC")
+	id := f.Int64("id", 0, "hold ID")
+	reason := f.String("reason", "", "reason recorded in the audit log")
+	if err := f.parse(args, "iban", "bic"); err != nil {
+		return err
+	}
+	if *id <= 0 {
+		return errors.New("-id is required")
+	}
+
+	hold, err := app.service.ReleaseHold(ctx, *iban, *bic, *id, *reason)
+	if err != nil {
+		return err
+	}
+
+	return write(w, *f.format, holdsOutput([]core.Hold{hold}))
+}
+
+func listHolds(ctx context.Context, app app, args []string, w io.Writer) error {
+	f := newFlags("holds list")
+	iban := f.String("iban", "", "account IBAN")
+	bic := f.String("bic", "", "account BIC")
+	if err := f.parse(args, "iban", "bic"); err != nil {
+		return err
+	}
+
+	holds, err := app.service.ListHolds(ctx, *iban, *bic)
+	if err != nil {
+		return err
+	}
+
+	return write(w, *f.format, holdsOutput(holds))
+}
+
+func listBatches(ctx context.Context, app app, args []string, w io.Writer) error {
+	f := newFlags("batches list")
+	iban := f.String("iban", "", "account IBAN")
+	bic := f.String("bic", "", "account BIC")
+	if err := f.parse(args, "iban", "bic"); err != nil {
+		return err
+	}
+
+	records, err := app.service.ListBulkTransfers(ctx, *iban, *bic)
+	if err != nil {
+		return err
+	}
+
+	return write(w, *f.format, batchesOutput(records))
+}
+
+func showBatch(ctx context.Context, app app, args []string, w io.Writer) error {
+	f := newFlags("batches show")
+	id := f.Int64("id", 0, "bulk transfer ID")
+	if err := f.parse(args); err != nil {
+		return err
+	}
+	if *id <= 0 {
+		return errors.New("-id is required")
+	}
+
+	record, err := app.service.GetBulkTransfer(ctx, *id)
+	if err != nil {
+		return err
+	}
+
+	return write(w, *f.format, batchesOutput([]core.BulkTransferRecord{record}))
+}
+
+func listHeldBatches(ctx context.Context, app app, args []string, w io.Writer) error {
+	f := newFlags("batches held")
+	if err := f.parse(args); err != nil {
+		return err
+	}
+
+	held, err := app.service.ListHeldBulkTransfers(ctx)
+	if err != nil {
+		return err
+	}
+
+	return write(w, *f.format, heldBatchesOutput(held))
+}
+
+// releaseHeldBatch executes a held bulk transfer. One that another review
+// holds again is reported as an error naming that review.
+func releaseHeldBatch(ctx context.Context, app app, args []string, w io.Writer) error {
+	f := newFlags("batches release")
+	id := f.Int64("id", 0, "held bulk transfer ID")
+	reason := f.String("reason", "", "reason recorded in the audit log")
+	if err := f.parse(args); err != nil {
+		return err
+	}
+	if *id <= 0 {
+		return errors.New("-id is required")
+	}
+
+	result, err := app.service.ReleaseHeldBulkTransfer(ctx, *id, *reason)
+	if err != nil {
+		return err
+	}
+
+	return write(w, *f.format, resultOutput(result))
+}
+
+func rejectHeldBatch(ctx context.Context, app app, args []string, w io.Writer) error {
+	f := newFlags("batches reject")
+	id := f.Int64("id", 0, "held bulk transfer ID")
+	reason := f.String("reason", "", "reason recorded in the audit log")
+	if err := f.parse(args); err != nil {
+		return err
+	}
+	if *id <= 0 {
+		return errors.New("-id is required")
+	}
+
+	held, err := app.service.RejectHeldBulkTransfer(ctx, *id, *reason)
+	if err != nil {
+		return err
+	}
+
+	return write(w, *f.format, heldBatchesOutput([]core.HeldBulkTransfer{held}))
+}
+
+func listTransactions(ctx context.Context, app app, args []string, w io.Writer) error {
+	f := newFlags("transactions list")
+	iban := f.String("iban", "", "account IBAN")
+	bic := f.String("bic", "", "account BIC")
+	batch := f.Int64("batch", 0, "only list the transactions of this bulk transfer")
+	after := f.Int64("after", 0, "only list transactions with a greater ID")
+	limit := f.Int("limit", 1000, "maximum number of transactions")
+	if err := f.parse(args, "iban", "bic"); err != nil {
+		return err
+	}
+
+	transactions, err := app.service.ListTransactions(ctx, *iban, *bic, core.TransactionQuery{
+		BulkTransferID: *batch,
+		AfterID:        *after,
+		Limit:          *limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	return write(w, *f.format, transactionsOutput(transactions))
+}
+
+func reconcile(ctx context.Context, app app, args []string, w io.Writer) error {
+	f := newFlags("reconcile")
+	if err := f.parse(args); err != nil {
+		return err
+	}
+
+	report, err := app.reconciler.RunOnce(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = write(w, *f.format, discrepanciesOutput(report.Discrepancies)); err != nil {
+		return err
+	}
+
+	if len(report.Discrepancies) > 0 {
+		return fmt.Errorf("%d of %d accounts do not match their ledger, see reconciliation report %d",
+			len(report.Discrepancies), report.AccountCount, report.ID)
+	}
+
+	return nil
+}
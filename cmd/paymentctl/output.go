@@ -0,0 +1,321 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"payment/internal/core"
+	"payment/internal/http"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// output holds the result of a command both as rows, for tables and CSV, and
+// as a value to encode as JSON.
+type output struct {
+	header []string
+	rows   [][]string
+	value  any
+}
+
+func write(w io.Writer, format string, out output) error {
+	switch format {
+	case formatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(out.header, "\t"))
+		for _, row := range out.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(out.value)
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(out.header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(out.rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	default:
+		return fmt.Errorf("unknown format %q, expected table, json or csv", format)
+	}
+}
+
+type accountView struct {
+	ID               int64  `json:"id"`
+	OrganizationName string `json:"organization_name"`
+	IBAN             string `json:"iban"`
+	BIC              string `json:"bic"`
+	Balance          string `json:"balance"`
+	Available        string `json:"available"`
+	OverdraftLimit   string `json:"overdraft_limit"`
+	OverdraftUsed    string `json:"overdraft_used"`
+	PricingPlan      string `json:"pricing_plan"`
+	Frozen           bool   `json:"frozen"`
+}
+
+func accountsOutput(accounts []core.Account) output {
+	out := output{header: []string{"id", "organization_name", "iban", "bic", "balance", "available", "overdraft_limit", "overdraft_used", "pricing_plan", "frozen"}}
+
+	views := make([]accountView, 0, len(accounts))
+	for _, account := range accounts {
+		view := accountView{
+			ID:               account.ID,
+			OrganizationName: account.OrganizationName,
+			IBAN:             account.IBAN,
+			BIC:              account.BIC,
+			Balance:          http.FormatCents(account.BalanceCents),
+			Available:        http.FormatCents(account.AvailableCents()),
+			OverdraftLimit:   http.FormatCents(account.OverdraftLimitCents),
+			OverdraftUsed:    http.FormatCents(account.OverdraftUsedCents()),
+			PricingPlan:      account.PricingPlan,
+			Frozen:           account.Frozen,
+		}
+		views = append(views, view)
+		out.rows = append(out.rows, []string{
+			strconv.FormatInt(view.ID, 10), view.OrganizationName, view.IBAN, view.BIC, view.Balance, view.Available,
+			view.OverdraftLimit, view.OverdraftUsed, view.PricingPlan, strconv.FormatBool(view.Frozen),
+		})
+	}
+	out.value = views
+
+	return out
+}
+
+type holdView struct {
+	ID        int64  `json:"id"`
+	Amount    string `json:"amount"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason"`
+	CreatedAt string `json:"created_at"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+func holdsOutput(holds []core.Hold) output {
+	out := output{header: []string{"id", "amount", "status", "reason", "created_at", "expires_at"}}
+
+	now := time.Now()
+	views := make([]holdView, 0, len(holds))
+	for _, hold := range holds {
+		view := holdView{
+			ID:        hold.ID,
+			Amount:    http.FormatCents(hold.AmountCents),
+			Status:    string(hold.StatusAt(now)),
+			Reason:    hold.Reason,
+			CreatedAt: hold.CreatedAt.UTC().Format(time.RFC3339),
+			ExpiresAt: hold.ExpiresAt.UTC().Format(time.RFC3339),
+		}
+		views = append(views, view)
+		out.rows = append(out.rows, []string{
+			strconv.FormatInt(view.ID, 10), view.Amount, view.Status, view.Reason, view.CreatedAt, view.ExpiresAt,
+		})
+	}
+	out.value = views
+
+	return out
+}
+
+type batchView struct {
+	ID               int64  `json:"id"`
+	OrganizationIBAN string `json:"organization_iban"`
+	OrganizationBIC  string `json:"organization_bic"`
+	ExecutionMode    string `json:"execution_mode"`
+	TotalAmount      string `json:"total_amount"`
+	TransferCount    int    `json:"transfer_count"`
+	RequestID        string `json:"request_id"`
+	CreatedAt        string `json:"created_at"`
+}
+
+func batchesOutput(records []core.BulkTransferRecord) output {
+	out := output{header: []string{
+		"id", "organization_iban", "organization_bic", "execution_mode", "total_amount", "transfer_count", "request_id", "created_at",
+	}}
+
+	views := make([]batchView, 0, len(records))
+	for _, record := range records {
+		view := batchView{
+			ID:               record.ID,
+			OrganizationIBAN: record.OrganizationIBAN,
+			OrganizationBIC:  record.OrganizationBIC,
+			ExecutionMode:    string(record.ExecutionMode),
+			TotalAmount:      http.FormatCents(record.TotalCents),
+			TransferCount:    record.TransferCount,
+			RequestID:        record.RequestID,
+			CreatedAt:        record.CreatedAt.UTC().Format(time.RFC3339),
+		}
+		views = append(views, view)
+		out.rows = append(out.rows, []string{
+			strconv.FormatInt(view.ID, 10), view.OrganizationIBAN, view.OrganizationBIC, view.ExecutionMode,
+			view.TotalAmount, strconv.Itoa(view.TransferCount), view.RequestID, view.CreatedAt,
+		})
+	}
+	out.value = views
+
+	return out
+}
+
+type heldBatchView struct {
+	ID               int64  `json:"id"`
+	OrganizationIBAN string `json:"organization_iban"`
+	OrganizationBIC  string `json:"organization_bic"`
+	ExecutionMode    string `json:"execution_mode"`
+	TotalAmount      string `json:"total_amount"`
+	TransferCount    int    `json:"transfer_count"`
+	Review           string `json:"review"`
+	Reason           string `json:"reason"`
+	RequestID        string `json:"request_id"`
+	HeldAt           string `json:"held_at"`
+}
+
+func heldBatchesOutput(held []core.HeldBulkTransfer) output {
+	out := output{header: []string{
+		"id", "organization_iban", "organization_bic", "execution_mode", "total_amount", "transfer_count", "review", "reason", "request_id", "held_at",
+	}}
+
+	views := make([]heldBatchView, 0, len(held))
+	for _, bulkTransfer := range held {
+		view := heldBatchView{
+			ID:               bulkTransfer.ID,
+			OrganizationIBAN: bulkTransfer.OrganizationIBAN,
+			OrganizationBIC:  bulkTransfer.OrganizationBIC,
+			ExecutionMode:    string(bulkTransfer.ExecutionMode),
+			TotalAmount:      http.FormatCents(bulkTransfer.TotalCents),
+			TransferCount:    bulkTransfer.TransferCount,
+			Review:           string(bulkTransfer.Review),
+			Reason:           bulkTransfer.Reason,
+			RequestID:        bulkTransfer.RequestID,
+			HeldAt:           bulkTransfer.HeldAt.UTC().Format(time.RFC3339),
+		}
+		views = append(views, view)
+		out.rows = append(out.rows, []string{
+			strconv.FormatInt(view.ID, 10), view.OrganizationIBAN, view.OrganizationBIC, view.ExecutionMode,
+			view.TotalAmount, strconv.Itoa(view.TransferCount), view.Review, view.Reason, view.RequestID, view.HeldAt,
+		})
+	}
+	out.value = views
+
+	return out
+}
+
+type resultView struct {
+	BulkTransferID int64  `json:"bulk_transfer_id"`
+	ExecutionMode  string `json:"execution_mode"`
+	AcceptedCount  int    `json:"accepted_count"`
+	RejectedCount  int    `json:"rejected_count"`
+	Fees           string `json:"fees"`
+}
+
+// resultOutput summarizes an executed bulk transfer; batches show and
+// transactions list detail it.
+func resultOutput(result core.BulkTransferResult) output {
+	view := resultView{
+		BulkTransferID: result.BulkTransferID,
+		ExecutionMode:  string(result.ExecutionMode),
+		AcceptedCount:  result.AcceptedCount(),
+		RejectedCount:  len(result.Transfers) - result.AcceptedCount(),
+		Fees:           http.FormatCents(result.FeesCents()),
+	}
+
+	return output{
+		header: []string{"bulk_transfer_id", "execution_mode", "accepted_count", "rejected_count", "fees"},
+		rows: [][]string{{
+			strconv.FormatInt(view.BulkTransferID, 10), view.ExecutionMode,
+			strconv.Itoa(view.AcceptedCount), strconv.Itoa(view.RejectedCount), view.Fees,
+		}},
+		value: view,
+	}
+}
+
+type transactionView struct {
+	ID               int64  `json:"id"`
+	BulkTransferID   int64  `json:"bulk_transfer_id"`
+	Kind             string `json:"kind"`
+	CounterpartyName string `json:"counterparty_name"`
+	CounterpartyIBAN string `json:"counterparty_iban"`
+	CounterpartyBIC  string `json:"counterparty_bic"`
+	Amount           string `json:"amount"`
+	Currency         string `json:"currency"`
+	Description      string `json:"description"`
+}
+
+func transactionsOutput(transactions []core.Transaction) output {
+	out := output{header: []string{
+		"id", "bulk_transfer_id", "kind", "counterparty_name", "counterparty_iban", "counterparty_bic", "amount", "currency", "description",
+	}}
+
+	views := make([]transactionView, 0, len(transactions))
+	for _, transaction := range transactions {
+		view := transactionView{
+			ID:               transaction.ID,
+			BulkTransferID:   transaction.BulkTransferID,
+			Kind:             string(transaction.Kind),
+			CounterpartyName: transaction.CounterpartyName,
+			CounterpartyIBAN: transaction.CounterpartyIBAN,
+			CounterpartyBIC:  transaction.CounterpartyBIC,
+			Amount:           http.FormatCents(transaction.AmountCents),
+			Currency:         transaction.Currency,
+			Description:      transaction.Description,
+		}
+		views = append(views, view)
+		out.rows = append(out.rows, []string{
+			strconv.FormatInt(view.ID, 10), strconv.FormatInt(view.BulkTransferID, 10), view.Kind, view.CounterpartyName,
+			view.CounterpartyIBAN, view.CounterpartyBIC, view.Amount, view.Currency, view.Description,
+		})
+	}
+	out.value = views
+
+	return out
+}
+
+type discrepancyView struct {
+	AccountID       int64  `json:"account_id"`
+	IBAN            string `json:"iban"`
+	BIC             string `json:"bic"`
+	OpeningBalance  string `json:"opening_balance"`
+	Transactions    string `json:"transactions"`
+	Balance         string `json:"balance"`
+	ExpectedBalance string `json:"expected_balance"`
+	Discrepancy     string `json:"discrepancy"`
+}
+
+func discrepanciesOutput(checks []core.BalanceCheck) output {
+	out := output{header: []string{
+		"account_id", "iban", "bic", "opening_balance", "transactions", "balance", "expected_balance", "discrepancy",
+	}}
+
+	views := make([]discrepancyView, 0, len(checks))
+	for _, check := range checks {
+		view := discrepancyView{
+			AccountID:       check.AccountID,
+			IBAN:            check.IBAN,
+			BIC:             check.BIC,
+			OpeningBalance:  http.FormatCents(check.OpeningBalanceCents),
+			Transactions:    http.FormatCents(check.TransactionsCents),
+			Balance:         http.FormatCents(check.BalanceCents),
+			ExpectedBalance: http.FormatCents(check.ExpectedBalanceCents()),
+			Discrepancy:     http.FormatCents(check.DiscrepancyCents()),
+		}
+		views = append(views, view)
+		out.rows = append(out.rows, []string{
+			strconv.FormatInt(view.AccountID, 10), view.IBAN, view.BIC, view.OpeningBalance,
+			view.Transactions, view.Balance, view.ExpectedBalance, view.Discrepancy,
+		})
+	}
+	out.value = views
+
+	return out
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"payment/config"
+	"payment/internal/core"
+	"payment/internal/sqlite"
+)
+
+// verify-audit walks the audit log and exits non-zero at the first record
+// whose link or hash does not match. Publishing the reported head hash
+// elsewhere also makes truncation of the log detectable.
+func main() {
+	if err := run(context.Background()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	dbClient, err := sqlite.NewClient(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to create db client: %w", err)
+	}
+	defer dbClient.Close()
+
+	accountStore := sqlite.NewAccountStore(dbClient.DB())
+
+	var verifier core.AuditChainVerifier
+	if err = accountStore.ForEachAuditRecord(ctx, verifier.Verify); err != nil {
+		return fmt.Errorf("audit log verification failed after %d records: %w", verifier.Count(), err)
+	}
+
+	fmt.Printf("audit log OK: %d records, head hash %q\n", verifier.Count(), verifier.LastHash())
+	return nil
+}
@@ -5,14 +5,34 @@ import (
 
 	"github.com/kelseyhightower/envconfig"
 
+	"payment/internal/admission"
+	"payment/internal/fraudrules"
+	"payment/internal/grpc"
 	"payment/internal/http"
+	"payment/internal/pricing"
+	"payment/internal/ratelimit"
+	"payment/internal/reconciliation"
+	"payment/internal/sanctions"
 	"payment/internal/sqlite"
+	"payment/internal/telemetry"
 )
 
 type Config struct {
 	LogLevel int `envconfig:"LOG_LEVEL" default:"-4"`
-	Database sqlite.Config
-	HTTP     http.Config
+	// Repository selects where accounts are stored: "sqlite", or "memory" for
+	// development, where everything is lost on shutdown.
+	Repository string `envconfig:"ACCOUNT_REPOSITORY" default:"sqlite"`
+	Database   sqlite.Config
+	HTTP       http.Config
+	GRPC       grpc.Config
+	RateLimit  ratelimit.Config
+	Admission  admission.Config
+	Sanctions  sanctions.Config
+	FraudRules fraudrules.Config
+	Pricing    pricing.Config
+	Tracing    telemetry.Config
+
+	Reconciliation reconciliation.Config
 }
 
 func Load() (Config, error) {
@@ -0,0 +1,96 @@
+package admission
+
+import (
+	"context"
+	"errors"
+
+	"payment/internal/core"
+	"payment/internal/metrics"
+	"payment/internal/ratelimit"
+)
+
+//go:generate go tool go.uber.org/mock/mockgen -source=admission.go -destination=limiter_mock.go -package=admission
+
+type QuotaLimiter interface {
+	ReserveQuota(ctx context.Context, key string, transfers int) (ratelimit.Decision, ratelimit.Reservation, error)
+	ReleaseQuota(ctx context.Context, reservation ratelimit.Reservation, transfers int) error
+}
+
+type Logger interface {
+	ErrorContext(ctx context.Context, msg string, args ...any)
+}
+
+// Admission applies the rules every bulk transfer goes through before it
+// executes, whether it came over HTTP or gRPC, streamed or not: the maximum
+// batch size and the daily transfer quota of the organization. It also
+// records the bulk transfer metrics.
+type Admission struct {
+	quotaLimiter QuotaLimiter
+	logger       Logger
+	config       Config
+}
+
+var _ core.Admission = Admission{}
+
+func New(quotaLimiter QuotaLimiter, logger Logger, config Config) Admission {
+	return Admission{
+		quotaLimiter: quotaLimiter,
+		logger:       logger,
+		config:       config,
+	}
+}
+
+// Admit reserves the transfers of bulkTransfer from the daily quota of its
+// organization. The returned function gives back the transfers that did not
+// execute, so that only accepted transfers count. A held bulk transfer is
+// admitted again when it is released. Limiter failures are logged
+// and the bulk transfer is let through.
+func (a Admission) Admit(ctx context.Context, bulkTransfer core.BulkTransfer) (func(core.BulkTransferResult, error), error) {
+	size := len(bulkTransfer.Transfers)
+	if a.config.MaxBatchSize > 0 && size > a.config.MaxBatchSize {
+		return nil, &core.BatchSizeError{Size: size, MaxSize: a.config.MaxBatchSize}
+	}
+
+	decision, reservation, err := a.quotaLimiter.ReserveQuota(ctx, "org:"+bulkTransfer.OrganizationIBAN, size)
+	switch {
+	case err != nil:
+		a.logger.ErrorContext(ctx, "Failed to reserve transfer quota", "error", err)
+	case !decision.Allowed:
+		return nil, &core.QuotaError{RetryAfter: decision.RetryAfter}
+	}
+
+	metrics.BulkTransferSize.Observe(float64(size))
+	metrics.BulkTransferAmount.Observe(float64(bulkTransfer.TotalAmount()))
+
+	return func(result core.BulkTransferResult, err error) {
+		metrics.BulkTransferOutcomes.WithLabelValues(outcome(err)).Inc()
+
+		if err := a.quotaLimiter.ReleaseQuota(ctx, reservation, size-result.AcceptedCount()); err != nil {
+			a.logger.ErrorContext(ctx, "Failed to release transfer quota", "error", err)
+		}
+	}, nil
+}
+
+func outcome(err error) string {
+	var sanctionsErr *core.SanctionsError
+	var fraudErr *core.FraudError
+
+	switch {
+	case err == nil:
+		return metrics.OutcomeAccepted
+	case errors.Is(err, core.ErrAccountNotFound), errors.Is(err, core.ErrHoldNotFound):
+		return metrics.OutcomeNotFound
+	case errors.Is(err, core.ErrInsufficientFunds):
+		return metrics.OutcomeInsufficientFunds
+	case errors.Is(err, core.ErrAccountFrozen):
+		return metrics.OutcomeAccountFrozen
+	case errors.Is(err, core.ErrBulkTransferHeld):
+		return metrics.OutcomeHeld
+	case errors.As(err, &sanctionsErr):
+		return metrics.OutcomeSanctions
+	case errors.As(err, &fraudErr):
+		return metrics.OutcomeFraud
+	default:
+		return metrics.OutcomeInternal
+	}
+}
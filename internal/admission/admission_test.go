@@ -0,0 +1,133 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"payment/internal/core"
+	"payment/internal/metrics"
+	"payment/internal/ratelimit"
+)
+
+// The tests share the global metrics and do not run in parallel.
+
+func TestAdmission_Admit(t *testing.T) {
+	bulkTransfer := core.BulkTransfer{
+		OrganizationIBAN: "TESTIBAN",
+		Transfers: []core.Transfer{
+			{AmountCents: 1000},
+			{AmountCents: 500},
+		},
+	}
+	partialResult := core.BulkTransferResult{Transfers: []core.TransferResult{
+		{Index: 0, Status: core.TransferAccepted},
+		{Index: 1, Status: core.TransferRejected, Reason: core.RejectionInsufficientFunds},
+	}}
+
+	tests := []struct {
+		name            string
+		maxBatchSize    int
+		setupLimiter    func(mock *MockQuotaLimiter)
+		result          core.BulkTransferResult
+		err             error
+		expectedErr     error
+		expectedOutcome string
+	}{
+		{
+			name:         "batch_too_large",
+			maxBatchSize: 1,
+			setupLimiter: func(*MockQuotaLimiter) {},
+			expectedErr:  &core.BatchSizeError{Size: 2, MaxSize: 1},
+		},
+		{
+			name:         "quota_exceeded",
+			maxBatchSize: 2,
+			setupLimiter: func(mock *MockQuotaLimiter) {
+				mock.EXPECT().
+					ReserveQuota(gomock.Any(), "org:TESTIBAN", 2).
+					Return(ratelimit.Decision{RetryAfter: time.Hour, Reason: ratelimit.ReasonQuotaExceeded}, ratelimit.Reservation{}, nil)
+			},
+			expectedErr: &core.QuotaError{RetryAfter: time.Hour},
+		},
+		{
+			name: "quota_of_rejected_transfers_is_given_back",
+			setupLimiter: func(mock *MockQuotaLimiter) {
+				reservation := ratelimit.Reservation{Key: "org:TESTIBAN", Transfers: 2}
+				mock.EXPECT().
+					ReserveQuota(gomock.Any(), "org:TESTIBAN", 2).
+					Return(ratelimit.Decision{Allowed: true}, reservation, nil)
+				mock.EXPECT().ReleaseQuota(gomock.Any(), reservation, 1).Return(nil)
+			},
+			result:          partialResult,
+			expectedOutcome: metrics.OutcomeAccepted,
+		},
+		{
+			name: "quota_of_failed_bulk_transfer_is_given_back",
+			setupLimiter: func(mock *MockQuotaLimiter) {
+				reservation := ratelimit.Reservation{Key: "org:TESTIBAN", Transfers: 2}
+				mock.EXPECT().
+					ReserveQuota(gomock.Any(), "org:TESTIBAN", 2).
+					Return(ratelimit.Decision{Allowed: true}, reservation, nil)
+				mock.EXPECT().ReleaseQuota(gomock.Any(), reservation, 2).Return(nil)
+			},
+			err:             core.ErrInsufficientFunds,
+			expectedOutcome: metrics.OutcomeInsufficientFunds,
+		},
+		{
+			name: "quota_of_held_bulk_transfer_is_given_back",
+			setupLimiter: func(mock *MockQuotaLimiter) {
+				reservation := ratelimit.Reservation{Key: "org:TESTIBAN", Transfers: 2}
+				mock.EXPECT().
+					ReserveQuota(gomock.Any(), "org:TESTIBAN", 2).
+					Return(ratelimit.Decision{Allowed: true}, reservation, nil)
+				mock.EXPECT().ReleaseQuota(gomock.Any(), reservation, 2).Return(nil)
+			},
+			err:             &core.HeldError{HeldBulkTransferID: 7, Review: core.ReviewKindSanctions, Reason: &core.SanctionsError{Decision: core.DecisionReview}},
+			expectedOutcome: metrics.OutcomeHeld,
+		},
+		{
+			name: "limiter_error_lets_bulk_transfer_through",
+			setupLimiter: func(mock *MockQuotaLimiter) {
+				mock.EXPECT().
+					ReserveQuota(gomock.Any(), "org:TESTIBAN", 2).
+					Return(ratelimit.Decision{}, ratelimit.Reservation{}, errors.New("store unavailable"))
+				mock.EXPECT().ReleaseQuota(gomock.Any(), ratelimit.Reservation{}, 1).Return(nil)
+			},
+			result:          partialResult,
+			expectedOutcome: metrics.OutcomeAccepted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			limiter := NewMockQuotaLimiter(ctrl)
+			tt.setupLimiter(limiter)
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			admission := New(limiter, logger, Config{MaxBatchSize: tt.maxBatchSize})
+
+			done, err := admission.Admit(context.Background(), bulkTransfer)
+			if tt.expectedErr != nil {
+				require.Equal(t, tt.expectedErr, err)
+				require.Nil(t, done)
+				return
+			}
+			require.NoError(t, err)
+
+			outcomes := testutil.ToFloat64(metrics.BulkTransferOutcomes.WithLabelValues(tt.expectedOutcome))
+			done(tt.result, tt.err)
+			require.Equal(t, outcomes+1, testutil.ToFloat64(metrics.BulkTransferOutcomes.WithLabelValues(tt.expectedOutcome)))
+		})
+	}
+}
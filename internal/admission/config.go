@@ -0,0 +1,5 @@
+package admission
+
+type Config struct {
+	MaxBatchSize int `envconfig:"MAX_BATCH_SIZE" default:"50000"` // Maximum credit transfers per bulk transfer, whatever the transport, 0 for no limit
+}
@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: admission.go
+//
+// Generated by this command:
+//
+//	mockgen -source=admission.go -destination=limiter_mock.go -package=admission
+//
+
+// Package admission is a generated GoMock package.
+package admission
+
+import (
+	context "context"
+	ratelimit "payment/internal/ratelimit"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockQuotaLimiter is a mock of QuotaLimiter interface.
+type MockQuotaLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuotaLimiterMockRecorder
+	isgomock struct{}
+}
+
+// MockQuotaLimiterMockRecorder is the mock recorder for MockQuotaLimiter.
+type MockQuotaLimiterMockRecorder struct {
+	mock *MockQuotaLimiter
+}
+
+// NewMockQuotaLimiter creates a new mock instance.
+func NewMockQuotaLimiter(ctrl *gomock.Controller) *MockQuotaLimiter {
+	mock := &MockQuotaLimiter{ctrl: ctrl}
+	mock.recorder = &MockQuotaLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuotaLimiter) EXPECT() *MockQuotaLimiterMockRecorder {
+	return m.recorder
+}
+
+// ReleaseQuota mocks base method.
+func (m *MockQuotaLimiter) ReleaseQuota(ctx context.Context, reservation ratelimit.Reservation, transfers int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseQuota", ctx, reservation, transfers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseQuota indicates an expected call of ReleaseQuota.
+func (mr *MockQuotaLimiterMockRecorder) ReleaseQuota(ctx, reservation, transfers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseQuota", reflect.TypeOf((*MockQuotaLimiter)(nil).ReleaseQuota), ctx, reservation, transfers)
+}
+
+// ReserveQuota mocks base method.
+func (m *MockQuotaLimiter) ReserveQuota(ctx context.Context, key string, transfers int) (ratelimit.Decision, ratelimit.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveQuota", ctx, key, transfers)
+	ret0, _ := ret[0].(ratelimit.Decision)
+	ret1, _ := ret[1].(ratelimit.Reservation)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReserveQuota indicates an expected call of ReserveQuota.
+func (mr *MockQuotaLimiterMockRecorder) ReserveQuota(ctx, key, transfers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveQuota", reflect.TypeOf((*MockQuotaLimiter)(nil).ReserveQuota), ctx, key, transfers)
+}
+
+// MockLogger is a mock of Logger interface.
+type MockLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoggerMockRecorder
+	isgomock struct{}
+}
+
+// MockLoggerMockRecorder is the mock recorder for MockLogger.
+type MockLoggerMockRecorder struct {
+	mock *MockLogger
+}
+
+// NewMockLogger creates a new mock instance.
+func NewMockLogger(ctrl *gomock.Controller) *MockLogger {
+	mock := &MockLogger{ctrl: ctrl}
+	mock.recorder = &MockLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogger) EXPECT() *MockLoggerMockRecorder {
+	return m.recorder
+}
+
+// ErrorContext mocks base method.
+func (m *MockLogger) ErrorContext(ctx context.Context, msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "ErrorContext", varargs...)
+}
+
+// ErrorContext indicates an expected call of ErrorContext.
+func (mr *MockLoggerMockRecorder) ErrorContext(ctx, msg any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, msg}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ErrorContext", reflect.TypeOf((*MockLogger)(nil).ErrorContext), varargs...)
+}
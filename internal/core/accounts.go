@@ -0,0 +1,192 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// CreateAccount opens an account with the balance of account.
+func (s Service) CreateAccount(ctx context.Context, account Account) (Account, error) {
+	if account.BalanceCents < 0 {
+		return Account{}, errors.New("opening balance cannot be negative")
+	}
+
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		var err error
+		account.ID, err = r.CreateAccount(ctx, account)
+		if err != nil {
+			return err
+		}
+
+		created := NewAuditRecord(ctx, AuditEventAccountCreated, account, 0)
+		created.AmountCents = account.BalanceCents
+		return r.AppendAuditRecord(ctx, created)
+	})
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to create account: %w", err)
+	}
+
+	return account, nil
+}
+
+func (s Service) ListAccounts(ctx context.Context) ([]Account, error) {
+	var accounts []Account
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		var err error
+		accounts, err = r.ListAccounts(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+func (s Service) GetAccount(ctx context.Context, iban string, bic string) (Account, error) {
+	var account Account
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		var err error
+		account, err = r.GetAccountByID(ctx, iban, bic)
+		return err
+	})
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	return account, nil
+}
+
+// SetAccountFrozen freezes or unfreezes an account. reason is kept in the
+// audit log; nothing is recorded when the account is already in that state.
+func (s Service) SetAccountFrozen(ctx context.Context, iban string, bic string, frozen bool, reason string) (Account, error) {
+	var account Account
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		var err error
+		account, err = r.GetAccountByID(ctx, iban, bic)
+		if err != nil || account.Frozen == frozen {
+			return err
+		}
+
+		if err = r.SetAccountFrozen(ctx, account.ID, frozen); err != nil {
+			return err
+		}
+		account.Frozen = frozen
+
+		event := AuditEventAccountUnfrozen
+		if frozen {
+			event = AuditEventAccountFrozen
+		}
+
+		record := NewAuditRecord(ctx, event, account, account.BalanceCents)
+		record.Reason = reason
+		return r.AppendAuditRecord(ctx, record)
+	})
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to update account: %w", err)
+	}
+
+	return account, nil
+}
+
+// SetOverdraftLimit sets how far below zero debits may take the balance of
+// an account. Lowering the limit under the overdraft in use only prevents
+// further debits.
+func (s Service) SetOverdraftLimit(ctx context.Context, iban string, bic string, limitCents int64, reason string) (Account, error) {
+	if limitCents < 0 {
+		return Account{}, errors.New("overdraft limit cannot be negative")
+	}
+
+	var account Account
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		var err error
+		account, err = r.GetAccountByID(ctx, iban, bic)
+		if err != nil || account.OverdraftLimitCents == limitCents {
+			return err
+		}
+
+		if err = r.SetOverdraftLimit(ctx, account.ID, limitCents); err != nil {
+			return err
+		}
+		account.OverdraftLimitCents = limitCents
+
+		record := NewAuditRecord(ctx, AuditEventOverdraftLimitSet, account, account.BalanceCents)
+		record.AmountCents = limitCents
+		record.Reason = reason
+		return r.AppendAuditRecord(ctx, record)
+	})
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to set overdraft limit: %w", err)
+	}
+
+	return account, nil
+}
+
+// CreditAccount adds amountCents to the balance of an account.
+func (s Service) CreditAccount(ctx context.Context, iban string, bic string, amountCents int64) (Account, error) {
+	if amountCents <= 0 {
+		return Account{}, errors.New("credit amount must be positive")
+	}
+
+	var account Account
+	err := s.atomic(ctx, func(r AccountRepository) error {
+		var err error
+		account, err = r.GetAccountByID(ctx, iban, bic)
+		if err != nil {
+			return err
+		}
+
+		balanceBefore := account.BalanceCents
+		account.Credit(amountCents)
+		if err = r.UpdateBalance(ctx, account); err != nil {
+			return err
+		}
+		account.Version++
+
+		// The credit is booked like any other movement so that the balance
+		// still reconciles with the ledger.
+		err = r.AddTransaction(ctx, Transaction{
+			AccountID:        account.ID,
+			Kind:             TransactionKindCredit,
+			CounterpartyName: account.OrganizationName,
+			CounterpartyIBAN: account.IBAN,
+			CounterpartyBIC:  account.BIC,
+			AmountCents:      amountCents,
+			Currency:         account.Currency,
+			Description:      "Manual credit",
+		})
+		if err != nil {
+			return err
+		}
+
+		balanceChanged := NewAuditRecord(ctx, AuditEventBalanceChanged, account, balanceBefore)
+		balanceChanged.AmountCents = amountCents
+		return r.AppendAuditRecord(ctx, balanceChanged)
+	})
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to credit account: %w", err)
+	}
+
+	return account, nil
+}
+
+// ListBulkTransfers returns the executed bulk transfers of an account, oldest
+// first.
+func (s Service) ListBulkTransfers(ctx context.Context, iban string, bic string) ([]BulkTransferRecord, error) {
+	var records []BulkTransferRecord
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		account, err := r.GetAccountByID(ctx, iban, bic)
+		if err != nil {
+			return err
+		}
+
+		records, err = r.ListBulkTransfers(ctx, account.ID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bulk transfers: %w", err)
+	}
+
+	return records, nil
+}
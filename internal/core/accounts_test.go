@@ -0,0 +1,275 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// atomicRepository returns a repository whose Atomic runs the callback
+// against a transaction repository set up by txSetup.
+func atomicRepository(ctrl *gomock.Controller, txSetup func(r *MockAccountRepository)) *MockAccountRepository {
+	mockRepo := NewMockAccountRepository(ctrl)
+	mockRepo.EXPECT().
+		Atomic(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+			txRepo := NewMockAccountRepository(ctrl)
+			txSetup(txRepo)
+			return cb(txRepo)
+		})
+
+	return mockRepo
+}
+
+func TestService_CreateAccount(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	account := Account{OrganizationName: "ACME", BalanceCents: 5000, IBAN: "IBAN", BIC: "BIC"}
+	repo := atomicRepository(ctrl, func(r *MockAccountRepository) {
+		r.EXPECT().CreateAccount(gomock.Any(), account).Return(int64(7), nil)
+		r.EXPECT().AppendAuditRecord(gomock.Any(), AuditRecord{
+			EventType:         AuditEventAccountCreated,
+			Actor:             "ops",
+			AccountID:         7,
+			BalanceAfterCents: 5000,
+			AmountCents:       5000,
+		}).Return(nil)
+	})
+
+	service := NewService(repo, NewMockScreener(ctrl), NewFraudEngine(nil), Pricing{})
+	created, err := service.CreateAccount(WithActor(context.Background(), "ops"), account)
+	require.NoError(t, err)
+
+	account.ID = 7
+	require.Equal(t, account, created)
+}
+
+func TestService_SetAccountFrozen(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		frozen        bool
+		mockSetup     func(r *MockAccountRepository)
+		expected      Account
+		expectedError error
+	}{
+		{
+			name:   "freezes_the_account",
+			frozen: true,
+			mockSetup: func(r *MockAccountRepository) {
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{ID: 1, BalanceCents: 100}, nil)
+				r.EXPECT().SetAccountFrozen(gomock.Any(), int64(1), true).Return(nil)
+				r.EXPECT().AppendAuditRecord(gomock.Any(), AuditRecord{
+					EventType:          AuditEventAccountFrozen,
+					AccountID:          1,
+					BalanceBeforeCents: 100,
+					BalanceAfterCents:  100,
+					Reason:             "chargeback investigation",
+				}).Return(nil)
+			},
+			expected: Account{ID: 1, BalanceCents: 100, Frozen: true},
+		},
+		{
+			name:   "unfreezes_the_account",
+			frozen: false,
+			mockSetup: func(r *MockAccountRepository) {
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{ID: 1, Frozen: true}, nil)
+				r.EXPECT().SetAccountFrozen(gomock.Any(), int64(1), false).Return(nil)
+				r.EXPECT().AppendAuditRecord(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expected: Account{ID: 1},
+		},
+		{
+			name:   "already_frozen_is_a_no_op",
+			frozen: true,
+			mockSetup: func(r *MockAccountRepository) {
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{ID: 1, Frozen: true}, nil)
+			},
+			expected: Account{ID: 1, Frozen: true},
+		},
+		{
+			name:   "unknown_account",
+			frozen: true,
+			mockSetup: func(r *MockAccountRepository) {
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{}, ErrAccountNotFound)
+			},
+			expectedError: ErrAccountNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewService(atomicRepository(ctrl, tt.mockSetup), NewMockScreener(ctrl), NewFraudEngine(nil), Pricing{})
+			account, err := service.SetAccountFrozen(context.Background(), "IBAN", "BIC", tt.frozen, "chargeback investigation")
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, account)
+		})
+	}
+}
+
+func TestService_CreditAccount(t *testing.T) {
+	t.Parallel()
+
+	t.Run("credits_the_balance", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := atomicRepository(ctrl, func(r *MockAccountRepository) {
+			r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{ID: 1, BalanceCents: 100, Currency: "CHF"}, nil)
+			r.EXPECT().UpdateBalance(gomock.Any(), Account{ID: 1, BalanceCents: 350, Currency: "CHF"}).Return(nil)
+			r.EXPECT().AddTransaction(gomock.Any(), Transaction{
+				AccountID:   1,
+				Kind:        TransactionKindCredit,
+				AmountCents: 250,
+				Currency:    "CHF",
+				Description: "Manual credit",
+			}).Return(nil)
+			r.EXPECT().AppendAuditRecord(gomock.Any(), AuditRecord{
+				EventType:          AuditEventBalanceChanged,
+				AccountID:          1,
+				BalanceBeforeCents: 100,
+				BalanceAfterCents:  350,
+				AmountCents:        250,
+			}).Return(nil)
+		})
+
+		service := NewService(repo, NewMockScreener(ctrl), NewFraudEngine(nil), Pricing{})
+		account, err := service.CreditAccount(context.Background(), "IBAN", "BIC", 250)
+		require.NoError(t, err)
+		require.Equal(t, Account{ID: 1, BalanceCents: 350, Currency: "CHF", Version: 1}, account)
+	})
+
+	t.Run("retries_after_a_version_conflict", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stale := NewMockAccountRepository(ctrl)
+		stale.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{ID: 1, BalanceCents: 100, Version: 4}, nil)
+		stale.EXPECT().UpdateBalance(gomock.Any(), Account{ID: 1, BalanceCents: 350, Version: 4}).
+			Return(&VersionConflictError{AccountID: 1, Version: 4})
+
+		fresh := NewMockAccountRepository(ctrl)
+		fresh.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{ID: 1, BalanceCents: 200, Version: 5}, nil)
+		fresh.EXPECT().UpdateBalance(gomock.Any(), Account{ID: 1, BalanceCents: 450, Version: 5}).Return(nil)
+		fresh.EXPECT().AddTransaction(gomock.Any(), gomock.Any()).Return(nil)
+		fresh.EXPECT().AppendAuditRecord(gomock.Any(), gomock.Any()).Return(nil)
+
+		repo := NewMockAccountRepository(ctrl)
+		gomock.InOrder(
+			repo.EXPECT().Atomic(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+				return cb(stale)
+			}),
+			repo.EXPECT().Atomic(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+				return cb(fresh)
+			}),
+		)
+
+		service := NewService(repo, NewMockScreener(ctrl), NewFraudEngine(nil), Pricing{})
+		account, err := service.CreditAccount(context.Background(), "IBAN", "BIC", 250)
+		require.NoError(t, err)
+		require.Equal(t, Account{ID: 1, BalanceCents: 450, Version: 6}, account)
+	})
+
+	t.Run("gives_up_after_repeated_conflicts", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := NewMockAccountRepository(ctrl)
+		repo.EXPECT().Atomic(gomock.Any(), gomock.Any()).
+			Return(&VersionConflictError{AccountID: 1}).
+			Times(maxConflictRetries + 1)
+
+		service := NewService(repo, NewMockScreener(ctrl), NewFraudEngine(nil), Pricing{})
+		_, err := service.CreditAccount(context.Background(), "IBAN", "BIC", 250)
+		require.ErrorIs(t, err, ErrVersionConflict)
+	})
+
+	t.Run("rejects_non_positive_amounts", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := NewService(NewMockAccountRepository(ctrl), NewMockScreener(ctrl), NewFraudEngine(nil), Pricing{})
+		_, err := service.CreditAccount(context.Background(), "IBAN", "BIC", 0)
+		require.Error(t, err)
+	})
+}
+
+func TestService_SetOverdraftLimit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sets_the_limit", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := atomicRepository(ctrl, func(r *MockAccountRepository) {
+			r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{ID: 1, BalanceCents: 100}, nil)
+			r.EXPECT().SetOverdraftLimit(gomock.Any(), int64(1), int64(50000)).Return(nil)
+			r.EXPECT().AppendAuditRecord(gomock.Any(), AuditRecord{
+				EventType:          AuditEventOverdraftLimitSet,
+				AccountID:          1,
+				BalanceBeforeCents: 100,
+				BalanceAfterCents:  100,
+				AmountCents:        50000,
+				Reason:             "credit committee",
+			}).Return(nil)
+		})
+
+		service := NewService(repo, NewMockScreener(ctrl), NewFraudEngine(nil), Pricing{})
+		account, err := service.SetOverdraftLimit(context.Background(), "IBAN", "BIC", 50000, "credit committee")
+		require.NoError(t, err)
+		require.Equal(t, Account{ID: 1, BalanceCents: 100, OverdraftLimitCents: 50000}, account)
+	})
+
+	t.Run("unchanged_limit_is_a_no_op", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := atomicRepository(ctrl, func(r *MockAccountRepository) {
+			r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{ID: 1, OverdraftLimitCents: 50000}, nil)
+		})
+
+		service := NewService(repo, NewMockScreener(ctrl), NewFraudEngine(nil), Pricing{})
+		_, err := service.SetOverdraftLimit(context.Background(), "IBAN", "BIC", 50000, "")
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects_negative_limits", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := NewService(NewMockAccountRepository(ctrl), NewMockScreener(ctrl), NewFraudEngine(nil), Pricing{})
+		_, err := service.SetOverdraftLimit(context.Background(), "IBAN", "BIC", -1, "")
+		require.Error(t, err)
+	})
+}
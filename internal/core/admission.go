@@ -0,0 +1,55 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+//go:generate go tool go.uber.org/mock/mockgen -source=admission.go -destination=admission_mock.go -package=core
+
+// Admission decides whether a bulk transfer may execute, whichever transport
+// it came through. Admit is called before screening. When it lets the batch
+// through, the returned function must be called with the outcome of the
+// execution, so that the admission can account for what actually executed.
+type Admission interface {
+	Admit(ctx context.Context, bulkTransfer BulkTransfer) (func(BulkTransferResult, error), error)
+}
+
+// BatchSizeError refuses a bulk transfer with more transfers than allowed.
+type BatchSizeError struct {
+	Size    int
+	MaxSize int
+}
+
+func (e *BatchSizeError) Error() string {
+	return fmt.Sprintf("%d transfers exceed the maximum batch size of %d transfers", e.Size, e.MaxSize)
+}
+
+func (e *BatchSizeError) Unwrap() error {
+	return ErrBatchTooLarge
+}
+
+// QuotaError refuses a bulk transfer that does not fit in the daily transfer
+// quota of its organization. RetryAfter is when the quota is renewed.
+type QuotaError struct {
+	RetryAfter time.Duration
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("daily transfer quota exceeded, renewed in %s", e.RetryAfter)
+}
+
+func (e *QuotaError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// admit runs the admission of the service, if any. The returned function is
+// never nil.
+func (s Service) admit(ctx context.Context, bulkTransfer BulkTransfer) (func(BulkTransferResult, error), error) {
+	if s.admission == nil {
+		return func(BulkTransferResult, error) {}, nil
+	}
+
+	return s.admission.Admit(ctx, bulkTransfer)
+}
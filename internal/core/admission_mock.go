@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: admission.go
+//
+// Generated by this command:
+//
+//	mockgen -source=admission.go -destination=admission_mock.go -package=core
+//
+
+// Package core is a generated GoMock package.
+package core
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAdmission is a mock of Admission interface.
+type MockAdmission struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdmissionMockRecorder
+	isgomock struct{}
+}
+
+// MockAdmissionMockRecorder is the mock recorder for MockAdmission.
+type MockAdmissionMockRecorder struct {
+	mock *MockAdmission
+}
+
+// NewMockAdmission creates a new mock instance.
+func NewMockAdmission(ctrl *gomock.Controller) *MockAdmission {
+	mock := &MockAdmission{ctrl: ctrl}
+	mock.recorder = &MockAdmissionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdmission) EXPECT() *MockAdmissionMockRecorder {
+	return m.recorder
+}
+
+// Admit mocks base method.
+func (m *MockAdmission) Admit(ctx context.Context, bulkTransfer BulkTransfer) (func(BulkTransferResult, error), error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Admit", ctx, bulkTransfer)
+	ret0, _ := ret[0].(func(BulkTransferResult, error))
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Admit indicates an expected call of Admit.
+func (mr *MockAdmissionMockRecorder) Admit(ctx, bulkTransfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Admit", reflect.TypeOf((*MockAdmission)(nil).Admit), ctx, bulkTransfer)
+}
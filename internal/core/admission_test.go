@@ -0,0 +1,75 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_ProcessBulkTransfer_Admission(t *testing.T) {
+	t.Parallel()
+
+	bulkTransfer := BulkTransfer{
+		OrganizationBIC:  "OIVUSCLQXXX",
+		OrganizationIBAN: "FR10474608000002006107XXXXX",
+		Transfers: []Transfer{
+			{CounterpartyName: "Bip Bip", CounterpartyIBAN: "EE383680981021245685", AmountCents: 1450, Currency: "EUR"},
+		},
+	}
+	dbErr := errors.New("database is locked")
+
+	tests := []struct {
+		name          string
+		mockSetup     func(m *MockAccountRepository, a *MockAdmission)
+		expectedError error
+	}{
+		{
+			name: "refused_bulk_transfer_is_not_executed",
+			mockSetup: func(_ *MockAccountRepository, a *MockAdmission) {
+				a.EXPECT().Admit(gomock.Any(), gomock.Any()).Return(nil, &QuotaError{RetryAfter: time.Hour})
+			},
+			expectedError: ErrQuotaExceeded,
+		},
+		{
+			name: "admission_is_told_the_outcome",
+			mockSetup: func(m *MockAccountRepository, a *MockAdmission) {
+				a.EXPECT().
+					Admit(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, admitted BulkTransfer) (func(BulkTransferResult, error), error) {
+						require.Equal(t, ExecutionModeAllOrNothing, admitted.ExecutionMode)
+						require.Len(t, admitted.Transfers, 1)
+						return func(result BulkTransferResult, err error) {
+							require.Zero(t, result)
+							require.ErrorIs(t, err, dbErr)
+						}, nil
+					})
+				m.EXPECT().Atomic(gomock.Any(), gomock.Any()).Return(dbErr)
+			},
+			expectedError: dbErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := NewMockAccountRepository(ctrl)
+			mockAdmission := NewMockAdmission(ctrl)
+			tt.mockSetup(mockRepo, mockAdmission)
+			mockScreener := NewMockScreener(ctrl)
+			mockScreener.EXPECT().Screen(gomock.Any(), gomock.Any()).Return(ScreeningResult{Decision: DecisionAllow}, nil).AnyTimes()
+
+			service := NewService(mockRepo, mockScreener, NewFraudEngine(nil), Pricing{}).WithAdmission(mockAdmission)
+
+			_, err := service.ProcessBulkTransfer(context.Background(), bulkTransfer)
+			require.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type AuditEventType string
+
+const (
+	AuditEventBulkTransferAccepted AuditEventType = "bulk_transfer_accepted"
+	AuditEventBulkTransferRejected AuditEventType = "bulk_transfer_rejected"
+	AuditEventBulkTransferHeld     AuditEventType = "bulk_transfer_held"
+	AuditEventBulkTransferReleased AuditEventType = "bulk_transfer_released"
+	AuditEventBalanceChanged       AuditEventType = "balance_changed"
+	AuditEventAccountCreated       AuditEventType = "account_created"
+	AuditEventAccountFrozen        AuditEventType = "account_frozen"
+	AuditEventAccountUnfrozen      AuditEventType = "account_unfrozen"
+	AuditEventHoldPlaced           AuditEventType = "hold_placed"
+	AuditEventHoldCaptured         AuditEventType = "hold_captured"
+	AuditEventHoldReleased         AuditEventType = "hold_released"
+	AuditEventOverdraftLimitSet    AuditEventType = "overdraft_limit_set"
+	AuditEventPricingPlanSet       AuditEventType = "pricing_plan_set"
+	// AuditEventDryRunScreeningHit records a dry run whose counterparties
+	// matched the sanctions list. Nothing was executed.
+	AuditEventDryRunScreeningHit AuditEventType = "dry_run_screening_hit"
+	// AuditEventOverdraftEntered warns that a debit took the balance below
+	// zero, into the overdraft of the account.
+	AuditEventOverdraftEntered AuditEventType = "overdraft_entered"
+)
+
+// AuditRecord is an entry of the append-only audit log. Records are chained:
+// each one stores the hash of its predecessor, so editing, inserting or
+// removing a record breaks every hash after it.
+type AuditRecord struct {
+	ID                 int64
+	EventType          AuditEventType
+	Actor              string
+	RequestID          string
+	AccountID          int64
+	BalanceBeforeCents int64
+	BalanceAfterCents  int64
+	AmountCents        int64
+	TransferCount      int
+	Reason             string
+	CreatedAt          time.Time
+	PrevHash           string
+	Hash               string
+}
+
+func NewAuditRecord(ctx context.Context, eventType AuditEventType, account Account, balanceBefore int64) AuditRecord {
+	return AuditRecord{
+		EventType:          eventType,
+		Actor:              ActorFromContext(ctx),
+		RequestID:          RequestIDFromContext(ctx),
+		AccountID:          account.ID,
+		BalanceBeforeCents: balanceBefore,
+		BalanceAfterCents:  account.BalanceCents,
+	}
+}
+
+// Seal links the record to the previous one in the chain and computes its hash.
+func (r AuditRecord) Seal(prevHash string) AuditRecord {
+	r.PrevHash = prevHash
+	r.Hash = r.ComputeHash()
+	return r
+}
+
+func (r AuditRecord) ComputeHash() string {
+	// Struct fields marshal in declaration order, which keeps the payload stable.
+	payload, _ := json.Marshal(struct {
+		EventType          AuditEventType `json:"event_type"`
+		Actor              string         `json:"actor"`
+		RequestID          string         `json:"request_id"`
+		AccountID          int64          `json:"account_id"`
+		BalanceBeforeCents int64          `json:"balance_before_cents"`
+		BalanceAfterCents  int64          `json:"balance_after_cents"`
+		AmountCents        int64          `json:"amount_cents"`
+		TransferCount      int            `json:"transfer_count"`
+		Reason             string         `json:"reason"`
+		CreatedAt          string         `json:"created_at"`
+		PrevHash           string         `json:"prev_hash"`
+	}{
+		EventType:          r.EventType,
+		Actor:              r.Actor,
+		RequestID:          r.RequestID,
+		AccountID:          r.AccountID,
+		BalanceBeforeCents: r.BalanceBeforeCents,
+		BalanceAfterCents:  r.BalanceAfterCents,
+		AmountCents:        r.AmountCents,
+		TransferCount:      r.TransferCount,
+		Reason:             r.Reason,
+		CreatedAt:          r.CreatedAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:           r.PrevHash,
+	})
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditChainVerifier checks records one at a time, in insertion order.
+type AuditChainVerifier struct {
+	lastHash string
+	count    int
+}
+
+func (v *AuditChainVerifier) Verify(record AuditRecord) error {
+	if record.PrevHash != v.lastHash {
+		return fmt.Errorf("%w: record %d links to %q, expected %q", ErrAuditChainBroken, record.ID, record.PrevHash, v.lastHash)
+	}
+
+	if hash := record.ComputeHash(); hash != record.Hash {
+		return fmt.Errorf("%w: record %d has hash %q, computed %q", ErrAuditChainBroken, record.ID, record.Hash, hash)
+	}
+
+	v.lastHash = record.Hash
+	v.count++
+
+	return nil
+}
+
+func (v *AuditChainVerifier) Count() int {
+	return v.count
+}
+
+func (v *AuditChainVerifier) LastHash() string {
+	return v.lastHash
+}
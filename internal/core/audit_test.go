@@ -0,0 +1,102 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAuditRecord(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithActor(context.Background(), "org:FR10474608000002006107XXXXX")
+	ctx = WithRequestID(ctx, "req-42")
+
+	record := NewAuditRecord(ctx, AuditEventBalanceChanged, Account{ID: 7, BalanceCents: 500}, 1500)
+
+	require.Equal(t, AuditRecord{
+		EventType:          AuditEventBalanceChanged,
+		Actor:              "org:FR10474608000002006107XXXXX",
+		RequestID:          "req-42",
+		AccountID:          7,
+		BalanceBeforeCents: 1500,
+		BalanceAfterCents:  500,
+	}, record)
+}
+
+func TestAuditChainVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2025, 9, 30, 12, 0, 0, 0, time.UTC)
+
+	buildChain := func() []AuditRecord {
+		first := AuditRecord{ID: 1, EventType: AuditEventBalanceChanged, AccountID: 1, BalanceBeforeCents: 1000, BalanceAfterCents: 500, CreatedAt: createdAt}.Seal("")
+		second := AuditRecord{ID: 2, EventType: AuditEventBulkTransferAccepted, AccountID: 1, AmountCents: 500, CreatedAt: createdAt}.Seal(first.Hash)
+		third := AuditRecord{ID: 3, EventType: AuditEventBulkTransferRejected, AccountID: 1, Reason: "insufficient funds", CreatedAt: createdAt}.Seal(second.Hash)
+		return []AuditRecord{first, second, third}
+	}
+
+	tests := []struct {
+		name          string
+		tamper        func(records []AuditRecord) []AuditRecord
+		expectedCount int
+		expectedError error
+	}{
+		{
+			name:          "intact_chain",
+			tamper:        func(records []AuditRecord) []AuditRecord { return records },
+			expectedCount: 3,
+		},
+		{
+			name: "edited_balance",
+			tamper: func(records []AuditRecord) []AuditRecord {
+				records[1].BalanceAfterCents = 999999
+				return records
+			},
+			expectedCount: 1,
+			expectedError: ErrAuditChainBroken,
+		},
+		{
+			name: "deleted_record",
+			tamper: func(records []AuditRecord) []AuditRecord {
+				return append(records[:1], records[2:]...)
+			},
+			expectedCount: 1,
+			expectedError: ErrAuditChainBroken,
+		},
+		{
+			name: "edited_and_resealed_record",
+			tamper: func(records []AuditRecord) []AuditRecord {
+				records[0].Reason = "rewritten"
+				records[0] = records[0].Seal("")
+				return records
+			},
+			expectedCount: 1,
+			expectedError: ErrAuditChainBroken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var verifier AuditChainVerifier
+
+			var err error
+			for _, record := range tt.tamper(buildChain()) {
+				if err = verifier.Verify(record); err != nil {
+					break
+				}
+			}
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.expectedCount, verifier.Count())
+		})
+	}
+}
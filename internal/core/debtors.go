@@ -0,0 +1,131 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"slices"
+)
+
+// AccountKey identifies an account by its IBAN and BIC.
+type AccountKey struct {
+	IBAN string
+	BIC  string
+}
+
+func compareAccountKeys(a, b AccountKey) int {
+	return cmp.Or(cmp.Compare(a.IBAN, b.IBAN), cmp.Compare(a.BIC, b.BIC))
+}
+
+// OrganizationKey identifies the account of the organization that submits
+// the bulk transfer.
+func (bt BulkTransfer) OrganizationKey() AccountKey {
+	return AccountKey{IBAN: bt.OrganizationIBAN, BIC: bt.OrganizationBIC}
+}
+
+// DebtorKey identifies the account transfer is paid from: its own debtor
+// account, or the organization account when it names none.
+func (bt BulkTransfer) DebtorKey(transfer Transfer) AccountKey {
+	if transfer.DebtorIBAN == "" {
+		return bt.OrganizationKey()
+	}
+
+	return AccountKey{IBAN: transfer.DebtorIBAN, BIC: transfer.DebtorBIC}
+}
+
+// AccountKeys returns the organization account and every debtor account of
+// the bulk transfer once, in the order they are locked.
+func (bt BulkTransfer) AccountKeys() []AccountKey {
+	keys := []AccountKey{bt.OrganizationKey()}
+	for _, transfer := range bt.Transfers {
+		keys = append(keys, bt.DebtorKey(transfer))
+	}
+
+	slices.SortFunc(keys, compareAccountKeys)
+	return slices.Compact(keys)
+}
+
+// debtorAccounts are the accounts involved in a bulk transfer, sorted by
+// IBAN and BIC.
+type debtorAccounts struct {
+	keys     []AccountKey
+	accounts []Account
+}
+
+// lockDebtors reads the accounts of bulkTransfer. They are always read in the
+// same order so that, on databases that lock rows rather than the whole
+// database, two bulk transfers sharing accounts cannot deadlock.
+func lockDebtors(ctx context.Context, r AccountRepository, bulkTransfer BulkTransfer) (debtorAccounts, error) {
+	debtors := debtorAccounts{keys: bulkTransfer.AccountKeys()}
+	for _, key := range debtors.keys {
+		account, err := r.GetAccountByID(ctx, key.IBAN, key.BIC)
+		if err != nil {
+			return debtorAccounts{}, err
+		}
+		debtors.accounts = append(debtors.accounts, account)
+	}
+
+	// Accounts of other organizations are not disclosed.
+	owner := debtors.find(bulkTransfer.OrganizationKey())
+	for _, account := range debtors.accounts {
+		if account.ID != owner.ID && (owner.OrganizationID == "" || account.OrganizationID != owner.OrganizationID) {
+			return debtorAccounts{}, ErrAccountNotFound
+		}
+	}
+
+	return debtors, nil
+}
+
+// index returns the position of the account identified by key, or -1.
+func (d debtorAccounts) index(key AccountKey) int {
+	i, found := slices.BinarySearchFunc(d.keys, key, compareAccountKeys)
+	if !found {
+		return -1
+	}
+
+	return i
+}
+
+func (d debtorAccounts) find(key AccountKey) *Account {
+	i := d.index(key)
+	if i < 0 {
+		return nil
+	}
+
+	return &d.accounts[i]
+}
+
+func (d debtorAccounts) clone() debtorAccounts {
+	return debtorAccounts{keys: d.keys, accounts: slices.Clone(d.accounts)}
+}
+
+// debtorGroup is the part of a bulk transfer paid from one account.
+type debtorGroup struct {
+	key          AccountKey
+	account      *Account
+	bulkTransfer BulkTransfer
+	// indexes maps the transfers of bulkTransfer to their position in the
+	// whole bulk transfer.
+	indexes []int
+}
+
+// groupByDebtor splits bulkTransfer by debtor account, in lock order.
+// Accounts without transfers get no group.
+func groupByDebtor(bulkTransfer BulkTransfer, debtors debtorAccounts) []debtorGroup {
+	groups := make([]debtorGroup, len(debtors.accounts))
+	for i := range debtors.accounts {
+		groups[i].key = debtors.keys[i]
+		groups[i].account = &debtors.accounts[i]
+		groups[i].bulkTransfer = bulkTransfer
+		groups[i].bulkTransfer.Transfers = nil
+	}
+
+	for i, transfer := range bulkTransfer.Transfers {
+		group := &groups[debtors.index(bulkTransfer.DebtorKey(transfer))]
+		group.bulkTransfer.Transfers = append(group.bulkTransfer.Transfers, transfer)
+		group.indexes = append(group.indexes, i)
+	}
+
+	return slices.DeleteFunc(groups, func(group debtorGroup) bool {
+		return len(group.indexes) == 0
+	})
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkTransfer_AccountKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		transfers    []Transfer
+		expectedKeys []AccountKey
+	}{
+		{
+			name:         "organization account only",
+			transfers:    []Transfer{{AmountCents: 100}, {AmountCents: 200}},
+			expectedKeys: []AccountKey{{IBAN: "IBAN-B", BIC: "BIC"}},
+		},
+		{
+			name: "sorted and without duplicates",
+			transfers: []Transfer{
+				{AmountCents: 100, DebtorIBAN: "IBAN-C", DebtorBIC: "BIC"},
+				{AmountCents: 200, DebtorIBAN: "IBAN-A", DebtorBIC: "BIC"},
+				{AmountCents: 300, DebtorIBAN: "IBAN-C", DebtorBIC: "BIC"},
+				{AmountCents: 400, DebtorIBAN: "IBAN-B", DebtorBIC: "BIC"},
+			},
+			expectedKeys: []AccountKey{
+				{IBAN: "IBAN-A", BIC: "BIC"},
+				{IBAN: "IBAN-B", BIC: "BIC"},
+				{IBAN: "IBAN-C", BIC: "BIC"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			bulkTransfer := BulkTransfer{OrganizationIBAN: "IBAN-B", OrganizationBIC: "BIC", Transfers: tt.transfers}
+			require.Equal(t, tt.expectedKeys, bulkTransfer.AccountKeys())
+		})
+	}
+}
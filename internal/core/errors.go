@@ -2,9 +2,41 @@ package core
 
 import (
 	"errors"
+	"fmt"
 )
 
 var (
 	ErrInsufficientFunds = errors.New("insufficient funds for bulk transfer")
 	ErrAccountNotFound   = errors.New("account not found")
+	ErrAccountFrozen     = errors.New("account is frozen")
+	ErrAccountExists     = errors.New("account already exists")
+	ErrAuditChainBroken  = errors.New("audit chain broken")
+	ErrSanctionsHit      = errors.New("sanctions screening hit")
+	ErrFraudSuspected    = errors.New("fraud suspected")
+	ErrVersionConflict   = errors.New("account version conflict")
+
+	ErrBulkTransferNotFound       = errors.New("bulk transfer not found")
+	ErrStagedBulkTransferNotFound = errors.New("staged bulk transfer not found")
+	ErrHeldBulkTransferNotFound   = errors.New("held bulk transfer not found")
+	ErrBulkTransferHeld           = errors.New("bulk transfer held for review")
+	ErrHoldNotFound               = errors.New("hold not found")
+	ErrHoldNotPending             = errors.New("hold is no longer pending")
+	ErrUnknownPricingPlan         = errors.New("unknown pricing plan")
+	ErrBatchTooLarge              = errors.New("bulk transfer exceeds the maximum batch size")
+	ErrQuotaExceeded              = errors.New("daily transfer quota exceeded")
 )
+
+// VersionConflictError reports a balance update of an account that was
+// changed by another transaction since it was read.
+type VersionConflictError struct {
+	AccountID int64
+	Version   int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("account %d is no longer at version %d", e.AccountID, e.Version)
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
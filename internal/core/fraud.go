@@ -0,0 +1,199 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	FraudRuleNewCounterpartyLargeAmount = "new_counterparty_large_amount"
+	FraudRuleBatchTotalAboveAverage     = "batch_total_above_average"
+	FraudRuleRepeatedCounterparty       = "repeated_counterparty"
+)
+
+// AccountHistory is what the fraud rules know about an account's past batches.
+// A batch paid from several accounts counts for each of them, with the total
+// of the transfers that account paid.
+type AccountHistory struct {
+	BatchCount             int
+	AverageBatchTotalCents int64
+	KnownCounterparties    map[string]bool
+}
+
+type FraudInput struct {
+	BulkTransfer BulkTransfer
+	Account      Account
+	History      AccountHistory
+}
+
+type FraudAssessment struct {
+	Decision Decision
+	Reasons  []string
+}
+
+type FraudRule interface {
+	Name() string
+	// Evaluate returns DecisionAllow, or the rule's decision and why it fired.
+	Evaluate(input FraudInput) (Decision, string)
+}
+
+// FraudRuleSpec configures one of the built-in rules.
+type FraudRuleSpec struct {
+	Type                string
+	Decision            Decision
+	MinAmountCents      int64
+	Multiplier          float64
+	MinHistory          int
+	MaxTransfersPerIBAN int
+}
+
+func BuildFraudRules(specs []FraudRuleSpec) ([]FraudRule, error) {
+	rules := make([]FraudRule, 0, len(specs))
+
+	for i, spec := range specs {
+		decision := spec.Decision
+		if decision == "" {
+			decision = DecisionReview
+		}
+		if decision != DecisionReview && decision != DecisionBlock {
+			return nil, fmt.Errorf("rule %d (%s): invalid decision %q", i, spec.Type, spec.Decision)
+		}
+
+		switch spec.Type {
+		case FraudRuleNewCounterpartyLargeAmount:
+			if spec.MinAmountCents <= 0 {
+				return nil, fmt.Errorf("rule %d (%s): min amount must be positive", i, spec.Type)
+			}
+			rules = append(rules, newCounterpartyLargeAmountRule{decision: decision, minAmountCents: spec.MinAmountCents})
+		case FraudRuleBatchTotalAboveAverage:
+			if spec.Multiplier <= 1 {
+				return nil, fmt.Errorf("rule %d (%s): multiplier must be greater than 1", i, spec.Type)
+			}
+			rules = append(rules, batchTotalAboveAverageRule{decision: decision, multiplier: spec.Multiplier, minHistory: max(spec.MinHistory, 1)})
+		case FraudRuleRepeatedCounterparty:
+			if spec.MaxTransfersPerIBAN <= 0 {
+				return nil, fmt.Errorf("rule %d (%s): max transfers per IBAN must be positive", i, spec.Type)
+			}
+			rules = append(rules, repeatedCounterpartyRule{decision: decision, maxTransfersPerIBAN: spec.MaxTransfersPerIBAN})
+		default:
+			return nil, fmt.Errorf("rule %d: unknown type %q", i, spec.Type)
+		}
+	}
+
+	return rules, nil
+}
+
+// FraudEngine evaluates bulk transfers against a set of rules that can be
+// swapped at runtime. The most severe decision of all rules wins.
+type FraudEngine struct {
+	rules atomic.Pointer[[]FraudRule]
+}
+
+func NewFraudEngine(rules []FraudRule) *FraudEngine {
+	engine := &FraudEngine{}
+	engine.SetRules(rules)
+	return engine
+}
+
+func (e *FraudEngine) SetRules(rules []FraudRule) {
+	e.rules.Store(&rules)
+}
+
+func (e *FraudEngine) Rules() []FraudRule {
+	return *e.rules.Load()
+}
+
+func (e *FraudEngine) Evaluate(input FraudInput) FraudAssessment {
+	assessment := FraudAssessment{Decision: DecisionAllow}
+
+	for _, rule := range e.Rules() {
+		decision, reason := rule.Evaluate(input)
+		if decision == DecisionAllow {
+			continue
+		}
+
+		assessment.Reasons = append(assessment.Reasons, rule.Name()+": "+reason)
+		if decision == DecisionBlock || assessment.Decision == DecisionAllow {
+			assessment.Decision = decision
+		}
+	}
+
+	return assessment
+}
+
+type FraudError struct {
+	Decision Decision
+	Reasons  []string
+}
+
+func (e *FraudError) Error() string {
+	return fmt.Sprintf("fraud rules %s: %s", e.Decision, strings.Join(e.Reasons, "; "))
+}
+
+func (e *FraudError) Unwrap() error {
+	return ErrFraudSuspected
+}
+
+type newCounterpartyLargeAmountRule struct {
+	decision       Decision
+	minAmountCents int64
+}
+
+func (r newCounterpartyLargeAmountRule) Name() string {
+	return FraudRuleNewCounterpartyLargeAmount
+}
+
+func (r newCounterpartyLargeAmountRule) Evaluate(input FraudInput) (Decision, string) {
+	for i, transfer := range input.BulkTransfer.Transfers {
+		if transfer.AmountCents >= r.minAmountCents && !input.History.KnownCounterparties[transfer.CounterpartyIBAN] {
+			return r.decision, fmt.Sprintf("transfer %d sends %d cents to new counterparty %s", i, transfer.AmountCents, transfer.CounterpartyIBAN)
+		}
+	}
+
+	return DecisionAllow, ""
+}
+
+type batchTotalAboveAverageRule struct {
+	decision   Decision
+	multiplier float64
+	minHistory int
+}
+
+func (r batchTotalAboveAverageRule) Name() string {
+	return FraudRuleBatchTotalAboveAverage
+}
+
+func (r batchTotalAboveAverageRule) Evaluate(input FraudInput) (Decision, string) {
+	if input.History.BatchCount < r.minHistory {
+		return DecisionAllow, ""
+	}
+
+	total := input.BulkTransfer.TotalAmount()
+	if float64(total) > r.multiplier*float64(input.History.AverageBatchTotalCents) {
+		return r.decision, fmt.Sprintf("total of %d cents is more than %.1fx the average of %d cents", total, r.multiplier, input.History.AverageBatchTotalCents)
+	}
+
+	return DecisionAllow, ""
+}
+
+type repeatedCounterpartyRule struct {
+	decision            Decision
+	maxTransfersPerIBAN int
+}
+
+func (r repeatedCounterpartyRule) Name() string {
+	return FraudRuleRepeatedCounterparty
+}
+
+func (r repeatedCounterpartyRule) Evaluate(input FraudInput) (Decision, string) {
+	counts := make(map[string]int)
+	for _, transfer := range input.BulkTransfer.Transfers {
+		counts[transfer.CounterpartyIBAN]++
+		if counts[transfer.CounterpartyIBAN] > r.maxTransfersPerIBAN {
+			return r.decision, fmt.Sprintf("more than %d transfers to %s", r.maxTransfersPerIBAN, transfer.CounterpartyIBAN)
+		}
+	}
+
+	return DecisionAllow, ""
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFraudEngine_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	transfer := func(iban string, amount int64) Transfer {
+		return Transfer{CounterpartyIBAN: iban, AmountCents: amount}
+	}
+
+	tests := []struct {
+		name             string
+		specs            []FraudRuleSpec
+		input            FraudInput
+		expectedDecision Decision
+		expectedReasons  []string
+	}{
+		{
+			name:             "no_rules_allows",
+			input:            FraudInput{BulkTransfer: BulkTransfer{Transfers: []Transfer{transfer("IBAN1", 100)}}},
+			expectedDecision: DecisionAllow,
+		},
+		{
+			name:  "known_counterparty_large_amount_allows",
+			specs: []FraudRuleSpec{{Type: FraudRuleNewCounterpartyLargeAmount, MinAmountCents: 1000}},
+			input: FraudInput{
+				BulkTransfer: BulkTransfer{Transfers: []Transfer{transfer("IBAN1", 5000)}},
+				History:      AccountHistory{KnownCounterparties: map[string]bool{"IBAN1": true}},
+			},
+			expectedDecision: DecisionAllow,
+		},
+		{
+			name:  "new_counterparty_large_amount_defaults_to_review",
+			specs: []FraudRuleSpec{{Type: FraudRuleNewCounterpartyLargeAmount, MinAmountCents: 1000}},
+			input: FraudInput{
+				BulkTransfer: BulkTransfer{Transfers: []Transfer{transfer("IBAN1", 999), transfer("IBAN2", 1000)}},
+			},
+			expectedDecision: DecisionReview,
+			expectedReasons:  []string{"new_counterparty_large_amount: transfer 1 sends 1000 cents to new counterparty IBAN2"},
+		},
+		{
+			name:  "batch_total_above_average",
+			specs: []FraudRuleSpec{{Type: FraudRuleBatchTotalAboveAverage, Decision: DecisionBlock, Multiplier: 3, MinHistory: 2}},
+			input: FraudInput{
+				BulkTransfer: BulkTransfer{Transfers: []Transfer{transfer("IBAN1", 2000), transfer("IBAN2", 1001)}},
+				History:      AccountHistory{BatchCount: 2, AverageBatchTotalCents: 1000},
+			},
+			expectedDecision: DecisionBlock,
+			expectedReasons:  []string{"batch_total_above_average: total of 3001 cents is more than 3.0x the average of 1000 cents"},
+		},
+		{
+			name:  "batch_total_above_average_needs_history",
+			specs: []FraudRuleSpec{{Type: FraudRuleBatchTotalAboveAverage, Multiplier: 3, MinHistory: 2}},
+			input: FraudInput{
+				BulkTransfer: BulkTransfer{Transfers: []Transfer{transfer("IBAN1", 100000)}},
+				History:      AccountHistory{BatchCount: 1, AverageBatchTotalCents: 1000},
+			},
+			expectedDecision: DecisionAllow,
+		},
+		{
+			name:  "repeated_counterparty",
+			specs: []FraudRuleSpec{{Type: FraudRuleRepeatedCounterparty, MaxTransfersPerIBAN: 2}},
+			input: FraudInput{
+				BulkTransfer: BulkTransfer{Transfers: []Transfer{transfer("IBAN1", 1), transfer("IBAN2", 1), transfer("IBAN1", 1), transfer("IBAN1", 1)}},
+			},
+			expectedDecision: DecisionReview,
+			expectedReasons:  []string{"repeated_counterparty: more than 2 transfers to IBAN1"},
+		},
+		{
+			name: "block_wins_over_review",
+			specs: []FraudRuleSpec{
+				{Type: FraudRuleRepeatedCounterparty, Decision: DecisionBlock, MaxTransfersPerIBAN: 1},
+				{Type: FraudRuleNewCounterpartyLargeAmount, Decision: DecisionReview, MinAmountCents: 1},
+			},
+			input: FraudInput{
+				BulkTransfer: BulkTransfer{Transfers: []Transfer{transfer("IBAN1", 1), transfer("IBAN1", 1)}},
+			},
+			expectedDecision: DecisionBlock,
+			expectedReasons: []string{
+				"repeated_counterparty: more than 1 transfers to IBAN1",
+				"new_counterparty_large_amount: transfer 0 sends 1 cents to new counterparty IBAN1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules, err := BuildFraudRules(tt.specs)
+			require.NoError(t, err)
+
+			assessment := NewFraudEngine(rules).Evaluate(tt.input)
+			require.Equal(t, tt.expectedDecision, assessment.Decision)
+			require.Equal(t, tt.expectedReasons, assessment.Reasons)
+		})
+	}
+}
+
+func TestBuildFraudRules_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		spec FraudRuleSpec
+	}{
+		{name: "unknown_type", spec: FraudRuleSpec{Type: "velocity"}},
+		{name: "invalid_decision", spec: FraudRuleSpec{Type: FraudRuleRepeatedCounterparty, Decision: DecisionAllow, MaxTransfersPerIBAN: 1}},
+		{name: "missing_min_amount", spec: FraudRuleSpec{Type: FraudRuleNewCounterpartyLargeAmount}},
+		{name: "multiplier_not_above_one", spec: FraudRuleSpec{Type: FraudRuleBatchTotalAboveAverage, Multiplier: 1}},
+		{name: "missing_max_transfers", spec: FraudRuleSpec{Type: FraudRuleRepeatedCounterparty}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := BuildFraudRules([]FraudRuleSpec{tt.spec})
+			require.Error(t, err)
+		})
+	}
+}
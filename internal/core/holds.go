@@ -0,0 +1,176 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type HoldStatus string
+
+const (
+	HoldPending  HoldStatus = "pending"
+	HoldCaptured HoldStatus = "captured"
+	HoldReleased HoldStatus = "released"
+	// HoldExpired is never stored: a pending hold expires by itself once
+	// ExpiresAt has passed.
+	HoldExpired HoldStatus = "expired"
+)
+
+// Hold reserves funds of an account, typically for a bulk transfer waiting
+// for an approval or its execution date. Pending holds count against the
+// available balance until they are captured, released or expire.
+type Hold struct {
+	ID          int64
+	AccountID   int64
+	AmountCents int64
+	Reason      string
+	Status      HoldStatus
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// StatusAt returns the status of the hold at now, taking expiry into account.
+func (h Hold) StatusAt(now time.Time) HoldStatus {
+	if h.Status == HoldPending && !now.Before(h.ExpiresAt) {
+		return HoldExpired
+	}
+
+	return h.Status
+}
+
+// PlaceHold reserves amountCents of an account until expiresAt.
+func (s Service) PlaceHold(ctx context.Context, iban string, bic string, amountCents int64, expiresAt time.Time, reason string) (Hold, error) {
+	if amountCents <= 0 {
+		return Hold{}, errors.New("hold amount must be positive")
+	}
+
+	hold := Hold{
+		AmountCents: amountCents,
+		Reason:      reason,
+		Status:      HoldPending,
+		CreatedAt:   time.Now(),
+		ExpiresAt:   expiresAt,
+	}
+	if !hold.CreatedAt.Before(expiresAt) {
+		return Hold{}, errors.New("hold must expire in the future")
+	}
+
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		account, err := r.GetAccountByID(ctx, iban, bic)
+		if err != nil {
+			return err
+		}
+
+		if account.Frozen {
+			return ErrAccountFrozen
+		}
+		if !account.HasSufficientFunds(amountCents) {
+			return ErrInsufficientFunds
+		}
+
+		hold.AccountID = account.ID
+		hold.ID, err = r.AddHold(ctx, hold)
+		if err != nil {
+			return err
+		}
+
+		placed := NewAuditRecord(ctx, AuditEventHoldPlaced, account, account.BalanceCents)
+		placed.AmountCents = amountCents
+		placed.Reason = reason
+		return r.AppendAuditRecord(ctx, placed)
+	})
+	if err != nil {
+		return Hold{}, fmt.Errorf("failed to place hold: %w", err)
+	}
+
+	return hold, nil
+}
+
+// ReleaseHold gives the funds reserved by a pending hold of an account back
+// to its available balance.
+func (s Service) ReleaseHold(ctx context.Context, iban string, bic string, holdID int64, reason string) (Hold, error) {
+	var hold Hold
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		account, err := r.GetAccountByID(ctx, iban, bic)
+		if err != nil {
+			return err
+		}
+
+		hold, err = pendingHold(ctx, r, account, holdID)
+		if err != nil {
+			return err
+		}
+
+		if err = r.SetHoldStatus(ctx, hold.ID, HoldReleased); err != nil {
+			return err
+		}
+		hold.Status = HoldReleased
+
+		released := NewAuditRecord(ctx, AuditEventHoldReleased, account, account.BalanceCents)
+		released.AmountCents = hold.AmountCents
+		released.Reason = reason
+		return r.AppendAuditRecord(ctx, released)
+	})
+	if err != nil {
+		return Hold{}, fmt.Errorf("failed to release hold: %w", err)
+	}
+
+	return hold, nil
+}
+
+// ListHolds returns the holds of an account, oldest first.
+func (s Service) ListHolds(ctx context.Context, iban string, bic string) ([]Hold, error) {
+	var holds []Hold
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		account, err := r.GetAccountByID(ctx, iban, bic)
+		if err != nil {
+			return err
+		}
+
+		holds, err = r.ListHolds(ctx, account.ID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holds: %w", err)
+	}
+
+	return holds, nil
+}
+
+// captureHold makes the funds reserved by a pending hold of account available
+// to the bulk transfer being executed. The hold is captured whole, whatever
+// the bulk transfer actually spends.
+func captureHold(ctx context.Context, r AccountRepository, account *Account, holdID int64) error {
+	hold, err := pendingHold(ctx, r, *account, holdID)
+	if err != nil {
+		return err
+	}
+
+	if err = r.SetHoldStatus(ctx, hold.ID, HoldCaptured); err != nil {
+		return err
+	}
+	account.HeldCents -= hold.AmountCents
+
+	captured := NewAuditRecord(ctx, AuditEventHoldCaptured, *account, account.BalanceCents)
+	captured.AmountCents = hold.AmountCents
+	return r.AppendAuditRecord(ctx, captured)
+}
+
+func pendingHold(ctx context.Context, r AccountRepository, account Account, holdID int64) (Hold, error) {
+	hold, err := r.GetHold(ctx, holdID)
+	if err != nil {
+		return Hold{}, err
+	}
+
+	// Holds of other accounts are not disclosed.
+	if hold.AccountID != account.ID {
+		return Hold{}, ErrHoldNotFound
+	}
+	if hold.StatusAt(time.Now()) != HoldPending {
+		return Hold{}, ErrHoldNotPending
+	}
+
+	return hold, nil
+}
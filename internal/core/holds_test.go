@@ -0,0 +1,258 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHold_StatusAt(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	require.Equal(t, HoldPending, Hold{Status: HoldPending, ExpiresAt: now.Add(time.Minute)}.StatusAt(now))
+	require.Equal(t, HoldExpired, Hold{Status: HoldPending, ExpiresAt: now}.StatusAt(now))
+	require.Equal(t, HoldReleased, Hold{Status: HoldReleased, ExpiresAt: now.Add(-time.Minute)}.StatusAt(now))
+}
+
+func TestService_PlaceHold(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		amountCents   int64
+		mockSetup     func(r *MockAccountRepository)
+		expectedError error
+	}{
+		{
+			name:        "reserves_available_funds",
+			amountCents: 600,
+			mockSetup: func(r *MockAccountRepository) {
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{ID: 1, BalanceCents: 1000, HeldCents: 400}, nil)
+				r.EXPECT().
+					AddHold(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, hold Hold) (int64, error) {
+						require.Equal(t, int64(1), hold.AccountID)
+						require.Equal(t, int64(600), hold.AmountCents)
+						require.Equal(t, HoldPending, hold.Status)
+						require.Equal(t, expiresAt, hold.ExpiresAt)
+						return 5, nil
+					})
+				r.EXPECT().AppendAuditRecord(gomock.Any(), AuditRecord{
+					EventType:          AuditEventHoldPlaced,
+					AccountID:          1,
+					BalanceBeforeCents: 1000,
+					BalanceAfterCents:  1000,
+					AmountCents:        600,
+					Reason:             "awaiting approval",
+				}).Return(nil)
+			},
+		},
+		{
+			name:        "held_funds_are_not_available",
+			amountCents: 601,
+			mockSetup: func(r *MockAccountRepository) {
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{ID: 1, BalanceCents: 1000, HeldCents: 400}, nil)
+			},
+			expectedError: ErrInsufficientFunds,
+		},
+		{
+			name:        "frozen_account",
+			amountCents: 100,
+			mockSetup: func(r *MockAccountRepository) {
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{ID: 1, BalanceCents: 1000, Frozen: true}, nil)
+			},
+			expectedError: ErrAccountFrozen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewService(atomicRepository(ctrl, tt.mockSetup), NewMockScreener(ctrl), NewFraudEngine(nil), Pricing{})
+			hold, err := service.PlaceHold(context.Background(), "IBAN", "BIC", tt.amountCents, expiresAt, "awaiting approval")
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, int64(5), hold.ID)
+		})
+	}
+
+	t.Run("rejects_past_expiry", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := NewService(NewMockAccountRepository(ctrl), NewMockScreener(ctrl), NewFraudEngine(nil), Pricing{})
+		_, err := service.PlaceHold(context.Background(), "IBAN", "BIC", 100, time.Now().Add(-time.Second), "")
+		require.Error(t, err)
+	})
+}
+
+func TestService_ReleaseHold(t *testing.T) {
+	t.Parallel()
+
+	account := Account{ID: 1, BalanceCents: 1000, HeldCents: 300}
+	pending := Hold{ID: 5, AccountID: 1, AmountCents: 300, Status: HoldPending, ExpiresAt: time.Now().Add(time.Hour)}
+
+	tests := []struct {
+		name          string
+		hold          Hold
+		mockSetup     func(r *MockAccountRepository)
+		expectedError error
+	}{
+		{
+			name: "releases_a_pending_hold",
+			hold: pending,
+			mockSetup: func(r *MockAccountRepository) {
+				r.EXPECT().SetHoldStatus(gomock.Any(), int64(5), HoldReleased).Return(nil)
+				r.EXPECT().AppendAuditRecord(gomock.Any(), AuditRecord{
+					EventType:          AuditEventHoldReleased,
+					AccountID:          1,
+					BalanceBeforeCents: 1000,
+					BalanceAfterCents:  1000,
+					AmountCents:        300,
+					Reason:             "batch cancelled",
+				}).Return(nil)
+			},
+		},
+		{
+			name:          "hold_of_another_account",
+			hold:          Hold{ID: 5, AccountID: 2, Status: HoldPending, ExpiresAt: pending.ExpiresAt},
+			expectedError: ErrHoldNotFound,
+		},
+		{
+			name:          "expired_hold",
+			hold:          Hold{ID: 5, AccountID: 1, Status: HoldPending, ExpiresAt: time.Now().Add(-time.Hour)},
+			expectedError: ErrHoldNotPending,
+		},
+		{
+			name:          "captured_hold",
+			hold:          Hold{ID: 5, AccountID: 1, Status: HoldCaptured, ExpiresAt: pending.ExpiresAt},
+			expectedError: ErrHoldNotPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := atomicRepository(ctrl, func(r *MockAccountRepository) {
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(account, nil)
+				r.EXPECT().GetHold(gomock.Any(), int64(5)).Return(tt.hold, nil)
+				if tt.mockSetup != nil {
+					tt.mockSetup(r)
+				}
+			})
+
+			service := NewService(repo, NewMockScreener(ctrl), NewFraudEngine(nil), Pricing{})
+			hold, err := service.ReleaseHold(context.Background(), "IBAN", "BIC", 5, "batch cancelled")
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, HoldReleased, hold.Status)
+		})
+	}
+}
+
+func TestService_ProcessBulkTransfer_CapturesHold(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The whole available balance is held: only the captured hold can pay for
+	// the bulk transfer.
+	account := Account{ID: 1, BalanceCents: 1000, HeldCents: 1000}
+	hold := Hold{ID: 5, AccountID: 1, AmountCents: 800, Status: HoldPending, ExpiresAt: time.Now().Add(time.Hour)}
+
+	repo := atomicRepository(ctrl, func(r *MockAccountRepository) {
+		r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(account, nil)
+		r.EXPECT().GetHold(gomock.Any(), int64(5)).Return(hold, nil)
+		r.EXPECT().SetHoldStatus(gomock.Any(), int64(5), HoldCaptured).Return(nil)
+		r.EXPECT().GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).Return(AccountHistory{}, nil)
+		r.EXPECT().UpdateBalance(gomock.Any(), Account{ID: 1, BalanceCents: 250, HeldCents: 200}).Return(nil)
+		r.EXPECT().AddBulkTransfer(gomock.Any(), int64(1), gomock.Any()).Return(int64(42), nil)
+		r.EXPECT().AddTransfers(gomock.Any(), gomock.Any()).Return(nil)
+		r.EXPECT().
+			AppendAuditRecord(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, record AuditRecord) error {
+				require.Equal(t, AuditEventHoldCaptured, record.EventType)
+				require.Equal(t, int64(800), record.AmountCents)
+				return nil
+			})
+		r.EXPECT().AppendAuditRecord(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	})
+
+	screener := NewMockScreener(ctrl)
+	screener.EXPECT().Screen(gomock.Any(), gomock.Any()).Return(ScreeningResult{Decision: DecisionAllow}, nil)
+
+	service := NewService(repo, screener, NewFraudEngine(nil), Pricing{})
+	result, err := service.ProcessBulkTransfer(context.Background(), BulkTransfer{
+		OrganizationIBAN: "IBAN",
+		OrganizationBIC:  "BIC",
+		HoldID:           5,
+		Transfers:        []Transfer{{CounterpartyIBAN: "EE383680981021245685", AmountCents: 750, Currency: "EUR"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(42), result.BulkTransferID)
+}
+
+func TestService_ValidateBulkTransfer_HoldNotPending(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockAccountRepository(ctrl)
+	repo.EXPECT().
+		Atomic(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+			txRepo := NewMockAccountRepository(ctrl)
+			txRepo.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{ID: 1, BalanceCents: 1000}, nil)
+			txRepo.EXPECT().GetHold(gomock.Any(), int64(5)).
+				Return(Hold{ID: 5, AccountID: 1, AmountCents: 800, Status: HoldCaptured, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+			txRepo.EXPECT().GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).Return(AccountHistory{}, nil)
+			txRepo.EXPECT().UpdateBalance(gomock.Any(), gomock.Any()).Return(nil)
+			txRepo.EXPECT().AddBulkTransfer(gomock.Any(), int64(1), gomock.Any()).Return(int64(42), nil)
+			txRepo.EXPECT().AddTransfers(gomock.Any(), gomock.Any()).Return(nil)
+			txRepo.EXPECT().AppendAuditRecord(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			return cb(txRepo)
+		})
+
+	screener := NewMockScreener(ctrl)
+	screener.EXPECT().Screen(gomock.Any(), gomock.Any()).Return(ScreeningResult{Decision: DecisionAllow}, nil)
+
+	service := NewService(repo, screener, NewFraudEngine(nil), Pricing{})
+	quote, err := service.ValidateBulkTransfer(context.Background(), BulkTransfer{
+		OrganizationIBAN: "IBAN",
+		OrganizationBIC:  "BIC",
+		HoldID:           5,
+		Transfers:        []Transfer{{CounterpartyIBAN: "EE383680981021245685", AmountCents: 500, Currency: "EUR"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Violation{
+		{TransferIndex: BatchViolation, Code: ViolationHoldNotPending, Message: "hold is no longer pending"},
+	}, quote.Violations)
+}
@@ -1,15 +1,53 @@
 package core
 
+import (
+	"time"
+)
+
+// DefaultCurrency is the currency of the accounts created without one.
+const DefaultCurrency = "EUR"
+
 type Account struct {
 	ID               int64
 	OrganizationName string
 	BalanceCents     int64
 	IBAN             string
 	BIC              string
+	// OrganizationID identifies the organization that owns the account.
+	// Accounts of the same organization may pay each other's transfers;
+	// accounts without one only pay their own.
+	OrganizationID string
+	// Currency is the currency of the balance, and of the credits and fees
+	// booked on the account.
+	Currency string
+	// Frozen accounts cannot execute bulk transfers.
+	Frozen bool
+	// HeldCents is the total of the pending holds on the account. It is part
+	// of the balance but cannot be spent.
+	HeldCents int64
+	// OverdraftLimitCents is how far below zero debits may take the balance.
+	OverdraftLimitCents int64
+	// PricingPlan names the plan that sets the fees of the transfers paid
+	// from the account.
+	PricingPlan string
+	// Version is incremented by every balance update. UpdateBalance only
+	// writes an account whose version has not changed since it was read.
+	Version int64
+}
+
+// AvailableCents is what can be spent: the balance that is not held, plus the
+// overdraft.
+func (a *Account) AvailableCents() int64 {
+	return a.BalanceCents - a.HeldCents + a.OverdraftLimitCents
+}
+
+// OverdraftUsedCents is the part of the overdraft that is in use.
+func (a *Account) OverdraftUsedCents() int64 {
+	return max(0, -a.BalanceCents)
 }
 
 func (a *Account) HasSufficientFunds(totalRequired int64) bool {
-	return a.BalanceCents >= totalRequired
+	return a.AvailableCents() >= totalRequired
 }
 
 func (a *Account) Debit(amount int64) error {
@@ -21,21 +59,49 @@ func (a *Account) Debit(amount int64) error {
 	return nil
 }
 
+func (a *Account) Credit(amount int64) {
+	a.BalanceCents += amount
+}
+
 type Transfer struct {
 	ID               int64
 	BankAccountID    int64
+	BulkTransferID   int64
 	CounterpartyName string
 	CounterpartyIBAN string
 	CounterpartyBIC  string
 	AmountCents      int64
 	Currency         string
 	Description      string
+	// DebtorIBAN and DebtorBIC name the account the transfer is paid from.
+	// When empty, the organization account of the bulk transfer pays.
+	DebtorIBAN string
+	DebtorBIC  string
 }
 
+type ExecutionMode string
+
+const (
+	// ExecutionModeAllOrNothing rejects the whole batch when any transfer
+	// cannot be executed.
+	ExecutionModeAllOrNothing ExecutionMode = "all_or_nothing"
+	// ExecutionModePartial accepts transfers in order until funds run out and
+	// rejects the rest.
+	ExecutionModePartial ExecutionMode = "partial"
+)
+
 type BulkTransfer struct {
 	OrganizationBIC  string
 	OrganizationIBAN string
-	Transfers        []Transfer
+	RequestID        string
+	ExecutionMode    ExecutionMode
+	// HoldID is captured by the execution when non-zero: the funds it
+	// reserved become available to the bulk transfer.
+	HoldID int64
+	// ApprovedReviews are the reviews a held bulk transfer passed when it was
+	// released. Their review decisions no longer stop it; blocks still do.
+	ApprovedReviews []ReviewKind
+	Transfers       []Transfer
 }
 
 func (bt BulkTransfer) TotalAmount() int64 {
@@ -46,3 +112,106 @@ func (bt BulkTransfer) TotalAmount() int64 {
 
 	return total
 }
+
+func (bt BulkTransfer) CounterpartyIBANs() []string {
+	seen := make(map[string]bool, len(bt.Transfers))
+	ibans := make([]string, 0, len(bt.Transfers))
+	for _, t := range bt.Transfers {
+		if !seen[t.CounterpartyIBAN] {
+			seen[t.CounterpartyIBAN] = true
+			ibans = append(ibans, t.CounterpartyIBAN)
+		}
+	}
+	return ibans
+}
+
+type TransferStatus string
+
+const (
+	TransferAccepted TransferStatus = "accepted"
+	TransferRejected TransferStatus = "rejected"
+)
+
+// Rejection reasons of individual transfers.
+const (
+	RejectionInsufficientFunds = "insufficient_funds"
+)
+
+type TransferResult struct {
+	Index  int
+	Status TransferStatus
+	Reason string
+	// FeeCents is charged on top of the amount of an accepted transfer.
+	FeeCents int64
+}
+
+type BulkTransferResult struct {
+	BulkTransferID int64
+	ExecutionMode  ExecutionMode
+	Transfers      []TransferResult
+}
+
+func (r BulkTransferResult) AcceptedCount() int {
+	var count int
+	for _, transfer := range r.Transfers {
+		if transfer.Status == TransferAccepted {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (r BulkTransferResult) FeesCents() int64 {
+	var total int64
+	for _, transfer := range r.Transfers {
+		total += transfer.FeeCents
+	}
+
+	return total
+}
+
+// BulkTransferRecord is an executed bulk transfer as stored.
+type BulkTransferRecord struct {
+	ID               int64
+	AccountID        int64
+	OrganizationIBAN string
+	OrganizationBIC  string
+	ExecutionMode    ExecutionMode
+	TotalCents       int64
+	TransferCount    int
+	RequestID        string
+	CreatedAt        time.Time
+}
+
+type TransactionKind string
+
+const (
+	TransactionKindTransfer TransactionKind = "transfer"
+	TransactionKindFee      TransactionKind = "fee"
+	TransactionKindCredit   TransactionKind = "credit"
+)
+
+// Transaction is a booked entry of an account. AmountCents is negative for
+// debits.
+type Transaction struct {
+	ID               int64
+	AccountID        int64
+	BulkTransferID   int64
+	Kind             TransactionKind
+	CounterpartyName string
+	CounterpartyIBAN string
+	CounterpartyBIC  string
+	AmountCents      int64
+	Currency         string
+	Description      string
+}
+
+// TransactionQuery selects a page of transactions, ordered by ID.
+type TransactionQuery struct {
+	// BulkTransferID restricts the page to one bulk transfer when non-zero.
+	BulkTransferID int64
+	// AfterID is the ID of the last transaction of the previous page.
+	AfterID int64
+	Limit   int
+}
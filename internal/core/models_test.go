@@ -12,6 +12,8 @@ func TestAccount_HasSufficientFunds(t *testing.T) {
 	tests := []struct {
 		name          string
 		balance       int64
+		held          int64
+		overdraft     int64
 		required      int64
 		expectedValid bool
 	}{
@@ -33,6 +35,20 @@ func TestAccount_HasSufficientFunds(t *testing.T) {
 			required:      10000,
 			expectedValid: false,
 		},
+		{
+			name:          "insufficient funds - held amount is not available",
+			balance:       10000,
+			held:          1,
+			required:      10000,
+			expectedValid: false,
+		},
+		{
+			name:          "sufficient funds - within the overdraft",
+			balance:       1000,
+			overdraft:     500,
+			required:      1500,
+			expectedValid: true,
+		},
 		{
 			name:          "zero balance - zero required",
 			balance:       0,
@@ -46,7 +62,9 @@ func TestAccount_HasSufficientFunds(t *testing.T) {
 			t.Parallel()
 
 			account := &Account{
-				BalanceCents: tt.balance,
+				BalanceCents:        tt.balance,
+				HeldCents:           tt.held,
+				OverdraftLimitCents: tt.overdraft,
 			}
 
 			got := account.HasSufficientFunds(tt.required)
@@ -61,6 +79,7 @@ func TestAccount_Debit(t *testing.T) {
 	tests := []struct {
 		name            string
 		initialBalance  int64
+		overdraftLimit  int64
 		debitAmount     int64
 		expectedBalance int64
 		expectedError   error
@@ -77,6 +96,21 @@ func TestAccount_Debit(t *testing.T) {
 			debitAmount:     10000,
 			expectedBalance: 0,
 		},
+		{
+			name:            "successful debit - into the overdraft",
+			initialBalance:  1000,
+			overdraftLimit:  5000,
+			debitAmount:     6000,
+			expectedBalance: -5000,
+		},
+		{
+			name:            "failed debit - beyond the overdraft",
+			initialBalance:  1000,
+			overdraftLimit:  5000,
+			debitAmount:     6001,
+			expectedBalance: 1000,
+			expectedError:   ErrInsufficientFunds,
+		},
 		{
 			name:            "failed debit - insufficient funds",
 			initialBalance:  5000,
@@ -91,7 +125,8 @@ func TestAccount_Debit(t *testing.T) {
 			t.Parallel()
 
 			account := &Account{
-				BalanceCents: tt.initialBalance,
+				BalanceCents:        tt.initialBalance,
+				OverdraftLimitCents: tt.overdraftLimit,
 			}
 
 			err := account.Debit(tt.debitAmount)
@@ -105,3 +140,10 @@ func TestAccount_Debit(t *testing.T) {
 		})
 	}
 }
+
+func TestAccount_OverdraftUsedCents(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, int64(0), (&Account{BalanceCents: 100, OverdraftLimitCents: 500}).OverdraftUsedCents())
+	require.Equal(t, int64(300), (&Account{BalanceCents: -300, OverdraftLimitCents: 500}).OverdraftUsedCents())
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PricingPlan sets the fees of the transfers paid from the accounts on it.
+type PricingPlan struct {
+	Name string
+	// SEPAFeeCents is charged for every transfer to a SEPA country.
+	SEPAFeeCents int64
+	// CrossBorderFeeBasisPoints is charged for every other transfer, in
+	// hundredths of a percent of its amount.
+	CrossBorderFeeBasisPoints int64
+	// MonthlyFreeTransfers is the number of transfers per calendar month, in
+	// UTC, that are charged no fee.
+	MonthlyFreeTransfers int
+}
+
+// Fee returns the fee of transfer, regardless of the monthly allowance.
+func (p PricingPlan) Fee(transfer Transfer) int64 {
+	if IsSEPA(transfer.CounterpartyIBAN) {
+		return p.SEPAFeeCents
+	}
+
+	// Rounded half up to the cent.
+	return (transfer.AmountCents*p.CrossBorderFeeBasisPoints + 5000) / 10000
+}
+
+// Pricing holds the pricing plans by name. Accounts without a plan, or on a
+// plan it does not know, are charged no fees.
+type Pricing struct {
+	plans map[string]PricingPlan
+}
+
+func NewPricing(plans []PricingPlan) (Pricing, error) {
+	pricing := Pricing{plans: make(map[string]PricingPlan, len(plans))}
+	for i, plan := range plans {
+		switch {
+		case plan.Name == "":
+			return Pricing{}, fmt.Errorf("plan %d: name is required", i)
+		case pricing.plans[plan.Name].Name != "":
+			return Pricing{}, fmt.Errorf("plan %q: defined twice", plan.Name)
+		case plan.SEPAFeeCents < 0, plan.CrossBorderFeeBasisPoints < 0, plan.MonthlyFreeTransfers < 0:
+			return Pricing{}, fmt.Errorf("plan %q: fees and allowance cannot be negative", plan.Name)
+		}
+		pricing.plans[plan.Name] = plan
+	}
+
+	return pricing, nil
+}
+
+func (p Pricing) Plan(name string) (PricingPlan, bool) {
+	plan, ok := p.plans[name]
+	return plan, ok
+}
+
+// sepaCountries are the country codes of the IBANs in the SEPA scheme.
+var sepaCountries = map[string]bool{
+	"AD": true, "AT": true, "BE": true, "BG": true, "CH": true, "CY": true, "CZ": true, "DE": true,
+	"DK": true, "EE": true, "ES": true, "FI": true, "FR": true, "GB": true, "GI": true, "GR": true,
+	"HR": true, "HU": true, "IE": true, "IS": true, "IT": true, "LI": true, "LT": true, "LU": true,
+	"LV": true, "MC": true, "MT": true, "NL": true, "NO": true, "PL": true, "PT": true, "RO": true,
+	"SE": true, "SI": true, "SK": true, "SM": true, "VA": true,
+}
+
+// IsSEPA reports whether iban belongs to a SEPA country.
+func IsSEPA(iban string) bool {
+	return len(iban) >= 2 && sepaCountries[strings.ToUpper(iban[:2])]
+}
+
+// transferFees returns the fee of every transfer of bulkTransfer paid from
+// account. The transfers use up what is left of the monthly allowance in
+// order, so the fees hold for any accepted prefix of them.
+func (s Service) transferFees(ctx context.Context, r AccountRepository, account Account, bulkTransfer BulkTransfer) ([]int64, error) {
+	fees := make([]int64, len(bulkTransfer.Transfers))
+	plan, ok := s.pricing.Plan(account.PricingPlan)
+	if !ok {
+		return fees, nil
+	}
+
+	var free int
+	if plan.MonthlyFreeTransfers > 0 {
+		now := time.Now().UTC()
+		used, err := r.CountTransfersSince(ctx, account.ID, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			return nil, err
+		}
+		free = max(plan.MonthlyFreeTransfers-used, 0)
+	}
+
+	for i, transfer := range bulkTransfer.Transfers {
+		if free > 0 {
+			free--
+			continue
+		}
+		fees[i] = plan.Fee(transfer)
+	}
+
+	return fees, nil
+}
+
+// SetPricingPlan moves an account to another pricing plan. An empty plan
+// removes the account from any plan: it is then charged no fees.
+func (s Service) SetPricingPlan(ctx context.Context, iban string, bic string, plan string, reason string) (Account, error) {
+	if _, ok := s.pricing.Plan(plan); !ok && plan != "" {
+		return Account{}, fmt.Errorf("failed to set pricing plan: %w", ErrUnknownPricingPlan)
+	}
+
+	var account Account
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		var err error
+		account, err = r.GetAccountByID(ctx, iban, bic)
+		if err != nil || account.PricingPlan == plan {
+			return err
+		}
+
+		if err = r.SetPricingPlan(ctx, account.ID, plan); err != nil {
+			return err
+		}
+		account.PricingPlan = plan
+
+		record := NewAuditRecord(ctx, AuditEventPricingPlanSet, account, account.BalanceCents)
+		record.Reason = reason
+		return r.AppendAuditRecord(ctx, record)
+	})
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to set pricing plan: %w", err)
+	}
+
+	return account, nil
+}
+
+// feeTransaction books the fees of a bulk transfer paid from account.
+func feeTransaction(account Account, bulkTransferID int64, feesCents int64) Transaction {
+	return Transaction{
+		AccountID:        account.ID,
+		BulkTransferID:   bulkTransferID,
+		Kind:             TransactionKindFee,
+		CounterpartyName: account.OrganizationName,
+		CounterpartyIBAN: account.IBAN,
+		CounterpartyBIC:  account.BIC,
+		AmountCents:      -feesCents,
+		Currency:         account.Currency,
+		Description:      fmt.Sprintf("Transfer fees of bulk transfer %d", bulkTransferID),
+	}
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPricingPlan_Fee(t *testing.T) {
+	t.Parallel()
+
+	plan := PricingPlan{Name: "business", SEPAFeeCents: 20, CrossBorderFeeBasisPoints: 50}
+
+	tests := []struct {
+		name        string
+		transfer    Transfer
+		expectedFee int64
+	}{
+		{
+			name:        "flat fee within SEPA",
+			transfer:    Transfer{CounterpartyIBAN: "EE383680981021245685", AmountCents: 1000000},
+			expectedFee: 20,
+		},
+		{
+			name:        "country codes are case insensitive",
+			transfer:    Transfer{CounterpartyIBAN: "fr1420041010050500013M02606", AmountCents: 1000},
+			expectedFee: 20,
+		},
+		{
+			name:        "percentage across borders",
+			transfer:    Transfer{CounterpartyIBAN: "US12345678901234567890", AmountCents: 100000},
+			expectedFee: 500,
+		},
+		{
+			name:        "percentage is rounded half up to the cent",
+			transfer:    Transfer{CounterpartyIBAN: "US12345678901234567890", AmountCents: 1100},
+			expectedFee: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expectedFee, plan.Fee(tt.transfer))
+		})
+	}
+}
+
+func TestNewPricing(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewPricing([]PricingPlan{{Name: "business"}, {Name: "business"}})
+	require.EqualError(t, err, `plan "business": defined twice`)
+
+	_, err = NewPricing([]PricingPlan{{SEPAFeeCents: 20}})
+	require.EqualError(t, err, "plan 0: name is required")
+
+	_, err = NewPricing([]PricingPlan{{Name: "business", MonthlyFreeTransfers: -1}})
+	require.EqualError(t, err, `plan "business": fees and allowance cannot be negative`)
+}
+
+func TestService_TransferFees(t *testing.T) {
+	t.Parallel()
+
+	pricing, err := NewPricing([]PricingPlan{
+		{Name: "flat", SEPAFeeCents: 20},
+		{Name: "allowance", SEPAFeeCents: 20, MonthlyFreeTransfers: 10},
+	})
+	require.NoError(t, err)
+
+	bulkTransfer := BulkTransfer{Transfers: []Transfer{
+		{CounterpartyIBAN: "EE383680981021245685", AmountCents: 100},
+		{CounterpartyIBAN: "EE383680981021245685", AmountCents: 200},
+		{CounterpartyIBAN: "EE383680981021245685", AmountCents: 300},
+	}}
+
+	tests := []struct {
+		name         string
+		plan         string
+		mockSetup    func(*MockAccountRepository)
+		expectedFees []int64
+	}{
+		{
+			name:         "no plan",
+			mockSetup:    func(*MockAccountRepository) {},
+			expectedFees: []int64{0, 0, 0},
+		},
+		{
+			name:         "unknown plan",
+			plan:         "legacy",
+			mockSetup:    func(*MockAccountRepository) {},
+			expectedFees: []int64{0, 0, 0},
+		},
+		{
+			name:         "plan without allowance",
+			plan:         "flat",
+			mockSetup:    func(*MockAccountRepository) {},
+			expectedFees: []int64{20, 20, 20},
+		},
+		{
+			name: "the first transfers use up the allowance",
+			plan: "allowance",
+			mockSetup: func(r *MockAccountRepository) {
+				r.EXPECT().CountTransfersSince(gomock.Any(), int64(1), gomock.Any()).Return(8, nil)
+			},
+			expectedFees: []int64{0, 0, 20},
+		},
+		{
+			name: "allowance used up",
+			plan: "allowance",
+			mockSetup: func(r *MockAccountRepository) {
+				r.EXPECT().CountTransfersSince(gomock.Any(), int64(1), gomock.Any()).Return(12, nil)
+			},
+			expectedFees: []int64{20, 20, 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := NewMockAccountRepository(ctrl)
+			tt.mockSetup(repo)
+
+			service := NewService(repo, NewMockScreener(ctrl), NewFraudEngine(nil), pricing)
+			fees, err := service.transferFees(context.Background(), repo, Account{ID: 1, PricingPlan: tt.plan}, bulkTransfer)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedFees, fees)
+		})
+	}
+}
+
+func TestService_SetPricingPlan(t *testing.T) {
+	t.Parallel()
+
+	pricing, err := NewPricing([]PricingPlan{{Name: "business", SEPAFeeCents: 20}})
+	require.NoError(t, err)
+
+	t.Run("moves the account to the plan", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := atomicRepository(ctrl, func(r *MockAccountRepository) {
+			r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{ID: 1, BalanceCents: 100}, nil)
+			r.EXPECT().SetPricingPlan(gomock.Any(), int64(1), "business").Return(nil)
+			r.EXPECT().AppendAuditRecord(gomock.Any(), AuditRecord{
+				EventType:          AuditEventPricingPlanSet,
+				AccountID:          1,
+				BalanceBeforeCents: 100,
+				BalanceAfterCents:  100,
+				Reason:             "contract signed",
+			}).Return(nil)
+		})
+
+		service := NewService(repo, NewMockScreener(ctrl), NewFraudEngine(nil), pricing)
+		account, err := service.SetPricingPlan(context.Background(), "IBAN", "BIC", "business", "contract signed")
+		require.NoError(t, err)
+		require.Equal(t, "business", account.PricingPlan)
+	})
+
+	t.Run("rejects unknown plans", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := NewService(NewMockAccountRepository(ctrl), NewMockScreener(ctrl), NewFraudEngine(nil), pricing)
+		_, err := service.SetPricingPlan(context.Background(), "IBAN", "BIC", "premium", "")
+		require.ErrorIs(t, err, ErrUnknownPricingPlan)
+	})
+}
@@ -0,0 +1,70 @@
+package core
+
+// BatchViolation is the TransferIndex of violations that concern the whole
+// bulk transfer rather than one of its transfers.
+const BatchViolation = -1
+
+// Violation codes reported by a dry run.
+const (
+	ViolationInsufficientFunds = "insufficient_funds"
+	ViolationAccountFrozen     = "account_frozen"
+	ViolationSanctionsBlock    = "sanctions_blocked"
+	ViolationSanctionsReview   = "sanctions_review"
+	ViolationFraudBlock        = "fraud_blocked"
+	ViolationFraudReview       = "fraud_review"
+	ViolationHoldNotPending    = "hold_not_pending"
+)
+
+type Violation struct {
+	TransferIndex int
+	Code          string
+	Message       string
+}
+
+// BulkTransferQuote previews the execution of a bulk transfer.
+type BulkTransferQuote struct {
+	TransferCount      int
+	TotalAmountCents   int64
+	FeesCents          int64
+	BalanceBeforeCents int64
+	BalanceAfterCents  int64
+	Violations         []Violation
+}
+
+func (q BulkTransferQuote) Valid() bool {
+	return len(q.Violations) == 0
+}
+
+func sanctionsViolation(result ScreeningResult) Violation {
+	// Neither the watchlist entries nor the matching transfers are disclosed,
+	// so that dry runs cannot be used to probe the list.
+	if result.Decision == DecisionReview {
+		return Violation{TransferIndex: BatchViolation, Code: ViolationSanctionsReview, Message: "bulk transfer would be held for sanctions review"}
+	}
+
+	return Violation{TransferIndex: BatchViolation, Code: ViolationSanctionsBlock, Message: "bulk transfer would be blocked by sanctions screening"}
+}
+
+func fraudViolation(fraudErr *FraudError) Violation {
+	// Rule thresholds are not disclosed.
+	if fraudErr.Decision == DecisionReview {
+		return Violation{TransferIndex: BatchViolation, Code: ViolationFraudReview, Message: "bulk transfer would be held for fraud review"}
+	}
+
+	return Violation{TransferIndex: BatchViolation, Code: ViolationFraudBlock, Message: "bulk transfer would be blocked by fraud rules"}
+}
+
+func rejectedTransferViolations(result BulkTransferResult) []Violation {
+	var violations []Violation
+	for _, transfer := range result.Transfers {
+		if transfer.Status == TransferRejected {
+			violations = append(violations, Violation{
+				TransferIndex: transfer.Index,
+				Code:          transfer.Reason,
+				Message:       "balance does not cover this transfer",
+			})
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// BalanceCheck compares the stored balance of an account with the balance
+// its ledger leads to.
+type BalanceCheck struct {
+	AccountID           int64
+	IBAN                string
+	BIC                 string
+	OpeningBalanceCents int64
+	TransactionsCents   int64
+	BalanceCents        int64
+}
+
+func (c BalanceCheck) ExpectedBalanceCents() int64 {
+	return c.OpeningBalanceCents + c.TransactionsCents
+}
+
+// DiscrepancyCents is positive when the stored balance exceeds the ledger.
+func (c BalanceCheck) DiscrepancyCents() int64 {
+	return c.BalanceCents - c.ExpectedBalanceCents()
+}
+
+type ReconciliationReport struct {
+	ID            int64
+	StartedAt     time.Time
+	FinishedAt    time.Time
+	AccountCount  int
+	Discrepancies []BalanceCheck
+}
+
+// Reconciler checks that the balance of every account equals its opening
+// balance plus the sum of its transactions.
+type Reconciler struct {
+	reader AccountRepository
+	writer AccountRepository
+}
+
+// NewReconciler returns a Reconciler that reads the balances from reader,
+// which may be a read-only connection, and saves its reports with writer.
+func NewReconciler(reader AccountRepository, writer AccountRepository) Reconciler {
+	return Reconciler{
+		reader: reader,
+		writer: writer,
+	}
+}
+
+func (r Reconciler) Reconcile(ctx context.Context) (ReconciliationReport, error) {
+	report := ReconciliationReport{StartedAt: time.Now().UTC()}
+
+	var checks []BalanceCheck
+	err := r.reader.Atomic(ctx, func(repo AccountRepository) error {
+		var err error
+		checks, err = repo.CheckBalances(ctx)
+		return err
+	})
+	if err != nil {
+		return ReconciliationReport{}, fmt.Errorf("failed to check balances: %w", err)
+	}
+
+	report.AccountCount = len(checks)
+	for _, check := range checks {
+		if check.DiscrepancyCents() != 0 {
+			report.Discrepancies = append(report.Discrepancies, check)
+		}
+	}
+	report.FinishedAt = time.Now().UTC()
+
+	err = r.writer.Atomic(ctx, func(repo AccountRepository) error {
+		var err error
+		report.ID, err = repo.AddReconciliationReport(ctx, report)
+		return err
+	})
+	if err != nil {
+		return ReconciliationReport{}, fmt.Errorf("failed to save reconciliation report: %w", err)
+	}
+
+	return report, nil
+}
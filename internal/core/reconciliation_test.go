@@ -0,0 +1,65 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBalanceCheck_DiscrepancyCents(t *testing.T) {
+	t.Parallel()
+
+	check := BalanceCheck{OpeningBalanceCents: 1000, TransactionsCents: -300, BalanceCents: 750}
+	require.Equal(t, int64(700), check.ExpectedBalanceCents())
+	require.Equal(t, int64(50), check.DiscrepancyCents())
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	matching := BalanceCheck{AccountID: 1, OpeningBalanceCents: 1000, TransactionsCents: -300, BalanceCents: 700}
+	mismatching := BalanceCheck{AccountID: 2, OpeningBalanceCents: 1000, TransactionsCents: -300, BalanceCents: 1000}
+
+	t.Run("saves_the_discrepancies", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		reader := atomicRepository(ctrl, func(r *MockAccountRepository) {
+			r.EXPECT().CheckBalances(gomock.Any()).Return([]BalanceCheck{matching, mismatching}, nil)
+		})
+		writer := atomicRepository(ctrl, func(r *MockAccountRepository) {
+			r.EXPECT().
+				AddReconciliationReport(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, report ReconciliationReport) (int64, error) {
+					require.Equal(t, 2, report.AccountCount)
+					require.Equal(t, []BalanceCheck{mismatching}, report.Discrepancies)
+					return 9, nil
+				})
+		})
+
+		report, err := NewReconciler(reader, writer).Reconcile(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int64(9), report.ID)
+		require.Equal(t, []BalanceCheck{mismatching}, report.Discrepancies)
+		require.False(t, report.FinishedAt.Before(report.StartedAt))
+	})
+
+	t.Run("no_report_when_the_check_fails", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		reader := atomicRepository(ctrl, func(r *MockAccountRepository) {
+			r.EXPECT().CheckBalances(gomock.Any()).Return(nil, errors.New("database is locked"))
+		})
+
+		_, err := NewReconciler(reader, NewMockAccountRepository(ctrl)).Reconcile(context.Background())
+		require.ErrorContains(t, err, "database is locked")
+	})
+}
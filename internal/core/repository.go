@@ -2,13 +2,67 @@ package core
 
 import (
 	"context"
+	"time"
 )
 
 //go:generate go tool go.uber.org/mock/mockgen -source=repository.go -destination=repository_mock.go -package=core
 
 type AccountRepository interface {
 	GetAccountByID(ctx context.Context, IBAN string, BIC string) (Account, error)
+	CreateAccount(ctx context.Context, account Account) (int64, error)
+	ListAccounts(ctx context.Context) ([]Account, error)
+	SetAccountFrozen(ctx context.Context, accountID int64, frozen bool) error
+	SetOverdraftLimit(ctx context.Context, accountID int64, limitCents int64) error
+	SetPricingPlan(ctx context.Context, accountID int64, plan string) error
+	GetAccountHistory(ctx context.Context, accountID int64, counterpartyIBANs []string) (AccountHistory, error)
+	AddBulkTransfer(ctx context.Context, accountID int64, bulkTransfer BulkTransfer) (int64, error)
 	AddTransfers(ctx context.Context, transfers []Transfer) error
+	// CountTransfersSince counts the transfers paid from the account by bulk
+	// transfers executed at or after since.
+	CountTransfersSince(ctx context.Context, accountID int64, since time.Time) (int, error)
+	// UpdateBalance writes the balance of account and increments its version.
+	// It returns a *VersionConflictError when the stored version is no longer
+	// account.Version.
 	UpdateBalance(ctx context.Context, account Account) error
+	AppendAuditRecord(ctx context.Context, record AuditRecord) error
+	AddStagedBulkTransfer(ctx context.Context, bulkTransfer BulkTransfer) (int64, error)
+	AddStagedTransfers(ctx context.Context, stagedBulkTransferID int64, transfers []StagedTransfer) error
+	// GetStagedBulkTransfer returns a staged bulk transfer without its
+	// transfers. ApprovedReviews are the reviews it passed before it was held.
+	GetStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) (BulkTransfer, error)
+	// ListStagedTransfers returns at most limit staged transfers positioned
+	// after afterPosition, in the order of their positions.
+	ListStagedTransfers(ctx context.Context, stagedBulkTransferID int64, afterPosition int, limit int) ([]StagedTransfer, error)
+	DeleteStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) error
+	// DeleteStagedBulkTransfersBefore deletes the staged bulk transfers created
+	// before createdBefore that are not held, and the staged transfers of
+	// missing staged bulk transfers. It returns the number of staged bulk
+	// transfers deleted.
+	DeleteStagedBulkTransfersBefore(ctx context.Context, createdBefore time.Time) (int, error)
+	// HoldStagedBulkTransfer holds the staged bulk transfer held.ID for
+	// held.Review, with held.Reason and held.Approved. The other fields are
+	// ignored.
+	HoldStagedBulkTransfer(ctx context.Context, held HeldBulkTransfer) error
+	// GetHeldBulkTransfer returns ErrHeldBulkTransferNotFound when the staged
+	// bulk transfer does not exist or is not held.
+	GetHeldBulkTransfer(ctx context.Context, heldBulkTransferID int64) (HeldBulkTransfer, error)
+	// ListHeldBulkTransfers returns the held bulk transfers in the order they
+	// were staged.
+	ListHeldBulkTransfers(ctx context.Context) ([]HeldBulkTransfer, error)
+	GetBulkTransfer(ctx context.Context, bulkTransferID int64) (BulkTransferRecord, error)
+	ListBulkTransfers(ctx context.Context, accountID int64) ([]BulkTransferRecord, error)
+	ListTransactions(ctx context.Context, accountID int64, query TransactionQuery) ([]Transaction, error)
+	AddTransaction(ctx context.Context, transaction Transaction) error
+	CheckBalances(ctx context.Context) ([]BalanceCheck, error)
+	AddReconciliationReport(ctx context.Context, report ReconciliationReport) (int64, error)
+	AddHold(ctx context.Context, hold Hold) (int64, error)
+	GetHold(ctx context.Context, holdID int64) (Hold, error)
+	ListHolds(ctx context.Context, accountID int64) ([]Hold, error)
+	SetHoldStatus(ctx context.Context, holdID int64, status HoldStatus) error
+	// Atomic runs cb in a transaction. Called on the repository given to a
+	// callback, it runs cb in a nested transaction whose failure only undoes
+	// what cb did. A transaction that fails on a transient error may be
+	// rolled back and cb run again, so cb must only assign the state it
+	// captures, never accumulate into it.
 	Atomic(ctx context.Context, cb func(r AccountRepository) error) error
 }
@@ -12,6 +12,7 @@ package core
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,6 +41,94 @@ func (m *MockAccountRepository) EXPECT() *MockAccountRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddBulkTransfer mocks base method.
+func (m *MockAccountRepository) AddBulkTransfer(ctx context.Context, accountID int64, bulkTransfer BulkTransfer) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBulkTransfer", ctx, accountID, bulkTransfer)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddBulkTransfer indicates an expected call of AddBulkTransfer.
+func (mr *MockAccountRepositoryMockRecorder) AddBulkTransfer(ctx, accountID, bulkTransfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBulkTransfer", reflect.TypeOf((*MockAccountRepository)(nil).AddBulkTransfer), ctx, accountID, bulkTransfer)
+}
+
+// AddHold mocks base method.
+func (m *MockAccountRepository) AddHold(ctx context.Context, hold Hold) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddHold", ctx, hold)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddHold indicates an expected call of AddHold.
+func (mr *MockAccountRepositoryMockRecorder) AddHold(ctx, hold any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHold", reflect.TypeOf((*MockAccountRepository)(nil).AddHold), ctx, hold)
+}
+
+// AddReconciliationReport mocks base method.
+func (m *MockAccountRepository) AddReconciliationReport(ctx context.Context, report ReconciliationReport) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReconciliationReport", ctx, report)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddReconciliationReport indicates an expected call of AddReconciliationReport.
+func (mr *MockAccountRepositoryMockRecorder) AddReconciliationReport(ctx, report any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReconciliationReport", reflect.TypeOf((*MockAccountRepository)(nil).AddReconciliationReport), ctx, report)
+}
+
+// AddStagedBulkTransfer mocks base method.
+func (m *MockAccountRepository) AddStagedBulkTransfer(ctx context.Context, bulkTransfer BulkTransfer) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddStagedBulkTransfer", ctx, bulkTransfer)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddStagedBulkTransfer indicates an expected call of AddStagedBulkTransfer.
+func (mr *MockAccountRepositoryMockRecorder) AddStagedBulkTransfer(ctx, bulkTransfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStagedBulkTransfer", reflect.TypeOf((*MockAccountRepository)(nil).AddStagedBulkTransfer), ctx, bulkTransfer)
+}
+
+// AddStagedTransfers mocks base method.
+func (m *MockAccountRepository) AddStagedTransfers(ctx context.Context, stagedBulkTransferID int64, transfers []StagedTransfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddStagedTransfers", ctx, stagedBulkTransferID, transfers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddStagedTransfers indicates an expected call of AddStagedTransfers.
+func (mr *MockAccountRepositoryMockRecorder) AddStagedTransfers(ctx, stagedBulkTransferID, transfers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStagedTransfers", reflect.TypeOf((*MockAccountRepository)(nil).AddStagedTransfers), ctx, stagedBulkTransferID, transfers)
+}
+
+// AddTransaction mocks base method.
+func (m *MockAccountRepository) AddTransaction(ctx context.Context, transaction Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTransaction", ctx, transaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTransaction indicates an expected call of AddTransaction.
+func (mr *MockAccountRepositoryMockRecorder) AddTransaction(ctx, transaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTransaction", reflect.TypeOf((*MockAccountRepository)(nil).AddTransaction), ctx, transaction)
+}
+
 // AddTransfers mocks base method.
 func (m *MockAccountRepository) AddTransfers(ctx context.Context, transfers []Transfer) error {
 	m.ctrl.T.Helper()
@@ -54,6 +143,20 @@ func (mr *MockAccountRepositoryMockRecorder) AddTransfers(ctx, transfers any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTransfers", reflect.TypeOf((*MockAccountRepository)(nil).AddTransfers), ctx, transfers)
 }
 
+// AppendAuditRecord mocks base method.
+func (m *MockAccountRepository) AppendAuditRecord(ctx context.Context, record AuditRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendAuditRecord", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendAuditRecord indicates an expected call of AppendAuditRecord.
+func (mr *MockAccountRepositoryMockRecorder) AppendAuditRecord(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendAuditRecord", reflect.TypeOf((*MockAccountRepository)(nil).AppendAuditRecord), ctx, record)
+}
+
 // Atomic mocks base method.
 func (m *MockAccountRepository) Atomic(ctx context.Context, cb func(AccountRepository) error) error {
 	m.ctrl.T.Helper()
@@ -68,6 +171,80 @@ func (mr *MockAccountRepositoryMockRecorder) Atomic(ctx, cb any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Atomic", reflect.TypeOf((*MockAccountRepository)(nil).Atomic), ctx, cb)
 }
 
+// CheckBalances mocks base method.
+func (m *MockAccountRepository) CheckBalances(ctx context.Context) ([]BalanceCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckBalances", ctx)
+	ret0, _ := ret[0].([]BalanceCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckBalances indicates an expected call of CheckBalances.
+func (mr *MockAccountRepositoryMockRecorder) CheckBalances(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckBalances", reflect.TypeOf((*MockAccountRepository)(nil).CheckBalances), ctx)
+}
+
+// CountTransfersSince mocks base method.
+func (m *MockAccountRepository) CountTransfersSince(ctx context.Context, accountID int64, since time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTransfersSince", ctx, accountID, since)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTransfersSince indicates an expected call of CountTransfersSince.
+func (mr *MockAccountRepositoryMockRecorder) CountTransfersSince(ctx, accountID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTransfersSince", reflect.TypeOf((*MockAccountRepository)(nil).CountTransfersSince), ctx, accountID, since)
+}
+
+// CreateAccount mocks base method.
+func (m *MockAccountRepository) CreateAccount(ctx context.Context, account Account) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccount", ctx, account)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockAccountRepositoryMockRecorder) CreateAccount(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockAccountRepository)(nil).CreateAccount), ctx, account)
+}
+
+// DeleteStagedBulkTransfer mocks base method.
+func (m *MockAccountRepository) DeleteStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStagedBulkTransfer", ctx, stagedBulkTransferID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStagedBulkTransfer indicates an expected call of DeleteStagedBulkTransfer.
+func (mr *MockAccountRepositoryMockRecorder) DeleteStagedBulkTransfer(ctx, stagedBulkTransferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStagedBulkTransfer", reflect.TypeOf((*MockAccountRepository)(nil).DeleteStagedBulkTransfer), ctx, stagedBulkTransferID)
+}
+
+// DeleteStagedBulkTransfersBefore mocks base method.
+func (m *MockAccountRepository) DeleteStagedBulkTransfersBefore(ctx context.Context, createdBefore time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStagedBulkTransfersBefore", ctx, createdBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStagedBulkTransfersBefore indicates an expected call of DeleteStagedBulkTransfersBefore.
+func (mr *MockAccountRepositoryMockRecorder) DeleteStagedBulkTransfersBefore(ctx, createdBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStagedBulkTransfersBefore", reflect.TypeOf((*MockAccountRepository)(nil).DeleteStagedBulkTransfersBefore), ctx, createdBefore)
+}
+
 // GetAccountByID mocks base method.
 func (m *MockAccountRepository) GetAccountByID(ctx context.Context, IBAN, BIC string) (Account, error) {
 	m.ctrl.T.Helper()
@@ -83,6 +260,241 @@ func (mr *MockAccountRepositoryMockRecorder) GetAccountByID(ctx, IBAN, BIC any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByID", reflect.TypeOf((*MockAccountRepository)(nil).GetAccountByID), ctx, IBAN, BIC)
 }
 
+// GetAccountHistory mocks base method.
+func (m *MockAccountRepository) GetAccountHistory(ctx context.Context, accountID int64, counterpartyIBANs []string) (AccountHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountHistory", ctx, accountID, counterpartyIBANs)
+	ret0, _ := ret[0].(AccountHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountHistory indicates an expected call of GetAccountHistory.
+func (mr *MockAccountRepositoryMockRecorder) GetAccountHistory(ctx, accountID, counterpartyIBANs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountHistory", reflect.TypeOf((*MockAccountRepository)(nil).GetAccountHistory), ctx, accountID, counterpartyIBANs)
+}
+
+// GetBulkTransfer mocks base method.
+func (m *MockAccountRepository) GetBulkTransfer(ctx context.Context, bulkTransferID int64) (BulkTransferRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBulkTransfer", ctx, bulkTransferID)
+	ret0, _ := ret[0].(BulkTransferRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBulkTransfer indicates an expected call of GetBulkTransfer.
+func (mr *MockAccountRepositoryMockRecorder) GetBulkTransfer(ctx, bulkTransferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBulkTransfer", reflect.TypeOf((*MockAccountRepository)(nil).GetBulkTransfer), ctx, bulkTransferID)
+}
+
+// GetHeldBulkTransfer mocks base method.
+func (m *MockAccountRepository) GetHeldBulkTransfer(ctx context.Context, heldBulkTransferID int64) (HeldBulkTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeldBulkTransfer", ctx, heldBulkTransferID)
+	ret0, _ := ret[0].(HeldBulkTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeldBulkTransfer indicates an expected call of GetHeldBulkTransfer.
+func (mr *MockAccountRepositoryMockRecorder) GetHeldBulkTransfer(ctx, heldBulkTransferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeldBulkTransfer", reflect.TypeOf((*MockAccountRepository)(nil).GetHeldBulkTransfer), ctx, heldBulkTransferID)
+}
+
+// GetHold mocks base method.
+func (m *MockAccountRepository) GetHold(ctx context.Context, holdID int64) (Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHold", ctx, holdID)
+	ret0, _ := ret[0].(Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHold indicates an expected call of GetHold.
+func (mr *MockAccountRepositoryMockRecorder) GetHold(ctx, holdID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHold", reflect.TypeOf((*MockAccountRepository)(nil).GetHold), ctx, holdID)
+}
+
+// GetStagedBulkTransfer mocks base method.
+func (m *MockAccountRepository) GetStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) (BulkTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStagedBulkTransfer", ctx, stagedBulkTransferID)
+	ret0, _ := ret[0].(BulkTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStagedBulkTransfer indicates an expected call of GetStagedBulkTransfer.
+func (mr *MockAccountRepositoryMockRecorder) GetStagedBulkTransfer(ctx, stagedBulkTransferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStagedBulkTransfer", reflect.TypeOf((*MockAccountRepository)(nil).GetStagedBulkTransfer), ctx, stagedBulkTransferID)
+}
+
+// HoldStagedBulkTransfer mocks base method.
+func (m *MockAccountRepository) HoldStagedBulkTransfer(ctx context.Context, held HeldBulkTransfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HoldStagedBulkTransfer", ctx, held)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HoldStagedBulkTransfer indicates an expected call of HoldStagedBulkTransfer.
+func (mr *MockAccountRepositoryMockRecorder) HoldStagedBulkTransfer(ctx, held any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HoldStagedBulkTransfer", reflect.TypeOf((*MockAccountRepository)(nil).HoldStagedBulkTransfer), ctx, held)
+}
+
+// ListAccounts mocks base method.
+func (m *MockAccountRepository) ListAccounts(ctx context.Context) ([]Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", ctx)
+	ret0, _ := ret[0].([]Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockAccountRepositoryMockRecorder) ListAccounts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockAccountRepository)(nil).ListAccounts), ctx)
+}
+
+// ListBulkTransfers mocks base method.
+func (m *MockAccountRepository) ListBulkTransfers(ctx context.Context, accountID int64) ([]BulkTransferRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBulkTransfers", ctx, accountID)
+	ret0, _ := ret[0].([]BulkTransferRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBulkTransfers indicates an expected call of ListBulkTransfers.
+func (mr *MockAccountRepositoryMockRecorder) ListBulkTransfers(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBulkTransfers", reflect.TypeOf((*MockAccountRepository)(nil).ListBulkTransfers), ctx, accountID)
+}
+
+// ListHeldBulkTransfers mocks base method.
+func (m *MockAccountRepository) ListHeldBulkTransfers(ctx context.Context) ([]HeldBulkTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHeldBulkTransfers", ctx)
+	ret0, _ := ret[0].([]HeldBulkTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHeldBulkTransfers indicates an expected call of ListHeldBulkTransfers.
+func (mr *MockAccountRepositoryMockRecorder) ListHeldBulkTransfers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHeldBulkTransfers", reflect.TypeOf((*MockAccountRepository)(nil).ListHeldBulkTransfers), ctx)
+}
+
+// ListHolds mocks base method.
+func (m *MockAccountRepository) ListHolds(ctx context.Context, accountID int64) ([]Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHolds", ctx, accountID)
+	ret0, _ := ret[0].([]Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHolds indicates an expected call of ListHolds.
+func (mr *MockAccountRepositoryMockRecorder) ListHolds(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHolds", reflect.TypeOf((*MockAccountRepository)(nil).ListHolds), ctx, accountID)
+}
+
+// ListStagedTransfers mocks base method.
+func (m *MockAccountRepository) ListStagedTransfers(ctx context.Context, stagedBulkTransferID int64, afterPosition, limit int) ([]StagedTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStagedTransfers", ctx, stagedBulkTransferID, afterPosition, limit)
+	ret0, _ := ret[0].([]StagedTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStagedTransfers indicates an expected call of ListStagedTransfers.
+func (mr *MockAccountRepositoryMockRecorder) ListStagedTransfers(ctx, stagedBulkTransferID, afterPosition, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStagedTransfers", reflect.TypeOf((*MockAccountRepository)(nil).ListStagedTransfers), ctx, stagedBulkTransferID, afterPosition, limit)
+}
+
+// ListTransactions mocks base method.
+func (m *MockAccountRepository) ListTransactions(ctx context.Context, accountID int64, query TransactionQuery) ([]Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, accountID, query)
+	ret0, _ := ret[0].([]Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockAccountRepositoryMockRecorder) ListTransactions(ctx, accountID, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockAccountRepository)(nil).ListTransactions), ctx, accountID, query)
+}
+
+// SetAccountFrozen mocks base method.
+func (m *MockAccountRepository) SetAccountFrozen(ctx context.Context, accountID int64, frozen bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccountFrozen", ctx, accountID, frozen)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAccountFrozen indicates an expected call of SetAccountFrozen.
+func (mr *MockAccountRepositoryMockRecorder) SetAccountFrozen(ctx, accountID, frozen any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountFrozen", reflect.TypeOf((*MockAccountRepository)(nil).SetAccountFrozen), ctx, accountID, frozen)
+}
+
+// SetHoldStatus mocks base method.
+func (m *MockAccountRepository) SetHoldStatus(ctx context.Context, holdID int64, status HoldStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHoldStatus", ctx, holdID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHoldStatus indicates an expected call of SetHoldStatus.
+func (mr *MockAccountRepositoryMockRecorder) SetHoldStatus(ctx, holdID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHoldStatus", reflect.TypeOf((*MockAccountRepository)(nil).SetHoldStatus), ctx, holdID, status)
+}
+
+// SetOverdraftLimit mocks base method.
+func (m *MockAccountRepository) SetOverdraftLimit(ctx context.Context, accountID, limitCents int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOverdraftLimit", ctx, accountID, limitCents)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOverdraftLimit indicates an expected call of SetOverdraftLimit.
+func (mr *MockAccountRepositoryMockRecorder) SetOverdraftLimit(ctx, accountID, limitCents any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOverdraftLimit", reflect.TypeOf((*MockAccountRepository)(nil).SetOverdraftLimit), ctx, accountID, limitCents)
+}
+
+// SetPricingPlan mocks base method.
+func (m *MockAccountRepository) SetPricingPlan(ctx context.Context, accountID int64, plan string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPricingPlan", ctx, accountID, plan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPricingPlan indicates an expected call of SetPricingPlan.
+func (mr *MockAccountRepositoryMockRecorder) SetPricingPlan(ctx, accountID, plan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPricingPlan", reflect.TypeOf((*MockAccountRepository)(nil).SetPricingPlan), ctx, accountID, plan)
+}
+
 // UpdateBalance mocks base method.
 func (m *MockAccountRepository) UpdateBalance(ctx context.Context, account Account) error {
 	m.ctrl.T.Helper()
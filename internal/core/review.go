@@ -0,0 +1,243 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// A bulk transfer that sanctions screening or the fraud rules send to a manual
+// review is not executed but held: it is kept as a staged bulk transfer until an operator releases it,
+// which executes it, or rejects it, which drops it.
+
+// ReviewKind names the check that sent a bulk transfer to a manual review.
+type ReviewKind string
+
+const (
+	ReviewKindSanctions ReviewKind = "sanctions"
+	ReviewKindFraud     ReviewKind = "fraud"
+)
+
+// HeldBulkTransfer is a bulk transfer held for a manual review. ID is the ID
+// of its staged bulk transfer.
+type HeldBulkTransfer struct {
+	ID               int64
+	OrganizationIBAN string
+	OrganizationBIC  string
+	ExecutionMode    ExecutionMode
+	RequestID        string
+	TransferCount    int
+	TotalCents       int64
+	// Review is the review the bulk transfer waits for, and Reason what
+	// stopped it.
+	Review ReviewKind
+	Reason string
+	// Approved are the reviews the bulk transfer passed before it was held
+	// again.
+	Approved []ReviewKind
+	HeldAt   time.Time
+}
+
+// HeldError reports a bulk transfer that was held for a manual review instead
+// of executed.
+type HeldError struct {
+	HeldBulkTransferID int64
+	Review             ReviewKind
+	// Reason is the *SanctionsError or *FraudError that stopped the bulk
+	// transfer.
+	Reason error
+}
+
+func (e *HeldError) Error() string {
+	return fmt.Sprintf("held for %s review as held bulk transfer %d: %s", e.Review, e.HeldBulkTransferID, e.Reason)
+}
+
+func (e *HeldError) Unwrap() error {
+	return ErrBulkTransferHeld
+}
+
+// approved tells whether the bulk transfer passed review.
+func (bt BulkTransfer) approved(review ReviewKind) bool {
+	return slices.Contains(bt.ApprovedReviews, review)
+}
+
+// heldFor returns the review that err sends a bulk transfer to, if any.
+func heldFor(err error) ReviewKind {
+	var sanctionsErr *SanctionsError
+	if errors.As(err, &sanctionsErr) && sanctionsErr.Decision == DecisionReview {
+		return ReviewKindSanctions
+	}
+
+	var fraudErr *FraudError
+	if errors.As(err, &fraudErr) && fraudErr.Decision == DecisionReview {
+		return ReviewKindFraud
+	}
+
+	return ""
+}
+
+// hold keeps bulkTransfer, stopped by reason, for a review and returns the
+// *HeldError that reports it. A bulk transfer that was not staged is staged
+// first, in the same transaction as the hold and its audit record.
+func (s Service) hold(ctx context.Context, bulkTransfer BulkTransfer, stagedBulkTransferID int64, account Account, review ReviewKind, reason error) error {
+	record := NewAuditRecord(ctx, AuditEventBulkTransferHeld, account, account.BalanceCents)
+	record.AmountCents = bulkTransfer.TotalAmount()
+	record.TransferCount = len(bulkTransfer.Transfers)
+
+	var heldBulkTransferID int64
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		heldBulkTransferID = stagedBulkTransferID
+		if heldBulkTransferID == 0 {
+			var err error
+			heldBulkTransferID, err = r.AddStagedBulkTransfer(ctx, bulkTransfer)
+			if err != nil {
+				return err
+			}
+
+			transfers := make([]StagedTransfer, len(bulkTransfer.Transfers))
+			for i, transfer := range bulkTransfer.Transfers {
+				transfers[i] = StagedTransfer{Position: i, Transfer: transfer}
+			}
+			if err = r.AddStagedTransfers(ctx, heldBulkTransferID, transfers); err != nil {
+				return err
+			}
+		}
+
+		err := r.HoldStagedBulkTransfer(ctx, HeldBulkTransfer{
+			ID:       heldBulkTransferID,
+			Review:   review,
+			Reason:   reason.Error(),
+			Approved: bulkTransfer.ApprovedReviews,
+		})
+		if err != nil {
+			return err
+		}
+
+		record.Reason = fmt.Sprintf("held bulk transfer %d: %s", heldBulkTransferID, reason)
+		return r.AppendAuditRecord(ctx, record)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to hold bulk transfer: %w", err)
+	}
+
+	return &HeldError{HeldBulkTransferID: heldBulkTransferID, Review: review, Reason: reason}
+}
+
+// ListHeldBulkTransfers returns the bulk transfers waiting for a review,
+// oldest first.
+func (s Service) ListHeldBulkTransfers(ctx context.Context) ([]HeldBulkTransfer, error) {
+	var held []HeldBulkTransfer
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		var err error
+		held, err = r.ListHeldBulkTransfers(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list held bulk transfers: %w", err)
+	}
+
+	return held, nil
+}
+
+// ReleaseHeldBulkTransfer approves the review a held bulk transfer waits for
+// and executes it like ExecuteStagedBulkTransfer. It is screened and scored
+// again: a block still stops it, and a review it has not passed yet holds it
+// again. A bulk transfer that fails for good is dropped; one that fails on a
+// transient error stays held.
+func (s Service) ReleaseHeldBulkTransfer(ctx context.Context, heldBulkTransferID int64, reason string) (BulkTransferResult, error) {
+	ctx, span := tracer.Start(ctx, "ReleaseHeldBulkTransfer", trace.WithAttributes(
+		attribute.Int64("held_bulk_transfer.id", heldBulkTransferID),
+	))
+	defer span.End()
+
+	result, err := s.releaseHeldBulkTransfer(ctx, heldBulkTransferID, reason)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return result, err
+}
+
+func (s Service) releaseHeldBulkTransfer(ctx context.Context, heldBulkTransferID int64, reason string) (BulkTransferResult, error) {
+	var held HeldBulkTransfer
+	var bulkTransfer BulkTransfer
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		var err error
+		held, err = r.GetHeldBulkTransfer(ctx, heldBulkTransferID)
+		if err != nil {
+			return err
+		}
+
+		bulkTransfer, err = r.GetStagedBulkTransfer(ctx, heldBulkTransferID)
+		if err != nil {
+			return err
+		}
+		bulkTransfer.ApprovedReviews = append(bulkTransfer.ApprovedReviews, held.Review)
+
+		released := NewAuditRecord(heldContext(ctx, held), AuditEventBulkTransferReleased, Account{}, 0)
+		released.AmountCents = held.TotalCents
+		released.TransferCount = held.TransferCount
+		released.Reason = fmt.Sprintf("released held bulk transfer %d after %s review: %s", heldBulkTransferID, held.Review, reason)
+		return r.AppendAuditRecord(ctx, released)
+	})
+	if err != nil {
+		return BulkTransferResult{}, fmt.Errorf("failed to release held bulk transfer: %w", err)
+	}
+
+	result, err := s.executeStagedTransfers(heldContext(ctx, held), bulkTransfer, heldBulkTransferID)
+	if err != nil {
+		if isRejection(err) {
+			if discardErr := s.DiscardStagedBulkTransfer(ctx, heldBulkTransferID); discardErr != nil {
+				return BulkTransferResult{}, fmt.Errorf("%w (%w)", err, discardErr)
+			}
+		}
+		return BulkTransferResult{}, err
+	}
+
+	return result, nil
+}
+
+// heldContext carries the request ID of the request that submitted held, so
+// that its release or rejection is traced back to it.
+func heldContext(ctx context.Context, held HeldBulkTransfer) context.Context {
+	if held.RequestID == "" {
+		return ctx
+	}
+
+	return WithRequestID(ctx, held.RequestID)
+}
+
+// RejectHeldBulkTransfer drops a held bulk transfer without executing it and
+// returns it.
+func (s Service) RejectHeldBulkTransfer(ctx context.Context, heldBulkTransferID int64, reason string) (HeldBulkTransfer, error) {
+	var held HeldBulkTransfer
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		var err error
+		held, err = r.GetHeldBulkTransfer(ctx, heldBulkTransferID)
+		if err != nil {
+			return err
+		}
+
+		if err = r.DeleteStagedBulkTransfer(ctx, heldBulkTransferID); err != nil {
+			return err
+		}
+
+		rejected := NewAuditRecord(heldContext(ctx, held), AuditEventBulkTransferRejected, Account{}, 0)
+		rejected.AmountCents = held.TotalCents
+		rejected.TransferCount = held.TransferCount
+		rejected.Reason = fmt.Sprintf("rejected held bulk transfer %d after %s review: %s", heldBulkTransferID, held.Review, reason)
+		return r.AppendAuditRecord(ctx, rejected)
+	})
+	if err != nil {
+		return HeldBulkTransfer{}, fmt.Errorf("failed to reject held bulk transfer: %w", err)
+	}
+
+	return held, nil
+}
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_ReleaseHeldBulkTransfer(t *testing.T) {
+	t.Parallel()
+
+	staged := BulkTransfer{
+		OrganizationBIC:  "OIVUSCLQXXX",
+		OrganizationIBAN: "FR10474608000002006107XXXXX",
+		ExecutionMode:    ExecutionModeAllOrNothing,
+	}
+	held := HeldBulkTransfer{
+		ID:            7,
+		RequestID:     "req-1",
+		TransferCount: 1,
+		TotalCents:    1450,
+		Review:        ReviewKindSanctions,
+		Reason:        "sanctions screening review: 1 hit(s)",
+	}
+	transfers := []StagedTransfer{
+		{Position: 0, Transfer: Transfer{CounterpartyName: "Ivan Sanctionedov", CounterpartyIBAN: "EE383680981021245685", AmountCents: 1450, Currency: "EUR"}},
+	}
+
+	// atomic expects one Atomic call whose transaction is set up by setup.
+	atomic := func(t *testing.T, m *MockAccountRepository, setup func(*MockAccountRepository)) *gomock.Call {
+		return m.EXPECT().
+			Atomic(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+				txRepo := NewMockAccountRepository(gomock.NewController(t))
+				setup(txRepo)
+				return cb(txRepo)
+			})
+	}
+
+	// release expects the transaction approving the review and the one
+	// loading the staged transfers.
+	release := func(t *testing.T, m *MockAccountRepository) *gomock.Call {
+		approve := atomic(t, m, func(r *MockAccountRepository) {
+			r.EXPECT().GetHeldBulkTransfer(gomock.Any(), int64(7)).Return(held, nil)
+			r.EXPECT().GetStagedBulkTransfer(gomock.Any(), int64(7)).Return(staged, nil)
+			r.EXPECT().AppendAuditRecord(gomock.Any(), AuditRecord{
+				EventType:     AuditEventBulkTransferReleased,
+				RequestID:     "req-1",
+				AmountCents:   1450,
+				TransferCount: 1,
+				Reason:        "released held bulk transfer 7 after sanctions review: name is a homonym",
+			}).Return(nil)
+		})
+		return atomic(t, m, func(r *MockAccountRepository) {
+			r.EXPECT().ListStagedTransfers(gomock.Any(), int64(7), -1, stagedChunkSize).Return(transfers, nil)
+		}).After(approve)
+	}
+
+	tests := []struct {
+		name           string
+		decision       Decision
+		mockSetup      func(t *testing.T, m *MockAccountRepository)
+		expectedResult BulkTransferResult
+		expectedError  error
+	}{
+		{
+			name:     "approved_review_no_longer_holds_the_bulk_transfer",
+			decision: DecisionReview,
+			mockSetup: func(t *testing.T, m *MockAccountRepository) {
+				atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().GetAccountByID(gomock.Any(), staged.OrganizationIBAN, staged.OrganizationBIC).Return(Account{ID: 1, BalanceCents: 10000}, nil)
+					r.EXPECT().GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).Return(AccountHistory{}, nil)
+					r.EXPECT().UpdateBalance(gomock.Any(), Account{ID: 1, BalanceCents: 8550}).Return(nil)
+					// The executed bulk transfer keeps the request that submitted it.
+					r.EXPECT().AddBulkTransfer(gomock.Any(), int64(1), gomock.Any()).DoAndReturn(func(_ context.Context, _ int64, bulkTransfer BulkTransfer) (int64, error) {
+						require.Equal(t, "req-1", bulkTransfer.RequestID)
+						return 42, nil
+					})
+					r.EXPECT().AddTransfers(gomock.Any(), gomock.Len(1)).Return(nil)
+					r.EXPECT().AppendAuditRecord(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+					r.EXPECT().DeleteStagedBulkTransfer(gomock.Any(), int64(7)).Return(nil)
+				}).After(release(t, m))
+			},
+			expectedResult: BulkTransferResult{
+				BulkTransferID: 42,
+				ExecutionMode:  ExecutionModeAllOrNothing,
+				Transfers:      []TransferResult{{Index: 0, Status: TransferAccepted}},
+			},
+		},
+		{
+			name:     "block_still_rejects_and_discards_the_bulk_transfer",
+			decision: DecisionBlock,
+			mockSetup: func(t *testing.T, m *MockAccountRepository) {
+				audit := atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().AppendAuditRecord(gomock.Any(), gomock.Any()).Return(nil)
+				}).After(release(t, m))
+				atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().DeleteStagedBulkTransfer(gomock.Any(), int64(7)).Return(nil)
+				}).After(audit)
+			},
+			expectedError: &SanctionsError{Decision: DecisionBlock},
+		},
+		{
+			name: "bulk_transfer_not_held",
+			mockSetup: func(t *testing.T, m *MockAccountRepository) {
+				atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().GetHeldBulkTransfer(gomock.Any(), int64(7)).Return(HeldBulkTransfer{}, ErrHeldBulkTransferNotFound)
+				})
+			},
+			expectedError: ErrHeldBulkTransferNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockRepo := NewMockAccountRepository(ctrl)
+			tt.mockSetup(t, mockRepo)
+
+			mockScreener := NewMockScreener(ctrl)
+			mockScreener.EXPECT().
+				Screen(gomock.Any(), gomock.Any()).
+				Return(ScreeningResult{Decision: tt.decision}, nil).
+				AnyTimes()
+
+			service := NewService(mockRepo, mockScreener, NewFraudEngine(nil), Pricing{})
+			result, err := service.ReleaseHeldBulkTransfer(context.Background(), 7, "name is a homonym")
+
+			if tt.expectedError != nil {
+				require.ErrorContains(t, err, tt.expectedError.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestService_RejectHeldBulkTransfer(t *testing.T) {
+	t.Parallel()
+
+	held := HeldBulkTransfer{
+		ID:            7,
+		TransferCount: 1,
+		TotalCents:    1450,
+		Review:        ReviewKindSanctions,
+	}
+
+	ctrl := gomock.NewController(t)
+	mockRepo := NewMockAccountRepository(ctrl)
+	mockRepo.EXPECT().
+		Atomic(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+			txRepo := NewMockAccountRepository(gomock.NewController(t))
+			txRepo.EXPECT().GetHeldBulkTransfer(gomock.Any(), int64(7)).Return(held, nil)
+			txRepo.EXPECT().DeleteStagedBulkTransfer(gomock.Any(), int64(7)).Return(nil)
+			txRepo.EXPECT().AppendAuditRecord(gomock.Any(), AuditRecord{
+				EventType:     AuditEventBulkTransferRejected,
+				AmountCents:   1450,
+				TransferCount: 1,
+				Reason:        "rejected held bulk transfer 7 after sanctions review: confirmed match",
+			}).Return(nil)
+			return cb(txRepo)
+		})
+
+	service := NewService(mockRepo, NewMockScreener(ctrl), NewFraudEngine(nil), Pricing{})
+	rejected, err := service.RejectHeldBulkTransfer(context.Background(), 7, "confirmed match")
+	require.NoError(t, err)
+	require.Equal(t, held, rejected)
+}
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"context"
+	"fmt"
+)
+
+//go:generate go tool go.uber.org/mock/mockgen -source=screening.go -destination=screening_mock.go -package=core
+
+type Decision string
+
+const (
+	DecisionAllow  Decision = "allow"
+	DecisionReview Decision = "review"
+	DecisionBlock  Decision = "block"
+)
+
+type SanctionsHit struct {
+	TransferIndex int
+	MatchedOn     string // "name" or "iban"
+	ListEntry     string
+	Score         float64
+}
+
+type ScreeningResult struct {
+	Decision Decision
+	Hits     []SanctionsHit
+}
+
+type Screener interface {
+	Screen(ctx context.Context, transfers []Transfer) (ScreeningResult, error)
+}
+
+// SanctionsError stops a bulk transfer whose counterparties matched the
+// sanctions list. Decision tells whether the batch is blocked or held for a
+// compliance review.
+type SanctionsError struct {
+	Decision Decision
+	Hits     []SanctionsHit
+}
+
+func (e *SanctionsError) Error() string {
+	return fmt.Sprintf("sanctions screening %s: %d hit(s)", e.Decision, len(e.Hits))
+}
+
+func (e *SanctionsError) Unwrap() error {
+	return ErrSanctionsHit
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: screening.go
+//
+// Generated by this command:
+//
+//	mockgen -source=screening.go -destination=screening_mock.go -package=core
+//
+
+// Package core is a generated GoMock package.
+package core
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScreener is a mock of Screener interface.
+type MockScreener struct {
+	ctrl     *gomock.Controller
+	recorder *MockScreenerMockRecorder
+	isgomock struct{}
+}
+
+// MockScreenerMockRecorder is the mock recorder for MockScreener.
+type MockScreenerMockRecorder struct {
+	mock *MockScreener
+}
+
+// NewMockScreener creates a new mock instance.
+func NewMockScreener(ctrl *gomock.Controller) *MockScreener {
+	mock := &MockScreener{ctrl: ctrl}
+	mock.recorder = &MockScreenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScreener) EXPECT() *MockScreenerMockRecorder {
+	return m.recorder
+}
+
+// Screen mocks base method.
+func (m *MockScreener) Screen(ctx context.Context, transfers []Transfer) (ScreeningResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Screen", ctx, transfers)
+	ret0, _ := ret[0].(ScreeningResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Screen indicates an expected call of Screen.
+func (mr *MockScreenerMockRecorder) Screen(ctx, transfers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Screen", reflect.TypeOf((*MockScreener)(nil).Screen), ctx, transfers)
+}
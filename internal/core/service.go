@@ -2,45 +2,540 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("payment/internal/core")
+
 type Service struct {
 	accountRepository AccountRepository
+	screener          Screener
+	fraudEngine       *FraudEngine
+	pricing           Pricing
+	admission         Admission
 }
 
-func NewService(accountRepo AccountRepository) Service {
+func NewService(accountRepo AccountRepository, screener Screener, fraudEngine *FraudEngine, pricing Pricing) Service {
 	return Service{
 		accountRepository: accountRepo,
+		screener:          screener,
+		fraudEngine:       fraudEngine,
+		pricing:           pricing,
+	}
+}
+
+// WithAdmission returns a copy of the service that submits every bulk transfer
+// to admission before executing it. Dry runs are not submitted.
+func (s Service) WithAdmission(admission Admission) Service {
+	s.admission = admission
+	return s
+}
+
+// maxConflictRetries bounds how many times a transaction is rerun after one of
+// its balance updates lost to a concurrent transaction.
+const maxConflictRetries = 3
+
+// atomic runs callback in a transaction like AccountRepository.Atomic. When a
+// balance update hits a version conflict, the whole callback is run again so
+// that it reads the accounts afresh.
+func (s Service) atomic(ctx context.Context, callback func(AccountRepository) error) error {
+	err := s.accountRepository.Atomic(ctx, callback)
+	for attempt := 1; attempt <= maxConflictRetries && errors.Is(err, ErrVersionConflict); attempt++ {
+		trace.SpanFromContext(ctx).AddEvent("retrying after version conflict", trace.WithAttributes(
+			attribute.Int("retry.attempt", attempt),
+		))
+		err = s.accountRepository.Atomic(ctx, callback)
+	}
+
+	return err
+}
+
+func (s Service) ProcessBulkTransfer(ctx context.Context, bulkTransfer BulkTransfer) (BulkTransferResult, error) {
+	ctx, span := tracer.Start(ctx, "ProcessBulkTransfer", trace.WithAttributes(
+		attribute.Int("bulk_transfer.transfer_count", len(bulkTransfer.Transfers)),
+		attribute.Int64("bulk_transfer.total_cents", bulkTransfer.TotalAmount()),
+		attribute.String("bulk_transfer.execution_mode", string(bulkTransfer.ExecutionMode)),
+	))
+	defer span.End()
+
+	result, err := s.processBulkTransfer(ctx, bulkTransfer, 0)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 	}
+
+	return result, err
 }
 
-func (s Service) ProcessBulkTransfer(ctx context.Context, bulkTransfer BulkTransfer) error {
+// processBulkTransfer executes bulkTransfer. A non-zero stagedBulkTransferID
+// is deleted in the same transaction, so that a staged batch executes once.
+func (s Service) processBulkTransfer(ctx context.Context, bulkTransfer BulkTransfer, stagedBulkTransferID int64) (BulkTransferResult, error) {
 	if len(bulkTransfer.Transfers) == 0 {
-		return nil
+		return BulkTransferResult{}, nil
+	}
+
+	bulkTransfer.RequestID = RequestIDFromContext(ctx)
+	if bulkTransfer.ExecutionMode == "" {
+		bulkTransfer.ExecutionMode = ExecutionModeAllOrNothing
+	}
+
+	done, err := s.admit(ctx, bulkTransfer)
+	if err != nil {
+		return BulkTransferResult{}, err
+	}
+
+	result, err := s.executeAdmitted(ctx, bulkTransfer, stagedBulkTransferID)
+	done(result, err)
+
+	return result, err
+}
+
+func (s Service) executeAdmitted(ctx context.Context, bulkTransfer BulkTransfer, stagedBulkTransferID int64) (BulkTransferResult, error) {
+	// Screening runs before the transaction so that matching does not hold the
+	// write lock.
+	if err := s.screen(ctx, bulkTransfer); err != nil {
+		return BulkTransferResult{}, s.reject(ctx, bulkTransfer, stagedBulkTransferID, Account{}, err)
 	}
 
+	var account Account
+	var result BulkTransferResult
 	transactionCallback := func(r AccountRepository) error {
-		account, err := r.GetAccountByID(ctx, bulkTransfer.OrganizationIBAN, bulkTransfer.OrganizationBIC)
+		debtors, err := lockDebtors(ctx, r, bulkTransfer)
 		if err != nil {
 			return err
 		}
 
-		if err = account.Debit(bulkTransfer.TotalAmount()); err != nil {
+		owner := debtors.find(bulkTransfer.OrganizationKey())
+		account = *owner
+		if bulkTransfer.HoldID != 0 {
+			if err = captureHold(ctx, r, owner, bulkTransfer.HoldID); err != nil {
+				return err
+			}
+		}
+
+		result, err = s.execute(ctx, r, debtors, bulkTransfer)
+		if err != nil || stagedBulkTransferID == 0 {
 			return err
 		}
 
-		if err = r.UpdateBalance(ctx, account); err != nil {
+		return r.DeleteStagedBulkTransfer(ctx, stagedBulkTransferID)
+	}
+
+	err := s.atomic(ctx, transactionCallback)
+	if err != nil {
+		return BulkTransferResult{}, s.reject(ctx, bulkTransfer, stagedBulkTransferID, account, err)
+	}
+
+	return result, nil
+}
+
+// ValidateBulkTransfer runs a bulk transfer inside a transaction that is
+// always rolled back and reports what executing it would do. Business rule
+// failures are returned as violations, not errors, and are not audited, except
+// for sanctions hits: compliance must see every batch that matched the list.
+func (s Service) ValidateBulkTransfer(ctx context.Context, bulkTransfer BulkTransfer) (BulkTransferQuote, error) {
+	ctx, span := tracer.Start(ctx, "ValidateBulkTransfer", trace.WithAttributes(
+		attribute.Int("bulk_transfer.transfer_count", len(bulkTransfer.Transfers)),
+	))
+	defer span.End()
+
+	if bulkTransfer.ExecutionMode == "" {
+		bulkTransfer.ExecutionMode = ExecutionModeAllOrNothing
+	}
+
+	screening, err := s.screener.Screen(ctx, bulkTransfer.Transfers)
+	if err != nil {
+		return BulkTransferQuote{}, fmt.Errorf("failed to screen bulk transfer: %w", err)
+	}
+	var screeningViolations []Violation
+	if screening.Decision != DecisionAllow {
+		if err = s.auditDryRunScreening(ctx, bulkTransfer, screening); err != nil {
+			return BulkTransferQuote{}, fmt.Errorf("failed to audit dry run screening: %w", err)
+		}
+		screeningViolations = append(screeningViolations, sanctionsViolation(screening))
+	}
+
+	var quote BulkTransferQuote
+	err = s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		var err error
+		quote, err = s.dryRun(ctx, r, bulkTransfer)
+		if err != nil {
 			return err
 		}
 
-		transfers := make([]Transfer, len(bulkTransfer.Transfers))
-		for i, transfer := range bulkTransfer.Transfers {
-			transfer.BankAccountID = account.ID
-			transfers[i] = transfer
+		return errDryRun
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return BulkTransferQuote{}, err
+	}
+
+	quote.Violations = append(screeningViolations, quote.Violations...)
+
+	return quote, nil
+}
+
+// dryRun executes bulkTransfer within a transaction that the caller rolls
+// back, and quotes the outcome. The quote is built afresh on every call, so
+// that a transaction retried by the repository reports it once.
+func (s Service) dryRun(ctx context.Context, r AccountRepository, bulkTransfer BulkTransfer) (BulkTransferQuote, error) {
+	quote := BulkTransferQuote{
+		TransferCount:    len(bulkTransfer.Transfers),
+		TotalAmountCents: bulkTransfer.TotalAmount(),
+	}
+
+	debtors, err := lockDebtors(ctx, r, bulkTransfer)
+	if err != nil {
+		return BulkTransferQuote{}, err
+	}
+
+	if bulkTransfer.HoldID != 0 {
+		err = captureHold(ctx, r, debtors.find(bulkTransfer.OrganizationKey()), bulkTransfer.HoldID)
+		switch {
+		case errors.Is(err, ErrHoldNotPending):
+			quote.Violations = append(quote.Violations, Violation{
+				TransferIndex: BatchViolation,
+				Code:          ViolationHoldNotPending,
+				Message:       "hold is no longer pending",
+			})
+		case err != nil:
+			return BulkTransferQuote{}, err
+		}
+	}
+
+	groups := groupByDebtor(bulkTransfer, debtors)
+	groupFees := make([]int64, len(groups))
+	for i, group := range groups {
+		fees, err := s.transferFees(ctx, r, *group.account, group.bulkTransfer)
+		if err != nil {
+			return BulkTransferQuote{}, err
 		}
+		for _, fee := range fees {
+			groupFees[i] += fee
+		}
+		quote.FeesCents += groupFees[i]
+	}
+
+	for _, account := range debtors.accounts {
+		quote.BalanceBeforeCents += account.BalanceCents
+	}
+	quote.BalanceAfterCents = quote.BalanceBeforeCents - quote.TotalAmountCents - quote.FeesCents
+
+	result, err := s.execute(ctx, r, debtors, bulkTransfer)
+
+	var fraudErr *FraudError
+	switch {
+	case err == nil, errors.Is(err, ErrInsufficientFunds):
+	case errors.Is(err, ErrAccountFrozen):
+		quote.Violations = append(quote.Violations, Violation{
+			TransferIndex: BatchViolation,
+			Code:          ViolationAccountFrozen,
+			Message:       "account is frozen",
+		})
+	case errors.As(err, &fraudErr):
+		quote.Violations = append(quote.Violations, fraudViolation(fraudErr))
+	default:
+		return BulkTransferQuote{}, err
+	}
+
+	if bulkTransfer.ExecutionMode == ExecutionModePartial && err == nil {
+		quote.Violations = append(quote.Violations, rejectedTransferViolations(result)...)
+		quote.FeesCents = result.FeesCents()
+		quote.BalanceAfterCents = quote.BalanceBeforeCents - acceptedAmount(bulkTransfer, result) - quote.FeesCents
+		return quote, nil
+	}
+
+	// Freezes and fraud rules stop the execution before the funds check.
+	for i, group := range groups {
+		if group.account.HasSufficientFunds(group.bulkTransfer.TotalAmount() + groupFees[i]) {
+			continue
+		}
+
+		message := "balance does not cover the bulk transfer"
+		if len(groups) > 1 {
+			message = fmt.Sprintf("balance of %s does not cover its transfers", group.key.IBAN)
+		}
+		quote.Violations = append(quote.Violations, Violation{
+			TransferIndex: BatchViolation,
+			Code:          ViolationInsufficientFunds,
+			Message:       message,
+		})
+	}
+
+	return quote, nil
+}
+
+// errDryRun rolls back the transaction of a dry run.
+var errDryRun = errors.New("dry run")
+
+// execute applies a bulk transfer to its debtor accounts within a
+// transaction. Each account is checked and debited for its own transfers.
+func (s Service) execute(ctx context.Context, r AccountRepository, debtors debtorAccounts, bulkTransfer BulkTransfer) (BulkTransferResult, error) {
+	debtors = debtors.clone()
+	for _, account := range debtors.accounts {
+		if account.Frozen {
+			return BulkTransferResult{}, ErrAccountFrozen
+		}
+	}
+
+	groups := groupByDebtor(bulkTransfer, debtors)
+	for _, group := range groups {
+		history, err := r.GetAccountHistory(ctx, group.account.ID, group.bulkTransfer.CounterpartyIBANs())
+		if err != nil {
+			return BulkTransferResult{}, err
+		}
+
+		// A fraud review the bulk transfer passed no longer stops it; blocks
+		// still do.
+		assessment := s.fraudEngine.Evaluate(FraudInput{
+			BulkTransfer: group.bulkTransfer,
+			Account:      *group.account,
+			History:      history,
+		})
+		if assessment.Decision == DecisionBlock || assessment.Decision == DecisionReview && !bulkTransfer.approved(ReviewKindFraud) {
+			return BulkTransferResult{}, &FraudError{Decision: assessment.Decision, Reasons: assessment.Reasons}
+		}
+	}
 
-		return r.AddTransfers(ctx, transfers)
+	balancesBefore := make([]int64, len(debtors.accounts))
+	for i, account := range debtors.accounts {
+		balancesBefore[i] = account.BalanceCents
 	}
 
-	return s.accountRepository.Atomic(ctx, transactionCallback)
+	results := make([]TransferResult, len(bulkTransfer.Transfers))
+	// debitedFrom holds the account of every accepted transfer.
+	debitedFrom := make([]*Account, len(bulkTransfer.Transfers))
+	// groupFees holds the fees of the accepted transfers of every group.
+	groupFees := make([]int64, len(groups))
+	for i, group := range groups {
+		fees, err := s.transferFees(ctx, r, *group.account, group.bulkTransfer)
+		if err != nil {
+			return BulkTransferResult{}, err
+		}
+
+		groupResults, err := allocate(group.account, group.bulkTransfer, fees)
+		if err != nil {
+			return BulkTransferResult{}, err
+		}
+
+		for j, result := range groupResults {
+			result.Index = group.indexes[j]
+			results[result.Index] = result
+			if result.Status == TransferAccepted {
+				debitedFrom[result.Index] = group.account
+				groupFees[i] += result.FeeCents
+			}
+		}
+	}
+
+	accepted := bulkTransfer
+	accepted.Transfers = make([]Transfer, 0, len(bulkTransfer.Transfers))
+	for i, transfer := range bulkTransfer.Transfers {
+		if debitedFrom[i] != nil {
+			accepted.Transfers = append(accepted.Transfers, transfer)
+		}
+	}
+	if len(accepted.Transfers) == 0 {
+		return BulkTransferResult{}, ErrInsufficientFunds
+	}
+
+	// Balances are written in lock order too.
+	for i, account := range debtors.accounts {
+		if account.BalanceCents == balancesBefore[i] {
+			continue
+		}
+		if err := r.UpdateBalance(ctx, account); err != nil {
+			return BulkTransferResult{}, err
+		}
+		debtors.accounts[i].Version++
+	}
+
+	owner := debtors.index(bulkTransfer.OrganizationKey())
+	bulkTransferID, err := r.AddBulkTransfer(ctx, debtors.accounts[owner].ID, accepted)
+	if err != nil {
+		return BulkTransferResult{}, err
+	}
+
+	transfers := make([]Transfer, 0, len(accepted.Transfers))
+	for i, transfer := range bulkTransfer.Transfers {
+		if debitedFrom[i] == nil {
+			continue
+		}
+		transfer.BankAccountID = debitedFrom[i].ID
+		transfer.BulkTransferID = bulkTransferID
+		transfers = append(transfers, transfer)
+	}
+
+	if err = r.AddTransfers(ctx, transfers); err != nil {
+		return BulkTransferResult{}, err
+	}
+
+	for i, group := range groups {
+		if groupFees[i] == 0 {
+			continue
+		}
+		if err = r.AddTransaction(ctx, feeTransaction(*group.account, bulkTransferID, groupFees[i])); err != nil {
+			return BulkTransferResult{}, err
+		}
+	}
+
+	for i, account := range debtors.accounts {
+		if account.BalanceCents == balancesBefore[i] {
+			continue
+		}
+
+		balanceChanged := NewAuditRecord(ctx, AuditEventBalanceChanged, account, balancesBefore[i])
+		balanceChanged.AmountCents = account.BalanceCents - balancesBefore[i]
+		if err = r.AppendAuditRecord(ctx, balanceChanged); err != nil {
+			return BulkTransferResult{}, err
+		}
+
+		if balancesBefore[i] >= 0 && account.BalanceCents < 0 {
+			overdraftEntered := NewAuditRecord(ctx, AuditEventOverdraftEntered, account, balancesBefore[i])
+			overdraftEntered.AmountCents = account.OverdraftUsedCents()
+			if err = r.AppendAuditRecord(ctx, overdraftEntered); err != nil {
+				return BulkTransferResult{}, err
+			}
+		}
+	}
+
+	acceptedRecord := NewAuditRecord(ctx, AuditEventBulkTransferAccepted, debtors.accounts[owner], balancesBefore[owner])
+	acceptedRecord.AmountCents = accepted.TotalAmount()
+	acceptedRecord.TransferCount = len(accepted.Transfers)
+	if err = r.AppendAuditRecord(ctx, acceptedRecord); err != nil {
+		return BulkTransferResult{}, err
+	}
+
+	return BulkTransferResult{
+		BulkTransferID: bulkTransferID,
+		ExecutionMode:  bulkTransfer.ExecutionMode,
+		Transfers:      results,
+	}, nil
+}
+
+// allocate debits account with the transfers of bulkTransfer that can be
+// executed according to the execution mode, each with its fee. In partial mode
+// the transfers that do not fit are rejected rather than failing the
+// allocation.
+func allocate(account *Account, bulkTransfer BulkTransfer, fees []int64) ([]TransferResult, error) {
+	results := make([]TransferResult, len(bulkTransfer.Transfers))
+
+	if bulkTransfer.ExecutionMode != ExecutionModePartial {
+		total := bulkTransfer.TotalAmount()
+		for _, fee := range fees {
+			total += fee
+		}
+		if err := account.Debit(total); err != nil {
+			return nil, err
+		}
+
+		for i := range bulkTransfer.Transfers {
+			results[i] = TransferResult{Index: i, Status: TransferAccepted, FeeCents: fees[i]}
+		}
+		return results, nil
+	}
+
+	// Transfers are accepted strictly in order: once one does not fit, the
+	// following ones are rejected too so that the batch order is a priority.
+	exhausted := false
+	for i, transfer := range bulkTransfer.Transfers {
+		if !exhausted && account.Debit(transfer.AmountCents+fees[i]) == nil {
+			results[i] = TransferResult{Index: i, Status: TransferAccepted, FeeCents: fees[i]}
+			continue
+		}
+
+		exhausted = true
+		results[i] = TransferResult{Index: i, Status: TransferRejected, Reason: RejectionInsufficientFunds}
+	}
+
+	return results, nil
+}
+
+func acceptedAmount(bulkTransfer BulkTransfer, result BulkTransferResult) int64 {
+	var total int64
+	for _, transfer := range result.Transfers {
+		if transfer.Status == TransferAccepted {
+			total += bulkTransfer.Transfers[transfer.Index].AmountCents
+		}
+	}
+
+	return total
+}
+
+// screen returns a *SanctionsError when the counterparties of bulkTransfer
+// match the sanctions list, unless the match only asks for a review that the
+// bulk transfer passed.
+func (s Service) screen(ctx context.Context, bulkTransfer BulkTransfer) error {
+	result, err := s.screener.Screen(ctx, bulkTransfer.Transfers)
+	if err != nil {
+		return fmt.Errorf("failed to screen bulk transfer: %w", err)
+	}
+
+	if result.Decision == DecisionAllow || result.Decision == DecisionReview && bulkTransfer.approved(ReviewKindSanctions) {
+		return nil
+	}
+
+	return &SanctionsError{
+		Decision: result.Decision,
+		Hits:     result.Hits,
+	}
+}
+
+// reject records business rejections in the audit log and returns err. A
+// bulk transfer sent to a review is held instead, and a *HeldError returned.
+func (s Service) reject(ctx context.Context, bulkTransfer BulkTransfer, stagedBulkTransferID int64, account Account, err error) error {
+	if review := heldFor(err); review != "" {
+		return s.hold(ctx, bulkTransfer, stagedBulkTransferID, account, review, err)
+	}
+
+	if isRejection(err) {
+		// A rejected bulk transfer never commits, so its outcome is recorded in
+		// a transaction of its own.
+		if auditErr := s.auditRejection(ctx, bulkTransfer, account, err); auditErr != nil {
+			return fmt.Errorf("failed to audit rejected bulk transfer: %w", auditErr)
+		}
+	}
+
+	return err
+}
+
+// auditDryRunScreening records a dry run stopped by sanctions screening in a
+// transaction of its own, since the dry run itself is rolled back.
+func (s Service) auditDryRunScreening(ctx context.Context, bulkTransfer BulkTransfer, screening ScreeningResult) error {
+	record := NewAuditRecord(ctx, AuditEventDryRunScreeningHit, Account{}, 0)
+	record.AmountCents = bulkTransfer.TotalAmount()
+	record.TransferCount = len(bulkTransfer.Transfers)
+	record.Reason = (&SanctionsError{Decision: screening.Decision, Hits: screening.Hits}).Error()
+
+	return s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		return r.AppendAuditRecord(ctx, record)
+	})
+}
+
+func (s Service) auditRejection(ctx context.Context, bulkTransfer BulkTransfer, account Account, reason error) error {
+	rejected := NewAuditRecord(ctx, AuditEventBulkTransferRejected, account, account.BalanceCents)
+	rejected.AmountCents = bulkTransfer.TotalAmount()
+	rejected.TransferCount = len(bulkTransfer.Transfers)
+	rejected.Reason = reason.Error()
+
+	return s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		return r.AppendAuditRecord(ctx, rejected)
+	})
+}
+
+func isRejection(err error) bool {
+	return errors.Is(err, ErrInsufficientFunds) ||
+		errors.Is(err, ErrAccountNotFound) ||
+		errors.Is(err, ErrAccountFrozen) ||
+		errors.Is(err, ErrHoldNotFound) ||
+		errors.Is(err, ErrHoldNotPending) ||
+		errors.Is(err, ErrSanctionsHit) ||
+		errors.Is(err, ErrFraudSuspected)
 }
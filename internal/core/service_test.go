@@ -13,10 +13,13 @@ func TestService_ProcessBulkTransfer(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name          string
-		bulkTransfer  BulkTransfer
-		mockSetup     func(*MockAccountRepository)
-		expectedError error
+		name           string
+		bulkTransfer   BulkTransfer
+		mockSetup      func(*MockAccountRepository)
+		screenerSetup  func(*MockScreener)
+		fraudRules     []FraudRuleSpec
+		expectedResult BulkTransferResult
+		expectedError  error
 	}{
 		{
 			name: "successful bulk transfer",
@@ -44,7 +47,7 @@ func TestService_ProcessBulkTransfer(t *testing.T) {
 			},
 			mockSetup: func(m *MockAccountRepository) {
 				m.EXPECT().
-					Atomic(context.Background(), gomock.Any()).
+					Atomic(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
 						ctrl := gomock.NewController(t)
 						mockRepo := NewMockAccountRepository(ctrl)
@@ -57,6 +60,7 @@ func TestService_ProcessBulkTransfer(t *testing.T) {
 						expectedTransfers := []Transfer{
 							{
 								BankAccountID:    1, // bank_account_id set by service
+								BulkTransferID:   42,
 								CounterpartyName: "Bip Bip",
 								CounterpartyIBAN: "EE383680981021245685",
 								CounterpartyBIC:  "CRLYFRPPTOU",
@@ -66,6 +70,7 @@ func TestService_ProcessBulkTransfer(t *testing.T) {
 							},
 							{
 								BankAccountID:    1, // bank_account_id set by service
+								BulkTransferID:   42,
 								CounterpartyName: "Bugs Bunny",
 								CounterpartyIBAN: "FR0010009380540930414023042",
 								CounterpartyBIC:  "RNJZNTMC",
@@ -81,23 +86,247 @@ func TestService_ProcessBulkTransfer(t *testing.T) {
 						}
 
 						mockRepo.EXPECT().
-							GetAccountByID(context.Background(), "FR10474608000002006107XXXXX", "OIVUSCLQXXX").
+							GetAccountByID(gomock.Any(), "FR10474608000002006107XXXXX", "OIVUSCLQXXX").
 							Return(account, nil)
 
 						mockRepo.EXPECT().
-							UpdateBalance(context.Background(), expectedAccount).
+							GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).
+							Return(AccountHistory{}, nil)
+
+						mockRepo.EXPECT().
+							UpdateBalance(gomock.Any(), expectedAccount).
+							Return(nil)
+
+						mockRepo.EXPECT().
+							AddBulkTransfer(gomock.Any(), int64(1), gomock.Any()).
+							Return(int64(42), nil)
+
+						mockRepo.EXPECT().
+							AddTransfers(gomock.Any(), expectedTransfers).
+							Return(nil)
+
+						mockRepo.EXPECT().
+							AppendAuditRecord(gomock.Any(), AuditRecord{
+								EventType:          AuditEventBalanceChanged,
+								AccountID:          1,
+								BalanceBeforeCents: 10000000,
+								BalanceAfterCents:  9898650,
+								AmountCents:        -101350,
+							}).
 							Return(nil)
 
 						mockRepo.EXPECT().
-							AddTransfers(context.Background(), expectedTransfers).
+							AppendAuditRecord(gomock.Any(), AuditRecord{
+								EventType:          AuditEventBulkTransferAccepted,
+								AccountID:          1,
+								BalanceBeforeCents: 10000000,
+								BalanceAfterCents:  9898650,
+								AmountCents:        101350,
+								TransferCount:      2,
+							}).
 							Return(nil)
 
 						return cb(mockRepo)
 					}).
 					Times(1)
 			},
+			expectedResult: BulkTransferResult{
+				BulkTransferID: 42,
+				ExecutionMode:  ExecutionModeAllOrNothing,
+				Transfers: []TransferResult{
+					{Index: 0, Status: TransferAccepted},
+					{Index: 1, Status: TransferAccepted},
+				},
+			},
 			expectedError: nil,
 		},
+		{
+			name: "partial mode accepts transfers in order until funds run out",
+			bulkTransfer: BulkTransfer{
+				OrganizationBIC:  "OIVUSCLQXXX",
+				OrganizationIBAN: "FR10474608000002006107XXXXX",
+				ExecutionMode:    ExecutionModePartial,
+				Transfers: []Transfer{
+					{CounterpartyIBAN: "IBAN1", AmountCents: 600},
+					{CounterpartyIBAN: "IBAN2", AmountCents: 300},
+					{CounterpartyIBAN: "IBAN3", AmountCents: 200},
+					{CounterpartyIBAN: "IBAN4", AmountCents: 50},
+				},
+			},
+			mockSetup: func(m *MockAccountRepository) {
+				m.EXPECT().
+					Atomic(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+						ctrl := gomock.NewController(t)
+						mockRepo := NewMockAccountRepository(ctrl)
+
+						mockRepo.EXPECT().
+							GetAccountByID(gomock.Any(), "FR10474608000002006107XXXXX", "OIVUSCLQXXX").
+							Return(Account{ID: 1, BalanceCents: 1000}, nil)
+
+						mockRepo.EXPECT().
+							GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).
+							Return(AccountHistory{}, nil)
+
+						mockRepo.EXPECT().
+							UpdateBalance(gomock.Any(), Account{ID: 1, BalanceCents: 100}).
+							Return(nil)
+
+						// The 50 cents transfer would fit but comes after a rejected one.
+						mockRepo.EXPECT().
+							AddBulkTransfer(gomock.Any(), int64(1), BulkTransfer{
+								OrganizationBIC:  "OIVUSCLQXXX",
+								OrganizationIBAN: "FR10474608000002006107XXXXX",
+								ExecutionMode:    ExecutionModePartial,
+								Transfers: []Transfer{
+									{CounterpartyIBAN: "IBAN1", AmountCents: 600},
+									{CounterpartyIBAN: "IBAN2", AmountCents: 300},
+								},
+							}).
+							Return(int64(7), nil)
+
+						mockRepo.EXPECT().
+							AddTransfers(gomock.Any(), []Transfer{
+								{BankAccountID: 1, BulkTransferID: 7, CounterpartyIBAN: "IBAN1", AmountCents: 600},
+								{BankAccountID: 1, BulkTransferID: 7, CounterpartyIBAN: "IBAN2", AmountCents: 300},
+							}).
+							Return(nil)
+
+						mockRepo.EXPECT().
+							AppendAuditRecord(gomock.Any(), AuditRecord{
+								EventType:          AuditEventBalanceChanged,
+								AccountID:          1,
+								BalanceBeforeCents: 1000,
+								BalanceAfterCents:  100,
+								AmountCents:        -900,
+							}).
+							Return(nil)
+
+						mockRepo.EXPECT().
+							AppendAuditRecord(gomock.Any(), AuditRecord{
+								EventType:          AuditEventBulkTransferAccepted,
+								AccountID:          1,
+								BalanceBeforeCents: 1000,
+								BalanceAfterCents:  100,
+								AmountCents:        900,
+								TransferCount:      2,
+							}).
+							Return(nil)
+
+						return cb(mockRepo)
+					}).
+					Times(1)
+			},
+			expectedResult: BulkTransferResult{
+				BulkTransferID: 7,
+				ExecutionMode:  ExecutionModePartial,
+				Transfers: []TransferResult{
+					{Index: 0, Status: TransferAccepted},
+					{Index: 1, Status: TransferAccepted},
+					{Index: 2, Status: TransferRejected, Reason: RejectionInsufficientFunds},
+					{Index: 3, Status: TransferRejected, Reason: RejectionInsufficientFunds},
+				},
+			},
+		},
+		{
+			name: "partial mode without any affordable transfer is rejected",
+			bulkTransfer: BulkTransfer{
+				OrganizationBIC:  "OIVUSCLQXXX",
+				OrganizationIBAN: "FR10474608000002006107XXXXX",
+				ExecutionMode:    ExecutionModePartial,
+				Transfers: []Transfer{
+					{CounterpartyIBAN: "IBAN1", AmountCents: 600},
+				},
+			},
+			mockSetup: func(m *MockAccountRepository) {
+				m.EXPECT().
+					Atomic(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+						ctrl := gomock.NewController(t)
+						mockRepo := NewMockAccountRepository(ctrl)
+
+						mockRepo.EXPECT().
+							GetAccountByID(gomock.Any(), "FR10474608000002006107XXXXX", "OIVUSCLQXXX").
+							Return(Account{ID: 1, BalanceCents: 500}, nil)
+
+						mockRepo.EXPECT().
+							GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).
+							Return(AccountHistory{}, nil)
+
+						return cb(mockRepo)
+					}).
+					Times(1)
+
+				m.EXPECT().
+					Atomic(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+						ctrl := gomock.NewController(t)
+						mockRepo := NewMockAccountRepository(ctrl)
+
+						mockRepo.EXPECT().
+							AppendAuditRecord(gomock.Any(), gomock.Any()).
+							Return(nil)
+
+						return cb(mockRepo)
+					}).
+					Times(1)
+			},
+			expectedError: ErrInsufficientFunds,
+		},
+		{
+			name: "frozen account is rejected and audited",
+			bulkTransfer: BulkTransfer{
+				OrganizationBIC:  "OIVUSCLQXXX",
+				OrganizationIBAN: "FR10474608000002006107XXXXX",
+				Transfers: []Transfer{
+					{
+						CounterpartyName: "Bip Bip",
+						CounterpartyIBAN: "EE383680981021245685",
+						CounterpartyBIC:  "CRLYFRPPTOU",
+						AmountCents:      1450,
+						Currency:         "EUR",
+						Description:      "Test",
+					},
+				},
+			},
+			mockSetup: func(m *MockAccountRepository) {
+				m.EXPECT().
+					Atomic(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+						ctrl := gomock.NewController(t)
+						mockRepo := NewMockAccountRepository(ctrl)
+
+						mockRepo.EXPECT().
+							GetAccountByID(gomock.Any(), "FR10474608000002006107XXXXX", "OIVUSCLQXXX").
+							Return(Account{ID: 1, BalanceCents: 5000, Frozen: true}, nil)
+
+						return cb(mockRepo)
+					}).
+					Times(1)
+				m.EXPECT().
+					Atomic(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+						ctrl := gomock.NewController(t)
+						mockRepo := NewMockAccountRepository(ctrl)
+
+						mockRepo.EXPECT().
+							AppendAuditRecord(gomock.Any(), AuditRecord{
+								EventType:          AuditEventBulkTransferRejected,
+								AccountID:          1,
+								BalanceBeforeCents: 5000,
+								BalanceAfterCents:  5000,
+								AmountCents:        1450,
+								TransferCount:      1,
+								Reason:             ErrAccountFrozen.Error(),
+							}).
+							Return(nil)
+
+						return cb(mockRepo)
+					}).
+					Times(1)
+			},
+			expectedError: ErrAccountFrozen,
+		},
 		{
 			name: "empty transfer list returns nil",
 			bulkTransfer: BulkTransfer{
@@ -126,7 +355,7 @@ func TestService_ProcessBulkTransfer(t *testing.T) {
 			},
 			mockSetup: func(m *MockAccountRepository) {
 				m.EXPECT().
-					Atomic(context.Background(), gomock.Any()).
+					Atomic(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
 						ctrl := gomock.NewController(t)
 						mockRepo := NewMockAccountRepository(ctrl)
@@ -136,15 +365,288 @@ func TestService_ProcessBulkTransfer(t *testing.T) {
 							BalanceCents: 5000,
 						}
 						mockRepo.EXPECT().
-							GetAccountByID(context.Background(), "FR10474608000002006107XXXXX", "OIVUSCLQXXX").
+							GetAccountByID(gomock.Any(), "FR10474608000002006107XXXXX", "OIVUSCLQXXX").
 							Return(account, nil)
 
+						mockRepo.EXPECT().
+							GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).
+							Return(AccountHistory{}, nil)
+
+						return cb(mockRepo)
+					}).
+					Times(1)
+				m.EXPECT().
+					Atomic(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+						ctrl := gomock.NewController(t)
+						mockRepo := NewMockAccountRepository(ctrl)
+
+						mockRepo.EXPECT().
+							AppendAuditRecord(gomock.Any(), AuditRecord{
+								EventType:          AuditEventBulkTransferRejected,
+								AccountID:          1,
+								BalanceBeforeCents: 5000,
+								BalanceAfterCents:  5000,
+								AmountCents:        10000000,
+								TransferCount:      1,
+								Reason:             ErrInsufficientFunds.Error(),
+							}).
+							Return(nil)
+
 						return cb(mockRepo)
 					}).
 					Times(1)
 			},
 			expectedError: ErrInsufficientFunds,
 		},
+		{
+			name: "sanctions hit blocks before debiting",
+			bulkTransfer: BulkTransfer{
+				OrganizationBIC:  "OIVUSCLQXXX",
+				OrganizationIBAN: "FR10474608000002006107XXXXX",
+				Transfers: []Transfer{
+					{
+						CounterpartyName: "Ivan Sanctionedov",
+						CounterpartyIBAN: "EE383680981021245685",
+						CounterpartyBIC:  "CRLYFRPPTOU",
+						AmountCents:      1450,
+						Currency:         "EUR",
+						Description:      "Test",
+					},
+				},
+			},
+			screenerSetup: func(m *MockScreener) {
+				m.EXPECT().
+					Screen(gomock.Any(), gomock.Any()).
+					Return(ScreeningResult{
+						Decision: DecisionBlock,
+						Hits:     []SanctionsHit{{TransferIndex: 0, MatchedOn: "name", ListEntry: "Ivan Sanctionedov", Score: 1}},
+					}, nil)
+			},
+			mockSetup: func(m *MockAccountRepository) {
+				m.EXPECT().
+					Atomic(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+						ctrl := gomock.NewController(t)
+						mockRepo := NewMockAccountRepository(ctrl)
+
+						mockRepo.EXPECT().
+							AppendAuditRecord(gomock.Any(), AuditRecord{
+								EventType:     AuditEventBulkTransferRejected,
+								AmountCents:   1450,
+								TransferCount: 1,
+								Reason:        "sanctions screening block: 1 hit(s)",
+							}).
+							Return(nil)
+
+						return cb(mockRepo)
+					}).
+					Times(1)
+			},
+			expectedError: &SanctionsError{
+				Decision: DecisionBlock,
+				Hits:     []SanctionsHit{{TransferIndex: 0, MatchedOn: "name", ListEntry: "Ivan Sanctionedov", Score: 1}},
+			},
+		},
+		{
+			name: "sanctions hit held for review is staged and audited as held",
+			bulkTransfer: BulkTransfer{
+				OrganizationBIC:  "OIVUSCLQXXX",
+				OrganizationIBAN: "FR10474608000002006107XXXXX",
+				Transfers: []Transfer{
+					{
+						CounterpartyName: "Ivan Sanctionedov",
+						CounterpartyIBAN: "EE383680981021245685",
+						CounterpartyBIC:  "CRLYFRPPTOU",
+						AmountCents:      1450,
+						Currency:         "EUR",
+						Description:      "Test",
+					},
+				},
+			},
+			screenerSetup: func(m *MockScreener) {
+				m.EXPECT().
+					Screen(gomock.Any(), gomock.Any()).
+					Return(ScreeningResult{
+						Decision: DecisionReview,
+						Hits:     []SanctionsHit{{TransferIndex: 0, MatchedOn: "name", ListEntry: "Ivan Sanctionedov", Score: 0.95}},
+					}, nil)
+			},
+			mockSetup: func(m *MockAccountRepository) {
+				m.EXPECT().
+					Atomic(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+						ctrl := gomock.NewController(t)
+						mockRepo := NewMockAccountRepository(ctrl)
+
+						mockRepo.EXPECT().
+							AddStagedBulkTransfer(gomock.Any(), gomock.Any()).
+							Return(int64(7), nil)
+						mockRepo.EXPECT().
+							AddStagedTransfers(gomock.Any(), int64(7), gomock.Len(1)).
+							Return(nil)
+						mockRepo.EXPECT().
+							HoldStagedBulkTransfer(gomock.Any(), HeldBulkTransfer{
+								ID:     7,
+								Review: ReviewKindSanctions,
+								Reason: "sanctions screening review: 1 hit(s)",
+							}).
+							Return(nil)
+						mockRepo.EXPECT().
+							AppendAuditRecord(gomock.Any(), AuditRecord{
+								EventType:     AuditEventBulkTransferHeld,
+								AmountCents:   1450,
+								TransferCount: 1,
+								Reason:        "held bulk transfer 7: sanctions screening review: 1 hit(s)",
+							}).
+							Return(nil)
+
+						return cb(mockRepo)
+					}).
+					Times(1)
+			},
+			expectedError: &HeldError{
+				HeldBulkTransferID: 7,
+				Review:             ReviewKindSanctions,
+				Reason: &SanctionsError{
+					Decision: DecisionReview,
+					Hits:     []SanctionsHit{{TransferIndex: 0, MatchedOn: "name", ListEntry: "Ivan Sanctionedov", Score: 0.95}},
+				},
+			},
+		},
+		{
+			name: "screening error propagates",
+			bulkTransfer: BulkTransfer{
+				OrganizationBIC:  "OIVUSCLQXXX",
+				OrganizationIBAN: "FR10474608000002006107XXXXX",
+				Transfers: []Transfer{
+					{
+						CounterpartyName: "Bip Bip",
+						CounterpartyIBAN: "EE383680981021245685",
+						CounterpartyBIC:  "CRLYFRPPTOU",
+						AmountCents:      1450,
+						Currency:         "EUR",
+						Description:      "Test",
+					},
+				},
+			},
+			screenerSetup: func(m *MockScreener) {
+				m.EXPECT().
+					Screen(gomock.Any(), gomock.Any()).
+					Return(ScreeningResult{}, context.Canceled)
+			},
+			mockSetup:     func(m *MockAccountRepository) {},
+			expectedError: errors.New("failed to screen bulk transfer: context canceled"),
+		},
+		{
+			name: "fraud rule review holds batch for review before debiting",
+			bulkTransfer: BulkTransfer{
+				OrganizationBIC:  "OIVUSCLQXXX",
+				OrganizationIBAN: "FR10474608000002006107XXXXX",
+				Transfers: []Transfer{
+					{
+						CounterpartyName: "Bip Bip",
+						CounterpartyIBAN: "EE383680981021245685",
+						CounterpartyBIC:  "CRLYFRPPTOU",
+						AmountCents:      900000,
+						Currency:         "EUR",
+						Description:      "Large payment",
+					},
+				},
+			},
+			fraudRules: []FraudRuleSpec{
+				{Type: FraudRuleNewCounterpartyLargeAmount, Decision: DecisionReview, MinAmountCents: 500000},
+			},
+			mockSetup: func(m *MockAccountRepository) {
+				m.EXPECT().
+					Atomic(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+						ctrl := gomock.NewController(t)
+						mockRepo := NewMockAccountRepository(ctrl)
+
+						mockRepo.EXPECT().
+							GetAccountByID(gomock.Any(), "FR10474608000002006107XXXXX", "OIVUSCLQXXX").
+							Return(Account{ID: 1, BalanceCents: 10000000}, nil)
+
+						mockRepo.EXPECT().
+							GetAccountHistory(gomock.Any(), int64(1), []string{"EE383680981021245685"}).
+							Return(AccountHistory{BatchCount: 3, AverageBatchTotalCents: 800000}, nil)
+
+						return cb(mockRepo)
+					}).
+					Times(1)
+				m.EXPECT().
+					Atomic(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+						ctrl := gomock.NewController(t)
+						mockRepo := NewMockAccountRepository(ctrl)
+
+						mockRepo.EXPECT().
+							AddStagedBulkTransfer(gomock.Any(), gomock.Any()).
+							Return(int64(7), nil)
+						mockRepo.EXPECT().
+							AddStagedTransfers(gomock.Any(), int64(7), gomock.Len(1)).
+							Return(nil)
+						mockRepo.EXPECT().
+							HoldStagedBulkTransfer(gomock.Any(), HeldBulkTransfer{
+								ID:     7,
+								Review: ReviewKindFraud,
+								Reason: "fraud rules review: new_counterparty_large_amount: transfer 0 sends 900000 cents to new counterparty EE383680981021245685",
+							}).
+							Return(nil)
+						mockRepo.EXPECT().
+							AppendAuditRecord(gomock.Any(), AuditRecord{
+								EventType:          AuditEventBulkTransferHeld,
+								AccountID:          1,
+								BalanceBeforeCents: 10000000,
+								BalanceAfterCents:  10000000,
+								AmountCents:        900000,
+								TransferCount:      1,
+								Reason:             "held bulk transfer 7: fraud rules review: new_counterparty_large_amount: transfer 0 sends 900000 cents to new counterparty EE383680981021245685",
+							}).
+							Return(nil)
+
+						return cb(mockRepo)
+					}).
+					Times(1)
+			},
+			expectedError: &HeldError{
+				HeldBulkTransferID: 7,
+				Review:             ReviewKindFraud,
+				Reason: &FraudError{
+					Decision: DecisionReview,
+					Reasons:  []string{"new_counterparty_large_amount: transfer 0 sends 900000 cents to new counterparty EE383680981021245685"},
+				},
+			},
+		},
+		{
+			name: "rejection audit failure returns error",
+			bulkTransfer: BulkTransfer{
+				OrganizationBIC:  "OIVUSCLQXXX",
+				OrganizationIBAN: "FR10474608000002006107XXXXX",
+				Transfers: []Transfer{
+					{
+						CounterpartyName: "Bip Bip",
+						CounterpartyIBAN: "EE383680981021245685",
+						CounterpartyBIC:  "CRLYFRPPTOU",
+						AmountCents:      1450,
+						Currency:         "EUR",
+						Description:      "Test",
+					},
+				},
+			},
+			mockSetup: func(m *MockAccountRepository) {
+				m.EXPECT().
+					Atomic(gomock.Any(), gomock.Any()).
+					Return(ErrAccountNotFound).
+					Times(1)
+				m.EXPECT().
+					Atomic(gomock.Any(), gomock.Any()).
+					Return(errors.New("disk I/O error")).
+					Times(1)
+			},
+			expectedError: errors.New("failed to audit rejected bulk transfer: disk I/O error"),
+		},
 		{
 			name: "account not found error propagates",
 			bulkTransfer: BulkTransfer{
@@ -163,14 +665,14 @@ func TestService_ProcessBulkTransfer(t *testing.T) {
 			},
 			mockSetup: func(m *MockAccountRepository) {
 				m.EXPECT().
-					Atomic(context.Background(), gomock.Any()).
+					Atomic(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
 						ctrl := gomock.NewController(t)
 						mockRepo := NewMockAccountRepository(ctrl)
 
 						accountNotFoundErr := errors.New("account not found")
 						mockRepo.EXPECT().
-							GetAccountByID(context.Background(), "FR10474608000002006107XXXXX", "OIVUSCLQXXX").
+							GetAccountByID(gomock.Any(), "FR10474608000002006107XXXXX", "OIVUSCLQXXX").
 							Return(Account{}, accountNotFoundErr)
 
 						return cb(mockRepo)
@@ -197,7 +699,7 @@ func TestService_ProcessBulkTransfer(t *testing.T) {
 			},
 			mockSetup: func(m *MockAccountRepository) {
 				m.EXPECT().
-					Atomic(context.Background(), gomock.Any()).
+					Atomic(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
 						ctrl := gomock.NewController(t)
 						mockRepo := NewMockAccountRepository(ctrl)
@@ -213,12 +715,16 @@ func TestService_ProcessBulkTransfer(t *testing.T) {
 						}
 
 						mockRepo.EXPECT().
-							GetAccountByID(context.Background(), "FR10474608000002006107XXXXX", "OIVUSCLQXXX").
+							GetAccountByID(gomock.Any(), "FR10474608000002006107XXXXX", "OIVUSCLQXXX").
 							Return(account, nil)
 
+						mockRepo.EXPECT().
+							GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).
+							Return(AccountHistory{}, nil)
+
 						dbError := errors.New("database connection error")
 						mockRepo.EXPECT().
-							UpdateBalance(context.Background(), expectedAccount).
+							UpdateBalance(gomock.Any(), expectedAccount).
 							Return(dbError)
 
 						return cb(mockRepo)
@@ -241,15 +747,689 @@ func TestService_ProcessBulkTransfer(t *testing.T) {
 				tt.mockSetup(mockRepo)
 			}
 
-			service := NewService(mockRepo)
-			err := service.ProcessBulkTransfer(context.Background(), tt.bulkTransfer)
+			mockScreener := NewMockScreener(ctrl)
+			if tt.screenerSetup != nil {
+				tt.screenerSetup(mockScreener)
+			} else {
+				mockScreener.EXPECT().
+					Screen(gomock.Any(), gomock.Any()).
+					Return(ScreeningResult{Decision: DecisionAllow}, nil).
+					AnyTimes()
+			}
+
+			fraudRules, err := BuildFraudRules(tt.fraudRules)
+			require.NoError(t, err)
+
+			service := NewService(mockRepo, mockScreener, NewFraudEngine(fraudRules), Pricing{})
+			result, err := service.ProcessBulkTransfer(context.Background(), tt.bulkTransfer)
 
 			if tt.expectedError != nil {
 				require.Error(t, err)
 				require.Equal(t, tt.expectedError.Error(), err.Error())
 			} else {
 				require.NoError(t, err)
+				require.Equal(t, tt.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestService_ValidateBulkTransfer(t *testing.T) {
+	t.Parallel()
+
+	bulkTransfer := BulkTransfer{
+		OrganizationBIC:  "OIVUSCLQXXX",
+		OrganizationIBAN: "FR10474608000002006107XXXXX",
+		Transfers: []Transfer{
+			{CounterpartyName: "Bip Bip", CounterpartyIBAN: "EE383680981021245685", AmountCents: 1000, Currency: "EUR"},
+			{CounterpartyName: "Bugs Bunny", CounterpartyIBAN: "EE383680981021245685", AmountCents: 2000, Currency: "EUR"},
+		},
+	}
+
+	// dryRunRepository runs the callback against a repository holding an
+	// account with balance and checks that the transaction is rolled back.
+	dryRunRepository := func(balance int64, accountErr error, writeErr error) func(*MockAccountRepository) {
+		return func(m *MockAccountRepository) {
+			m.EXPECT().
+				Atomic(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+					ctrl := gomock.NewController(t)
+					mockRepo := NewMockAccountRepository(ctrl)
+
+					mockRepo.EXPECT().
+						GetAccountByID(gomock.Any(), "FR10474608000002006107XXXXX", "OIVUSCLQXXX").
+						Return(Account{ID: 1, BalanceCents: balance}, accountErr)
+					mockRepo.EXPECT().GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).Return(AccountHistory{}, nil).AnyTimes()
+					mockRepo.EXPECT().UpdateBalance(gomock.Any(), gomock.Any()).Return(writeErr).AnyTimes()
+					mockRepo.EXPECT().AddBulkTransfer(gomock.Any(), int64(1), gomock.Any()).Return(int64(42), nil).AnyTimes()
+					mockRepo.EXPECT().AddTransfers(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+					mockRepo.EXPECT().AppendAuditRecord(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+					err := cb(mockRepo)
+					if accountErr == nil && writeErr == nil {
+						require.ErrorIs(t, err, errDryRun, "dry runs must roll back")
+					}
+					return err
+				}).
+				Times(1)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		mockSetup     func(*MockAccountRepository)
+		executionMode ExecutionMode
+		screening     ScreeningResult
+		fraudRules    []FraudRuleSpec
+		expectedQuote BulkTransferQuote
+		expectedError error
+	}{
+		{
+			name:      "valid bulk transfer",
+			mockSetup: dryRunRepository(10000, nil, nil),
+			screening: ScreeningResult{Decision: DecisionAllow},
+			expectedQuote: BulkTransferQuote{
+				TransferCount:      2,
+				TotalAmountCents:   3000,
+				BalanceBeforeCents: 10000,
+				BalanceAfterCents:  7000,
+			},
+		},
+		{
+			name:      "insufficient funds",
+			mockSetup: dryRunRepository(1000, nil, nil),
+			screening: ScreeningResult{Decision: DecisionAllow},
+			expectedQuote: BulkTransferQuote{
+				TransferCount:      2,
+				TotalAmountCents:   3000,
+				BalanceBeforeCents: 1000,
+				BalanceAfterCents:  -2000,
+				Violations: []Violation{
+					{TransferIndex: BatchViolation, Code: ViolationInsufficientFunds, Message: "balance does not cover the bulk transfer"},
+				},
+			},
+		},
+		{
+			name:          "partial mode reports rejected transfers",
+			mockSetup:     dryRunRepository(1500, nil, nil),
+			executionMode: ExecutionModePartial,
+			screening:     ScreeningResult{Decision: DecisionAllow},
+			expectedQuote: BulkTransferQuote{
+				TransferCount:      2,
+				TotalAmountCents:   3000,
+				BalanceBeforeCents: 1500,
+				BalanceAfterCents:  500,
+				Violations: []Violation{
+					{TransferIndex: 1, Code: RejectionInsufficientFunds, Message: "balance does not cover this transfer"},
+				},
+			},
+		},
+		{
+			name: "frozen account",
+			mockSetup: func(m *MockAccountRepository) {
+				m.EXPECT().
+					Atomic(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+						mockRepo := NewMockAccountRepository(gomock.NewController(t))
+						mockRepo.EXPECT().
+							GetAccountByID(gomock.Any(), "FR10474608000002006107XXXXX", "OIVUSCLQXXX").
+							Return(Account{ID: 1, BalanceCents: 10000, Frozen: true}, nil)
+
+						err := cb(mockRepo)
+						require.ErrorIs(t, err, errDryRun, "dry runs must roll back")
+						return err
+					}).
+					Times(1)
+			},
+			screening: ScreeningResult{Decision: DecisionAllow},
+			expectedQuote: BulkTransferQuote{
+				TransferCount:      2,
+				TotalAmountCents:   3000,
+				BalanceBeforeCents: 10000,
+				BalanceAfterCents:  7000,
+				Violations: []Violation{
+					{TransferIndex: BatchViolation, Code: ViolationAccountFrozen, Message: "account is frozen"},
+				},
+			},
+		},
+		{
+			name:       "fraud review and insufficient funds are both reported",
+			mockSetup:  dryRunRepository(1000, nil, nil),
+			screening:  ScreeningResult{Decision: DecisionAllow},
+			fraudRules: []FraudRuleSpec{{Type: FraudRuleRepeatedCounterparty, MaxTransfersPerIBAN: 1}},
+			expectedQuote: BulkTransferQuote{
+				TransferCount:      2,
+				TotalAmountCents:   3000,
+				BalanceBeforeCents: 1000,
+				BalanceAfterCents:  -2000,
+				Violations: []Violation{
+					{TransferIndex: BatchViolation, Code: ViolationFraudReview, Message: "bulk transfer would be held for fraud review"},
+					{TransferIndex: BatchViolation, Code: ViolationInsufficientFunds, Message: "balance does not cover the bulk transfer"},
+				},
+			},
+		},
+		{
+			name: "sanctions hits are audited and reported for the whole batch",
+			mockSetup: func(m *MockAccountRepository) {
+				m.EXPECT().
+					Atomic(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+						ctrl := gomock.NewController(t)
+						mockRepo := NewMockAccountRepository(ctrl)
+						mockRepo.EXPECT().AppendAuditRecord(gomock.Any(), AuditRecord{
+							EventType:     AuditEventDryRunScreeningHit,
+							AmountCents:   3000,
+							TransferCount: 2,
+							Reason:        "sanctions screening block: 2 hit(s)",
+						}).Return(nil)
+						return cb(mockRepo)
+					})
+				dryRunRepository(10000, nil, nil)(m)
+			},
+			screening: ScreeningResult{
+				Decision: DecisionBlock,
+				Hits: []SanctionsHit{
+					{TransferIndex: 1, MatchedOn: "name", ListEntry: "Bugs Bunny", Score: 1},
+					{TransferIndex: 1, MatchedOn: "iban", ListEntry: "EE383680981021245685", Score: 1},
+				},
+			},
+			expectedQuote: BulkTransferQuote{
+				TransferCount:      2,
+				TotalAmountCents:   3000,
+				BalanceBeforeCents: 10000,
+				BalanceAfterCents:  7000,
+				Violations: []Violation{
+					{TransferIndex: BatchViolation, Code: ViolationSanctionsBlock, Message: "bulk transfer would be blocked by sanctions screening"},
+				},
+			},
+		},
+		{
+			name:          "account not found",
+			mockSetup:     dryRunRepository(0, ErrAccountNotFound, nil),
+			screening:     ScreeningResult{Decision: DecisionAllow},
+			expectedError: ErrAccountNotFound,
+		},
+		{
+			name:          "repository failure",
+			mockSetup:     dryRunRepository(10000, nil, errors.New("disk I/O error")),
+			screening:     ScreeningResult{Decision: DecisionAllow},
+			expectedError: errors.New("disk I/O error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Only the dry-run transaction is expected: rejections other than
+			// sanctions hits are not audited.
+			mockRepo := NewMockAccountRepository(ctrl)
+			tt.mockSetup(mockRepo)
+
+			mockScreener := NewMockScreener(ctrl)
+			mockScreener.EXPECT().
+				Screen(gomock.Any(), bulkTransfer.Transfers).
+				Return(tt.screening, nil)
+
+			fraudRules, err := BuildFraudRules(tt.fraudRules)
+			require.NoError(t, err)
+
+			request := bulkTransfer
+			request.ExecutionMode = tt.executionMode
+
+			service := NewService(mockRepo, mockScreener, NewFraudEngine(fraudRules), Pricing{})
+			quote, err := service.ValidateBulkTransfer(context.Background(), request)
+
+			if tt.expectedError != nil {
+				require.Error(t, err)
+				require.Equal(t, tt.expectedError.Error(), err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuote, quote)
+			require.Equal(t, len(tt.expectedQuote.Violations) == 0, quote.Valid())
+		})
+	}
+}
+
+func TestService_ProcessBulkTransfer_EntersOverdraft(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	account := Account{ID: 1, BalanceCents: 1000, OverdraftLimitCents: 5000}
+
+	repo := atomicRepository(ctrl, func(r *MockAccountRepository) {
+		r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(account, nil)
+		r.EXPECT().GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).Return(AccountHistory{}, nil)
+		r.EXPECT().UpdateBalance(gomock.Any(), Account{ID: 1, BalanceCents: -2000, OverdraftLimitCents: 5000}).Return(nil)
+		r.EXPECT().AddBulkTransfer(gomock.Any(), int64(1), gomock.Any()).Return(int64(42), nil)
+		r.EXPECT().AddTransfers(gomock.Any(), gomock.Any()).Return(nil)
+		gomock.InOrder(
+			r.EXPECT().AppendAuditRecord(gomock.Any(), gomock.Any()).Return(nil),
+			r.EXPECT().AppendAuditRecord(gomock.Any(), AuditRecord{
+				EventType:          AuditEventOverdraftEntered,
+				AccountID:          1,
+				BalanceBeforeCents: 1000,
+				BalanceAfterCents:  -2000,
+				AmountCents:        2000,
+			}).Return(nil),
+			r.EXPECT().AppendAuditRecord(gomock.Any(), gomock.Any()).Return(nil),
+		)
+	})
+
+	screener := NewMockScreener(ctrl)
+	screener.EXPECT().Screen(gomock.Any(), gomock.Any()).Return(ScreeningResult{Decision: DecisionAllow}, nil)
+
+	service := NewService(repo, screener, NewFraudEngine(nil), Pricing{})
+	_, err := service.ProcessBulkTransfer(context.Background(), BulkTransfer{
+		OrganizationIBAN: "IBAN",
+		OrganizationBIC:  "BIC",
+		Transfers:        []Transfer{{CounterpartyIBAN: "EE383680981021245685", AmountCents: 3000, Currency: "EUR"}},
+	})
+	require.NoError(t, err)
+}
+
+func TestService_ProcessBulkTransfer_MultipleDebtors(t *testing.T) {
+	t.Parallel()
+
+	// IBAN-A sorts before the organization account IBAN-B, so it is locked
+	// first although the request names it second.
+	organization := Account{ID: 2, OrganizationName: "ACME", OrganizationID: "org-acme", BalanceCents: 5000, IBAN: "IBAN-B", BIC: "BIC"}
+	debtor := Account{ID: 1, OrganizationName: "ACME", OrganizationID: "org-acme", BalanceCents: 1000, IBAN: "IBAN-A", BIC: "BIC"}
+	transfers := []Transfer{
+		{CounterpartyIBAN: "EE383680981021245685", AmountCents: 3000, Currency: "EUR"},
+		{CounterpartyIBAN: "EE383680981021245685", AmountCents: 800, Currency: "EUR", DebtorIBAN: "IBAN-A", DebtorBIC: "BIC"},
+		{CounterpartyIBAN: "EE383680981021245685", AmountCents: 500, Currency: "EUR", DebtorIBAN: "IBAN-A", DebtorBIC: "BIC"},
+	}
+
+	tests := []struct {
+		name           string
+		executionMode  ExecutionMode
+		txSetup        func(r *MockAccountRepository)
+		expectedResult BulkTransferResult
+		expectedError  error
+	}{
+		{
+			name:          "debits every debtor account for its own transfers",
+			executionMode: ExecutionModeAllOrNothing,
+			txSetup: func(r *MockAccountRepository) {
+				gomock.InOrder(
+					r.EXPECT().GetAccountByID(gomock.Any(), "IBAN-A", "BIC").
+						Return(Account{ID: 1, OrganizationName: "ACME", OrganizationID: "org-acme", BalanceCents: 2000, IBAN: "IBAN-A", BIC: "BIC"}, nil),
+					r.EXPECT().GetAccountByID(gomock.Any(), "IBAN-B", "BIC").Return(organization, nil),
+					r.EXPECT().GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).Return(AccountHistory{}, nil),
+					r.EXPECT().GetAccountHistory(gomock.Any(), int64(2), gomock.Any()).Return(AccountHistory{}, nil),
+					r.EXPECT().UpdateBalance(gomock.Any(), Account{ID: 1, OrganizationName: "ACME", OrganizationID: "org-acme", BalanceCents: 700, IBAN: "IBAN-A", BIC: "BIC"}).Return(nil),
+					r.EXPECT().UpdateBalance(gomock.Any(), Account{ID: 2, OrganizationName: "ACME", OrganizationID: "org-acme", BalanceCents: 2000, IBAN: "IBAN-B", BIC: "BIC"}).Return(nil),
+					r.EXPECT().AddBulkTransfer(gomock.Any(), int64(2), gomock.Any()).Return(int64(42), nil),
+					r.EXPECT().AddTransfers(gomock.Any(), []Transfer{
+						{BankAccountID: 2, BulkTransferID: 42, CounterpartyIBAN: "EE383680981021245685", AmountCents: 3000, Currency: "EUR"},
+						{BankAccountID: 1, BulkTransferID: 42, CounterpartyIBAN: "EE383680981021245685", AmountCents: 800, Currency: "EUR", DebtorIBAN: "IBAN-A", DebtorBIC: "BIC"},
+						{BankAccountID: 1, BulkTransferID: 42, CounterpartyIBAN: "EE383680981021245685", AmountCents: 500, Currency: "EUR", DebtorIBAN: "IBAN-A", DebtorBIC: "BIC"},
+					}).Return(nil),
+					r.EXPECT().AppendAuditRecord(gomock.Any(), AuditRecord{
+						EventType:          AuditEventBalanceChanged,
+						AccountID:          1,
+						BalanceBeforeCents: 2000,
+						BalanceAfterCents:  700,
+						AmountCents:        -1300,
+					}).Return(nil),
+					r.EXPECT().AppendAuditRecord(gomock.Any(), AuditRecord{
+						EventType:          AuditEventBalanceChanged,
+						AccountID:          2,
+						BalanceBeforeCents: 5000,
+						BalanceAfterCents:  2000,
+						AmountCents:        -3000,
+					}).Return(nil),
+					r.EXPECT().AppendAuditRecord(gomock.Any(), AuditRecord{
+						EventType:          AuditEventBulkTransferAccepted,
+						AccountID:          2,
+						BalanceBeforeCents: 5000,
+						BalanceAfterCents:  2000,
+						AmountCents:        4300,
+						TransferCount:      3,
+					}).Return(nil),
+				)
+			},
+			expectedResult: BulkTransferResult{
+				BulkTransferID: 42,
+				ExecutionMode:  ExecutionModeAllOrNothing,
+				Transfers: []TransferResult{
+					{Index: 0, Status: TransferAccepted},
+					{Index: 1, Status: TransferAccepted},
+					{Index: 2, Status: TransferAccepted},
+				},
+			},
+		},
+		{
+			name:          "partial mode rejects the transfers each account cannot cover",
+			executionMode: ExecutionModePartial,
+			txSetup: func(r *MockAccountRepository) {
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN-A", "BIC").Return(debtor, nil)
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN-B", "BIC").Return(organization, nil)
+				r.EXPECT().GetAccountHistory(gomock.Any(), gomock.Any(), gomock.Any()).Return(AccountHistory{}, nil).Times(2)
+				r.EXPECT().UpdateBalance(gomock.Any(), Account{ID: 1, OrganizationName: "ACME", OrganizationID: "org-acme", BalanceCents: 200, IBAN: "IBAN-A", BIC: "BIC"}).Return(nil)
+				r.EXPECT().UpdateBalance(gomock.Any(), Account{ID: 2, OrganizationName: "ACME", OrganizationID: "org-acme", BalanceCents: 2000, IBAN: "IBAN-B", BIC: "BIC"}).Return(nil)
+				r.EXPECT().AddBulkTransfer(gomock.Any(), int64(2), gomock.Any()).Return(int64(42), nil)
+				r.EXPECT().AddTransfers(gomock.Any(), gomock.Len(2)).Return(nil)
+				r.EXPECT().AppendAuditRecord(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+			},
+			expectedResult: BulkTransferResult{
+				BulkTransferID: 42,
+				ExecutionMode:  ExecutionModePartial,
+				Transfers: []TransferResult{
+					{Index: 0, Status: TransferAccepted},
+					{Index: 1, Status: TransferAccepted},
+					{Index: 2, Status: TransferRejected, Reason: RejectionInsufficientFunds},
+				},
+			},
+		},
+		{
+			name:          "rejects the whole batch when one account cannot cover its transfers",
+			executionMode: ExecutionModeAllOrNothing,
+			txSetup: func(r *MockAccountRepository) {
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN-A", "BIC").Return(debtor, nil)
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN-B", "BIC").Return(organization, nil)
+				r.EXPECT().GetAccountHistory(gomock.Any(), gomock.Any(), gomock.Any()).Return(AccountHistory{}, nil).Times(2)
+			},
+			expectedError: ErrInsufficientFunds,
+		},
+		{
+			name:          "does not debit accounts of other organizations",
+			executionMode: ExecutionModeAllOrNothing,
+			txSetup: func(r *MockAccountRepository) {
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN-A", "BIC").
+					Return(Account{ID: 1, OrganizationName: "Globex", OrganizationID: "org-globex", BalanceCents: 1000, IBAN: "IBAN-A", BIC: "BIC"}, nil)
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN-B", "BIC").Return(organization, nil)
+			},
+			expectedError: ErrAccountNotFound,
+		},
+		{
+			name:          "does not debit accounts of other organizations with the same name",
+			executionMode: ExecutionModeAllOrNothing,
+			txSetup: func(r *MockAccountRepository) {
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN-A", "BIC").
+					Return(Account{ID: 1, OrganizationName: "ACME", OrganizationID: "org-other", BalanceCents: 1000, IBAN: "IBAN-A", BIC: "BIC"}, nil)
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN-B", "BIC").Return(organization, nil)
+			},
+			expectedError: ErrAccountNotFound,
+		},
+		{
+			name:          "does not debit other accounts for an organization account without organization",
+			executionMode: ExecutionModeAllOrNothing,
+			txSetup: func(r *MockAccountRepository) {
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN-A", "BIC").
+					Return(Account{ID: 1, OrganizationName: "ACME", BalanceCents: 1000, IBAN: "IBAN-A", BIC: "BIC"}, nil)
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN-B", "BIC").
+					Return(Account{ID: 2, OrganizationName: "ACME", BalanceCents: 5000, IBAN: "IBAN-B", BIC: "BIC"}, nil)
+			},
+			expectedError: ErrAccountNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := atomicRepository(ctrl, tt.txSetup)
+			if tt.expectedError != nil {
+				// The rejection is audited in a transaction of its own.
+				repo.EXPECT().Atomic(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			screener := NewMockScreener(ctrl)
+			screener.EXPECT().Screen(gomock.Any(), gomock.Any()).Return(ScreeningResult{Decision: DecisionAllow}, nil)
+
+			service := NewService(repo, screener, NewFraudEngine(nil), Pricing{})
+			result, err := service.ProcessBulkTransfer(context.Background(), BulkTransfer{
+				OrganizationIBAN: "IBAN-B",
+				OrganizationBIC:  "BIC",
+				ExecutionMode:    tt.executionMode,
+				Transfers:        transfers,
+			})
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
 			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedResult, result)
 		})
 	}
 }
+
+func TestService_ProcessBulkTransfer_ChargesFees(t *testing.T) {
+	t.Parallel()
+
+	pricing, err := NewPricing([]PricingPlan{{Name: "business", SEPAFeeCents: 20, CrossBorderFeeBasisPoints: 100}})
+	require.NoError(t, err)
+
+	account := Account{ID: 1, OrganizationName: "ACME", BalanceCents: 3000, IBAN: "IBAN", BIC: "BIC", Currency: "EUR", PricingPlan: "business"}
+	transfers := []Transfer{
+		{CounterpartyIBAN: "EE383680981021245685", AmountCents: 1000, Currency: "EUR"},
+		{CounterpartyIBAN: "US12345678901234567890", AmountCents: 1960, Currency: "EUR"},
+	}
+
+	tests := []struct {
+		name           string
+		executionMode  ExecutionMode
+		txSetup        func(r *MockAccountRepository)
+		expectedResult BulkTransferResult
+		expectedError  error
+	}{
+		{
+			name:          "debits the fees and books them apart",
+			executionMode: ExecutionModeAllOrNothing,
+			txSetup: func(r *MockAccountRepository) {
+				debited := account
+				debited.BalanceCents = 0
+				gomock.InOrder(
+					r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(account, nil),
+					r.EXPECT().GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).Return(AccountHistory{}, nil),
+					r.EXPECT().UpdateBalance(gomock.Any(), debited).Return(nil),
+					r.EXPECT().AddBulkTransfer(gomock.Any(), int64(1), gomock.Any()).Return(int64(42), nil),
+					r.EXPECT().AddTransfers(gomock.Any(), gomock.Len(2)).Return(nil),
+					r.EXPECT().AddTransaction(gomock.Any(), Transaction{
+						AccountID:        1,
+						BulkTransferID:   42,
+						Kind:             TransactionKindFee,
+						CounterpartyName: "ACME",
+						CounterpartyIBAN: "IBAN",
+						CounterpartyBIC:  "BIC",
+						AmountCents:      -40,
+						Currency:         "EUR",
+						Description:      "Transfer fees of bulk transfer 42",
+					}).Return(nil),
+					r.EXPECT().AppendAuditRecord(gomock.Any(), AuditRecord{
+						EventType:          AuditEventBalanceChanged,
+						AccountID:          1,
+						BalanceBeforeCents: 3000,
+						AmountCents:        -3000,
+					}).Return(nil),
+					r.EXPECT().AppendAuditRecord(gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+			expectedResult: BulkTransferResult{
+				BulkTransferID: 42,
+				ExecutionMode:  ExecutionModeAllOrNothing,
+				Transfers: []TransferResult{
+					{Index: 0, Status: TransferAccepted, FeeCents: 20},
+					{Index: 1, Status: TransferAccepted, FeeCents: 20},
+				},
+			},
+		},
+		{
+			name:          "partial mode rejects transfers whose fee does not fit",
+			executionMode: ExecutionModePartial,
+			txSetup: func(r *MockAccountRepository) {
+				account := account
+				account.BalanceCents = 2990
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(account, nil)
+				r.EXPECT().GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).Return(AccountHistory{}, nil)
+				r.EXPECT().UpdateBalance(gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().AddBulkTransfer(gomock.Any(), int64(1), gomock.Any()).Return(int64(42), nil)
+				r.EXPECT().AddTransfers(gomock.Any(), gomock.Len(1)).Return(nil)
+				r.EXPECT().AddTransaction(gomock.Any(), gomock.Any()).Return(nil)
+				r.EXPECT().AppendAuditRecord(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			},
+			expectedResult: BulkTransferResult{
+				BulkTransferID: 42,
+				ExecutionMode:  ExecutionModePartial,
+				Transfers: []TransferResult{
+					{Index: 0, Status: TransferAccepted, FeeCents: 20},
+					{Index: 1, Status: TransferRejected, Reason: RejectionInsufficientFunds},
+				},
+			},
+		},
+		{
+			name:          "fees count towards the funds check",
+			executionMode: ExecutionModeAllOrNothing,
+			txSetup: func(r *MockAccountRepository) {
+				account := account
+				account.BalanceCents = 2990
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(account, nil)
+				r.EXPECT().GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).Return(AccountHistory{}, nil)
+			},
+			expectedError: ErrInsufficientFunds,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := atomicRepository(ctrl, tt.txSetup)
+			if tt.expectedError != nil {
+				repo.EXPECT().Atomic(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			screener := NewMockScreener(ctrl)
+			screener.EXPECT().Screen(gomock.Any(), gomock.Any()).Return(ScreeningResult{Decision: DecisionAllow}, nil)
+
+			service := NewService(repo, screener, NewFraudEngine(nil), pricing)
+			result, err := service.ProcessBulkTransfer(context.Background(), BulkTransfer{
+				OrganizationIBAN: "IBAN",
+				OrganizationBIC:  "BIC",
+				ExecutionMode:    tt.executionMode,
+				Transfers:        transfers,
+			})
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestService_ValidateBulkTransfer_QuotesFees(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pricing, err := NewPricing([]PricingPlan{{Name: "business", SEPAFeeCents: 20}})
+	require.NoError(t, err)
+
+	repo := NewMockAccountRepository(ctrl)
+	repo.EXPECT().
+		Atomic(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+			txRepo := NewMockAccountRepository(ctrl)
+			txRepo.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").
+				Return(Account{ID: 1, BalanceCents: 3000, PricingPlan: "business"}, nil)
+			txRepo.EXPECT().GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).Return(AccountHistory{}, nil)
+			return cb(txRepo)
+		})
+
+	screener := NewMockScreener(ctrl)
+	screener.EXPECT().Screen(gomock.Any(), gomock.Any()).Return(ScreeningResult{Decision: DecisionAllow}, nil)
+
+	service := NewService(repo, screener, NewFraudEngine(nil), pricing)
+	quote, err := service.ValidateBulkTransfer(context.Background(), BulkTransfer{
+		OrganizationIBAN: "IBAN",
+		OrganizationBIC:  "BIC",
+		Transfers: []Transfer{
+			{CounterpartyIBAN: "EE383680981021245685", AmountCents: 1000, Currency: "EUR"},
+			{CounterpartyIBAN: "EE383680981021245685", AmountCents: 1990, Currency: "EUR"},
+		},
+	})
+	require.NoError(t, err)
+
+	// The transfers alone would fit: their fees do not.
+	require.Equal(t, BulkTransferQuote{
+		TransferCount:      2,
+		TotalAmountCents:   2990,
+		FeesCents:          40,
+		BalanceBeforeCents: 3000,
+		BalanceAfterCents:  -30,
+		Violations: []Violation{
+			{TransferIndex: BatchViolation, Code: ViolationInsufficientFunds, Message: "balance does not cover the bulk transfer"},
+		},
+	}, quote)
+}
+
+func TestService_ValidateBulkTransfer_RetriedTransaction(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pricing, err := NewPricing([]PricingPlan{{Name: "business", SEPAFeeCents: 20}})
+	require.NoError(t, err)
+
+	// The repository runs the callback again, as after a transient failure.
+	repo := NewMockAccountRepository(ctrl)
+	repo.EXPECT().
+		Atomic(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+			var err error
+			for range 2 {
+				txRepo := NewMockAccountRepository(ctrl)
+				txRepo.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").
+					Return(Account{ID: 1, BalanceCents: 3000, PricingPlan: "business"}, nil)
+				txRepo.EXPECT().GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).Return(AccountHistory{}, nil)
+				err = cb(txRepo)
+			}
+			return err
+		})
+
+	screener := NewMockScreener(ctrl)
+	screener.EXPECT().Screen(gomock.Any(), gomock.Any()).Return(ScreeningResult{Decision: DecisionAllow}, nil)
+
+	service := NewService(repo, screener, NewFraudEngine(nil), pricing)
+	quote, err := service.ValidateBulkTransfer(context.Background(), BulkTransfer{
+		OrganizationIBAN: "IBAN",
+		OrganizationBIC:  "BIC",
+		Transfers: []Transfer{
+			{CounterpartyIBAN: "EE383680981021245685", AmountCents: 1000, Currency: "EUR"},
+			{CounterpartyIBAN: "EE383680981021245685", AmountCents: 1990, Currency: "EUR"},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, BulkTransferQuote{
+		TransferCount:      2,
+		TotalAmountCents:   2990,
+		FeesCents:          40,
+		BalanceBeforeCents: 3000,
+		BalanceAfterCents:  -30,
+		Violations: []Violation{
+			{TransferIndex: BatchViolation, Code: ViolationInsufficientFunds, Message: "balance does not cover the bulk transfer"},
+		},
+	}, quote)
+}
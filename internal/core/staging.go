@@ -0,0 +1,191 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Very large bulk transfers are staged in chunks, each in a short transaction
+// of its own, and executed once complete. The upload then never holds the
+// write lock and the caller holds one chunk at a time. Execution still loads
+// the whole batch, which the admission batch size bounds, because the fraud
+// rules and the funds check of all_or_nothing mode evaluate it as a whole.
+
+// stagedChunkSize is the number of staged transfers read per transaction.
+const stagedChunkSize = 1000
+
+// StagedTransfer is a transfer of a staged bulk transfer. Position is its index
+// in the request, which differs from its rank among the staged transfers when
+// invalid transfers were left out.
+type StagedTransfer struct {
+	Position int
+	Transfer Transfer
+}
+
+// StageBulkTransfer creates an empty staged bulk transfer from the debited
+// account and execution mode of bulkTransfer. Its transfers are ignored.
+func (s Service) StageBulkTransfer(ctx context.Context, bulkTransfer BulkTransfer) (int64, error) {
+	bulkTransfer.RequestID = RequestIDFromContext(ctx)
+	bulkTransfer.Transfers = nil
+
+	var stagedBulkTransferID int64
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		var err error
+		stagedBulkTransferID, err = r.AddStagedBulkTransfer(ctx, bulkTransfer)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to stage bulk transfer: %w", err)
+	}
+
+	return stagedBulkTransferID, nil
+}
+
+// StageTransfers appends transfers to a staged bulk transfer. Their positions
+// must be unique within the staged bulk transfer.
+func (s Service) StageTransfers(ctx context.Context, stagedBulkTransferID int64, transfers []StagedTransfer) error {
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		return r.AddStagedTransfers(ctx, stagedBulkTransferID, transfers)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stage transfers: %w", err)
+	}
+
+	return nil
+}
+
+// ExecuteStagedBulkTransfer processes a staged bulk transfer like
+// ProcessBulkTransfer. The result reports the position of every transfer as
+// its index. The staged bulk transfer is removed whatever the outcome, unless
+// it is held for a review.
+func (s Service) ExecuteStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) (BulkTransferResult, error) {
+	ctx, span := tracer.Start(ctx, "ExecuteStagedBulkTransfer", trace.WithAttributes(
+		attribute.Int64("staged_bulk_transfer.id", stagedBulkTransferID),
+	))
+	defer span.End()
+
+	result, err := s.executeStagedBulkTransfer(ctx, stagedBulkTransferID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return result, err
+}
+
+func (s Service) executeStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) (BulkTransferResult, error) {
+	var bulkTransfer BulkTransfer
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		var err error
+		bulkTransfer, err = r.GetStagedBulkTransfer(ctx, stagedBulkTransferID)
+		return err
+	})
+	if err != nil {
+		return BulkTransferResult{}, err
+	}
+
+	result, err := s.executeStagedTransfers(ctx, bulkTransfer, stagedBulkTransferID)
+	if errors.Is(err, ErrBulkTransferHeld) {
+		return BulkTransferResult{}, err
+	}
+	if err != nil {
+		if discardErr := s.DiscardStagedBulkTransfer(ctx, stagedBulkTransferID); discardErr != nil {
+			return BulkTransferResult{}, fmt.Errorf("%w (%w)", err, discardErr)
+		}
+		return BulkTransferResult{}, err
+	}
+
+	return result, nil
+}
+
+func (s Service) executeStagedTransfers(ctx context.Context, bulkTransfer BulkTransfer, stagedBulkTransferID int64) (BulkTransferResult, error) {
+	positions, err := s.loadStagedTransfers(ctx, &bulkTransfer, stagedBulkTransferID)
+	if err != nil {
+		return BulkTransferResult{}, err
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int("bulk_transfer.transfer_count", len(bulkTransfer.Transfers)),
+		attribute.Int64("bulk_transfer.total_cents", bulkTransfer.TotalAmount()),
+	)
+
+	result, err := s.processBulkTransfer(ctx, bulkTransfer, stagedBulkTransferID)
+	if err != nil {
+		return BulkTransferResult{}, err
+	}
+
+	for i, transfer := range result.Transfers {
+		result.Transfers[i].Index = positions[transfer.Index]
+	}
+
+	return result, nil
+}
+
+// loadStagedTransfers appends the staged transfers to bulkTransfer in the order
+// of their positions, and returns these positions. The transfers are read
+// after a cursor on their position, one chunk per transaction, so that no
+// query or transaction grows with the batch.
+func (s Service) loadStagedTransfers(ctx context.Context, bulkTransfer *BulkTransfer, stagedBulkTransferID int64) ([]int, error) {
+	var positions []int
+	afterPosition := -1
+	for {
+		var chunk []StagedTransfer
+		err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+			var err error
+			chunk, err = r.ListStagedTransfers(ctx, stagedBulkTransferID, afterPosition, stagedChunkSize)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load staged transfers: %w", err)
+		}
+
+		for _, staged := range chunk {
+			bulkTransfer.Transfers = append(bulkTransfer.Transfers, staged.Transfer)
+			positions = append(positions, staged.Position)
+		}
+		if len(chunk) < stagedChunkSize {
+			return positions, nil
+		}
+		afterPosition = chunk[len(chunk)-1].Position
+	}
+}
+
+// DiscardStagedBulkTransfer removes a staged bulk transfer without executing it.
+// It runs even once ctx is canceled, so that an aborted upload leaves no
+// staged rows behind.
+func (s Service) DiscardStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) error {
+	ctx = context.WithoutCancel(ctx)
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		return r.DeleteStagedBulkTransfer(ctx, stagedBulkTransferID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to discard staged bulk transfer: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeStagedBulkTransfers removes the staged bulk transfers created before
+// createdBefore, and the staged transfers left without their bulk transfer. A
+// staged bulk transfer lives no longer than its upload: older ones were
+// abandoned by a crash. Those held for a review are kept. It returns the
+// number of staged bulk transfers removed.
+func (s Service) PurgeStagedBulkTransfers(ctx context.Context, createdBefore time.Time) (int, error) {
+	var purged int
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		var err error
+		purged, err = r.DeleteStagedBulkTransfersBefore(ctx, createdBefore)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge staged bulk transfers: %w", err)
+	}
+
+	return purged, nil
+}
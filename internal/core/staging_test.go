@@ -0,0 +1,193 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_ExecuteStagedBulkTransfer(t *testing.T) {
+	t.Parallel()
+
+	staged := BulkTransfer{
+		OrganizationBIC:  "OIVUSCLQXXX",
+		OrganizationIBAN: "FR10474608000002006107XXXXX",
+		ExecutionMode:    ExecutionModeAllOrNothing,
+	}
+	dbErr := errors.New("database is locked")
+	// The transfer at position 0 was left out of the staged bulk transfer.
+	transfers := []StagedTransfer{
+		{Position: 1, Transfer: Transfer{CounterpartyName: "Bip Bip", CounterpartyIBAN: "EE383680981021245685", AmountCents: 1450, Currency: "EUR"}},
+	}
+
+	// atomic expects one Atomic call whose transaction is set up by setup.
+	atomic := func(t *testing.T, m *MockAccountRepository, setup func(*MockAccountRepository)) *gomock.Call {
+		return m.EXPECT().
+			Atomic(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+				txRepo := NewMockAccountRepository(gomock.NewController(t))
+				setup(txRepo)
+				return cb(txRepo)
+			})
+	}
+
+	tests := []struct {
+		name           string
+		mockSetup      func(t *testing.T, m *MockAccountRepository)
+		expectedResult BulkTransferResult
+		expectedError  error
+	}{
+		{
+			name: "executes_and_deletes_the_staged_bulk_transfer_in_one_transaction",
+			mockSetup: func(t *testing.T, m *MockAccountRepository) {
+				header := atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().GetStagedBulkTransfer(gomock.Any(), int64(7)).Return(staged, nil)
+				})
+				load := atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().ListStagedTransfers(gomock.Any(), int64(7), -1, stagedChunkSize).Return(transfers, nil)
+				}).After(header)
+				atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().GetAccountByID(gomock.Any(), staged.OrganizationIBAN, staged.OrganizationBIC).Return(Account{ID: 1, BalanceCents: 10000}, nil)
+					r.EXPECT().GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).Return(AccountHistory{}, nil)
+					r.EXPECT().UpdateBalance(gomock.Any(), Account{ID: 1, BalanceCents: 8550}).Return(nil)
+					r.EXPECT().AddBulkTransfer(gomock.Any(), int64(1), gomock.Any()).Return(int64(42), nil)
+					r.EXPECT().AddTransfers(gomock.Any(), gomock.Len(1)).Return(nil)
+					r.EXPECT().AppendAuditRecord(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+					r.EXPECT().DeleteStagedBulkTransfer(gomock.Any(), int64(7)).Return(nil)
+				}).After(load)
+			},
+			expectedResult: BulkTransferResult{
+				BulkTransferID: 42,
+				ExecutionMode:  ExecutionModeAllOrNothing,
+				Transfers:      []TransferResult{{Index: 1, Status: TransferAccepted}},
+			},
+		},
+		{
+			name: "rejection_is_audited_and_the_staged_bulk_transfer_discarded",
+			mockSetup: func(t *testing.T, m *MockAccountRepository) {
+				header := atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().GetStagedBulkTransfer(gomock.Any(), int64(7)).Return(staged, nil)
+				})
+				load := atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().ListStagedTransfers(gomock.Any(), int64(7), -1, stagedChunkSize).Return(transfers, nil)
+				}).After(header)
+				execute := atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().GetAccountByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(Account{ID: 1, BalanceCents: 100}, nil)
+					r.EXPECT().GetAccountHistory(gomock.Any(), int64(1), gomock.Any()).Return(AccountHistory{}, nil)
+				}).After(load)
+				audit := atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().AppendAuditRecord(gomock.Any(), gomock.Any()).Return(nil)
+				}).After(execute)
+				atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().DeleteStagedBulkTransfer(gomock.Any(), int64(7)).Return(nil)
+				}).After(audit)
+			},
+			expectedError: ErrInsufficientFunds,
+		},
+		{
+			name: "transfers_are_read_after_a_cursor_until_a_short_chunk",
+			mockSetup: func(t *testing.T, m *MockAccountRepository) {
+				full := make([]StagedTransfer, stagedChunkSize)
+				for i := range full {
+					full[i] = StagedTransfer{Position: 2 * i, Transfer: transfers[0].Transfer}
+				}
+				last := full[len(full)-1].Position
+
+				header := atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().GetStagedBulkTransfer(gomock.Any(), int64(7)).Return(staged, nil)
+				})
+				first := atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().ListStagedTransfers(gomock.Any(), int64(7), -1, stagedChunkSize).Return(full, nil)
+				}).After(header)
+				second := atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().ListStagedTransfers(gomock.Any(), int64(7), last, stagedChunkSize).Return(nil, nil)
+				}).After(first)
+				execute := atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().GetAccountByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(Account{ID: 1, Frozen: true}, nil)
+				}).After(second)
+				audit := atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().AppendAuditRecord(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, record AuditRecord) error {
+						require.Equal(t, stagedChunkSize, record.TransferCount)
+						return nil
+					})
+				}).After(execute)
+				atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().DeleteStagedBulkTransfer(gomock.Any(), int64(7)).Return(nil)
+				}).After(audit)
+			},
+			expectedError: ErrAccountFrozen,
+		},
+		{
+			name: "failed_load_discards_the_staged_bulk_transfer",
+			mockSetup: func(t *testing.T, m *MockAccountRepository) {
+				header := atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().GetStagedBulkTransfer(gomock.Any(), int64(7)).Return(staged, nil)
+				})
+				load := m.EXPECT().Atomic(gomock.Any(), gomock.Any()).Return(dbErr).After(header)
+				atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().DeleteStagedBulkTransfer(gomock.Any(), int64(7)).Return(nil)
+				}).After(load)
+			},
+			expectedError: dbErr,
+		},
+		{
+			name: "unknown_staged_bulk_transfer",
+			mockSetup: func(t *testing.T, m *MockAccountRepository) {
+				atomic(t, m, func(r *MockAccountRepository) {
+					r.EXPECT().GetStagedBulkTransfer(gomock.Any(), int64(7)).Return(BulkTransfer{}, ErrStagedBulkTransferNotFound)
+				})
+			},
+			expectedError: ErrStagedBulkTransferNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := NewMockAccountRepository(ctrl)
+			tt.mockSetup(t, mockRepo)
+
+			mockScreener := NewMockScreener(ctrl)
+			mockScreener.EXPECT().
+				Screen(gomock.Any(), gomock.Any()).
+				Return(ScreeningResult{Decision: DecisionAllow}, nil).
+				AnyTimes()
+
+			service := NewService(mockRepo, mockScreener, NewFraudEngine(nil), Pricing{})
+			result, err := service.ExecuteStagedBulkTransfer(context.Background(), 7)
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestService_PurgeStagedBulkTransfers(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	createdBefore := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := atomicRepository(ctrl, func(r *MockAccountRepository) {
+		r.EXPECT().DeleteStagedBulkTransfersBefore(gomock.Any(), createdBefore).Return(2, nil)
+	})
+
+	service := NewService(repo, nil, NewFraudEngine(nil), Pricing{})
+	purged, err := service.PurgeStagedBulkTransfers(context.Background(), createdBefore)
+	require.NoError(t, err)
+	require.Equal(t, 2, purged)
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetBulkTransfer returns an executed bulk transfer.
+func (s Service) GetBulkTransfer(ctx context.Context, bulkTransferID int64) (BulkTransferRecord, error) {
+	var record BulkTransferRecord
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		var err error
+		record, err = r.GetBulkTransfer(ctx, bulkTransferID)
+		return err
+	})
+	if err != nil {
+		return BulkTransferRecord{}, fmt.Errorf("failed to get bulk transfer: %w", err)
+	}
+
+	return record, nil
+}
+
+// ListTransactions returns a page of the transactions of an account.
+func (s Service) ListTransactions(ctx context.Context, iban string, bic string, query TransactionQuery) ([]Transaction, error) {
+	var transactions []Transaction
+	err := s.accountRepository.Atomic(ctx, func(r AccountRepository) error {
+		account, err := r.GetAccountByID(ctx, iban, bic)
+		if err != nil {
+			return err
+		}
+
+		transactions, err = r.ListTransactions(ctx, account.ID, query)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	return transactions, nil
+}
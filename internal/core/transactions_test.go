@@ -0,0 +1,67 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_ListTransactions(t *testing.T) {
+	t.Parallel()
+
+	query := TransactionQuery{BulkTransferID: 3, AfterID: 10, Limit: 50}
+
+	tests := []struct {
+		name          string
+		mockSetup     func(r *MockAccountRepository)
+		expected      []Transaction
+		expectedError error
+	}{
+		{
+			name: "lists_the_transactions_of_the_account",
+			mockSetup: func(r *MockAccountRepository) {
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{ID: 1}, nil)
+				r.EXPECT().ListTransactions(gomock.Any(), int64(1), query).Return([]Transaction{{ID: 11, AccountID: 1}}, nil)
+			},
+			expected: []Transaction{{ID: 11, AccountID: 1}},
+		},
+		{
+			name: "unknown_account",
+			mockSetup: func(r *MockAccountRepository) {
+				r.EXPECT().GetAccountByID(gomock.Any(), "IBAN", "BIC").Return(Account{}, ErrAccountNotFound)
+			},
+			expectedError: ErrAccountNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := NewMockAccountRepository(ctrl)
+			mockRepo.EXPECT().
+				Atomic(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, cb func(AccountRepository) error) error {
+					txRepo := NewMockAccountRepository(ctrl)
+					tt.mockSetup(txRepo)
+					return cb(txRepo)
+				})
+
+			service := NewService(mockRepo, NewMockScreener(ctrl), NewFraudEngine(nil), Pricing{})
+			transactions, err := service.ListTransactions(context.Background(), "IBAN", "BIC", query)
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, transactions)
+		})
+	}
+}
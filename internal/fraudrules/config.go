@@ -0,0 +1,10 @@
+package fraudrules
+
+import (
+	"time"
+)
+
+type Config struct {
+	Path           string        `envconfig:"FRAUD_RULES_PATH"`                          // JSON rules file, no rule applies when empty
+	ReloadInterval time.Duration `envconfig:"FRAUD_RULES_RELOAD_INTERVAL" default:"10s"` // How often the file is checked for changes
+}
@@ -0,0 +1,61 @@
+package fraudrules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"payment/internal/core"
+)
+
+type rulesFile struct {
+	Rules []ruleSpec `json:"rules"`
+}
+
+type ruleSpec struct {
+	Type                string  `json:"type"`
+	Decision            string  `json:"decision"`
+	MinAmountCents      int64   `json:"min_amount_cents"`
+	Multiplier          float64 `json:"multiplier"`
+	MinHistory          int     `json:"min_history"`
+	MaxTransfersPerIBAN int     `json:"max_transfers_per_iban"`
+}
+
+func LoadRules(path string) ([]core.FraudRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fraud rules: %w", err)
+	}
+
+	return ParseRules(data)
+}
+
+func ParseRules(data []byte) ([]core.FraudRule, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var file rulesFile
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse fraud rules: %w", err)
+	}
+
+	specs := make([]core.FraudRuleSpec, 0, len(file.Rules))
+	for _, rule := range file.Rules {
+		specs = append(specs, core.FraudRuleSpec{
+			Type:                rule.Type,
+			Decision:            core.Decision(rule.Decision),
+			MinAmountCents:      rule.MinAmountCents,
+			Multiplier:          rule.Multiplier,
+			MinHistory:          rule.MinHistory,
+			MaxTransfersPerIBAN: rule.MaxTransfersPerIBAN,
+		})
+	}
+
+	rules, err := core.BuildFraudRules(specs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fraud rules: %w", err)
+	}
+
+	return rules, nil
+}
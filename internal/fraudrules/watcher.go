@@ -0,0 +1,97 @@
+package fraudrules
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"payment/internal/core"
+)
+
+type Logger interface {
+	InfoContext(ctx context.Context, msg string, args ...any)
+	ErrorContext(ctx context.Context, msg string, args ...any)
+}
+
+// Watcher keeps a FraudEngine in sync with the rules file. It polls the
+// file's modification time, which works on every platform and through
+// Kubernetes ConfigMap symlink swaps.
+type Watcher struct {
+	config  Config
+	engine  *core.FraudEngine
+	logger  Logger
+	modTime time.Time
+}
+
+func NewWatcher(config Config, engine *core.FraudEngine, logger Logger) *Watcher {
+	return &Watcher{
+		config: config,
+		engine: engine,
+		logger: logger,
+	}
+}
+
+// Load reads the rules file into the engine. Unlike Run, it returns an error
+// on invalid rules so that the service does not start with a broken file.
+func (w *Watcher) Load() error {
+	if w.config.Path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(w.config.Path)
+	if err != nil {
+		return fmt.Errorf("failed to stat fraud rules: %w", err)
+	}
+
+	rules, err := LoadRules(w.config.Path)
+	if err != nil {
+		return err
+	}
+
+	w.engine.SetRules(rules)
+	w.modTime = info.ModTime()
+
+	return nil
+}
+
+// Run reloads the rules whenever the file changes, until ctx is done. Invalid
+// files are logged and the previous rules stay in place.
+func (w *Watcher) Run(ctx context.Context) {
+	if w.config.Path == "" || w.config.ReloadInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.config.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.reload(ctx)
+		}
+	}
+}
+
+func (w *Watcher) reload(ctx context.Context) {
+	info, err := os.Stat(w.config.Path)
+	if err != nil {
+		w.logger.ErrorContext(ctx, "Failed to stat fraud rules", "path", w.config.Path, "error", err)
+		return
+	}
+
+	if info.ModTime().Equal(w.modTime) {
+		return
+	}
+
+	if err = w.Load(); err != nil {
+		// Remember the broken version so that it is reported once, not on every tick.
+		w.modTime = info.ModTime()
+		w.logger.ErrorContext(ctx, "Failed to reload fraud rules, keeping previous rules", "path", w.config.Path, "error", err)
+		return
+	}
+
+	w.logger.InfoContext(ctx, "Fraud rules reloaded", "path", w.config.Path, "rules", len(w.engine.Rules()))
+}
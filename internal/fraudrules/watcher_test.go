@@ -0,0 +1,125 @@
+package fraudrules
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"payment/internal/core"
+)
+
+func TestParseRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		data          string
+		expectedRules []string
+		expectedError bool
+	}{
+		{
+			name: "all_builtin_rules",
+			data: `{"rules": [
+				{"type": "new_counterparty_large_amount", "decision": "review", "min_amount_cents": 500000},
+				{"type": "batch_total_above_average", "decision": "block", "multiplier": 5, "min_history": 3},
+				{"type": "repeated_counterparty", "max_transfers_per_iban": 10}
+			]}`,
+			expectedRules: []string{
+				core.FraudRuleNewCounterpartyLargeAmount,
+				core.FraudRuleBatchTotalAboveAverage,
+				core.FraudRuleRepeatedCounterparty,
+			},
+		},
+		{
+			name:          "empty_rules",
+			data:          `{"rules": []}`,
+			expectedRules: []string{},
+		},
+		{
+			name:          "unknown_field",
+			data:          `{"rules": [{"type": "repeated_counterparty", "max_transfers": 10}]}`,
+			expectedError: true,
+		},
+		{
+			name:          "invalid_rule",
+			data:          `{"rules": [{"type": "repeated_counterparty"}]}`,
+			expectedError: true,
+		},
+		{
+			name:          "invalid_json",
+			data:          `{"rules": [`,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules, err := ParseRules([]byte(tt.data))
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				names = append(names, rule.Name())
+			}
+			require.Equal(t, tt.expectedRules, names)
+		})
+	}
+}
+
+func TestWatcher_ReloadsChangedFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	writeRules := func(data string, modTime time.Time) {
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	start := time.Now().Add(-time.Hour)
+	writeRules(`{"rules": [{"type": "repeated_counterparty", "max_transfers_per_iban": 10}]}`, start)
+
+	engine := core.NewFraudEngine(nil)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	watcher := NewWatcher(Config{Path: path, ReloadInterval: time.Millisecond}, engine, logger)
+
+	require.NoError(t, watcher.Load())
+	require.Len(t, engine.Rules(), 1)
+
+	writeRules(`{"rules": [
+		{"type": "repeated_counterparty", "max_transfers_per_iban": 10},
+		{"type": "new_counterparty_large_amount", "min_amount_cents": 100}
+	]}`, start.Add(time.Minute))
+	watcher.reload(context.Background())
+	require.Len(t, engine.Rules(), 2)
+
+	writeRules(`{"rules": [{"type": "unknown"}]}`, start.Add(2*time.Minute))
+	watcher.reload(context.Background())
+	require.Len(t, engine.Rules(), 2, "invalid files keep the previous rules")
+}
+
+func TestWatcher_NoPath(t *testing.T) {
+	t.Parallel()
+
+	engine := core.NewFraudEngine(nil)
+	watcher := NewWatcher(Config{}, engine, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	require.NoError(t, watcher.Load())
+	require.Empty(t, engine.Rules())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	watcher.Run(ctx)
+}
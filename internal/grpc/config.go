@@ -0,0 +1,5 @@
+package grpc
+
+type Config struct {
+	Address string `envconfig:"GRPC_ADDRESS" default:"localhost:9090"`
+}
@@ -0,0 +1,158 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"payment/internal/core"
+)
+
+// errorDomain is the ErrorInfo domain of the reasons below.
+const errorDomain = "payment"
+
+// ErrorInfo reasons. They are stable and meant to be matched by clients.
+const (
+	ReasonAccountNotFound      = "ACCOUNT_NOT_FOUND"
+	ReasonBulkTransferNotFound = "BULK_TRANSFER_NOT_FOUND"
+	ReasonInsufficientFunds    = "INSUFFICIENT_FUNDS"
+	ReasonAccountFrozen        = "ACCOUNT_FROZEN"
+	ReasonHoldNotFound         = "HOLD_NOT_FOUND"
+	ReasonHoldNotPending       = "HOLD_NOT_PENDING"
+	ReasonSanctionsBlocked     = "SANCTIONS_BLOCKED"
+	ReasonFraudBlocked         = "FRAUD_BLOCKED"
+	ReasonUnknownAPIKey        = "UNKNOWN_API_KEY"
+	ReasonOrganizationRequired = "ORGANIZATION_REQUIRED"
+	ReasonRateLimited          = "RATE_LIMITED"
+	ReasonQuotaExceeded        = "QUOTA_EXCEEDED"
+)
+
+// held returns the *core.HeldError of a bulk transfer held for a manual
+// review, which is answered with a response rather than a status.
+func (h Handler) held(ctx context.Context, err error) (*core.HeldError, bool) {
+	var heldErr *core.HeldError
+	if !errors.As(err, &heldErr) {
+		return nil, false
+	}
+
+	// Watchlist entries and rule thresholds are not returned to the client.
+	var sanctionsErr *core.SanctionsError
+	if errors.As(heldErr.Reason, &sanctionsErr) {
+		h.logger.InfoContext(ctx, "Bulk transfer held for sanctions review", "held_bulk_transfer_id", heldErr.HeldBulkTransferID, "hits", sanctionsErr.Hits)
+	}
+
+	var fraudErr *core.FraudError
+	if errors.As(heldErr.Reason, &fraudErr) {
+		h.logger.InfoContext(ctx, "Bulk transfer held for fraud review", "held_bulk_transfer_id", heldErr.HeldBulkTransferID, "reasons", fraudErr.Reasons)
+	}
+
+	return heldErr, true
+}
+
+// processingError maps a service error to a status with an ErrorInfo detail.
+func (h Handler) processingError(ctx context.Context, err error) error {
+	if errors.Is(err, core.ErrAccountNotFound) {
+		return withDetails(codes.NotFound, "Account not found", errorInfo(ReasonAccountNotFound))
+	}
+
+	if errors.Is(err, core.ErrBulkTransferNotFound) {
+		return withDetails(codes.NotFound, "Bulk transfer not found", errorInfo(ReasonBulkTransferNotFound))
+	}
+
+	if errors.Is(err, core.ErrInsufficientFunds) {
+		return withDetails(codes.FailedPrecondition, "Insufficient funds for bulk transfer",
+			errorInfo(ReasonInsufficientFunds),
+			&errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        ReasonInsufficientFunds,
+				Subject:     "organization_iban",
+				Description: "the account balance does not cover the bulk transfer",
+			}}},
+		)
+	}
+
+	if errors.Is(err, core.ErrAccountFrozen) {
+		return withDetails(codes.FailedPrecondition, "Account is frozen",
+			errorInfo(ReasonAccountFrozen),
+			&errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        ReasonAccountFrozen,
+				Subject:     "organization_iban",
+				Description: "the account is frozen",
+			}}},
+		)
+	}
+
+	if errors.Is(err, core.ErrHoldNotFound) {
+		return withDetails(codes.NotFound, "Hold not found", errorInfo(ReasonHoldNotFound))
+	}
+
+	if errors.Is(err, core.ErrHoldNotPending) {
+		return withDetails(codes.FailedPrecondition, "Hold is no longer pending",
+			errorInfo(ReasonHoldNotPending),
+			&errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{{
+				Type:        ReasonHoldNotPending,
+				Subject:     "hold_id",
+				Description: "the hold was captured, released or has expired",
+			}}},
+		)
+	}
+
+	var quotaErr *core.QuotaError
+	if errors.As(err, &quotaErr) {
+		return retryLater("Daily transfer quota exceeded", ReasonQuotaExceeded, quotaErr.RetryAfter)
+	}
+
+	var batchSizeErr *core.BatchSizeError
+	if errors.As(err, &batchSizeErr) {
+		return invalidArgument(&errdetails.BadRequest_FieldViolation{
+			Field:       "credit_transfers",
+			Description: fmt.Sprintf("exceeds the maximum batch size of %d transfers", batchSizeErr.MaxSize),
+		})
+	}
+
+	var sanctionsErr *core.SanctionsError
+	if errors.As(err, &sanctionsErr) {
+		// Watchlist entries are not returned to the client to avoid tipping off.
+		h.logger.InfoContext(ctx, "Bulk transfer stopped by sanctions screening", "decision", sanctionsErr.Decision, "hits", sanctionsErr.Hits)
+		return withDetails(codes.PermissionDenied, "Bulk transfer blocked by sanctions screening", errorInfo(ReasonSanctionsBlocked))
+	}
+
+	var fraudErr *core.FraudError
+	if errors.As(err, &fraudErr) {
+		// Rule thresholds are not returned to the client.
+		h.logger.InfoContext(ctx, "Bulk transfer stopped by fraud rules", "decision", fraudErr.Decision, "reasons", fraudErr.Reasons)
+		return withDetails(codes.PermissionDenied, "Bulk transfer blocked by fraud rules", errorInfo(ReasonFraudBlocked))
+	}
+
+	h.logger.ErrorContext(ctx, "Failed to process request", "error", err)
+	return status.Error(codes.Internal, "Internal server error")
+}
+
+func invalidArgument(violations ...*errdetails.BadRequest_FieldViolation) error {
+	return withDetails(codes.InvalidArgument, "Request validation failed", &errdetails.BadRequest{FieldViolations: violations})
+}
+
+// retryLater refuses a call over a limit and tells the client when to retry.
+func retryLater(message string, reason string, retryAfter time.Duration) error {
+	return withDetails(codes.ResourceExhausted, message, errorInfo(reason), &errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+}
+
+func errorInfo(reason string) *errdetails.ErrorInfo {
+	return &errdetails.ErrorInfo{Reason: reason, Domain: errorDomain}
+}
+
+func withDetails(code codes.Code, message string, details ...protoadapt.MessageV1) error {
+	st, err := status.New(code, message).WithDetails(details...)
+	if err != nil {
+		// Only fails for details that cannot be marshalled.
+		return status.Error(code, message)
+	}
+
+	return st.Err()
+}
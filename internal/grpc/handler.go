@@ -0,0 +1,351 @@
+package grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentv1 "payment/api/payment/v1"
+	"payment/internal/core"
+	"payment/internal/ratelimit"
+)
+
+//go:generate go tool go.uber.org/mock/mockgen -source=handler.go -destination=service_mock.go -package=grpc
+
+type BulkTransferService interface {
+	ProcessBulkTransfer(ctx context.Context, bulkTransfer core.BulkTransfer) (core.BulkTransferResult, error)
+	GetBulkTransfer(ctx context.Context, bulkTransferID int64) (core.BulkTransferRecord, error)
+	ListTransactions(ctx context.Context, iban string, bic string, query core.TransactionQuery) ([]core.Transaction, error)
+}
+
+type RateLimiter interface {
+	Allow(ctx context.Context, apiKey string, address string) (ratelimit.Decision, error)
+}
+
+type Logger interface {
+	InfoContext(ctx context.Context, msg string, args ...any)
+	ErrorContext(ctx context.Context, msg string, args ...any)
+}
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+type Handler struct {
+	paymentv1.UnimplementedBulkTransferServiceServer
+
+	service BulkTransferService
+	logger  Logger
+}
+
+func NewHandler(service BulkTransferService, logger Logger) Handler {
+	return Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// SubmitBulkTransfer executes a bulk transfer. Unlike the HTTP API, invalid
+// credit transfers fail the whole request in both execution modes.
+func (h Handler) SubmitBulkTransfer(ctx context.Context, req *paymentv1.SubmitBulkTransferRequest) (*paymentv1.SubmitBulkTransferResponse, error) {
+	bulkTransfer, err := submitRequestToDomain(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = core.WithActor(ctx, callerID(ctx, req.GetOrganizationIban()))
+
+	result, err := h.service.ProcessBulkTransfer(ctx, bulkTransfer)
+	if heldErr, ok := h.held(ctx, err); ok {
+		return &paymentv1.SubmitBulkTransferResponse{
+			HeldBulkTransferId: heldErr.HeldBulkTransferID,
+			Review:             reviewToProto(heldErr.Review),
+		}, nil
+	}
+	if err != nil {
+		return nil, h.processingError(ctx, err)
+	}
+
+	response := &paymentv1.SubmitBulkTransferResponse{
+		BulkTransferId: result.BulkTransferID,
+		ExecutionMode:  executionModeToProto(result.ExecutionMode),
+		Transfers:      make([]*paymentv1.TransferResult, 0, len(result.Transfers)),
+		FeeCents:       result.FeesCents(),
+	}
+	for _, transfer := range result.Transfers {
+		status := paymentv1.TransferStatus_TRANSFER_STATUS_ACCEPTED
+		if transfer.Status == core.TransferRejected {
+			status = paymentv1.TransferStatus_TRANSFER_STATUS_REJECTED
+		}
+
+		response.Transfers = append(response.Transfers, &paymentv1.TransferResult{
+			Index:    int32(transfer.Index),
+			Status:   status,
+			Reason:   transfer.Reason,
+			FeeCents: transfer.FeeCents,
+		})
+	}
+
+	return response, nil
+}
+
+// GetBulkTransfer returns a bulk transfer of the organization the caller's API
+// key is bound to. Callers without such a key are refused, and a bulk transfer
+// of another organization is reported as not found, so that callers cannot
+// probe the IDs of others.
+func (h Handler) GetBulkTransfer(ctx context.Context, req *paymentv1.GetBulkTransferRequest) (*paymentv1.BulkTransfer, error) {
+	organization, _ := ctx.Value(clientOrganization{}).(string)
+	if organization == "" {
+		return nil, withDetails(codes.PermissionDenied, "API key not bound to an organization", errorInfo(ReasonOrganizationRequired))
+	}
+
+	if req.GetBulkTransferId() <= 0 {
+		return nil, invalidArgument(&errdetails.BadRequest_FieldViolation{Field: "bulk_transfer_id", Description: "must be positive"})
+	}
+
+	record, err := h.service.GetBulkTransfer(ctx, req.GetBulkTransferId())
+	if err != nil {
+		return nil, h.processingError(ctx, err)
+	}
+
+	if record.OrganizationIBAN != organization {
+		return nil, h.processingError(ctx, core.ErrBulkTransferNotFound)
+	}
+
+	return &paymentv1.BulkTransfer{
+		Id:               record.ID,
+		OrganizationBic:  record.OrganizationBIC,
+		OrganizationIban: record.OrganizationIBAN,
+		ExecutionMode:    executionModeToProto(record.ExecutionMode),
+		TotalAmountCents: record.TotalCents,
+		TransferCount:    int32(record.TransferCount),
+		RequestId:        record.RequestID,
+		CreateTime:       timestamppb.New(record.CreatedAt),
+	}, nil
+}
+
+func (h Handler) ListTransactions(ctx context.Context, req *paymentv1.ListTransactionsRequest) (*paymentv1.ListTransactionsResponse, error) {
+	query, err := listRequestToQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra transaction tells whether there is a next page.
+	limit := query.Limit
+	query.Limit++
+
+	transactions, err := h.service.ListTransactions(ctx, req.GetOrganizationIban(), req.GetOrganizationBic(), query)
+	if err != nil {
+		return nil, h.processingError(ctx, err)
+	}
+
+	response := &paymentv1.ListTransactionsResponse{}
+	if len(transactions) > limit {
+		transactions = transactions[:limit]
+		response.NextPageToken = encodePageToken(transactions[limit-1].ID)
+	}
+
+	response.Transactions = make([]*paymentv1.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		response.Transactions = append(response.Transactions, &paymentv1.Transaction{
+			Id:               transaction.ID,
+			BulkTransferId:   transaction.BulkTransferID,
+			CounterpartyName: transaction.CounterpartyName,
+			CounterpartyBic:  transaction.CounterpartyBIC,
+			CounterpartyIban: transaction.CounterpartyIBAN,
+			AmountCents:      transaction.AmountCents,
+			Currency:         transaction.Currency,
+			Description:      transaction.Description,
+			Kind:             string(transaction.Kind),
+		})
+	}
+
+	return response, nil
+}
+
+func submitRequestToDomain(req *paymentv1.SubmitBulkTransferRequest) (core.BulkTransfer, error) {
+	var violations []*errdetails.BadRequest_FieldViolation
+	required := func(field string, value string) {
+		if value == "" {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field, Description: "is required"})
+		}
+	}
+
+	required("organization_bic", req.GetOrganizationBic())
+	required("organization_iban", req.GetOrganizationIban())
+
+	executionMode, ok := executionModeToDomain(req.GetExecutionMode())
+	if !ok {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "execution_mode", Description: "is not a known execution mode"})
+	}
+
+	if req.GetHoldId() < 0 {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "hold_id", Description: "must not be negative"})
+	}
+
+	if len(req.GetCreditTransfers()) == 0 {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "credit_transfers", Description: "must contain at least 1 item"})
+	}
+
+	transfers := make([]core.Transfer, 0, len(req.GetCreditTransfers()))
+	for i, ct := range req.GetCreditTransfers() {
+		field := func(name string) string {
+			return fmt.Sprintf("credit_transfers[%d].%s", i, name)
+		}
+
+		if ct.GetAmountCents() <= 0 {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field("amount_cents"), Description: "must be positive"})
+		}
+		if ct.GetCurrency() != "EUR" {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field("currency"), Description: "must be EUR"})
+		}
+		required(field("counterparty_name"), ct.GetCounterpartyName())
+		required(field("counterparty_bic"), ct.GetCounterpartyBic())
+		required(field("counterparty_iban"), ct.GetCounterpartyIban())
+		required(field("description"), ct.GetDescription())
+		if (ct.GetDebtorIban() == "") != (ct.GetDebtorBic() == "") {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field("debtor_iban"), Description: "must be set together with debtor_bic"})
+		}
+
+		transfers = append(transfers, core.Transfer{
+			CounterpartyName: ct.GetCounterpartyName(),
+			CounterpartyIBAN: ct.GetCounterpartyIban(),
+			CounterpartyBIC:  ct.GetCounterpartyBic(),
+			AmountCents:      ct.GetAmountCents(),
+			Currency:         ct.GetCurrency(),
+			Description:      ct.GetDescription(),
+			DebtorIBAN:       ct.GetDebtorIban(),
+			DebtorBIC:        ct.GetDebtorBic(),
+		})
+	}
+
+	if len(violations) > 0 {
+		return core.BulkTransfer{}, invalidArgument(violations...)
+	}
+
+	return core.BulkTransfer{
+		OrganizationBIC:  req.GetOrganizationBic(),
+		OrganizationIBAN: req.GetOrganizationIban(),
+		ExecutionMode:    executionMode,
+		HoldID:           req.GetHoldId(),
+		Transfers:        transfers,
+	}, nil
+}
+
+func listRequestToQuery(req *paymentv1.ListTransactionsRequest) (core.TransactionQuery, error) {
+	var violations []*errdetails.BadRequest_FieldViolation
+	if req.GetOrganizationBic() == "" {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "organization_bic", Description: "is required"})
+	}
+	if req.GetOrganizationIban() == "" {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "organization_iban", Description: "is required"})
+	}
+	if req.GetBulkTransferId() < 0 {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "bulk_transfer_id", Description: "must not be negative"})
+	}
+
+	pageSize := int(req.GetPageSize())
+	switch {
+	case pageSize < 0:
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "page_size", Description: "must not be negative"})
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+
+	afterID, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "page_token", Description: "is invalid"})
+	}
+
+	if len(violations) > 0 {
+		return core.TransactionQuery{}, invalidArgument(violations...)
+	}
+
+	return core.TransactionQuery{
+		BulkTransferID: req.GetBulkTransferId(),
+		AfterID:        afterID,
+		Limit:          pageSize,
+	}, nil
+}
+
+// Page tokens are opaque to clients; they hold the ID of the last transaction
+// returned.
+func encodePageToken(afterID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(afterID, 10)))
+}
+
+func decodePageToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+
+	afterID, err := strconv.ParseInt(string(decoded), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if afterID <= 0 {
+		return 0, fmt.Errorf("invalid page token %q", token)
+	}
+
+	return afterID, nil
+}
+
+func executionModeToDomain(mode paymentv1.ExecutionMode) (core.ExecutionMode, bool) {
+	switch mode {
+	case paymentv1.ExecutionMode_EXECUTION_MODE_UNSPECIFIED:
+		return "", true
+	case paymentv1.ExecutionMode_EXECUTION_MODE_ALL_OR_NOTHING:
+		return core.ExecutionModeAllOrNothing, true
+	case paymentv1.ExecutionMode_EXECUTION_MODE_PARTIAL:
+		return core.ExecutionModePartial, true
+	default:
+		return "", false
+	}
+}
+
+func executionModeToProto(mode core.ExecutionMode) paymentv1.ExecutionMode {
+	switch mode {
+	case core.ExecutionModeAllOrNothing:
+		return paymentv1.ExecutionMode_EXECUTION_MODE_ALL_OR_NOTHING
+	case core.ExecutionModePartial:
+		return paymentv1.ExecutionMode_EXECUTION_MODE_PARTIAL
+	default:
+		return paymentv1.ExecutionMode_EXECUTION_MODE_UNSPECIFIED
+	}
+}
+
+func reviewToProto(review core.ReviewKind) paymentv1.Review {
+	switch review {
+	case core.ReviewKindSanctions:
+		return paymentv1.Review_REVIEW_SANCTIONS
+	case core.ReviewKindFraud:
+		return paymentv1.Review_REVIEW_FRAUD
+	default:
+		return paymentv1.Review_REVIEW_UNSPECIFIED
+	}
+}
+
+// callerID identifies the caller for the audit log, like the HTTP API does:
+// by its API key when it sent a configured one, by the debited organization
+// otherwise.
+func callerID(ctx context.Context, organizationIBAN string) string {
+	if key, _ := ctx.Value(clientKey{}).(string); strings.HasPrefix(key, "api_key:") {
+		return key
+	}
+
+	return "org:" + organizationIBAN
+}
@@ -0,0 +1,559 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	paymentv1 "payment/api/payment/v1"
+	"payment/internal/core"
+)
+
+func validSubmitRequest() *paymentv1.SubmitBulkTransferRequest {
+	return &paymentv1.SubmitBulkTransferRequest{
+		OrganizationBic:  "OIVUSCLQXXX",
+		OrganizationIban: "FR10474608000002006107XXXXX",
+		CreditTransfers: []*paymentv1.CreditTransfer{
+			{
+				AmountCents:      1450,
+				Currency:         "EUR",
+				CounterpartyName: "Bip Bip",
+				CounterpartyBic:  "CRLYFRPPTOU",
+				CounterpartyIban: "EE383680981021245685",
+				Description:      "Wonderland/4410",
+			},
+		},
+	}
+}
+
+func TestHandler_SubmitBulkTransfer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		request    func() *paymentv1.SubmitBulkTransferRequest
+		setupMock  func(mock *MockBulkTransferService)
+		expected   *paymentv1.SubmitBulkTransferResponse
+		code       codes.Code
+		reason     string
+		violations []string
+	}{
+		{
+			name:    "accepted",
+			request: validSubmitRequest,
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), core.BulkTransfer{
+						OrganizationBIC:  "OIVUSCLQXXX",
+						OrganizationIBAN: "FR10474608000002006107XXXXX",
+						Transfers: []core.Transfer{{
+							CounterpartyName: "Bip Bip",
+							CounterpartyIBAN: "EE383680981021245685",
+							CounterpartyBIC:  "CRLYFRPPTOU",
+							AmountCents:      1450,
+							Currency:         "EUR",
+							Description:      "Wonderland/4410",
+						}},
+					}).
+					Return(core.BulkTransferResult{
+						BulkTransferID: 42,
+						ExecutionMode:  core.ExecutionModeAllOrNothing,
+						Transfers:      []core.TransferResult{{Index: 0, Status: core.TransferAccepted, FeeCents: 20}},
+					}, nil)
+			},
+			expected: &paymentv1.SubmitBulkTransferResponse{
+				BulkTransferId: 42,
+				ExecutionMode:  paymentv1.ExecutionMode_EXECUTION_MODE_ALL_OR_NOTHING,
+				Transfers: []*paymentv1.TransferResult{
+					{Index: 0, Status: paymentv1.TransferStatus_TRANSFER_STATUS_ACCEPTED, FeeCents: 20},
+				},
+				FeeCents: 20,
+			},
+		},
+		{
+			name: "partial_mode_reports_rejections",
+			request: func() *paymentv1.SubmitBulkTransferRequest {
+				req := validSubmitRequest()
+				req.ExecutionMode = paymentv1.ExecutionMode_EXECUTION_MODE_PARTIAL
+				return req
+			},
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, bulkTransfer core.BulkTransfer) (core.BulkTransferResult, error) {
+						require.Equal(t, core.ExecutionModePartial, bulkTransfer.ExecutionMode)
+						return core.BulkTransferResult{
+							BulkTransferID: 42,
+							ExecutionMode:  core.ExecutionModePartial,
+							Transfers:      []core.TransferResult{{Index: 0, Status: core.TransferRejected, Reason: core.RejectionInsufficientFunds}},
+						}, nil
+					})
+			},
+			expected: &paymentv1.SubmitBulkTransferResponse{
+				BulkTransferId: 42,
+				ExecutionMode:  paymentv1.ExecutionMode_EXECUTION_MODE_PARTIAL,
+				Transfers: []*paymentv1.TransferResult{
+					{Index: 0, Status: paymentv1.TransferStatus_TRANSFER_STATUS_REJECTED, Reason: core.RejectionInsufficientFunds},
+				},
+			},
+		},
+		{
+			name: "debtor_accounts_and_hold_are_mapped",
+			request: func() *paymentv1.SubmitBulkTransferRequest {
+				req := validSubmitRequest()
+				req.HoldId = 7
+				req.CreditTransfers[0].DebtorIban = "FR7630006000011234567890189"
+				req.CreditTransfers[0].DebtorBic = "AGRIFRPPXXX"
+				return req
+			},
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, bulkTransfer core.BulkTransfer) (core.BulkTransferResult, error) {
+						require.Equal(t, int64(7), bulkTransfer.HoldID)
+						require.Equal(t, "FR7630006000011234567890189", bulkTransfer.Transfers[0].DebtorIBAN)
+						require.Equal(t, "AGRIFRPPXXX", bulkTransfer.Transfers[0].DebtorBIC)
+						return core.BulkTransferResult{
+							BulkTransferID: 42,
+							ExecutionMode:  core.ExecutionModeAllOrNothing,
+							Transfers:      []core.TransferResult{{Index: 0, Status: core.TransferAccepted}},
+						}, nil
+					})
+			},
+			expected: &paymentv1.SubmitBulkTransferResponse{
+				BulkTransferId: 42,
+				ExecutionMode:  paymentv1.ExecutionMode_EXECUTION_MODE_ALL_OR_NOTHING,
+				Transfers: []*paymentv1.TransferResult{
+					{Index: 0, Status: paymentv1.TransferStatus_TRANSFER_STATUS_ACCEPTED},
+				},
+			},
+		},
+		{
+			name: "invalid_request_lists_field_violations",
+			request: func() *paymentv1.SubmitBulkTransferRequest {
+				req := validSubmitRequest()
+				req.OrganizationBic = ""
+				req.HoldId = -1
+				req.CreditTransfers = append(req.CreditTransfers, &paymentv1.CreditTransfer{
+					AmountCents:      -5,
+					Currency:         "USD",
+					CounterpartyName: "A",
+					CounterpartyBic:  "B",
+					CounterpartyIban: "C",
+					Description:      "D",
+					DebtorIban:       "FR7630006000011234567890189",
+				})
+				return req
+			},
+			setupMock:  func(mock *MockBulkTransferService) {},
+			code:       codes.InvalidArgument,
+			violations: []string{"organization_bic", "hold_id", "credit_transfers[1].amount_cents", "credit_transfers[1].currency", "credit_transfers[1].debtor_iban"},
+		},
+		{
+			name: "empty_batch_is_invalid",
+			request: func() *paymentv1.SubmitBulkTransferRequest {
+				req := validSubmitRequest()
+				req.CreditTransfers = nil
+				return req
+			},
+			setupMock:  func(mock *MockBulkTransferService) {},
+			code:       codes.InvalidArgument,
+			violations: []string{"credit_transfers"},
+		},
+		{
+			name:    "account_not_found",
+			request: validSubmitRequest,
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().ProcessBulkTransfer(gomock.Any(), gomock.Any()).Return(core.BulkTransferResult{}, core.ErrAccountNotFound)
+			},
+			code:   codes.NotFound,
+			reason: ReasonAccountNotFound,
+		},
+		{
+			name:    "insufficient_funds",
+			request: validSubmitRequest,
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().ProcessBulkTransfer(gomock.Any(), gomock.Any()).Return(core.BulkTransferResult{}, core.ErrInsufficientFunds)
+			},
+			code:   codes.FailedPrecondition,
+			reason: ReasonInsufficientFunds,
+		},
+		{
+			name:    "account_frozen",
+			request: validSubmitRequest,
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().ProcessBulkTransfer(gomock.Any(), gomock.Any()).Return(core.BulkTransferResult{}, core.ErrAccountFrozen)
+			},
+			code:   codes.FailedPrecondition,
+			reason: ReasonAccountFrozen,
+		},
+		{
+			name:    "hold_not_pending",
+			request: validSubmitRequest,
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().ProcessBulkTransfer(gomock.Any(), gomock.Any()).Return(core.BulkTransferResult{}, core.ErrHoldNotPending)
+			},
+			code:   codes.FailedPrecondition,
+			reason: ReasonHoldNotPending,
+		},
+		{
+			name:    "sanctions_review_returns_the_held_bulk_transfer",
+			request: validSubmitRequest,
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					Return(core.BulkTransferResult{}, &core.HeldError{
+						HeldBulkTransferID: 7,
+						Review:             core.ReviewKindSanctions,
+						Reason:             &core.SanctionsError{Decision: core.DecisionReview},
+					})
+			},
+			expected: &paymentv1.SubmitBulkTransferResponse{
+				HeldBulkTransferId: 7,
+				Review:             paymentv1.Review_REVIEW_SANCTIONS,
+			},
+		},
+		{
+			name:    "fraud_review_returns_the_held_bulk_transfer",
+			request: validSubmitRequest,
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					Return(core.BulkTransferResult{}, &core.HeldError{
+						HeldBulkTransferID: 8,
+						Review:             core.ReviewKindFraud,
+						Reason:             &core.FraudError{Decision: core.DecisionReview, Reasons: []string{"velocity"}},
+					})
+			},
+			expected: &paymentv1.SubmitBulkTransferResponse{
+				HeldBulkTransferId: 8,
+				Review:             paymentv1.Review_REVIEW_FRAUD,
+			},
+		},
+		{
+			name:    "fraud_block",
+			request: validSubmitRequest,
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					Return(core.BulkTransferResult{}, &core.FraudError{Decision: core.DecisionBlock, Reasons: []string{"velocity"}})
+			},
+			code:   codes.PermissionDenied,
+			reason: ReasonFraudBlocked,
+		},
+		{
+			name:    "quota_exceeded",
+			request: validSubmitRequest,
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					Return(core.BulkTransferResult{}, &core.QuotaError{RetryAfter: time.Hour})
+			},
+			code:   codes.ResourceExhausted,
+			reason: ReasonQuotaExceeded,
+		},
+		{
+			name:    "batch_too_large",
+			request: validSubmitRequest,
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					Return(core.BulkTransferResult{}, &core.BatchSizeError{Size: 2, MaxSize: 1})
+			},
+			code:       codes.InvalidArgument,
+			violations: []string{"credit_transfers"},
+		},
+		{
+			name:    "unexpected_error_is_internal",
+			request: validSubmitRequest,
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().ProcessBulkTransfer(gomock.Any(), gomock.Any()).Return(core.BulkTransferResult{}, io.ErrUnexpectedEOF)
+			},
+			code: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := NewMockBulkTransferService(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewHandler(mockService, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			response, err := handler.SubmitBulkTransfer(context.Background(), tt.request())
+
+			if tt.expected != nil {
+				require.NoError(t, err)
+				require.True(t, proto.Equal(tt.expected, response), "got %v", response)
+				return
+			}
+
+			requireStatus(t, err, tt.code, tt.reason, tt.violations)
+		})
+	}
+}
+
+func TestHandler_GetBulkTransfer(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	const organization = "FR10474608000002006107XXXXX"
+	request := func(id int64) *paymentv1.GetBulkTransferRequest {
+		return &paymentv1.GetBulkTransferRequest{BulkTransferId: id}
+	}
+
+	tests := []struct {
+		name    string
+		request *paymentv1.GetBulkTransferRequest
+		// organization is the one the caller's API key is bound to.
+		organization string
+		setupMock    func(mock *MockBulkTransferService)
+		expected     *paymentv1.BulkTransfer
+		code         codes.Code
+		reason       string
+		violations   []string
+	}{
+		{
+			name:         "found",
+			request:      request(42),
+			organization: organization,
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().GetBulkTransfer(gomock.Any(), int64(42)).Return(core.BulkTransferRecord{
+					ID:               42,
+					AccountID:        1,
+					OrganizationIBAN: "FR10474608000002006107XXXXX",
+					OrganizationBIC:  "OIVUSCLQXXX",
+					ExecutionMode:    core.ExecutionModePartial,
+					TotalCents:       2900,
+					TransferCount:    2,
+					RequestID:        "req-1",
+					CreatedAt:        createdAt,
+				}, nil)
+			},
+			expected: &paymentv1.BulkTransfer{
+				Id:               42,
+				OrganizationBic:  "OIVUSCLQXXX",
+				OrganizationIban: "FR10474608000002006107XXXXX",
+				ExecutionMode:    paymentv1.ExecutionMode_EXECUTION_MODE_PARTIAL,
+				TotalAmountCents: 2900,
+				TransferCount:    2,
+				RequestId:        "req-1",
+			},
+		},
+		{
+			name:         "not_found",
+			request:      request(42),
+			organization: organization,
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().GetBulkTransfer(gomock.Any(), int64(42)).Return(core.BulkTransferRecord{}, core.ErrBulkTransferNotFound)
+			},
+			code:   codes.NotFound,
+			reason: ReasonBulkTransferNotFound,
+		},
+		{
+			name:         "other_organization_is_not_found",
+			request:      request(42),
+			organization: organization,
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().GetBulkTransfer(gomock.Any(), int64(42)).Return(core.BulkTransferRecord{
+					ID:               42,
+					OrganizationIBAN: "DE89370400440532013000",
+					OrganizationBIC:  "COBADEFFXXX",
+				}, nil)
+			},
+			code:   codes.NotFound,
+			reason: ReasonBulkTransferNotFound,
+		},
+		{
+			name:      "caller_without_organization_is_refused",
+			request:   request(42),
+			setupMock: func(mock *MockBulkTransferService) {},
+			code:      codes.PermissionDenied,
+			reason:    ReasonOrganizationRequired,
+		},
+		{
+			name:         "invalid_request",
+			request:      &paymentv1.GetBulkTransferRequest{},
+			organization: organization,
+			setupMock:    func(mock *MockBulkTransferService) {},
+			code:         codes.InvalidArgument,
+			violations:   []string{"bulk_transfer_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := NewMockBulkTransferService(ctrl)
+			tt.setupMock(mockService)
+
+			ctx := context.WithValue(context.Background(), clientOrganization{}, tt.organization)
+			handler := NewHandler(mockService, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			response, err := handler.GetBulkTransfer(ctx, tt.request)
+
+			if tt.expected != nil {
+				require.NoError(t, err)
+				require.Equal(t, createdAt, response.GetCreateTime().AsTime())
+				response.CreateTime = nil
+				require.True(t, proto.Equal(tt.expected, response), "got %v", response)
+				return
+			}
+
+			requireStatus(t, err, tt.code, tt.reason, tt.violations)
+		})
+	}
+}
+
+func TestHandler_ListTransactions(t *testing.T) {
+	t.Parallel()
+
+	transactions := func(ids ...int64) []core.Transaction {
+		result := make([]core.Transaction, len(ids))
+		for i, id := range ids {
+			result[i] = core.Transaction{ID: id, AccountID: 1, BulkTransferID: 3, Kind: core.TransactionKindTransfer, AmountCents: -100, Currency: "EUR"}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name          string
+		request       *paymentv1.ListTransactionsRequest
+		setupMock     func(mock *MockBulkTransferService)
+		expectedIDs   []int64
+		expectedToken string
+		code          codes.Code
+		reason        string
+		violations    []string
+	}{
+		{
+			name:    "first_page_has_a_next_page_token",
+			request: &paymentv1.ListTransactionsRequest{OrganizationBic: "BIC", OrganizationIban: "IBAN", PageSize: 2},
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().
+					ListTransactions(gomock.Any(), "IBAN", "BIC", core.TransactionQuery{Limit: 3}).
+					Return(transactions(4, 5, 6), nil)
+			},
+			expectedIDs:   []int64{4, 5},
+			expectedToken: encodePageToken(5),
+		},
+		{
+			name: "last_page_has_no_token",
+			request: &paymentv1.ListTransactionsRequest{
+				OrganizationBic:  "BIC",
+				OrganizationIban: "IBAN",
+				BulkTransferId:   3,
+				PageToken:        encodePageToken(5),
+			},
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().
+					ListTransactions(gomock.Any(), "IBAN", "BIC", core.TransactionQuery{BulkTransferID: 3, AfterID: 5, Limit: defaultPageSize + 1}).
+					Return(transactions(6), nil)
+			},
+			expectedIDs: []int64{6},
+		},
+		{
+			name:    "page_size_is_capped",
+			request: &paymentv1.ListTransactionsRequest{OrganizationBic: "BIC", OrganizationIban: "IBAN", PageSize: 5000},
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().
+					ListTransactions(gomock.Any(), "IBAN", "BIC", core.TransactionQuery{Limit: maxPageSize + 1}).
+					Return(nil, nil)
+			},
+			expectedIDs: []int64{},
+		},
+		{
+			name:       "invalid_page_token",
+			request:    &paymentv1.ListTransactionsRequest{OrganizationBic: "BIC", OrganizationIban: "IBAN", PageToken: "!"},
+			setupMock:  func(mock *MockBulkTransferService) {},
+			code:       codes.InvalidArgument,
+			violations: []string{"page_token"},
+		},
+		{
+			name:    "account_not_found",
+			request: &paymentv1.ListTransactionsRequest{OrganizationBic: "BIC", OrganizationIban: "IBAN"},
+			setupMock: func(mock *MockBulkTransferService) {
+				mock.EXPECT().ListTransactions(gomock.Any(), "IBAN", "BIC", gomock.Any()).Return(nil, core.ErrAccountNotFound)
+			},
+			code:   codes.NotFound,
+			reason: ReasonAccountNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := NewMockBulkTransferService(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewHandler(mockService, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			response, err := handler.ListTransactions(context.Background(), tt.request)
+
+			if tt.expectedIDs != nil {
+				require.NoError(t, err)
+
+				ids := make([]int64, 0, len(response.GetTransactions()))
+				for _, transaction := range response.GetTransactions() {
+					ids = append(ids, transaction.GetId())
+					require.Equal(t, "transfer", transaction.GetKind())
+				}
+				require.Equal(t, tt.expectedIDs, ids)
+				require.Equal(t, tt.expectedToken, response.GetNextPageToken())
+				return
+			}
+
+			requireStatus(t, err, tt.code, tt.reason, tt.violations)
+		})
+	}
+}
+
+// requireStatus checks the code of err and, when given, the ErrorInfo reason
+// and the BadRequest field violations of its details.
+func requireStatus(t *testing.T, err error, code codes.Code, reason string, violations []string) {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok, "not a status error: %v", err)
+	require.Equal(t, code, st.Code())
+
+	var fields []string
+	var reasons []string
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			require.Equal(t, errorDomain, detail.GetDomain())
+			reasons = append(reasons, detail.GetReason())
+		case *errdetails.BadRequest:
+			for _, violation := range detail.GetFieldViolations() {
+				fields = append(fields, violation.GetField())
+			}
+		}
+	}
+
+	if reason != "" {
+		require.Equal(t, []string{reason}, reasons)
+	}
+	if violations != nil {
+		require.Equal(t, violations, fields)
+	}
+}
@@ -0,0 +1,240 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	paymentv1 "payment/api/payment/v1"
+	"payment/internal/core"
+	"payment/internal/ratelimit"
+)
+
+var tracer = otel.Tracer("payment/internal/grpc")
+
+const (
+	apiKeyMetadata     = "x-api-key"
+	requestIDMetadata  = "x-request-id"
+	maxRequestIDLength = 128
+)
+
+// requestIDInterceptor keeps the caller's x-request-id when it is usable and
+// generates one otherwise. The ID is returned in the response header.
+func requestIDInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var requestID string
+	if values := metadata.ValueFromIncomingContext(ctx, requestIDMetadata); len(values) > 0 {
+		requestID = values[0]
+	}
+	if !validRequestID(requestID) {
+		requestID = uuid.NewString()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, requestID))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("request.id", requestID))
+
+	return handler(core.WithRequestID(ctx, requestID), req)
+}
+
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range requestID {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tracingInterceptor continues the trace of an inbound traceparent metadata
+// entry, or starts a new one.
+func tracingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	ctx, span := tracer.Start(ctx, info.FullMethod, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.method", info.FullMethod),
+	))
+	defer span.End()
+
+	resp, err := handler(ctx, req)
+
+	code := status.Code(err)
+	span.SetAttributes(attribute.String("rpc.grpc.status_code", code.String()))
+	if code == codes.Internal || code == codes.Unknown {
+		span.SetStatus(otelcodes.Error, code.String())
+	}
+
+	return resp, err
+}
+
+// metadataCarrier reads propagation fields from incoming metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key string, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+var _ propagation.TextMapCarrier = metadataCarrier{}
+
+func loggingInterceptor(logger Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		logger.InfoContext(
+			ctx,
+			"rpc",
+			"method", info.FullMethod,
+			"code", status.Code(err).String(),
+			"duration", time.Since(start),
+		)
+
+		return resp, err
+	}
+}
+
+type clientKey struct{}
+
+// clientOrganization is the context key of the IBAN of the organization the
+// caller's API key is bound to.
+type clientOrganization struct{}
+
+// rateLimitInterceptor applies the rate limit of the client before the call
+// is handled. Clients are identified like on the HTTP API: by their x-api-key
+// metadata, or by their address. Calls with an API key that is not configured
+// are refused. Limiter failures are logged and the call is let through,
+// without the identity of the caller.
+func rateLimitInterceptor(rateLimiter RateLimiter, logger Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var apiKey string
+		if values := metadata.ValueFromIncomingContext(ctx, apiKeyMetadata); len(values) > 0 {
+			apiKey = values[0]
+		}
+
+		decision, err := rateLimiter.Allow(ctx, apiKey, peerAddress(ctx))
+		switch {
+		case errors.Is(err, ratelimit.ErrUnknownAPIKey):
+			return nil, withDetails(codes.Unauthenticated, "Unknown API key", errorInfo(ReasonUnknownAPIKey))
+		case err != nil:
+			logger.ErrorContext(ctx, "Failed to apply rate limit", "error", err)
+		case !decision.Allowed:
+			return nil, retryLater("Rate limit exceeded", ReasonRateLimited, decision.RetryAfter)
+		default:
+			ctx = context.WithValue(ctx, clientKey{}, decision.Key)
+			ctx = context.WithValue(ctx, clientOrganization{}, decision.Organization)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// peerAddress is the host the call comes from.
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+type Server struct {
+	grpcServer *grpc.Server
+	address    string
+	logger     Logger
+}
+
+func NewServer(service BulkTransferService, rateLimiter RateLimiter, logger Logger, config Config) *Server {
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		tracingInterceptor,
+		requestIDInterceptor,
+		loggingInterceptor(logger),
+		rateLimitInterceptor(rateLimiter, logger),
+	))
+	paymentv1.RegisterBulkTransferServiceServer(grpcServer, NewHandler(service, logger))
+
+	return &Server{
+		grpcServer: grpcServer,
+		address:    config.Address,
+		logger:     logger,
+	}
+}
+
+func (s *Server) Start(ctx context.Context) error {
+	s.logger.InfoContext(ctx, "Starting gRPC server", "address", s.address)
+
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.address, err)
+	}
+
+	go s.Serve(ctx, listener)
+
+	return nil
+}
+
+// Serve accepts connections on listener until the server is stopped.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) {
+	if err := s.grpcServer.Serve(listener); err != nil {
+		s.logger.ErrorContext(ctx, "gRPC server error", "error", err)
+	}
+}
+
+// Stop waits for in-flight calls to finish, or cancels them once ctx is done.
+func (s *Server) Stop(ctx context.Context) error {
+	s.logger.InfoContext(ctx, "Stopping gRPC server")
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,270 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	paymentv1 "payment/api/payment/v1"
+	"payment/internal/core"
+	"payment/internal/ratelimit"
+)
+
+func TestServer_SubmitBulkTransfer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		requestID         string
+		expectedRequestID func(t *testing.T, requestID string)
+	}{
+		{
+			name:      "caller_request_id_is_kept",
+			requestID: "req-42",
+			expectedRequestID: func(t *testing.T, requestID string) {
+				require.Equal(t, "req-42", requestID)
+			},
+		},
+		{
+			name: "request_id_is_generated",
+			expectedRequestID: func(t *testing.T, requestID string) {
+				require.Len(t, requestID, 36)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var serviceRequestID, serviceActor string
+			mockService := NewMockBulkTransferService(ctrl)
+			mockService.EXPECT().
+				ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, _ core.BulkTransfer) (core.BulkTransferResult, error) {
+					serviceRequestID = core.RequestIDFromContext(ctx)
+					serviceActor = core.ActorFromContext(ctx)
+					return core.BulkTransferResult{}, core.ErrInsufficientFunds
+				})
+
+			client := newTestClient(t, mockService, allowingLimiter(ctrl))
+
+			ctx := context.Background()
+			if tt.requestID != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, requestIDMetadata, tt.requestID)
+			}
+
+			var header metadata.MD
+			_, err := client.SubmitBulkTransfer(ctx, validSubmitRequest(), grpc.Header(&header))
+
+			// Details survive the round trip.
+			requireStatus(t, err, codes.FailedPrecondition, ReasonInsufficientFunds, nil)
+
+			require.Len(t, header.Get(requestIDMetadata), 1)
+			tt.expectedRequestID(t, header.Get(requestIDMetadata)[0])
+			require.Equal(t, header.Get(requestIDMetadata)[0], serviceRequestID)
+			require.Equal(t, "org:FR10474608000002006107XXXXX", serviceActor)
+		})
+	}
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		apiKey        string
+		setupLimiter  func(mock *MockRateLimiter)
+		setupMock     func(mock *MockBulkTransferService, actor *string)
+		code          codes.Code
+		reason        string
+		expectedActor string
+	}{
+		{
+			name: "limited_before_the_call_is_handled",
+			setupLimiter: func(mock *MockRateLimiter) {
+				mock.EXPECT().
+					Allow(gomock.Any(), "", gomock.Any()).
+					Return(ratelimit.Decision{RetryAfter: 1500 * time.Millisecond, Reason: ratelimit.ReasonRateLimited}, nil)
+			},
+			setupMock: func(*MockBulkTransferService, *string) {},
+			code:      codes.ResourceExhausted,
+			reason:    ReasonRateLimited,
+		},
+		{
+			name:   "unknown_api_key_is_refused",
+			apiKey: "made-up",
+			setupLimiter: func(mock *MockRateLimiter) {
+				mock.EXPECT().
+					Allow(gomock.Any(), "made-up", gomock.Any()).
+					Return(ratelimit.Decision{}, ratelimit.ErrUnknownAPIKey)
+			},
+			setupMock: func(*MockBulkTransferService, *string) {},
+			code:      codes.Unauthenticated,
+			reason:    ReasonUnknownAPIKey,
+		},
+		{
+			name:   "configured_api_key_identifies_the_caller",
+			apiKey: "secret",
+			setupLimiter: func(mock *MockRateLimiter) {
+				mock.EXPECT().
+					Allow(gomock.Any(), "secret", gomock.Any()).
+					Return(ratelimit.Decision{Allowed: true, Key: "api_key:2bb80d537b1da3e3"}, nil)
+			},
+			setupMock: func(mock *MockBulkTransferService, actor *string) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, _ core.BulkTransfer) (core.BulkTransferResult, error) {
+						*actor = core.ActorFromContext(ctx)
+						return core.BulkTransferResult{}, nil
+					})
+			},
+			code:          codes.OK,
+			expectedActor: "api_key:2bb80d537b1da3e3",
+		},
+		{
+			name:   "limiter_error_lets_call_through",
+			apiKey: "secret",
+			setupLimiter: func(mock *MockRateLimiter) {
+				mock.EXPECT().
+					Allow(gomock.Any(), "secret", gomock.Any()).
+					Return(ratelimit.Decision{}, errors.New("store unavailable"))
+			},
+			setupMock: func(mock *MockBulkTransferService, actor *string) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, _ core.BulkTransfer) (core.BulkTransferResult, error) {
+						*actor = core.ActorFromContext(ctx)
+						return core.BulkTransferResult{}, nil
+					})
+			},
+			code:          codes.OK,
+			expectedActor: "org:FR10474608000002006107XXXXX",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockLimiter := NewMockRateLimiter(ctrl)
+			tt.setupLimiter(mockLimiter)
+
+			var actor string
+			mockService := NewMockBulkTransferService(ctrl)
+			tt.setupMock(mockService, &actor)
+
+			client := newTestClient(t, mockService, mockLimiter)
+
+			ctx := context.Background()
+			if tt.apiKey != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, apiKeyMetadata, tt.apiKey)
+			}
+
+			_, err := client.SubmitBulkTransfer(ctx, validSubmitRequest())
+			if tt.code == codes.OK {
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedActor, actor)
+				return
+			}
+
+			requireStatus(t, err, tt.code, tt.reason, nil)
+			if tt.code == codes.ResourceExhausted {
+				st, _ := status.FromError(err)
+				var retryInfo *errdetails.RetryInfo
+				for _, detail := range st.Details() {
+					if detail, ok := detail.(*errdetails.RetryInfo); ok {
+						retryInfo = detail
+					}
+				}
+				require.NotNil(t, retryInfo)
+				require.Equal(t, 1500*time.Millisecond, retryInfo.GetRetryDelay().AsDuration())
+			}
+		})
+	}
+}
+
+func TestServer_GetBulkTransfer_ScopedToAPIKeyOrganization(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rateLimiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Config{
+		APIKeys:             []string{"unbound"},
+		APIKeyOrganizations: map[string]string{"acme": "FR10474608000002006107XXXXX", "other": "DE89370400440532013000"},
+	})
+
+	mockService := NewMockBulkTransferService(ctrl)
+	mockService.EXPECT().
+		GetBulkTransfer(gomock.Any(), int64(42)).
+		Return(core.BulkTransferRecord{ID: 42, OrganizationIBAN: "FR10474608000002006107XXXXX"}, nil).
+		Times(2)
+
+	client := newTestClient(t, mockService, rateLimiter)
+	get := func(apiKey string) (*paymentv1.BulkTransfer, error) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, apiKey)
+		return client.GetBulkTransfer(ctx, &paymentv1.GetBulkTransferRequest{BulkTransferId: 42})
+	}
+
+	response, err := get("acme")
+	require.NoError(t, err)
+	require.Equal(t, int64(42), response.GetId())
+
+	_, err = get("other")
+	requireStatus(t, err, codes.NotFound, ReasonBulkTransferNotFound, nil)
+
+	_, err = get("unbound")
+	requireStatus(t, err, codes.PermissionDenied, ReasonOrganizationRequired, nil)
+}
+
+// allowingLimiter returns a rate limiter that lets every call through.
+func allowingLimiter(ctrl *gomock.Controller) *MockRateLimiter {
+	limiter := NewMockRateLimiter(ctrl)
+	limiter.EXPECT().
+		Allow(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(ratelimit.Decision{Allowed: true}, nil).
+		AnyTimes()
+	return limiter
+}
+
+func newTestClient(t *testing.T, service BulkTransferService, rateLimiter RateLimiter) paymentv1.BulkTransferServiceClient {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server := NewServer(service, rateLimiter, logger, Config{})
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(context.Background(), listener)
+	t.Cleanup(func() { _ = server.Stop(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return paymentv1.NewBulkTransferServiceClient(conn)
+}
@@ -0,0 +1,185 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=handler.go -destination=service_mock.go -package=grpc
+//
+
+// Package grpc is a generated GoMock package.
+package grpc
+
+import (
+	context "context"
+	core "payment/internal/core"
+	ratelimit "payment/internal/ratelimit"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBulkTransferService is a mock of BulkTransferService interface.
+type MockBulkTransferService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBulkTransferServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockBulkTransferServiceMockRecorder is the mock recorder for MockBulkTransferService.
+type MockBulkTransferServiceMockRecorder struct {
+	mock *MockBulkTransferService
+}
+
+// NewMockBulkTransferService creates a new mock instance.
+func NewMockBulkTransferService(ctrl *gomock.Controller) *MockBulkTransferService {
+	mock := &MockBulkTransferService{ctrl: ctrl}
+	mock.recorder = &MockBulkTransferServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBulkTransferService) EXPECT() *MockBulkTransferServiceMockRecorder {
+	return m.recorder
+}
+
+// GetBulkTransfer mocks base method.
+func (m *MockBulkTransferService) GetBulkTransfer(ctx context.Context, bulkTransferID int64) (core.BulkTransferRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBulkTransfer", ctx, bulkTransferID)
+	ret0, _ := ret[0].(core.BulkTransferRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBulkTransfer indicates an expected call of GetBulkTransfer.
+func (mr *MockBulkTransferServiceMockRecorder) GetBulkTransfer(ctx, bulkTransferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBulkTransfer", reflect.TypeOf((*MockBulkTransferService)(nil).GetBulkTransfer), ctx, bulkTransferID)
+}
+
+// ListTransactions mocks base method.
+func (m *MockBulkTransferService) ListTransactions(ctx context.Context, iban, bic string, query core.TransactionQuery) ([]core.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, iban, bic, query)
+	ret0, _ := ret[0].([]core.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockBulkTransferServiceMockRecorder) ListTransactions(ctx, iban, bic, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockBulkTransferService)(nil).ListTransactions), ctx, iban, bic, query)
+}
+
+// ProcessBulkTransfer mocks base method.
+func (m *MockBulkTransferService) ProcessBulkTransfer(ctx context.Context, bulkTransfer core.BulkTransfer) (core.BulkTransferResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessBulkTransfer", ctx, bulkTransfer)
+	ret0, _ := ret[0].(core.BulkTransferResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessBulkTransfer indicates an expected call of ProcessBulkTransfer.
+func (mr *MockBulkTransferServiceMockRecorder) ProcessBulkTransfer(ctx, bulkTransfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessBulkTransfer", reflect.TypeOf((*MockBulkTransferService)(nil).ProcessBulkTransfer), ctx, bulkTransfer)
+}
+
+// MockRateLimiter is a mock of RateLimiter interface.
+type MockRateLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimiterMockRecorder
+	isgomock struct{}
+}
+
+// MockRateLimiterMockRecorder is the mock recorder for MockRateLimiter.
+type MockRateLimiterMockRecorder struct {
+	mock *MockRateLimiter
+}
+
+// NewMockRateLimiter creates a new mock instance.
+func NewMockRateLimiter(ctrl *gomock.Controller) *MockRateLimiter {
+	mock := &MockRateLimiter{ctrl: ctrl}
+	mock.recorder = &MockRateLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimiter) EXPECT() *MockRateLimiterMockRecorder {
+	return m.recorder
+}
+
+// Allow mocks base method.
+func (m *MockRateLimiter) Allow(ctx context.Context, apiKey, address string) (ratelimit.Decision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allow", ctx, apiKey, address)
+	ret0, _ := ret[0].(ratelimit.Decision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Allow indicates an expected call of Allow.
+func (mr *MockRateLimiterMockRecorder) Allow(ctx, apiKey, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allow", reflect.TypeOf((*MockRateLimiter)(nil).Allow), ctx, apiKey, address)
+}
+
+// MockLogger is a mock of Logger interface.
+type MockLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoggerMockRecorder
+	isgomock struct{}
+}
+
+// MockLoggerMockRecorder is the mock recorder for MockLogger.
+type MockLoggerMockRecorder struct {
+	mock *MockLogger
+}
+
+// NewMockLogger creates a new mock instance.
+func NewMockLogger(ctrl *gomock.Controller) *MockLogger {
+	mock := &MockLogger{ctrl: ctrl}
+	mock.recorder = &MockLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogger) EXPECT() *MockLoggerMockRecorder {
+	return m.recorder
+}
+
+// ErrorContext mocks base method.
+func (m *MockLogger) ErrorContext(ctx context.Context, msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "ErrorContext", varargs...)
+}
+
+// ErrorContext indicates an expected call of ErrorContext.
+func (mr *MockLoggerMockRecorder) ErrorContext(ctx, msg any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, msg}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ErrorContext", reflect.TypeOf((*MockLogger)(nil).ErrorContext), varargs...)
+}
+
+// InfoContext mocks base method.
+func (m *MockLogger) InfoContext(ctx context.Context, msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "InfoContext", varargs...)
+}
+
+// InfoContext indicates an expected call of InfoContext.
+func (mr *MockLoggerMockRecorder) InfoContext(ctx, msg any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, msg}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InfoContext", reflect.TypeOf((*MockLogger)(nil).InfoContext), varargs...)
+}
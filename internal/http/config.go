@@ -5,6 +5,7 @@ import (
 )
 
 type Config struct {
-	Address string        `envconfig:"HTTP_ADDRESS" default:"localhost:8080"`
-	Timeout time.Duration `envconfig:"HTTP_TIMEOUT" default:"10s"`
+	Address    string        `envconfig:"HTTP_ADDRESS" default:"localhost:8080"`
+	Timeout    time.Duration `envconfig:"HTTP_TIMEOUT" default:"10s"`
+	DrainDelay time.Duration `envconfig:"HTTP_DRAIN_DELAY" default:"5s"` // Time /readyz reports draining before the listener closes
 }
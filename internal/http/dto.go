@@ -2,6 +2,8 @@ package http
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -9,9 +11,13 @@ import (
 )
 
 type BulkTransferRequest struct {
-	OrganizationBIC  string           `json:"organization_bic" validate:"required"`
-	OrganizationIBAN string           `json:"organization_iban" validate:"required"`
-	CreditTransfers  []CreditTransfer `json:"credit_transfers" validate:"required,min=1,dive"`
+	OrganizationBIC  string `json:"organization_bic" validate:"required"`
+	OrganizationIBAN string `json:"organization_iban" validate:"required"`
+	ExecutionMode    string `json:"execution_mode,omitempty" validate:"omitempty,oneof=all_or_nothing partial"`
+	// HoldID names a pending hold of the organization account that the bulk
+	// transfer captures.
+	HoldID          int64            `json:"hold_id,omitempty" validate:"omitempty,gt=0"`
+	CreditTransfers []CreditTransfer `json:"credit_transfers" validate:"required,min=1,dive"`
 }
 
 type CreditTransfer struct {
@@ -21,6 +27,10 @@ type CreditTransfer struct {
 	CounterpartyBIC  string `json:"counterparty_bic" validate:"required"`
 	CounterpartyIBAN string `json:"counterparty_iban" validate:"required"`
 	Description      string `json:"description" validate:"required"`
+	// DebtorIBAN and DebtorBIC name the account that pays the transfer when it
+	// is not the organization account.
+	DebtorIBAN string `json:"debtor_iban,omitempty" validate:"required_with=DebtorBIC"`
+	DebtorBIC  string `json:"debtor_bic,omitempty" validate:"required_with=DebtorIBAN"`
 }
 
 func ParseAmountToCents(amount string) (int64, error) {
@@ -45,29 +55,319 @@ func ParseAmountToCents(amount string) (int64, error) {
 }
 
 func (req BulkTransferRequest) ToDomain() (core.BulkTransfer, error) {
+	bulkTransfer, _, lineErrors := req.toDomain(nil)
+	if len(lineErrors) > 0 {
+		return core.BulkTransfer{}, &ValidationError{Errors: flattenLineErrors(lineErrors)}
+	}
+
+	return bulkTransfer, nil
+}
+
+// toDomain converts the credit transfers that have no entry in lineErrors. It
+// returns the request index of every converted transfer and lineErrors
+// completed with the transfers that could not be converted.
+func (req BulkTransferRequest) toDomain(lineErrors map[int][]FieldError) (core.BulkTransfer, []int, map[int][]FieldError) {
 	transfers := make([]core.Transfer, 0, len(req.CreditTransfers))
+	indexes := make([]int, 0, len(req.CreditTransfers))
 
-	for _, ct := range req.CreditTransfers {
-		amountCents, err := ParseAmountToCents(ct.Amount)
-		if err != nil {
-			return core.BulkTransfer{}, fmt.Errorf("invalid amount for transfer %s: %w", ct.Amount, err)
+	if lineErrors == nil {
+		lineErrors = make(map[int][]FieldError)
+	}
+
+	for i, ct := range req.CreditTransfers {
+		if len(lineErrors[i]) > 0 {
+			continue
 		}
 
-		transfer := core.Transfer{
-			CounterpartyName: ct.CounterpartyName,
-			CounterpartyIBAN: ct.CounterpartyIBAN,
-			CounterpartyBIC:  ct.CounterpartyBIC,
-			AmountCents:      amountCents,
-			Currency:         ct.Currency,
-			Description:      ct.Description,
+		transfer, err := ct.toDomain()
+		if err != nil {
+			lineErrors[i] = append(lineErrors[i], amountError(i, ct.Amount, err))
+			continue
 		}
 
 		transfers = append(transfers, transfer)
+		indexes = append(indexes, i)
 	}
 
 	return core.BulkTransfer{
 		OrganizationBIC:  req.OrganizationBIC,
 		OrganizationIBAN: req.OrganizationIBAN,
+		ExecutionMode:    core.ExecutionMode(req.ExecutionMode),
+		HoldID:           req.HoldID,
 		Transfers:        transfers,
+	}, indexes, lineErrors
+}
+
+func (ct CreditTransfer) toDomain() (core.Transfer, error) {
+	amountCents, err := ParseAmountToCents(ct.Amount)
+	if err != nil {
+		return core.Transfer{}, err
+	}
+
+	return core.Transfer{
+		CounterpartyName: ct.CounterpartyName,
+		CounterpartyIBAN: ct.CounterpartyIBAN,
+		CounterpartyBIC:  ct.CounterpartyBIC,
+		AmountCents:      amountCents,
+		Currency:         ct.Currency,
+		Description:      ct.Description,
+		DebtorIBAN:       ct.DebtorIBAN,
+		DebtorBIC:        ct.DebtorBIC,
 	}, nil
 }
+
+func amountError(index int, amount string, err error) FieldError {
+	return FieldError{
+		Pointer: fmt.Sprintf("/credit_transfers/%d/amount", index),
+		Code:    "invalid_amount",
+		Detail:  fmt.Sprintf("invalid amount %q: %s", amount, err),
+	}
+}
+
+var linePointerPattern = regexp.MustCompile(`^/credit_transfers/(\d+)/`)
+
+// splitLineErrors separates the errors of individual credit transfers, keyed
+// by their index, from the errors that concern the whole request.
+func splitLineErrors(fieldErrors []FieldError) (map[int][]FieldError, []FieldError) {
+	lineErrors := make(map[int][]FieldError)
+	var requestErrors []FieldError
+
+	for _, fieldErr := range fieldErrors {
+		match := linePointerPattern.FindStringSubmatch(fieldErr.Pointer)
+		if match == nil {
+			requestErrors = append(requestErrors, fieldErr)
+			continue
+		}
+
+		index, _ := strconv.Atoi(match[1])
+		lineErrors[index] = append(lineErrors[index], fieldErr)
+	}
+
+	return lineErrors, requestErrors
+}
+
+func flattenLineErrors(lineErrors map[int][]FieldError) []FieldError {
+	indexes := make([]int, 0, len(lineErrors))
+	for index := range lineErrors {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	var fieldErrors []FieldError
+	for _, index := range indexes {
+		fieldErrors = append(fieldErrors, lineErrors[index]...)
+	}
+
+	return fieldErrors
+}
+
+// FormatCents renders cents as a decimal amount, e.g. -1205 as "-12.05".
+func FormatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// AccountResponse shows the balance of an account and what can be spent.
+// The available balance excludes held funds and includes the overdraft.
+type AccountResponse struct {
+	OrganizationName string `json:"organization_name"`
+	IBAN             string `json:"iban"`
+	BIC              string `json:"bic"`
+	Balance          string `json:"balance"`
+	AvailableBalance string `json:"available_balance"`
+	Held             string `json:"held"`
+	OverdraftLimit   string `json:"overdraft_limit"`
+	OverdraftUsed    string `json:"overdraft_used"`
+	Currency         string `json:"currency"`
+	Frozen           bool   `json:"frozen"`
+}
+
+func NewAccountResponse(account core.Account) AccountResponse {
+	return AccountResponse{
+		OrganizationName: account.OrganizationName,
+		IBAN:             account.IBAN,
+		BIC:              account.BIC,
+		Balance:          FormatCents(account.BalanceCents),
+		AvailableBalance: FormatCents(account.AvailableCents()),
+		Held:             FormatCents(account.HeldCents),
+		OverdraftLimit:   FormatCents(account.OverdraftLimitCents),
+		OverdraftUsed:    FormatCents(account.OverdraftUsedCents()),
+		Currency:         account.Currency,
+		Frozen:           account.Frozen,
+	}
+}
+
+type BulkTransferQuoteResponse struct {
+	Valid         bool                `json:"valid"`
+	TransferCount int                 `json:"transfer_count"`
+	TotalAmount   string              `json:"total_amount"`
+	Fees          string              `json:"fees"`
+	BalanceBefore string              `json:"balance_before"`
+	BalanceAfter  string              `json:"balance_after"`
+	Currency      string              `json:"currency"`
+	Violations    []ViolationResponse `json:"violations"`
+}
+
+type ViolationResponse struct {
+	TransferIndex *int   `json:"transfer_index,omitempty"`
+	Pointer       string `json:"pointer,omitempty"`
+	Code          string `json:"code"`
+	Message       string `json:"message"`
+}
+
+func NewBulkTransferQuoteResponse(quote core.BulkTransferQuote, indexes []int, lineErrors map[int][]FieldError) BulkTransferQuoteResponse {
+	violations := make([]ViolationResponse, 0, len(quote.Violations)+len(lineErrors))
+	for _, violation := range quote.Violations {
+		response := ViolationResponse{
+			Code:    violation.Code,
+			Message: violation.Message,
+		}
+		if violation.TransferIndex != core.BatchViolation {
+			index := indexes[violation.TransferIndex]
+			response.TransferIndex = &index
+			response.Pointer = fmt.Sprintf("/credit_transfers/%d", index)
+		}
+		violations = append(violations, response)
+	}
+
+	for _, fieldErr := range flattenLineErrors(lineErrors) {
+		match := linePointerPattern.FindStringSubmatch(fieldErr.Pointer)
+		index, _ := strconv.Atoi(match[1])
+		violations = append(violations, ViolationResponse{
+			TransferIndex: &index,
+			Pointer:       fieldErr.Pointer,
+			Code:          fieldErr.Code,
+			Message:       fieldErr.Detail,
+		})
+	}
+
+	return BulkTransferQuoteResponse{
+		Valid:         quote.Valid() && len(lineErrors) == 0,
+		TransferCount: quote.TransferCount + len(lineErrors),
+		TotalAmount:   FormatCents(quote.TotalAmountCents),
+		Fees:          FormatCents(quote.FeesCents),
+		BalanceBefore: FormatCents(quote.BalanceBeforeCents),
+		BalanceAfter:  FormatCents(quote.BalanceAfterCents),
+		Currency:      "EUR",
+		Violations:    violations,
+	}
+}
+
+type BulkTransferResponse struct {
+	BulkTransferID int64                    `json:"bulk_transfer_id"`
+	ExecutionMode  string                   `json:"execution_mode"`
+	AcceptedCount  int                      `json:"accepted_count"`
+	RejectedCount  int                      `json:"rejected_count"`
+	Fees           string                   `json:"fees"`
+	Currency       string                   `json:"currency"`
+	Transfers      []TransferResultResponse `json:"transfers"`
+}
+
+type TransferResultResponse struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	// Fee is only reported for accepted transfers.
+	Fee    string       `json:"fee,omitempty"`
+	Reason string       `json:"reason,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// NewBulkTransferResponse reports every credit transfer of the request, in
+// request order, including those rejected before reaching the service.
+// indexes maps the transfers of result to their index in the request; when
+// nil, result already reports request indexes.
+func NewBulkTransferResponse(result core.BulkTransferResult, indexes []int, lineErrors map[int][]FieldError) BulkTransferResponse {
+	count := len(indexes)
+	if indexes == nil {
+		count = len(result.Transfers)
+	}
+	transfers := make([]TransferResultResponse, count+len(lineErrors))
+
+	for _, transfer := range result.Transfers {
+		index := transfer.Index
+		if indexes != nil {
+			index = indexes[transfer.Index]
+		}
+		transfers[index] = TransferResultResponse{
+			Index:  index,
+			Status: string(transfer.Status),
+			Reason: transfer.Reason,
+		}
+		if transfer.Status == core.TransferAccepted {
+			transfers[index].Fee = FormatCents(transfer.FeeCents)
+		}
+	}
+
+	for index, fieldErrors := range lineErrors {
+		transfers[index] = TransferResultResponse{
+			Index:  index,
+			Status: string(core.TransferRejected),
+			Reason: CodeValidationFailed,
+			Errors: fieldErrors,
+		}
+	}
+
+	response := BulkTransferResponse{
+		BulkTransferID: result.BulkTransferID,
+		ExecutionMode:  string(result.ExecutionMode),
+		Fees:           FormatCents(result.FeesCents()),
+		Currency:       "EUR",
+		Transfers:      transfers,
+	}
+	for _, transfer := range transfers {
+		if transfer.Status == string(core.TransferAccepted) {
+			response.AcceptedCount++
+		} else {
+			response.RejectedCount++
+		}
+	}
+
+	return response
+}
+
+// HeldBulkTransferResponse reports a bulk transfer held for a manual review
+// instead of executed. It executes once an operator releases it.
+type HeldBulkTransferResponse struct {
+	HeldBulkTransferID int64  `json:"held_bulk_transfer_id"`
+	Review             string `json:"review"`
+}
+
+func NewHeldBulkTransferResponse(heldErr *core.HeldError) HeldBulkTransferResponse {
+	return HeldBulkTransferResponse{
+		HeldBulkTransferID: heldErr.HeldBulkTransferID,
+		Review:             string(heldErr.Review),
+	}
+}
+
+// StreamHeader is the first line of an NDJSON bulk transfer. Every following
+// line is a CreditTransfer.
+type StreamHeader struct {
+	OrganizationBIC  string `json:"organization_bic" validate:"required"`
+	OrganizationIBAN string `json:"organization_iban" validate:"required"`
+	ExecutionMode    string `json:"execution_mode,omitempty" validate:"omitempty,oneof=all_or_nothing partial"`
+	HoldID           int64  `json:"hold_id,omitempty" validate:"omitempty,gt=0"`
+	TransferCount    int    `json:"transfer_count" validate:"required,min=1"`
+}
+
+const (
+	StreamEventProgress  = "progress"
+	StreamEventCompleted = "completed"
+	StreamEventHeld      = "held"
+	StreamEventFailed    = "failed"
+)
+
+// StreamEvent is a line of the NDJSON response to a streamed bulk transfer.
+type StreamEvent struct {
+	Event    string                    `json:"event"`
+	Received int                       `json:"received,omitempty"`
+	Staged   int                       `json:"staged,omitempty"`
+	Total    int                       `json:"total,omitempty"`
+	Result   *BulkTransferResponse     `json:"result,omitempty"`
+	Held     *HeldBulkTransferResponse `json:"held,omitempty"`
+	Problem  *Problem                  `json:"problem,omitempty"`
+}
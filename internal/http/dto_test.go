@@ -162,6 +162,30 @@ func TestBulkTransferRequest_ToDomain(t *testing.T) {
 				require.Equal(t, "Second", result.Transfers[1].CounterpartyName)
 			},
 		},
+		{
+			name: "maps_debtor_accounts",
+			request: BulkTransferRequest{
+				OrganizationBIC:  "TESTBIC",
+				OrganizationIBAN: "TEST123",
+				CreditTransfers: []CreditTransfer{
+					{
+						Amount:           "100.00",
+						Currency:         "EUR",
+						CounterpartyName: "First",
+						CounterpartyBIC:  "BIC1",
+						CounterpartyIBAN: "IBAN1",
+						Description:      "First transfer",
+						DebtorIBAN:       "TEST456",
+						DebtorBIC:        "TESTBIC",
+					},
+				},
+			},
+			expected: func(t *testing.T, result core.BulkTransfer) {
+				require.Len(t, result.Transfers, 1)
+				require.Equal(t, "TEST456", result.Transfers[0].DebtorIBAN)
+				require.Equal(t, "TESTBIC", result.Transfers[0].DebtorBIC)
+			},
+		},
 		{
 			name: "empty_transfers_list",
 			request: BulkTransferRequest{
@@ -210,3 +234,26 @@ func TestBulkTransferRequest_ToDomain(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatCents(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		cents    int64
+		expected string
+	}{
+		{cents: 0, expected: "0.00"},
+		{cents: 5, expected: "0.05"},
+		{cents: 1450, expected: "14.50"},
+		{cents: -1205, expected: "-12.05"},
+		{cents: 123456789, expected: "1234567.89"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, FormatCents(tt.cents))
+		})
+	}
+}
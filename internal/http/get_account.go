@@ -0,0 +1,32 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"payment/internal/core"
+)
+
+// GetAccount returns the balances of the account identified by the iban and
+// bic path parameters. Lookups do not count towards the transfer quota.
+func (h Handler) GetAccount(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "GetAccount")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	iban, bic := r.PathValue("iban"), r.PathValue("bic")
+
+	account, err := h.bulkTransferProcessor.GetAccount(ctx, iban, bic)
+	if err != nil {
+		if errors.Is(err, core.ErrAccountNotFound) {
+			h.writeProblem(w, r, newProblem(http.StatusNotFound, CodeAccountNotFound, "Account not found"))
+			return
+		}
+
+		h.logger.ErrorContext(ctx, "Failed to get account", "error", err)
+		h.writeProblem(w, r, newProblem(http.StatusInternalServerError, CodeInternalServerError, "Failed to get account"))
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, NewAccountResponse(account))
+}
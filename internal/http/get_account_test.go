@@ -0,0 +1,105 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"payment/internal/core"
+	"payment/internal/ratelimit"
+)
+
+func TestHandler_GetAccount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		setupMock        func(mock *MockBulkTransferProcessor)
+		expectedStatus   int
+		expectedResponse string
+	}{
+		{
+			name: "returns_balances",
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().
+					GetAccount(gomock.Any(), "TESTIBAN", "TESTBIC").
+					Return(core.Account{
+						OrganizationName:    "ACME",
+						IBAN:                "TESTIBAN",
+						BIC:                 "TESTBIC",
+						Currency:            "EUR",
+						BalanceCents:        -2500,
+						HeldCents:           1000,
+						OverdraftLimitCents: 10000,
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: `{
+				"organization_name": "ACME",
+				"iban": "TESTIBAN",
+				"bic": "TESTBIC",
+				"balance": "-25.00",
+				"available_balance": "65.00",
+				"held": "10.00",
+				"overdraft_limit": "100.00",
+				"overdraft_used": "25.00",
+				"currency": "EUR",
+				"frozen": false
+			}`,
+		},
+		{
+			name: "account_not_found_returns_404",
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().
+					GetAccount(gomock.Any(), "TESTIBAN", "TESTBIC").
+					Return(core.Account{}, core.ErrAccountNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "unexpected_error_returns_500",
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().
+					GetAccount(gomock.Any(), "TESTIBAN", "TESTBIC").
+					Return(core.Account{}, errors.New("database is locked"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProcessor := NewMockBulkTransferProcessor(ctrl)
+			tt.setupMock(mockProcessor)
+
+			// Lookups do not count towards the transfer quota.
+			mockLimiter := NewMockRateLimiter(ctrl)
+			mockLimiter.EXPECT().
+				Allow(gomock.Any(), "", "192.0.2.1").
+				Return(ratelimit.Decision{Allowed: true, Key: "addr:192.0.2.1"}, nil)
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			server := NewServer(mockProcessor, mockLimiter, NewMockHealthChecker(ctrl), logger, 0, Config{})
+
+			req := httptest.NewRequest(http.MethodGet, "/accounts/TESTIBAN/TESTBIC", nil)
+			w := httptest.NewRecorder()
+			server.httpServer.Handler.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedResponse != "" {
+				require.JSONEq(t, tt.expectedResponse, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+//go:generate go tool go.uber.org/mock/mockgen -source=health.go -destination=health_mock.go -package=http
+
+type HealthChecker interface {
+	Ping(ctx context.Context) error
+	CheckSchema(ctx context.Context) error
+	CheckJournalMode(ctx context.Context) error
+}
+
+const (
+	ComponentStatusUp       = "up"
+	ComponentStatusDown     = "down"
+	ComponentStatusDraining = "draining"
+)
+
+const readinessCheckTimeout = 2 * time.Second
+
+type ComponentStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type HealthResponse struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+type HealthHandler struct {
+	checker  HealthChecker
+	draining *atomic.Bool
+	logger   Logger
+}
+
+func NewHealthHandler(checker HealthChecker, draining *atomic.Bool, logger Logger) HealthHandler {
+	return HealthHandler{
+		checker:  checker,
+		draining: draining,
+		logger:   logger,
+	}
+}
+
+// Liveness only reports that the process serves requests; a database outage
+// must not get the service restarted.
+func (h HealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	h.write(w, r, HealthResponse{
+		Status: ComponentStatusUp,
+		Components: map[string]ComponentStatus{
+			"server": {Status: ComponentStatusUp},
+		},
+	})
+}
+
+func (h HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessCheckTimeout)
+	defer cancel()
+
+	response := HealthResponse{
+		Status: ComponentStatusUp,
+		Components: map[string]ComponentStatus{
+			"server":       {Status: ComponentStatusUp},
+			"database":     componentStatus(h.checker.Ping(ctx)),
+			"schema":       componentStatus(h.checker.CheckSchema(ctx)),
+			"journal_mode": componentStatus(h.checker.CheckJournalMode(ctx)),
+		},
+	}
+
+	if h.draining.Load() {
+		response.Components["server"] = ComponentStatus{Status: ComponentStatusDraining}
+	}
+
+	for _, component := range response.Components {
+		if component.Status != ComponentStatusUp {
+			response.Status = ComponentStatusDown
+		}
+	}
+
+	h.write(w, r, response)
+}
+
+func componentStatus(err error) ComponentStatus {
+	if err != nil {
+		return ComponentStatus{Status: ComponentStatusDown, Error: err.Error()}
+	}
+
+	return ComponentStatus{Status: ComponentStatusUp}
+}
+
+func (h HealthHandler) write(w http.ResponseWriter, r *http.Request, response HealthResponse) {
+	status := http.StatusOK
+	if response.Status != ComponentStatusUp {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.ErrorContext(r.Context(), "Failed to write health response", "error", err)
+	}
+}
@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: health.go
+//
+// Generated by this command:
+//
+//	mockgen -source=health.go -destination=health_mock.go -package=http
+//
+
+// Package http is a generated GoMock package.
+package http
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHealthChecker is a mock of HealthChecker interface.
+type MockHealthChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthCheckerMockRecorder
+	isgomock struct{}
+}
+
+// MockHealthCheckerMockRecorder is the mock recorder for MockHealthChecker.
+type MockHealthCheckerMockRecorder struct {
+	mock *MockHealthChecker
+}
+
+// NewMockHealthChecker creates a new mock instance.
+func NewMockHealthChecker(ctrl *gomock.Controller) *MockHealthChecker {
+	mock := &MockHealthChecker{ctrl: ctrl}
+	mock.recorder = &MockHealthCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthChecker) EXPECT() *MockHealthCheckerMockRecorder {
+	return m.recorder
+}
+
+// CheckJournalMode mocks base method.
+func (m *MockHealthChecker) CheckJournalMode(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckJournalMode", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckJournalMode indicates an expected call of CheckJournalMode.
+func (mr *MockHealthCheckerMockRecorder) CheckJournalMode(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckJournalMode", reflect.TypeOf((*MockHealthChecker)(nil).CheckJournalMode), ctx)
+}
+
+// CheckSchema mocks base method.
+func (m *MockHealthChecker) CheckSchema(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckSchema", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckSchema indicates an expected call of CheckSchema.
+func (mr *MockHealthCheckerMockRecorder) CheckSchema(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckSchema", reflect.TypeOf((*MockHealthChecker)(nil).CheckSchema), ctx)
+}
+
+// Ping mocks base method.
+func (m *MockHealthChecker) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockHealthCheckerMockRecorder) Ping(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockHealthChecker)(nil).Ping), ctx)
+}
@@ -0,0 +1,144 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHealthHandler_Readiness(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		setupMock        func(mock *MockHealthChecker)
+		draining         bool
+		expectedStatus   int
+		expectedResponse HealthResponse
+	}{
+		{
+			name: "all_components_up_returns_200",
+			setupMock: func(mock *MockHealthChecker) {
+				mock.EXPECT().Ping(gomock.Any()).Return(nil)
+				mock.EXPECT().CheckSchema(gomock.Any()).Return(nil)
+				mock.EXPECT().CheckJournalMode(gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: HealthResponse{
+				Status: ComponentStatusUp,
+				Components: map[string]ComponentStatus{
+					"server":       {Status: ComponentStatusUp},
+					"database":     {Status: ComponentStatusUp},
+					"schema":       {Status: ComponentStatusUp},
+					"journal_mode": {Status: ComponentStatusUp},
+				},
+			},
+		},
+		{
+			name: "outdated_schema_returns_503",
+			setupMock: func(mock *MockHealthChecker) {
+				mock.EXPECT().Ping(gomock.Any()).Return(nil)
+				mock.EXPECT().CheckSchema(gomock.Any()).Return(errors.New("schema version is 3, expected 4"))
+				mock.EXPECT().CheckJournalMode(gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedResponse: HealthResponse{
+				Status: ComponentStatusDown,
+				Components: map[string]ComponentStatus{
+					"server":       {Status: ComponentStatusUp},
+					"database":     {Status: ComponentStatusUp},
+					"schema":       {Status: ComponentStatusDown, Error: "schema version is 3, expected 4"},
+					"journal_mode": {Status: ComponentStatusUp},
+				},
+			},
+		},
+		{
+			name: "database_down_returns_503",
+			setupMock: func(mock *MockHealthChecker) {
+				mock.EXPECT().Ping(gomock.Any()).Return(errors.New("disk I/O error"))
+				mock.EXPECT().CheckSchema(gomock.Any()).Return(errors.New("disk I/O error"))
+				mock.EXPECT().CheckJournalMode(gomock.Any()).Return(errors.New("disk I/O error"))
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedResponse: HealthResponse{
+				Status: ComponentStatusDown,
+				Components: map[string]ComponentStatus{
+					"server":       {Status: ComponentStatusUp},
+					"database":     {Status: ComponentStatusDown, Error: "disk I/O error"},
+					"schema":       {Status: ComponentStatusDown, Error: "disk I/O error"},
+					"journal_mode": {Status: ComponentStatusDown, Error: "disk I/O error"},
+				},
+			},
+		},
+		{
+			name: "draining_returns_503",
+			setupMock: func(mock *MockHealthChecker) {
+				mock.EXPECT().Ping(gomock.Any()).Return(nil)
+				mock.EXPECT().CheckSchema(gomock.Any()).Return(nil)
+				mock.EXPECT().CheckJournalMode(gomock.Any()).Return(nil)
+			},
+			draining:       true,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedResponse: HealthResponse{
+				Status: ComponentStatusDown,
+				Components: map[string]ComponentStatus{
+					"server":       {Status: ComponentStatusDraining},
+					"database":     {Status: ComponentStatusUp},
+					"schema":       {Status: ComponentStatusUp},
+					"journal_mode": {Status: ComponentStatusUp},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockChecker := NewMockHealthChecker(ctrl)
+			tt.setupMock(mockChecker)
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			server := NewServer(NewMockBulkTransferProcessor(ctrl), NewMockRateLimiter(ctrl), mockChecker, logger, 0, Config{})
+			if tt.draining {
+				require.NoError(t, server.Stop(context.Background()))
+			}
+
+			w := httptest.NewRecorder()
+			server.httpServer.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			require.Equal(t, tt.expectedStatus, w.Code)
+			require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+			var response HealthResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			require.Equal(t, tt.expectedResponse, response)
+		})
+	}
+}
+
+func TestHealthHandler_Liveness(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server := NewServer(NewMockBulkTransferProcessor(ctrl), NewMockRateLimiter(ctrl), NewMockHealthChecker(ctrl), logger, 0, Config{})
+	require.NoError(t, server.Stop(context.Background()))
+
+	w := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"status": "up", "components": {"server": {"status": "up"}}}`, w.Body.String())
+}
@@ -0,0 +1,217 @@
+package http
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+
+	"payment/internal/core"
+)
+
+// openAPISpec documents every route of the server. It is maintained by hand
+// next to the DTOs; TestOpenAPI_MatchesHandlers fails when they drift apart.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+func loadOpenAPISpec() (*openapi3.T, error) {
+	spec, err := openapi3.NewLoader().LoadFromData(openAPISpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI spec: %w", err)
+	}
+
+	if err = spec.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+	}
+
+	return spec, nil
+}
+
+func serveOpenAPISpec(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPISpec)
+}
+
+// requestValidator rejects requests that do not conform to the OpenAPI spec
+// before they reach the handlers.
+type requestValidator struct {
+	router  routers.Router
+	handler Handler
+}
+
+func newRequestValidator(spec *openapi3.T, handler Handler) (requestValidator, error) {
+	router, err := gorillamux.NewRouter(spec)
+	if err != nil {
+		return requestValidator{}, fmt.Errorf("failed to build OpenAPI router: %w", err)
+	}
+
+	return requestValidator{router: router, handler: handler}, nil
+}
+
+func (v requestValidator) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(r)
+		if err != nil {
+			// Unknown paths and methods are answered by the mux.
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		options := &openapi3filter.Options{
+			MultiError:          true,
+			SkipSettingDefaults: true,
+		}
+
+		if requestBody := route.Operation.RequestBody; requestBody != nil {
+			mediaType := requestBody.Value.Content.Get(r.Header.Get("Content-Type"))
+			if mediaType == nil {
+				problem := newProblem(http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, "Unsupported media type")
+				problem.Detail = fmt.Sprintf("expected %s", strings.Join(slices.Sorted(maps.Keys(requestBody.Value.Content)), " or "))
+				v.handler.writeProblem(w, r, problem)
+				return
+			}
+
+			// Bodies without a schema, such as NDJSON streams, are validated by
+			// their handler as they are read.
+			options.ExcludeRequestBody = mediaType.Schema == nil
+		}
+
+		err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+		if err == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			v.handler.writeProblem(w, r, v.handler.bodyProblem(err))
+			return
+		}
+
+		var parseErr *openapi3filter.ParseError
+		if errors.As(err, &parseErr) {
+			problem := newProblem(http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+			problem.Detail = parseErr.Error()
+			v.handler.writeProblem(w, r, problem)
+			return
+		}
+
+		fieldErrors := specFieldErrors(err)
+
+		// In partial mode, invalid credit transfers are rejected individually
+		// by the handler.
+		if _, requestErrors := splitLineErrors(fieldErrors); len(requestErrors) == 0 && partialMode(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		v.handler.writeProblem(w, r, validationProblem(&ValidationError{Errors: fieldErrors}))
+	})
+}
+
+// partialMode reports whether the JSON body of r asks for partial execution.
+// The body has already been validated and can be read again.
+func partialMode(r *http.Request) bool {
+	if r.GetBody == nil {
+		return false
+	}
+
+	body, err := r.GetBody()
+	if err != nil {
+		return false
+	}
+	defer body.Close()
+
+	var request struct {
+		ExecutionMode string `json:"execution_mode"`
+	}
+	if err = json.NewDecoder(body).Decode(&request); err != nil {
+		return false
+	}
+
+	return request.ExecutionMode == string(core.ExecutionModePartial)
+}
+
+// specFieldErrors flattens the errors of openapi3filter.ValidateRequest.
+func specFieldErrors(err error) []FieldError {
+	var multiErr openapi3.MultiError
+	if errors.As(err, &multiErr) {
+		var fieldErrors []FieldError
+		for _, err := range multiErr {
+			fieldErrors = append(fieldErrors, specFieldErrors(err)...)
+		}
+		return fieldErrors
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return []FieldError{schemaFieldError(schemaErr)}
+	}
+
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) {
+		pointer := ""
+		if requestErr.Parameter != nil {
+			pointer = "/" + requestErr.Parameter.Name
+		}
+		return []FieldError{{Pointer: pointer, Code: CodeValidationFailed, Detail: requestErr.Error()}}
+	}
+
+	return []FieldError{{Code: CodeValidationFailed, Detail: err.Error()}}
+}
+
+// schemaFieldError reports a schema violation with the codes and details the
+// handlers use for the same rule.
+func schemaFieldError(schemaErr *openapi3.SchemaError) FieldError {
+	segments := schemaErr.JSONPointer()
+	for i, segment := range segments {
+		segments[i] = strings.NewReplacer("~", "~0", "/", "~1").Replace(segment)
+	}
+
+	fieldErr := FieldError{
+		Pointer: "/" + strings.Join(segments, "/"),
+		Code:    schemaErr.SchemaField,
+		Detail:  schemaErr.Reason,
+	}
+
+	switch schemaErr.SchemaField {
+	case "required", "minLength":
+		fieldErr.Code = "required"
+		fieldErr.Detail = "is required"
+	case "enum":
+		values := make([]string, len(schemaErr.Schema.Enum))
+		for i, value := range schemaErr.Schema.Enum {
+			values[i] = fmt.Sprint(value)
+		}
+		fieldErr.Code = "oneof"
+		fieldErr.Detail = fmt.Sprintf("must be one of: %s", strings.Join(values, ", "))
+		if len(values) == 1 {
+			fieldErr.Code = "eq"
+			fieldErr.Detail = fmt.Sprintf("must be %s", values[0])
+		}
+	case "minItems":
+		fieldErr.Code = "min"
+		fieldErr.Detail = fmt.Sprintf("must contain at least %d item(s)", schemaErr.Schema.MinItems)
+	case "minimum":
+		fieldErr.Code = "min"
+		fieldErr.Detail = fmt.Sprintf("must be at least %v", *schemaErr.Schema.Min)
+	}
+
+	return fieldErr
+}
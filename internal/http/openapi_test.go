@@ -0,0 +1,413 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"payment/internal/core"
+)
+
+// TestOpenAPI_MatchesHandlers fails when openapi.json and the routes or DTOs
+// drift apart.
+func TestOpenAPI_MatchesHandlers(t *testing.T) {
+	t.Parallel()
+
+	spec, err := loadOpenAPISpec()
+	require.NoError(t, err)
+
+	t.Run("routes", func(t *testing.T) {
+		t.Parallel()
+
+		var documented []string
+		for path, item := range spec.Paths.Map() {
+			for method := range item.Operations() {
+				documented = append(documented, method+" "+path)
+			}
+		}
+
+		var served []string
+		for pattern := range routes(Handler{}, HealthHandler{}) {
+			served = append(served, pattern)
+		}
+
+		require.ElementsMatch(t, served, documented)
+	})
+
+	dtos := map[string]any{
+		"BulkTransferRequest":       BulkTransferRequest{},
+		"CreditTransfer":            CreditTransfer{},
+		"StreamHeader":              StreamHeader{},
+		"StreamEvent":               StreamEvent{},
+		"BulkTransferResponse":      BulkTransferResponse{},
+		"TransferResultResponse":    TransferResultResponse{},
+		"HeldBulkTransferResponse":  HeldBulkTransferResponse{},
+		"BulkTransferQuoteResponse": BulkTransferQuoteResponse{},
+		"ViolationResponse":         ViolationResponse{},
+		"AccountResponse":           AccountResponse{},
+		"Problem":                   Problem{},
+		"FieldError":                FieldError{},
+		"HealthResponse":            HealthResponse{},
+		"ComponentStatus":           ComponentStatus{},
+	}
+
+	t.Run("schemas", func(t *testing.T) {
+		t.Parallel()
+
+		require.ElementsMatch(t, slices.Collect(maps.Keys(dtos)), slices.Collect(maps.Keys(spec.Components.Schemas)))
+
+		for name, dto := range dtos {
+			requireSchemaMatches(t, name, spec.Components.Schemas[name].Value, reflect.TypeOf(dto))
+		}
+	})
+}
+
+// requireSchemaMatches compares the properties, types, required fields and
+// enums of schema with the JSON and validate tags of dtoType.
+func requireSchemaMatches(t *testing.T, name string, schema *openapi3.Schema, dtoType reflect.Type) {
+	t.Helper()
+
+	var fields, required []string
+	for i := range dtoType.NumField() {
+		field := dtoType.Field(i)
+		jsonName, jsonOptions, _ := strings.Cut(field.Tag.Get("json"), ",")
+		fields = append(fields, jsonName)
+
+		validate := field.Tag.Get("validate")
+		rules := strings.Split(validate, ",")
+		if slices.Contains(rules, "required") || (validate == "" && !strings.Contains(jsonOptions, "omitempty")) {
+			required = append(required, jsonName)
+		}
+
+		property := schema.Properties[jsonName]
+		require.NotNil(t, property, "%s.%s is not documented", name, jsonName)
+		require.Equal(t, schemaType(field.Type), property.Value.Type.Slice()[0], "%s.%s type", name, jsonName)
+
+		for _, rule := range rules {
+			ruleName, param, _ := strings.Cut(rule, "=")
+			switch ruleName {
+			case "oneof", "eq":
+				var enum []string
+				for _, value := range property.Value.Enum {
+					enum = append(enum, value.(string))
+				}
+				require.Equal(t, strings.Fields(param), enum, "%s.%s enum", name, jsonName)
+			}
+		}
+	}
+
+	require.ElementsMatch(t, fields, slices.Collect(maps.Keys(schema.Properties)), "%s properties", name)
+	require.ElementsMatch(t, required, schema.Required, "%s required properties", name)
+}
+
+func schemaType(goType reflect.Type) string {
+	switch goType.Kind() {
+	case reflect.String:
+		return openapi3.TypeString
+	case reflect.Int, reflect.Int64:
+		return openapi3.TypeInteger
+	case reflect.Bool:
+		return openapi3.TypeBoolean
+	case reflect.Slice:
+		return openapi3.TypeArray
+	case reflect.Pointer:
+		return schemaType(goType.Elem())
+	default:
+		return openapi3.TypeObject
+	}
+}
+
+// TestOpenAPI_ResponsesConformToSpec sends requests through the server and
+// validates the responses against the spec.
+func TestOpenAPI_ResponsesConformToSpec(t *testing.T) {
+	t.Parallel()
+
+	const validRequest = `{"organization_bic":"TESTBIC","organization_iban":"TESTIBAN","execution_mode":"partial","credit_transfers":[
+		{"amount":"1.00","currency":"EUR","counterparty_name":"A","counterparty_bic":"BIC","counterparty_iban":"IBAN","description":"D"},
+		{"amount":"1.00","currency":"USD","counterparty_name":"A","counterparty_bic":"BIC","counterparty_iban":"IBAN","description":"D"}]}`
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		setupMock      func(mock *MockBulkTransferProcessor, checker *MockHealthChecker)
+		expectedStatus int
+	}{
+		{
+			name:   "created",
+			method: http.MethodPost,
+			path:   "/transfers/bulk",
+			body:   validRequest,
+			setupMock: func(mock *MockBulkTransferProcessor, _ *MockHealthChecker) {
+				mock.EXPECT().ProcessBulkTransfer(gomock.Any(), gomock.Any()).Return(core.BulkTransferResult{
+					BulkTransferID: 1,
+					ExecutionMode:  core.ExecutionModePartial,
+					Transfers:      []core.TransferResult{{Index: 0, Status: core.TransferAccepted}},
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "insufficient_funds",
+			method: http.MethodPost,
+			path:   "/transfers/bulk",
+			body:   validRequest,
+			setupMock: func(mock *MockBulkTransferProcessor, _ *MockHealthChecker) {
+				mock.EXPECT().ProcessBulkTransfer(gomock.Any(), gomock.Any()).Return(core.BulkTransferResult{}, core.ErrInsufficientFunds)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "quote",
+			method: http.MethodPost,
+			path:   "/transfers/bulk:validate",
+			body:   validRequest,
+			setupMock: func(mock *MockBulkTransferProcessor, _ *MockHealthChecker) {
+				mock.EXPECT().ValidateBulkTransfer(gomock.Any(), gomock.Any()).Return(core.BulkTransferQuote{
+					TransferCount: 1,
+					Violations:    []core.Violation{{TransferIndex: core.BatchViolation, Code: core.ViolationInsufficientFunds, Message: "m"}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "validation_failed",
+			method:         http.MethodPost,
+			path:           "/transfers/bulk",
+			body:           `{"organization_bic":"TESTBIC","credit_transfers":[]}`,
+			setupMock:      func(*MockBulkTransferProcessor, *MockHealthChecker) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "readiness",
+			method: http.MethodGet,
+			path:   "/readyz",
+			setupMock: func(_ *MockBulkTransferProcessor, checker *MockHealthChecker) {
+				checker.EXPECT().Ping(gomock.Any()).Return(nil)
+				checker.EXPECT().CheckSchema(gomock.Any()).Return(nil)
+				checker.EXPECT().CheckJournalMode(gomock.Any()).Return(io.EOF)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "account",
+			method: http.MethodGet,
+			path:   "/accounts/TESTIBAN/TESTBIC",
+			setupMock: func(mock *MockBulkTransferProcessor, _ *MockHealthChecker) {
+				mock.EXPECT().GetAccount(gomock.Any(), "TESTIBAN", "TESTBIC").Return(core.Account{BalanceCents: -500, OverdraftLimitCents: 1000}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "spec",
+			method:         http.MethodGet,
+			path:           "/openapi.json",
+			setupMock:      func(*MockBulkTransferProcessor, *MockHealthChecker) {},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	spec, err := loadOpenAPISpec()
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(spec)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProcessor := NewMockBulkTransferProcessor(ctrl)
+			mockChecker := NewMockHealthChecker(ctrl)
+			tt.setupMock(mockProcessor, mockChecker)
+
+			mockLimiter := allowingLimiter(ctrl)
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			server := NewServer(mockProcessor, mockLimiter, mockChecker, logger, 0, Config{})
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+			server.httpServer.Handler.ServeHTTP(w, req)
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+
+			route, pathParams, err := router.FindRoute(httptest.NewRequest(tt.method, tt.path, nil))
+			require.NoError(t, err)
+
+			err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: &openapi3filter.RequestValidationInput{Request: req, PathParams: pathParams, Route: route},
+				Status:                 w.Code,
+				Header:                 w.Header(),
+				Body:                   io.NopCloser(bytes.NewReader(w.Body.Bytes())),
+				Options:                &openapi3filter.Options{IncludeResponseStatus: true, MultiError: true},
+			})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRequestValidator(t *testing.T) {
+	t.Parallel()
+
+	const validTransfer = `{"amount":"1.00","currency":"EUR","counterparty_name":"A","counterparty_bic":"BIC","counterparty_iban":"IBAN","description":"D"}`
+	const usdTransfer = `{"amount":"1.00","currency":"USD","counterparty_name":"A","counterparty_bic":"BIC","counterparty_iban":"IBAN","description":"D"}`
+
+	tests := []struct {
+		name           string
+		path           string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedCode   string
+		expectedErrors []FieldError
+		// reachesHandler is set when the request is expected to get through.
+		reachesHandler bool
+	}{
+		{
+			name:           "conforming_request_reaches_the_handler",
+			path:           "/transfers/bulk",
+			contentType:    "application/json; charset=utf-8",
+			body:           `{"organization_bic":"B","organization_iban":"I","credit_transfers":[` + validTransfer + `]}`,
+			reachesHandler: true,
+		},
+		{
+			name:           "missing_and_invalid_fields",
+			path:           "/transfers/bulk",
+			contentType:    "application/json",
+			body:           `{"organization_iban":"I","execution_mode":"sometimes","credit_transfers":[` + validTransfer + `,` + usdTransfer + `]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeValidationFailed,
+			expectedErrors: []FieldError{
+				{Pointer: "/organization_bic", Code: "required", Detail: "is required"},
+				{Pointer: "/execution_mode", Code: "oneof", Detail: "must be one of: all_or_nothing, partial"},
+				{Pointer: "/credit_transfers/1/currency", Code: "eq", Detail: "must be EUR"},
+			},
+		},
+		{
+			name:           "empty_batch",
+			path:           "/transfers/bulk:validate",
+			contentType:    "application/json",
+			body:           `{"organization_bic":"B","organization_iban":"I","credit_transfers":[]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeValidationFailed,
+			expectedErrors: []FieldError{
+				{Pointer: "/credit_transfers", Code: "min", Detail: "must contain at least 1 item(s)"},
+			},
+		},
+		{
+			name:           "partial_mode_leaves_invalid_transfers_to_the_handler",
+			path:           "/transfers/bulk",
+			contentType:    "application/json",
+			body:           `{"organization_bic":"B","organization_iban":"I","execution_mode":"partial","credit_transfers":[` + validTransfer + `,` + usdTransfer + `]}`,
+			reachesHandler: true,
+		},
+		{
+			name:           "partial_mode_still_rejects_request_errors",
+			path:           "/transfers/bulk",
+			contentType:    "application/json",
+			body:           `{"organization_iban":"I","execution_mode":"partial","credit_transfers":[` + usdTransfer + `]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeValidationFailed,
+			expectedErrors: []FieldError{
+				{Pointer: "/organization_bic", Code: "required", Detail: "is required"},
+				{Pointer: "/credit_transfers/0/currency", Code: "eq", Detail: "must be EUR"},
+			},
+		},
+		{
+			name:           "malformed_json",
+			path:           "/transfers/bulk",
+			contentType:    "application/json",
+			body:           `{"organization_bic":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeInvalidBody,
+		},
+		{
+			name:           "unsupported_media_type",
+			path:           "/transfers/bulk",
+			contentType:    "text/csv",
+			body:           "a,b",
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedCode:   CodeUnsupportedMediaType,
+		},
+		{
+			name:           "stream_bodies_are_left_to_the_handler",
+			path:           "/transfers/bulk:stream",
+			contentType:    ndjsonContentType,
+			body:           "not json\n",
+			reachesHandler: true,
+		},
+		{
+			name:           "undocumented_paths_are_left_to_the_mux",
+			path:           "/transfers",
+			contentType:    "application/json",
+			body:           `{}`,
+			reachesHandler: true,
+		},
+	}
+
+	spec, err := loadOpenAPISpec()
+	require.NoError(t, err)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	validator, err := newRequestValidator(spec, NewHandler(nil, nil, logger, 0))
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var reached bool
+			var receivedBody string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+				body, _ := io.ReadAll(r.Body)
+				receivedBody = string(body)
+				w.WriteHeader(http.StatusTeapot)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			validator.middleware(next).ServeHTTP(w, req)
+
+			if tt.reachesHandler {
+				require.True(t, reached, w.Body.String())
+				require.Equal(t, tt.body, receivedBody, "the handler must get the body unchanged")
+				return
+			}
+
+			require.False(t, reached)
+			require.Equal(t, tt.expectedStatus, w.Code)
+			require.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+
+			var problem Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			require.Equal(t, tt.expectedCode, problem.Code)
+			require.Equal(t, tt.path, problem.Instance)
+			if tt.expectedErrors != nil {
+				require.ElementsMatch(t, tt.expectedErrors, problem.Errors)
+			}
+		})
+	}
+}
@@ -4,68 +4,393 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 
 	"payment/internal/core"
+	"payment/internal/ratelimit"
 )
 
 //go:generate go tool go.uber.org/mock/mockgen -source=post_transfers.go -destination=service_mock.go -package=http
 
 type BulkTransferProcessor interface {
-	ProcessBulkTransfer(ctx context.Context, bulkTransfer core.BulkTransfer) error
+	ProcessBulkTransfer(ctx context.Context, bulkTransfer core.BulkTransfer) (core.BulkTransferResult, error)
+	ValidateBulkTransfer(ctx context.Context, bulkTransfer core.BulkTransfer) (core.BulkTransferQuote, error)
+	StageBulkTransfer(ctx context.Context, bulkTransfer core.BulkTransfer) (int64, error)
+	StageTransfers(ctx context.Context, stagedBulkTransferID int64, transfers []core.StagedTransfer) error
+	ExecuteStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) (core.BulkTransferResult, error)
+	DiscardStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) error
+	GetAccount(ctx context.Context, iban string, bic string) (core.Account, error)
 }
 
+type RateLimiter interface {
+	Allow(ctx context.Context, apiKey string, address string) (ratelimit.Decision, error)
+}
+
+const (
+	apiKeyHeader    = "X-API-Key"
+	requestIDHeader = "X-Request-ID"
+)
+
+const (
+	// maxCreditTransferSize bounds the JSON of one credit transfer. SEPA
+	// limits names and descriptions to 140 characters, which leaves a wide
+	// margin.
+	maxCreditTransferSize = 2 << 10
+	// maxEnvelopeSize bounds the rest of a bulk transfer request.
+	maxEnvelopeSize = 64 << 10
+)
+
 type Handler struct {
 	bulkTransferProcessor BulkTransferProcessor
+	rateLimiter           RateLimiter
 	logger                Logger
 	validator             *validator.Validate
+	maxBatchSize          int
+	// maxBodySize bounds the body of a bulk transfer request, 0 for no limit.
+	maxBodySize int64
 }
 
-func NewHandler(bulkTransferProcessor BulkTransferProcessor, logger Logger) Handler {
+func NewHandler(bulkTransferProcessor BulkTransferProcessor, rateLimiter RateLimiter, logger Logger, maxBatchSize int) Handler {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(jsonFieldName)
+
+	var maxBodySize int64
+	if maxBatchSize > 0 {
+		maxBodySize = int64(maxBatchSize)*maxCreditTransferSize + maxEnvelopeSize
+	}
+
 	return Handler{
 		bulkTransferProcessor: bulkTransferProcessor,
+		rateLimiter:           rateLimiter,
 		logger:                logger,
-		validator:             validator.New(),
+		validator:             validate,
+		maxBatchSize:          maxBatchSize,
+		maxBodySize:           maxBodySize,
 	}
 }
 
 func (h Handler) PostTransfers(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	ctx, span := tracer.Start(r.Context(), "PostTransfers")
+	defer span.End()
+	r = r.WithContext(ctx)
 
-	var req BulkTransferRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	decoded, ok := h.decode(w, r)
+	if !ok {
 		return
 	}
 
-	if err := h.validator.Struct(&req); err != nil {
-		http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+	ctx = core.WithActor(ctx, callerID(r, decoded.request.OrganizationIBAN))
+
+	result, err := h.bulkTransferProcessor.ProcessBulkTransfer(ctx, decoded.bulkTransfer)
+	if heldErr, ok := h.held(ctx, err); ok {
+		h.writeJSON(w, r, http.StatusAccepted, NewHeldBulkTransferResponse(heldErr))
+		return
+	}
+	if err != nil {
+		setRetryAfter(w, err)
+		h.writeProblem(w, r, h.processingProblem(ctx, err))
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, NewBulkTransferResponse(result, decoded.indexes, decoded.lineErrors))
+}
+
+// ValidateTransfers previews a bulk transfer without executing it. Dry runs
+// do not count towards the daily transfer quota.
+func (h Handler) ValidateTransfers(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "ValidateTransfers")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	decoded, ok := h.decode(w, r)
+	if !ok {
 		return
 	}
 
-	bulkTransfer, err := req.ToDomain()
+	ctx = core.WithActor(ctx, callerID(r, decoded.request.OrganizationIBAN))
+
+	quote, err := h.bulkTransferProcessor.ValidateBulkTransfer(ctx, decoded.bulkTransfer)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		h.writeProblem(w, r, h.processingProblem(ctx, err))
 		return
 	}
 
-	if err := h.bulkTransferProcessor.ProcessBulkTransfer(ctx, bulkTransfer); err != nil {
-		if errors.Is(err, core.ErrAccountNotFound) {
-			http.Error(w, "Account not found", http.StatusNotFound)
+	h.writeJSON(w, r, http.StatusOK, NewBulkTransferQuoteResponse(quote, decoded.indexes, decoded.lineErrors))
+}
+
+type decodedRequest struct {
+	request      BulkTransferRequest
+	bulkTransfer core.BulkTransfer
+	// indexes maps each transfer of bulkTransfer to its position in the request.
+	indexes []int
+	// lineErrors holds the credit transfers rejected by validation in
+	// partial mode, keyed by their position in the request.
+	lineErrors map[int][]FieldError
+}
+
+// decode parses and validates a bulk transfer request. It writes the error
+// response and returns false when the request cannot proceed. In partial mode,
+// invalid credit transfers are left out instead of failing the whole request.
+func (h Handler) decode(w http.ResponseWriter, r *http.Request) (decodedRequest, bool) {
+	var req BulkTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeProblem(w, r, h.bodyProblem(err))
+		return decodedRequest{}, false
+	}
+
+	if !h.withinBatchSize(w, r, "/credit_transfers", len(req.CreditTransfers)) {
+		return decodedRequest{}, false
+	}
+
+	partial := req.ExecutionMode == string(core.ExecutionModePartial)
+
+	var lineErrors map[int][]FieldError
+	if err := h.validator.Struct(&req); err != nil {
+		problem := validationProblem(err)
+		if !partial {
+			h.writeProblem(w, r, problem)
+			return decodedRequest{}, false
+		}
+
+		var requestErrors []FieldError
+		lineErrors, requestErrors = splitLineErrors(problem.Errors)
+		if len(requestErrors) > 0 || len(lineErrors) == 0 {
+			h.writeProblem(w, r, problem)
+			return decodedRequest{}, false
+		}
+	}
+
+	bulkTransfer, indexes, lineErrors := req.toDomain(lineErrors)
+	if len(lineErrors) > 0 && (!partial || len(bulkTransfer.Transfers) == 0) {
+		h.writeProblem(w, r, validationProblem(&ValidationError{Errors: flattenLineErrors(lineErrors)}))
+		return decodedRequest{}, false
+	}
+
+	return decodedRequest{
+		request:      req,
+		bulkTransfer: bulkTransfer,
+		indexes:      indexes,
+		lineErrors:   lineErrors,
+	}, true
+}
+
+func (h Handler) writeJSON(w http.ResponseWriter, r *http.Request, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.logger.ErrorContext(r.Context(), "Failed to write response", "error", err)
+	}
+}
+
+// processingProblem maps a ProcessBulkTransfer error to the response sent to
+// the client.
+func (h Handler) processingProblem(ctx context.Context, err error) Problem {
+	if errors.Is(err, core.ErrAccountNotFound) {
+		return newProblem(http.StatusNotFound, CodeAccountNotFound, "Account not found")
+	}
+
+	if errors.Is(err, core.ErrInsufficientFunds) {
+		return newProblem(http.StatusUnprocessableEntity, CodeInsufficientFunds, "Insufficient funds for bulk transfer")
+	}
+
+	if errors.Is(err, core.ErrAccountFrozen) {
+		return newProblem(http.StatusUnprocessableEntity, CodeAccountFrozen, "Account is frozen")
+	}
+
+	if errors.Is(err, core.ErrHoldNotFound) {
+		return newProblem(http.StatusNotFound, CodeHoldNotFound, "Hold not found")
+	}
+
+	if errors.Is(err, core.ErrHoldNotPending) {
+		return newProblem(http.StatusUnprocessableEntity, CodeHoldNotPending, "Hold is no longer pending")
+	}
+
+	var quotaErr *core.QuotaError
+	if errors.As(err, &quotaErr) {
+		return newProblem(http.StatusTooManyRequests, CodeQuotaExceeded, "Daily transfer quota exceeded")
+	}
+
+	var batchSizeErr *core.BatchSizeError
+	if errors.As(err, &batchSizeErr) {
+		return batchSizeProblem("/credit_transfers", batchSizeErr.MaxSize)
+	}
+
+	var sanctionsErr *core.SanctionsError
+	if errors.As(err, &sanctionsErr) {
+		// Watchlist entries are not returned to the client to avoid tipping off.
+		h.logger.InfoContext(ctx, "Bulk transfer stopped by sanctions screening", "decision", sanctionsErr.Decision, "hits", sanctionsErr.Hits)
+		return newProblem(http.StatusUnavailableForLegalReasons, CodeSanctionsBlocked, "Bulk transfer blocked by sanctions screening")
+	}
+
+	var fraudErr *core.FraudError
+	if errors.As(err, &fraudErr) {
+		// Rule thresholds are not returned to the client.
+		h.logger.InfoContext(ctx, "Bulk transfer stopped by fraud rules", "decision", fraudErr.Decision, "reasons", fraudErr.Reasons)
+		return newProblem(http.StatusForbidden, CodeFraudBlocked, "Bulk transfer blocked by fraud rules")
+	}
+
+	h.logger.ErrorContext(ctx, "Failed to process bulk transfer", "error", err)
+	return newProblem(http.StatusInternalServerError, CodeInternalServerError, "Failed to process bulk transfer")
+}
+
+// held returns the *core.HeldError of a bulk transfer held for a manual
+// review, which is not a failure: the client is told where it waits.
+func (h Handler) held(ctx context.Context, err error) (*core.HeldError, bool) {
+	var heldErr *core.HeldError
+	if !errors.As(err, &heldErr) {
+		return nil, false
+	}
+
+	// Watchlist entries and rule thresholds are not returned to the client.
+	var sanctionsErr *core.SanctionsError
+	if errors.As(heldErr.Reason, &sanctionsErr) {
+		h.logger.InfoContext(ctx, "Bulk transfer held for sanctions review", "held_bulk_transfer_id", heldErr.HeldBulkTransferID, "hits", sanctionsErr.Hits)
+	}
+
+	var fraudErr *core.FraudError
+	if errors.As(heldErr.Reason, &fraudErr) {
+		h.logger.InfoContext(ctx, "Bulk transfer held for fraud review", "held_bulk_transfer_id", heldErr.HeldBulkTransferID, "reasons", fraudErr.Reasons)
+	}
+
+	return heldErr, true
+}
+
+// rateLimitedRoutes are the routes of the API, as opposed to the operational
+// ones such as /healthz.
+var rateLimitedRoutes = map[string]bool{
+	"POST /transfers/bulk":          true,
+	"POST /transfers/bulk:validate": true,
+	"POST /transfers/bulk:stream":   true,
+	"GET /accounts/{iban}/{bic}":    true,
+}
+
+type clientKey struct{}
+
+// rateLimitMiddleware applies the rate limit of the client before anything
+// reads the request body. Requests with an API key that is not configured are
+// refused. Limiter failures are logged and the request is let through.
+func (h Handler) rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !rateLimitedRoutes[r.Pattern] {
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		if errors.Is(err, core.ErrInsufficientFunds) {
-			http.Error(w, "Insufficient funds for bulk transfer", http.StatusUnprocessableEntity)
+		ctx := r.Context()
+		decision, err := h.rateLimiter.Allow(ctx, r.Header.Get(apiKeyHeader), clientAddress(r))
+		switch {
+		case errors.Is(err, ratelimit.ErrUnknownAPIKey):
+			h.writeProblem(w, r, newProblem(http.StatusUnauthorized, CodeUnauthorized, "Unknown API key"))
 			return
+		case err != nil:
+			h.logger.ErrorContext(ctx, "Failed to apply rate limit", "error", err)
+		case !decision.Allowed:
+			h.writeLimited(w, r, decision)
+			return
+		default:
+			r = r.WithContext(context.WithValue(ctx, clientKey{}, decision.Key))
 		}
 
-		h.logger.ErrorContext(ctx, "Failed to process bulk transfer", "error", err)
-		http.Error(w, "Failed to process bulk transfer", http.StatusInternalServerError)
-		return
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h Handler) writeLimited(w http.ResponseWriter, r *http.Request, decision ratelimit.Decision) {
+	writeRetryAfter(w, decision.RetryAfter)
+	h.writeProblem(w, r, newProblem(http.StatusTooManyRequests, CodeRateLimited, "Rate limit exceeded"))
+}
+
+// setRetryAfter tells the client when to retry a bulk transfer refused by the
+// daily quota.
+func setRetryAfter(w http.ResponseWriter, err error) {
+	var quotaErr *core.QuotaError
+	if errors.As(err, &quotaErr) {
+		writeRetryAfter(w, quotaErr.RetryAfter)
+	}
+}
+
+func writeRetryAfter(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+}
+
+// boundedBodyRoutes are the routes whose whole body is read at once. Streams
+// are read line by line instead.
+var boundedBodyRoutes = map[string]bool{
+	"POST /transfers/bulk":          true,
+	"POST /transfers/bulk:validate": true,
+}
+
+// bodyLimitMiddleware bounds the body of the bulk transfer requests by the
+// size of the largest batch allowed, before the request validator reads it.
+func (h Handler) bodyLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if boundedBodyRoutes[r.Pattern] && h.maxBodySize > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, h.maxBodySize)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bodyProblem reports a body that could not be read or decoded.
+func (h Handler) bodyProblem(err error) Problem {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		problem := newProblem(http.StatusRequestEntityTooLarge, CodeBodyTooLarge, "Request body too large")
+		problem.Detail = fmt.Sprintf("exceeds %d bytes, the limit for %d transfers", maxBytesErr.Limit, h.maxBatchSize)
+		return problem
+	}
+
+	problem := newProblem(http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+	problem.Detail = err.Error()
+	return problem
+}
+
+// withinBatchSize writes a validation problem pointing at pointer and returns
+// false when a batch of size transfers exceeds the maximum batch size.
+func (h Handler) withinBatchSize(w http.ResponseWriter, r *http.Request, pointer string, size int) bool {
+	if h.maxBatchSize <= 0 || size <= h.maxBatchSize {
+		return true
+	}
+
+	h.writeProblem(w, r, batchSizeProblem(pointer, h.maxBatchSize))
+	return false
+}
+
+func batchSizeProblem(pointer string, maxBatchSize int) Problem {
+	return validationProblem(&ValidationError{Errors: []FieldError{{
+		Pointer: pointer,
+		Code:    "max",
+		Detail:  fmt.Sprintf("exceeds the maximum batch size of %d transfers", maxBatchSize),
+	}}})
+}
+
+// callerID identifies the client in the audit log: by its API key when it
+// sent a configured one, by the debited organization otherwise.
+func callerID(r *http.Request, organizationIBAN string) string {
+	if key, _ := r.Context().Value(clientKey{}).(string); strings.HasPrefix(key, "api_key:") {
+		return key
+	}
+
+	return "org:" + organizationIBAN
+}
+
+// clientAddress is the host the request comes from.
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
 	}
 
-	w.WriteHeader(http.StatusCreated)
+	return host
 }
@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -9,11 +10,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"payment/internal/core"
+	"payment/internal/ratelimit"
 )
 
 func TestHandler_PostTransfers(t *testing.T) {
@@ -25,6 +28,8 @@ func TestHandler_PostTransfers(t *testing.T) {
 		setupMock        func(mock *MockBulkTransferProcessor)
 		expectedStatus   int
 		expectedBodyPart string
+		expectedHeaders  map[string]string
+		expectedResponse *BulkTransferResponse
 	}{
 		{
 			name: "successful_transfer_returns_201",
@@ -45,7 +50,7 @@ func TestHandler_PostTransfers(t *testing.T) {
 			setupMock: func(mock *MockBulkTransferProcessor) {
 				mock.EXPECT().
 					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
-					Return(nil).
+					Return(core.BulkTransferResult{}, nil).
 					Times(1)
 			},
 			expectedStatus: http.StatusCreated,
@@ -69,12 +74,37 @@ func TestHandler_PostTransfers(t *testing.T) {
 			setupMock: func(mock *MockBulkTransferProcessor) {
 				mock.EXPECT().
 					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
-					Return(core.ErrInsufficientFunds).
+					Return(core.BulkTransferResult{}, core.ErrInsufficientFunds).
 					Times(1)
 			},
 			expectedStatus:   http.StatusUnprocessableEntity,
 			expectedBodyPart: "Insufficient funds",
 		},
+		{
+			name: "frozen_account_returns_422",
+			requestBody: BulkTransferRequest{
+				OrganizationBIC:  "TESTBIC",
+				OrganizationIBAN: "TESTIBAN",
+				CreditTransfers: []CreditTransfer{
+					{
+						Amount:           "100.00",
+						Currency:         "EUR",
+						CounterpartyName: "Test",
+						CounterpartyBIC:  "BIC",
+						CounterpartyIBAN: "IBAN",
+						Description:      "Test",
+					},
+				},
+			},
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					Return(core.BulkTransferResult{}, core.ErrAccountFrozen).
+					Times(1)
+			},
+			expectedStatus:   http.StatusUnprocessableEntity,
+			expectedBodyPart: CodeAccountFrozen,
+		},
 		{
 			name: "account_not_found_returns_404",
 			requestBody: BulkTransferRequest{
@@ -94,7 +124,7 @@ func TestHandler_PostTransfers(t *testing.T) {
 			setupMock: func(mock *MockBulkTransferProcessor) {
 				mock.EXPECT().
 					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
-					Return(core.ErrAccountNotFound).
+					Return(core.BulkTransferResult{}, core.ErrAccountNotFound).
 					Times(1)
 			},
 			expectedStatus:   http.StatusNotFound,
@@ -119,12 +149,120 @@ func TestHandler_PostTransfers(t *testing.T) {
 			setupMock: func(mock *MockBulkTransferProcessor) {
 				mock.EXPECT().
 					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
-					Return(errors.New("database connection failed")).
+					Return(core.BulkTransferResult{}, errors.New("database connection failed")).
 					Times(1)
 			},
 			expectedStatus:   http.StatusInternalServerError,
 			expectedBodyPart: "Failed to process bulk transfer",
 		},
+		{
+			name: "sanctions_block_returns_451",
+			requestBody: BulkTransferRequest{
+				OrganizationBIC:  "TESTBIC",
+				OrganizationIBAN: "TESTIBAN",
+				CreditTransfers: []CreditTransfer{
+					{
+						Amount:           "100.00",
+						Currency:         "EUR",
+						CounterpartyName: "Test",
+						CounterpartyBIC:  "BIC",
+						CounterpartyIBAN: "IBAN",
+						Description:      "Test",
+					},
+				},
+			},
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					Return(core.BulkTransferResult{}, &core.SanctionsError{Decision: core.DecisionBlock}).
+					Times(1)
+			},
+			expectedStatus:   http.StatusUnavailableForLegalReasons,
+			expectedBodyPart: "blocked by sanctions screening",
+		},
+		{
+			name: "sanctions_review_returns_202_with_the_held_bulk_transfer",
+			requestBody: BulkTransferRequest{
+				OrganizationBIC:  "TESTBIC",
+				OrganizationIBAN: "TESTIBAN",
+				CreditTransfers: []CreditTransfer{
+					{
+						Amount:           "100.00",
+						Currency:         "EUR",
+						CounterpartyName: "Test",
+						CounterpartyBIC:  "BIC",
+						CounterpartyIBAN: "IBAN",
+						Description:      "Test",
+					},
+				},
+			},
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					Return(core.BulkTransferResult{}, &core.HeldError{
+						HeldBulkTransferID: 7,
+						Review:             core.ReviewKindSanctions,
+						Reason:             &core.SanctionsError{Decision: core.DecisionReview},
+					}).
+					Times(1)
+			},
+			expectedStatus:   http.StatusAccepted,
+			expectedBodyPart: `{"held_bulk_transfer_id":7,"review":"sanctions"}`,
+		},
+		{
+			name: "fraud_block_returns_403",
+			requestBody: BulkTransferRequest{
+				OrganizationBIC:  "TESTBIC",
+				OrganizationIBAN: "TESTIBAN",
+				CreditTransfers: []CreditTransfer{
+					{
+						Amount:           "100.00",
+						Currency:         "EUR",
+						CounterpartyName: "Test",
+						CounterpartyBIC:  "BIC",
+						CounterpartyIBAN: "IBAN",
+						Description:      "Test",
+					},
+				},
+			},
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					Return(core.BulkTransferResult{}, &core.FraudError{Decision: core.DecisionBlock, Reasons: []string{"repeated_counterparty"}}).
+					Times(1)
+			},
+			expectedStatus:   http.StatusForbidden,
+			expectedBodyPart: "blocked by fraud rules",
+		},
+		{
+			name: "fraud_review_returns_202_with_the_held_bulk_transfer",
+			requestBody: BulkTransferRequest{
+				OrganizationBIC:  "TESTBIC",
+				OrganizationIBAN: "TESTIBAN",
+				CreditTransfers: []CreditTransfer{
+					{
+						Amount:           "100.00",
+						Currency:         "EUR",
+						CounterpartyName: "Test",
+						CounterpartyBIC:  "BIC",
+						CounterpartyIBAN: "IBAN",
+						Description:      "Test",
+					},
+				},
+			},
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					Return(core.BulkTransferResult{}, &core.HeldError{
+						HeldBulkTransferID: 8,
+						Review:             core.ReviewKindFraud,
+						Reason:             &core.FraudError{Decision: core.DecisionReview, Reasons: []string{"new_counterparty_large_amount"}},
+					}).
+					Times(1)
+			},
+			expectedStatus:   http.StatusAccepted,
+			expectedBodyPart: `{"held_bulk_transfer_id":8,"review":"fraud"}`,
+		},
 		{
 			name: "validation_error_returns_400",
 			requestBody: BulkTransferRequest{
@@ -145,6 +283,22 @@ func TestHandler_PostTransfers(t *testing.T) {
 			expectedStatus:   http.StatusBadRequest,
 			expectedBodyPart: "Validation failed",
 		},
+		{
+			name: "batch_above_max_size_returns_400",
+			requestBody: BulkTransferRequest{
+				OrganizationBIC:  "TESTBIC",
+				OrganizationIBAN: "TESTIBAN",
+				CreditTransfers: []CreditTransfer{
+					{Amount: "1.00", Currency: "EUR", CounterpartyName: "Test", CounterpartyBIC: "BIC", CounterpartyIBAN: "IBAN1", Description: "Test"},
+					{Amount: "1.00", Currency: "EUR", CounterpartyName: "Test", CounterpartyBIC: "BIC", CounterpartyIBAN: "IBAN2", Description: "Test"},
+					{Amount: "1.00", Currency: "EUR", CounterpartyName: "Test", CounterpartyBIC: "BIC", CounterpartyIBAN: "IBAN3", Description: "Test"},
+					{Amount: "1.00", Currency: "EUR", CounterpartyName: "Test", CounterpartyBIC: "BIC", CounterpartyIBAN: "IBAN4", Description: "Test"},
+				},
+			},
+			setupMock:        func(mock *MockBulkTransferProcessor) {},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBodyPart: "exceeds the maximum batch size of 3 transfers",
+		},
 		{
 			name: "invalid_amount_format_returns_400",
 			requestBody: BulkTransferRequest{
@@ -165,6 +319,145 @@ func TestHandler_PostTransfers(t *testing.T) {
 			expectedStatus:   http.StatusBadRequest,
 			expectedBodyPart: "invalid amount",
 		},
+		{
+			name: "quota_exceeded_returns_429_with_retry_after",
+			requestBody: BulkTransferRequest{
+				OrganizationBIC:  "TESTBIC",
+				OrganizationIBAN: "TESTIBAN",
+				CreditTransfers: []CreditTransfer{
+					{
+						Amount:           "100.00",
+						Currency:         "EUR",
+						CounterpartyName: "Test",
+						CounterpartyBIC:  "BIC",
+						CounterpartyIBAN: "IBAN",
+						Description:      "Test",
+					},
+				},
+			},
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					Return(core.BulkTransferResult{}, &core.QuotaError{RetryAfter: time.Hour}).
+					Times(1)
+			},
+			expectedStatus:   http.StatusTooManyRequests,
+			expectedBodyPart: "Daily transfer quota exceeded",
+			expectedHeaders:  map[string]string{"Retry-After": "3600"},
+		},
+		{
+			name: "partial_mode_rejects_invalid_lines_and_processes_the_rest",
+			requestBody: BulkTransferRequest{
+				OrganizationBIC:  "TESTBIC",
+				OrganizationIBAN: "TESTIBAN",
+				ExecutionMode:    "partial",
+				CreditTransfers: []CreditTransfer{
+					{
+						Amount:           "100.00",
+						Currency:         "USD",
+						CounterpartyName: "Test",
+						CounterpartyBIC:  "BIC",
+						CounterpartyIBAN: "IBAN1",
+						Description:      "Test",
+					},
+					{
+						Amount:           "50.00",
+						Currency:         "EUR",
+						CounterpartyName: "Test",
+						CounterpartyBIC:  "BIC",
+						CounterpartyIBAN: "IBAN2",
+						Description:      "Test",
+					},
+					{
+						Amount:           "70.00",
+						Currency:         "EUR",
+						CounterpartyName: "Test",
+						CounterpartyBIC:  "BIC",
+						CounterpartyIBAN: "IBAN3",
+						Description:      "Test",
+					},
+				},
+			},
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().
+					ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, bulkTransfer core.BulkTransfer) (core.BulkTransferResult, error) {
+						if bulkTransfer.ExecutionMode != core.ExecutionModePartial || len(bulkTransfer.Transfers) != 2 {
+							return core.BulkTransferResult{}, errors.New("unexpected bulk transfer")
+						}
+						return core.BulkTransferResult{
+							BulkTransferID: 9,
+							ExecutionMode:  core.ExecutionModePartial,
+							Transfers: []core.TransferResult{
+								{Index: 0, Status: core.TransferAccepted, FeeCents: 20},
+								{Index: 1, Status: core.TransferRejected, Reason: core.RejectionInsufficientFunds},
+							},
+						}, nil
+					}).
+					Times(1)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedResponse: &BulkTransferResponse{
+				BulkTransferID: 9,
+				ExecutionMode:  "partial",
+				AcceptedCount:  1,
+				RejectedCount:  2,
+				Fees:           "0.20",
+				Currency:       "EUR",
+				Transfers: []TransferResultResponse{
+					{
+						Index:  0,
+						Status: "rejected",
+						Reason: CodeValidationFailed,
+						Errors: []FieldError{{Pointer: "/credit_transfers/0/currency", Code: "eq", Detail: "must be EUR"}},
+					},
+					{Index: 1, Status: "accepted", Fee: "0.20"},
+					{Index: 2, Status: "rejected", Reason: core.RejectionInsufficientFunds},
+				},
+			},
+		},
+		{
+			name: "partial_mode_with_no_valid_line_returns_400",
+			requestBody: BulkTransferRequest{
+				OrganizationBIC:  "TESTBIC",
+				OrganizationIBAN: "TESTIBAN",
+				ExecutionMode:    "partial",
+				CreditTransfers: []CreditTransfer{
+					{
+						Amount:           "not-a-number",
+						Currency:         "EUR",
+						CounterpartyName: "Test",
+						CounterpartyBIC:  "BIC",
+						CounterpartyIBAN: "IBAN",
+						Description:      "Test",
+					},
+				},
+			},
+			setupMock:        func(mock *MockBulkTransferProcessor) {},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBodyPart: "invalid amount",
+		},
+		{
+			name: "unknown_execution_mode_returns_400",
+			requestBody: BulkTransferRequest{
+				OrganizationBIC:  "TESTBIC",
+				OrganizationIBAN: "TESTIBAN",
+				ExecutionMode:    "best_effort",
+				CreditTransfers: []CreditTransfer{
+					{
+						Amount:           "100.00",
+						Currency:         "EUR",
+						CounterpartyName: "Test",
+						CounterpartyBIC:  "BIC",
+						CounterpartyIBAN: "IBAN",
+						Description:      "Test",
+					},
+				},
+			},
+			setupMock:        func(mock *MockBulkTransferProcessor) {},
+			expectedStatus:   http.StatusBadRequest,
+			expectedBodyPart: "/execution_mode",
+		},
 	}
 
 	for _, tt := range tests {
@@ -177,8 +470,10 @@ func TestHandler_PostTransfers(t *testing.T) {
 			mockProcessor := NewMockBulkTransferProcessor(ctrl)
 			tt.setupMock(mockProcessor)
 
+			mockLimiter := allowingLimiter(ctrl)
+
 			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
-			handler := NewHandler(mockProcessor, logger)
+			handler := NewHandler(mockProcessor, mockLimiter, logger, 3)
 
 			body, err := json.Marshal(tt.requestBody)
 			require.NoError(t, err)
@@ -192,6 +487,32 @@ func TestHandler_PostTransfers(t *testing.T) {
 			if tt.expectedBodyPart != "" {
 				require.Contains(t, w.Body.String(), tt.expectedBodyPart)
 			}
+			for header, value := range tt.expectedHeaders {
+				require.Equal(t, value, w.Header().Get(header))
+			}
+			if tt.expectedResponse != nil {
+				var response BulkTransferResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				require.Equal(t, *tt.expectedResponse, response)
+			}
+			if w.Code >= http.StatusBadRequest {
+				require.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+
+				var problem Problem
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+				require.Equal(t, w.Code, problem.Status)
+				require.Equal(t, problemTypePrefix+problem.Code, problem.Type)
+			}
 		})
 	}
 }
+
+// allowingLimiter returns a rate limiter that lets every request through.
+func allowingLimiter(ctrl *gomock.Controller) *MockRateLimiter {
+	limiter := NewMockRateLimiter(ctrl)
+	limiter.EXPECT().
+		Allow(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(ratelimit.Decision{Allowed: true}, nil).
+		AnyTimes()
+	return limiter
+}
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"payment/internal/core"
+)
+
+const problemContentType = "application/problem+json"
+
+// problemTypePrefix is followed by the error code to form the stable problem
+// type URI, e.g. urn:payment:problem:insufficient_funds.
+const problemTypePrefix = "urn:payment:problem:"
+
+// Error codes returned in problem details. They are part of the API contract.
+const (
+	CodeInvalidBody          = "invalid_body"
+	CodeBodyTooLarge         = "body_too_large"
+	CodeValidationFailed     = "validation_failed"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeAccountNotFound      = "account_not_found"
+	CodeInsufficientFunds    = "insufficient_funds"
+	CodeAccountFrozen        = "account_frozen"
+	CodeHoldNotFound         = "hold_not_found"
+	CodeHoldNotPending       = "hold_not_pending"
+	CodeSanctionsBlocked     = "sanctions_blocked"
+	CodeFraudBlocked         = "fraud_blocked"
+	CodeUnauthorized         = "unauthorized"
+	CodeRateLimited          = "rate_limited"
+	CodeQuotaExceeded        = "quota_exceeded"
+	CodeInternalServerError  = "internal_error"
+)
+
+// Problem is an RFC 7807 problem details object.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError points at a failing request field with a JSON pointer
+// (RFC 6901), e.g. /credit_transfers/2/amount.
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Code    string `json:"code"`
+	Detail  string `json:"detail"`
+}
+
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	details := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		details[i] = fieldErr.Pointer + ": " + fieldErr.Detail
+	}
+	return strings.Join(details, "; ")
+}
+
+func newProblem(status int, code string, title string) Problem {
+	return Problem{
+		Type:   problemTypePrefix + code,
+		Title:  title,
+		Status: status,
+		Code:   code,
+	}
+}
+
+func (h Handler) writeProblem(w http.ResponseWriter, r *http.Request, problem Problem) {
+	problem = problem.forRequest(r)
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		h.logger.ErrorContext(r.Context(), "Failed to write problem response", "error", err)
+	}
+}
+
+func (p Problem) forRequest(r *http.Request) Problem {
+	p.Instance = r.URL.Path
+	p.RequestID = core.RequestIDFromContext(r.Context())
+	return p
+}
+
+func validationProblem(err error) Problem {
+	problem := newProblem(http.StatusBadRequest, CodeValidationFailed, "Validation failed")
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		problem.Errors = validationErr.Errors
+		return problem
+	}
+
+	var validatorErrs validator.ValidationErrors
+	if errors.As(err, &validatorErrs) {
+		for _, fieldErr := range validatorErrs {
+			problem.Errors = append(problem.Errors, FieldError{
+				Pointer: jsonPointer(fieldErr.Namespace()),
+				Code:    fieldErr.Tag(),
+				Detail:  validationDetail(fieldErr),
+			})
+		}
+		return problem
+	}
+
+	problem.Detail = err.Error()
+	return problem
+}
+
+var indexPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// jsonPointer converts a validator namespace built from JSON field names, such
+// as BulkTransferRequest.credit_transfers[2].amount, into /credit_transfers/2/amount.
+func jsonPointer(namespace string) string {
+	_, path, found := strings.Cut(namespace, ".")
+	if !found {
+		return ""
+	}
+
+	path = indexPattern.ReplaceAllString(path, ".$1")
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		segments[i] = strings.NewReplacer("~", "~0", "/", "~1").Replace(segment)
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
+
+func validationDetail(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "required_with":
+		return "is required when its paired field is set"
+	case "eq":
+		return fmt.Sprintf("must be %s", fieldErr.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fieldErr.Param(), " ", ", "))
+	case "min":
+		return fmt.Sprintf("must contain at least %s item(s)", fieldErr.Param())
+	case "gt":
+		return "must not be empty"
+	default:
+		return fmt.Sprintf("failed the %q rule", fieldErr.Tag())
+	}
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"payment/internal/core"
+)
+
+func TestHandler_PostTransfers_ProblemDetails(t *testing.T) {
+	t.Parallel()
+
+	validTransfer := `{"amount": "10", "currency": "EUR", "counterparty_name": "A", "counterparty_bic": "BIC", "counterparty_iban": "IBAN", "description": "D"}`
+
+	tests := []struct {
+		name            string
+		body            string
+		expectedProblem Problem
+	}{
+		{
+			name: "malformed_json",
+			body: `{"organization_bic": `,
+			expectedProblem: Problem{
+				Type:     "urn:payment:problem:invalid_body",
+				Title:    "Invalid request body",
+				Status:   http.StatusBadRequest,
+				Detail:   "unexpected EOF",
+				Instance: "/transfers/bulk",
+				Code:     CodeInvalidBody,
+			},
+		},
+		{
+			name: "validation_errors_point_at_fields",
+			body: `{"organization_bic": "BIC", "credit_transfers": [` + validTransfer + `, ` + validTransfer + `,
+				{"amount": "10", "currency": "USD", "counterparty_bic": "BIC", "counterparty_iban": "IBAN", "description": "D"}]}`,
+			expectedProblem: Problem{
+				Type:     "urn:payment:problem:validation_failed",
+				Title:    "Validation failed",
+				Status:   http.StatusBadRequest,
+				Instance: "/transfers/bulk",
+				Code:     CodeValidationFailed,
+				Errors: []FieldError{
+					{Pointer: "/organization_iban", Code: "required", Detail: "is required"},
+					{Pointer: "/credit_transfers/2/currency", Code: "eq", Detail: "must be EUR"},
+					{Pointer: "/credit_transfers/2/counterparty_name", Code: "required", Detail: "is required"},
+				},
+			},
+		},
+		{
+			name: "empty_transfer_list",
+			body: `{"organization_bic": "BIC", "organization_iban": "IBAN", "credit_transfers": []}`,
+			expectedProblem: Problem{
+				Type:     "urn:payment:problem:validation_failed",
+				Title:    "Validation failed",
+				Status:   http.StatusBadRequest,
+				Instance: "/transfers/bulk",
+				Code:     CodeValidationFailed,
+				Errors: []FieldError{
+					{Pointer: "/credit_transfers", Code: "min", Detail: "must contain at least 1 item(s)"},
+				},
+			},
+		},
+		{
+			name: "unparsable_amounts",
+			body: `{"organization_bic": "BIC", "organization_iban": "IBAN", "credit_transfers": [` + validTransfer + `,
+				` + strings.Replace(validTransfer, `"10"`, `"ten"`, 1) + `]}`,
+			expectedProblem: Problem{
+				Type:     "urn:payment:problem:validation_failed",
+				Title:    "Validation failed",
+				Status:   http.StatusBadRequest,
+				Instance: "/transfers/bulk",
+				Code:     CodeValidationFailed,
+				Errors: []FieldError{
+					{
+						Pointer: "/credit_transfers/1/amount",
+						Code:    "invalid_amount",
+						Detail:  `invalid amount "ten": invalid amount format: strconv.ParseFloat: parsing "ten": invalid syntax`,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockLimiter := allowingLimiter(ctrl)
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			handler := NewHandler(NewMockBulkTransferProcessor(ctrl), mockLimiter, logger, 0)
+
+			req := httptest.NewRequest(http.MethodPost, "/transfers/bulk", strings.NewReader(tt.body))
+			req = req.WithContext(core.WithRequestID(req.Context(), "req-1"))
+			w := httptest.NewRecorder()
+
+			handler.PostTransfers(w, req)
+
+			require.Equal(t, tt.expectedProblem.Status, w.Code)
+			require.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+
+			var problem Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+
+			tt.expectedProblem.RequestID = "req-1"
+			require.Equal(t, tt.expectedProblem, problem)
+		})
+	}
+}
+
+func TestJSONPointer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		namespace string
+		expected  string
+	}{
+		{namespace: "BulkTransferRequest.organization_iban", expected: "/organization_iban"},
+		{namespace: "BulkTransferRequest.credit_transfers[2].amount", expected: "/credit_transfers/2/amount"},
+		{namespace: "BulkTransferRequest.a/b~c", expected: "/a~1b~0c"},
+		{namespace: "BulkTransferRequest", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, jsonPointer(tt.namespace))
+		})
+	}
+}
@@ -3,9 +3,26 @@ package http
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"payment/internal/core"
+	"payment/internal/metrics"
 )
 
+var tracer = otel.Tracer("payment/internal/http")
+
 type Logger interface {
 	InfoContext(ctx context.Context, msg string, args ...any)
 	ErrorContext(ctx context.Context, msg string, args ...any)
@@ -13,35 +30,179 @@ type Logger interface {
 
 func loggingMiddleware(logger Logger, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
 		logger.InfoContext(
 			r.Context(),
 			"request",
 			"method", r.Method,
 			"path", r.URL.Path,
+			"status", recorder.status,
+			"duration", time.Since(start),
 		)
+	})
+}
+
+// requestIDMiddleware keeps the caller's X-Request-ID when it is usable and
+// generates one otherwise. The ID is echoed in the response.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		w.Header().Set(requestIDHeader, requestID)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("request.id", requestID))
+
+		next.ServeHTTP(w, r.WithContext(core.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+const maxRequestIDLength = 128
+
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
 
+	for _, c := range requestID {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// routeMiddleware resolves the mux pattern up front so that the middlewares
+// wrapping the mux can label requests by route.
+func routeMiddleware(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, r.Pattern = mux.Handler(r)
 		next.ServeHTTP(w, r)
 	})
 }
 
+func routeLabel(r *http.Request) string {
+	if r.Pattern == "" {
+		return "unmatched"
+	}
+	return r.Pattern
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// metricsMiddleware records request counts and latencies. Routes are labelled
+// by their mux pattern to keep the label cardinality bounded.
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		status := strconv.Itoa(recorder.status)
+
+		metrics.HTTPRequests.WithLabelValues(routeLabel(r), r.Method, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(routeLabel(r), r.Method, status).Observe(time.Since(start).Seconds())
+	})
+}
+
+// tracingMiddleware continues the trace of an inbound W3C traceparent header,
+// or starts a new one.
+func tracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, routeLabel(r), trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+			attribute.String("http.request.method", r.Method),
+			attribute.String("http.route", routeLabel(r)),
+			attribute.String("url.path", r.URL.Path),
+		))
+		defer span.End()
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		r = r.WithContext(ctx)
+
+		next.ServeHTTP(recorder, r)
+
+		span.SetAttributes(attribute.Int("http.response.status_code", recorder.status))
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.status))
+		}
+	})
+}
+
+// routes maps every mux pattern to its handler. Each route must be documented
+// in openapi.json.
+func routes(bulkTransferHandler Handler, healthHandler HealthHandler) map[string]http.Handler {
+	return map[string]http.Handler{
+		"POST /transfers/bulk":          http.HandlerFunc(bulkTransferHandler.PostTransfers),
+		"POST /transfers/bulk:validate": http.HandlerFunc(bulkTransferHandler.ValidateTransfers),
+		"POST /transfers/bulk:stream":   http.HandlerFunc(bulkTransferHandler.StreamTransfers),
+		"GET /accounts/{iban}/{bic}":    http.HandlerFunc(bulkTransferHandler.GetAccount),
+		"GET /healthz":                  http.HandlerFunc(healthHandler.Liveness),
+		"GET /readyz":                   http.HandlerFunc(healthHandler.Readiness),
+		"GET /metrics":                  metrics.Handler(),
+		"GET /openapi.json":             http.HandlerFunc(serveOpenAPISpec),
+	}
+}
+
 type Server struct {
 	httpServer          *http.Server
 	bulkTransferHandler Handler
 	logger              Logger
+	draining            *atomic.Bool
+	drainDelay          time.Duration
 }
 
+// NewServer creates a Server. maxBatchSize is the admission limit on credit
+// transfers per bulk transfer: larger batches are rejected before their body
+// is parsed, and 0 disables the check.
 func NewServer(
 	bulkTransferProcessor BulkTransferProcessor,
+	rateLimiter RateLimiter,
+	healthChecker HealthChecker,
 	logger Logger,
+	maxBatchSize int,
 	config Config,
 ) *Server {
-	bulkTransferHandler := NewHandler(bulkTransferProcessor, logger)
+	bulkTransferHandler := NewHandler(bulkTransferProcessor, rateLimiter, logger, maxBatchSize)
 
-	mux := http.NewServeMux()
+	draining := &atomic.Bool{}
+	healthHandler := NewHealthHandler(healthChecker, draining, logger)
+
+	spec, err := loadOpenAPISpec()
+	if err != nil {
+		// The spec is embedded, so this is a build defect caught by the tests.
+		panic(err)
+	}
+	validator, err := newRequestValidator(spec, bulkTransferHandler)
+	if err != nil {
+		panic(err)
+	}
 
-	mux.HandleFunc("POST /transfers/bulk", bulkTransferHandler.PostTransfers)
+	mux := http.NewServeMux()
+	for pattern, handler := range routes(bulkTransferHandler, healthHandler) {
+		mux.Handle(pattern, handler)
+	}
 
-	handler := loggingMiddleware(logger, mux)
+	handler := routeMiddleware(mux, tracingMiddleware(requestIDMiddleware(loggingMiddleware(logger, metricsMiddleware(bulkTransferHandler.rateLimitMiddleware(bulkTransferHandler.bodyLimitMiddleware(validator.middleware(mux))))))))
 
 	httpServer := &http.Server{
 		Addr:         config.Address,
@@ -54,22 +215,42 @@ func NewServer(
 		httpServer:          httpServer,
 		bulkTransferHandler: bulkTransferHandler,
 		logger:              logger,
+		draining:            draining,
+		drainDelay:          config.DrainDelay,
 	}
 }
 
 func (s *Server) Start(ctx context.Context) error {
 	s.logger.InfoContext(ctx, "Starting HTTP server", "address", s.httpServer.Addr)
 
-	go func() {
-		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			s.logger.ErrorContext(ctx, "HTTP server error", "error", err)
-		}
-	}()
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.httpServer.Addr, err)
+	}
+
+	go s.Serve(ctx, listener)
 
 	return nil
 }
 
+// Serve accepts connections on listener until the server is stopped.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) {
+	if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.logger.ErrorContext(ctx, "HTTP server error", "error", err)
+	}
+}
+
+// Stop reports the server as not ready, waits for the drain delay so that load
+// balancers stop routing to it, then shuts down gracefully.
 func (s *Server) Stop(ctx context.Context) error {
+	s.draining.Store(true)
+	s.logger.InfoContext(ctx, "Draining HTTP server", "delay", s.drainDelay)
+
+	select {
+	case <-time.After(s.drainDelay):
+	case <-ctx.Done():
+	}
+
 	s.logger.InfoContext(ctx, "Stopping HTTP server")
 	return s.httpServer.Shutdown(ctx)
 }
@@ -0,0 +1,353 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/mock/gomock"
+
+	"payment/internal/core"
+	"payment/internal/ratelimit"
+)
+
+func TestServer_Metrics(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProcessor := NewMockBulkTransferProcessor(ctrl)
+	mockProcessor.EXPECT().
+		ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+		Return(core.BulkTransferResult{}, core.ErrInsufficientFunds).
+		Times(1)
+
+	mockLimiter := allowingLimiter(ctrl)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server := NewServer(mockProcessor, mockLimiter, NewMockHealthChecker(ctrl), logger, 0, Config{})
+
+	body, err := json.Marshal(BulkTransferRequest{
+		OrganizationBIC:  "TESTBIC",
+		OrganizationIBAN: "TESTIBAN",
+		CreditTransfers: []CreditTransfer{
+			{
+				Amount:           "100.00",
+				Currency:         "EUR",
+				CounterpartyName: "Test",
+				CounterpartyBIC:  "BIC",
+				CounterpartyIBAN: "IBAN",
+				Description:      "Test",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/transfers/bulk", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	w = httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	metrics := w.Body.String()
+	require.Contains(t, metrics, `payment_http_requests_total{method="POST",route="POST /transfers/bulk",status="422"}`)
+	require.Contains(t, metrics, `payment_http_request_duration_seconds_count{method="POST",route="POST /transfers/bulk",status="422"}`)
+}
+
+func TestServer_Tracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProcessor := NewMockBulkTransferProcessor(ctrl)
+	mockProcessor.EXPECT().
+		ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+		Return(core.BulkTransferResult{}, nil).
+		Times(1)
+
+	mockLimiter := allowingLimiter(ctrl)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server := NewServer(mockProcessor, mockLimiter, NewMockHealthChecker(ctrl), logger, 0, Config{})
+
+	body, err := json.Marshal(BulkTransferRequest{
+		OrganizationBIC:  "TESTBIC",
+		OrganizationIBAN: "TESTIBAN",
+		CreditTransfers: []CreditTransfer{
+			{
+				Amount:           "100.00",
+				Currency:         "EUR",
+				CounterpartyName: "Test",
+				CounterpartyBIC:  "BIC",
+				CounterpartyIBAN: "IBAN",
+				Description:      "Test",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/transfers/bulk", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	server.httpServer.Handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	handlerSpan, serverSpan := spans[0], spans[1]
+	require.Equal(t, "PostTransfers", handlerSpan.Name)
+	require.Equal(t, "POST /transfers/bulk", serverSpan.Name)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", serverSpan.SpanContext.TraceID().String())
+	require.Equal(t, "00f067aa0ba902b7", serverSpan.Parent.SpanID().String())
+	require.Equal(t, serverSpan.SpanContext.SpanID(), handlerSpan.Parent.SpanID())
+}
+
+func TestServer_RequestID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		requestID         string
+		expectedRequestID string
+	}{
+		{
+			name:              "keeps_caller_request_id",
+			requestID:         "batch-2025-09-01.42",
+			expectedRequestID: "batch-2025-09-01.42",
+		},
+		{
+			name: "generates_missing_request_id",
+		},
+		{
+			name:      "replaces_request_id_with_control_characters",
+			requestID: "abc\ndef",
+		},
+		{
+			name:      "replaces_too_long_request_id",
+			requestID: strings.Repeat("a", maxRequestIDLength+1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var processedRequestID string
+			mockProcessor := NewMockBulkTransferProcessor(ctrl)
+			mockProcessor.EXPECT().
+				ProcessBulkTransfer(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, _ core.BulkTransfer) (core.BulkTransferResult, error) {
+					processedRequestID = core.RequestIDFromContext(ctx)
+					return core.BulkTransferResult{}, errors.New("database is locked")
+				}).
+				Times(1)
+
+			mockLimiter := allowingLimiter(ctrl)
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			server := NewServer(mockProcessor, mockLimiter, NewMockHealthChecker(ctrl), logger, 0, Config{})
+
+			body, err := json.Marshal(BulkTransferRequest{
+				OrganizationBIC:  "TESTBIC",
+				OrganizationIBAN: "TESTIBAN",
+				CreditTransfers: []CreditTransfer{
+					{
+						Amount:           "100.00",
+						Currency:         "EUR",
+						CounterpartyName: "Test",
+						CounterpartyBIC:  "BIC",
+						CounterpartyIBAN: "IBAN",
+						Description:      "Test",
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/transfers/bulk", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.requestID != "" {
+				req.Header.Set(requestIDHeader, tt.requestID)
+			}
+			w := httptest.NewRecorder()
+			server.httpServer.Handler.ServeHTTP(w, req)
+			require.Equal(t, http.StatusInternalServerError, w.Code)
+
+			responseRequestID := w.Header().Get(requestIDHeader)
+			require.Equal(t, responseRequestID, processedRequestID)
+			if tt.expectedRequestID != "" {
+				require.Equal(t, tt.expectedRequestID, responseRequestID)
+			} else {
+				require.NoError(t, uuid.Validate(responseRequestID))
+			}
+		})
+	}
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		path           string
+		apiKey         string
+		setupLimiter   func(mock *MockRateLimiter)
+		setupMock      func(mock *MockBulkTransferProcessor)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "limited_before_the_body_is_read",
+			path: "/transfers/bulk",
+			setupLimiter: func(mock *MockRateLimiter) {
+				mock.EXPECT().
+					Allow(gomock.Any(), "", "192.0.2.1").
+					Return(ratelimit.Decision{RetryAfter: 1500 * time.Millisecond, Reason: ratelimit.ReasonRateLimited, Key: "addr:192.0.2.1"}, nil)
+			},
+			setupMock:      func(*MockBulkTransferProcessor) {},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCode:   CodeRateLimited,
+		},
+		{
+			name:   "unknown_api_key_is_refused",
+			path:   "/transfers/bulk",
+			apiKey: "made-up",
+			setupLimiter: func(mock *MockRateLimiter) {
+				mock.EXPECT().
+					Allow(gomock.Any(), "made-up", "192.0.2.1").
+					Return(ratelimit.Decision{}, ratelimit.ErrUnknownAPIKey)
+			},
+			setupMock:      func(*MockBulkTransferProcessor) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   CodeUnauthorized,
+		},
+		{
+			name:   "limiter_error_lets_request_through",
+			path:   "/accounts/TESTIBAN/TESTBIC",
+			apiKey: "secret",
+			setupLimiter: func(mock *MockRateLimiter) {
+				mock.EXPECT().
+					Allow(gomock.Any(), "secret", "192.0.2.1").
+					Return(ratelimit.Decision{}, errors.New("store unavailable"))
+			},
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().GetAccount(gomock.Any(), "TESTIBAN", "TESTBIC").Return(core.Account{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "operational_routes_are_not_limited",
+			path:           "/healthz",
+			setupLimiter:   func(*MockRateLimiter) {},
+			setupMock:      func(*MockBulkTransferProcessor) {},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProcessor := NewMockBulkTransferProcessor(ctrl)
+			tt.setupMock(mockProcessor)
+			mockLimiter := NewMockRateLimiter(ctrl)
+			tt.setupLimiter(mockLimiter)
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			server := NewServer(mockProcessor, mockLimiter, NewMockHealthChecker(ctrl), logger, 0, Config{})
+
+			method, body := http.MethodGet, ""
+			if strings.HasPrefix(tt.path, "/transfers") {
+				// Not even valid JSON: the limiter answers first.
+				method, body = http.MethodPost, "{"
+			}
+
+			req := httptest.NewRequest(method, tt.path, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.apiKey != "" {
+				req.Header.Set(apiKeyHeader, tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			server.httpServer.Handler.ServeHTTP(w, req)
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+
+			if tt.expectedCode != "" {
+				var problem Problem
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+				require.Equal(t, tt.expectedCode, problem.Code)
+			}
+			if tt.expectedStatus == http.StatusTooManyRequests {
+				require.Equal(t, "2", w.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestServer_BodyLimit(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server := NewServer(NewMockBulkTransferProcessor(ctrl), allowingLimiter(ctrl), NewMockHealthChecker(ctrl), logger, 1, Config{})
+
+	body, err := json.Marshal(BulkTransferRequest{
+		OrganizationBIC:  "TESTBIC",
+		OrganizationIBAN: "TESTIBAN",
+		CreditTransfers: []CreditTransfer{
+			{
+				Amount:           "100.00",
+				Currency:         "EUR",
+				CounterpartyName: "Test",
+				CounterpartyBIC:  "BIC",
+				CounterpartyIBAN: "IBAN",
+				Description:      strings.Repeat("a", maxCreditTransferSize+maxEnvelopeSize),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	for _, path := range []string{"/transfers/bulk", "/transfers/bulk:validate"} {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.httpServer.Handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+
+		var problem Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		require.Equal(t, CodeBodyTooLarge, problem.Code)
+	}
+}
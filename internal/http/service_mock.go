@@ -12,6 +12,7 @@ package http
 import (
 	context "context"
 	core "payment/internal/core"
+	ratelimit "payment/internal/ratelimit"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -41,16 +42,144 @@ func (m *MockBulkTransferProcessor) EXPECT() *MockBulkTransferProcessorMockRecor
 	return m.recorder
 }
 
-// ProcessBulkTransfer mocks base method.
-func (m *MockBulkTransferProcessor) ProcessBulkTransfer(ctx context.Context, bulkTransfer core.BulkTransfer) error {
+// DiscardStagedBulkTransfer mocks base method.
+func (m *MockBulkTransferProcessor) DiscardStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ProcessBulkTransfer", ctx, bulkTransfer)
+	ret := m.ctrl.Call(m, "DiscardStagedBulkTransfer", ctx, stagedBulkTransferID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// DiscardStagedBulkTransfer indicates an expected call of DiscardStagedBulkTransfer.
+func (mr *MockBulkTransferProcessorMockRecorder) DiscardStagedBulkTransfer(ctx, stagedBulkTransferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardStagedBulkTransfer", reflect.TypeOf((*MockBulkTransferProcessor)(nil).DiscardStagedBulkTransfer), ctx, stagedBulkTransferID)
+}
+
+// ExecuteStagedBulkTransfer mocks base method.
+func (m *MockBulkTransferProcessor) ExecuteStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) (core.BulkTransferResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteStagedBulkTransfer", ctx, stagedBulkTransferID)
+	ret0, _ := ret[0].(core.BulkTransferResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteStagedBulkTransfer indicates an expected call of ExecuteStagedBulkTransfer.
+func (mr *MockBulkTransferProcessorMockRecorder) ExecuteStagedBulkTransfer(ctx, stagedBulkTransferID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteStagedBulkTransfer", reflect.TypeOf((*MockBulkTransferProcessor)(nil).ExecuteStagedBulkTransfer), ctx, stagedBulkTransferID)
+}
+
+// GetAccount mocks base method.
+func (m *MockBulkTransferProcessor) GetAccount(ctx context.Context, iban, bic string) (core.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", ctx, iban, bic)
+	ret0, _ := ret[0].(core.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockBulkTransferProcessorMockRecorder) GetAccount(ctx, iban, bic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockBulkTransferProcessor)(nil).GetAccount), ctx, iban, bic)
+}
+
+// ProcessBulkTransfer mocks base method.
+func (m *MockBulkTransferProcessor) ProcessBulkTransfer(ctx context.Context, bulkTransfer core.BulkTransfer) (core.BulkTransferResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessBulkTransfer", ctx, bulkTransfer)
+	ret0, _ := ret[0].(core.BulkTransferResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // ProcessBulkTransfer indicates an expected call of ProcessBulkTransfer.
 func (mr *MockBulkTransferProcessorMockRecorder) ProcessBulkTransfer(ctx, bulkTransfer any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessBulkTransfer", reflect.TypeOf((*MockBulkTransferProcessor)(nil).ProcessBulkTransfer), ctx, bulkTransfer)
 }
+
+// StageBulkTransfer mocks base method.
+func (m *MockBulkTransferProcessor) StageBulkTransfer(ctx context.Context, bulkTransfer core.BulkTransfer) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StageBulkTransfer", ctx, bulkTransfer)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StageBulkTransfer indicates an expected call of StageBulkTransfer.
+func (mr *MockBulkTransferProcessorMockRecorder) StageBulkTransfer(ctx, bulkTransfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StageBulkTransfer", reflect.TypeOf((*MockBulkTransferProcessor)(nil).StageBulkTransfer), ctx, bulkTransfer)
+}
+
+// StageTransfers mocks base method.
+func (m *MockBulkTransferProcessor) StageTransfers(ctx context.Context, stagedBulkTransferID int64, transfers []core.StagedTransfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StageTransfers", ctx, stagedBulkTransferID, transfers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StageTransfers indicates an expected call of StageTransfers.
+func (mr *MockBulkTransferProcessorMockRecorder) StageTransfers(ctx, stagedBulkTransferID, transfers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StageTransfers", reflect.TypeOf((*MockBulkTransferProcessor)(nil).StageTransfers), ctx, stagedBulkTransferID, transfers)
+}
+
+// ValidateBulkTransfer mocks base method.
+func (m *MockBulkTransferProcessor) ValidateBulkTransfer(ctx context.Context, bulkTransfer core.BulkTransfer) (core.BulkTransferQuote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateBulkTransfer", ctx, bulkTransfer)
+	ret0, _ := ret[0].(core.BulkTransferQuote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateBulkTransfer indicates an expected call of ValidateBulkTransfer.
+func (mr *MockBulkTransferProcessorMockRecorder) ValidateBulkTransfer(ctx, bulkTransfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateBulkTransfer", reflect.TypeOf((*MockBulkTransferProcessor)(nil).ValidateBulkTransfer), ctx, bulkTransfer)
+}
+
+// MockRateLimiter is a mock of RateLimiter interface.
+type MockRateLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimiterMockRecorder
+	isgomock struct{}
+}
+
+// MockRateLimiterMockRecorder is the mock recorder for MockRateLimiter.
+type MockRateLimiterMockRecorder struct {
+	mock *MockRateLimiter
+}
+
+// NewMockRateLimiter creates a new mock instance.
+func NewMockRateLimiter(ctrl *gomock.Controller) *MockRateLimiter {
+	mock := &MockRateLimiter{ctrl: ctrl}
+	mock.recorder = &MockRateLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimiter) EXPECT() *MockRateLimiterMockRecorder {
+	return m.recorder
+}
+
+// Allow mocks base method.
+func (m *MockRateLimiter) Allow(ctx context.Context, apiKey, address string) (ratelimit.Decision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allow", ctx, apiKey, address)
+	ret0, _ := ret[0].(ratelimit.Decision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Allow indicates an expected call of Allow.
+func (mr *MockRateLimiterMockRecorder) Allow(ctx, apiKey, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allow", reflect.TypeOf((*MockRateLimiter)(nil).Allow), ctx, apiKey, address)
+}
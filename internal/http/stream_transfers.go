@@ -0,0 +1,294 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"payment/internal/core"
+)
+
+const (
+	ndjsonContentType = "application/x-ndjson"
+	// streamChunkSize is the number of transfers staged per transaction and
+	// between two progress events.
+	streamChunkSize   = 1000
+	maxStreamLineSize = 64 << 10
+	// streamIdleTimeout replaces the server timeouts, which would cut off large
+	// uploads, and is renewed for every chunk.
+	streamIdleTimeout = 30 * time.Second
+)
+
+// StreamTransfers ingests a bulk transfer sent as NDJSON: a StreamHeader line
+// followed by one CreditTransfer per line. Transfers are validated and staged
+// as they arrive, so only one chunk and the errors of invalid lines are held
+// while the body is read, and the staged batch is executed once the stream
+// ends. The response is an NDJSON stream of StreamEvents that ends with a
+// completed, held or failed event.
+func (h Handler) StreamTransfers(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "StreamTransfers")
+	defer span.End()
+	r = r.WithContext(ctx)
+
+	controller := http.NewResponseController(w)
+	extendDeadlines(controller)
+
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 4096), maxStreamLineSize)
+
+	header, ok := h.decodeStreamHeader(w, r, scanner)
+	if !ok {
+		return
+	}
+
+	ctx = core.WithActor(ctx, callerID(r, header.OrganizationIBAN))
+
+	stagedBulkTransferID, err := h.bulkTransferProcessor.StageBulkTransfer(ctx, core.BulkTransfer{
+		OrganizationBIC:  header.OrganizationBIC,
+		OrganizationIBAN: header.OrganizationIBAN,
+		ExecutionMode:    core.ExecutionMode(header.ExecutionMode),
+		HoldID:           header.HoldID,
+	})
+	if err != nil {
+		h.writeProblem(w, r, h.processingProblem(ctx, err))
+		return
+	}
+
+	// Progress is reported while the body is still being read. HTTP/2 is
+	// always full duplex and test recorders do not need it.
+	_ = controller.EnableFullDuplex()
+
+	w.Header().Set("Content-Type", ndjsonContentType)
+	w.WriteHeader(http.StatusOK)
+
+	stream := &transferStream{
+		handler:              h,
+		request:              r,
+		w:                    w,
+		controller:           controller,
+		header:               header,
+		stagedBulkTransferID: stagedBulkTransferID,
+		chunk:                make([]core.StagedTransfer, 0, streamChunkSize),
+		lineErrors:           make(map[int][]FieldError),
+	}
+
+	if problem := stream.ingest(ctx, scanner); problem != nil {
+		if err = h.bulkTransferProcessor.DiscardStagedBulkTransfer(ctx, stagedBulkTransferID); err != nil {
+			h.logger.ErrorContext(ctx, "Failed to discard staged bulk transfer", "error", err)
+		}
+		stream.fail(*problem)
+		return
+	}
+
+	extendDeadlines(controller)
+	result, err := h.bulkTransferProcessor.ExecuteStagedBulkTransfer(ctx, stagedBulkTransferID)
+	if heldErr, ok := h.held(ctx, err); ok {
+		held := NewHeldBulkTransferResponse(heldErr)
+		stream.send(StreamEvent{Event: StreamEventHeld, Held: &held})
+		return
+	}
+	if err != nil {
+		stream.fail(h.processingProblem(ctx, err))
+		return
+	}
+
+	// The staged transfers keep their positions, which the result reports.
+	response := NewBulkTransferResponse(result, nil, stream.lineErrors)
+	stream.send(StreamEvent{Event: StreamEventCompleted, Result: &response})
+}
+
+func (h Handler) decodeStreamHeader(w http.ResponseWriter, r *http.Request, scanner *bufio.Scanner) (StreamHeader, bool) {
+	problem := newProblem(http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+
+	if !scanner.Scan() {
+		problem.Detail = "missing stream header"
+		if err := scanner.Err(); err != nil {
+			problem.Detail = err.Error()
+		}
+		h.writeProblem(w, r, problem)
+		return StreamHeader{}, false
+	}
+
+	var header StreamHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		problem.Detail = err.Error()
+		h.writeProblem(w, r, problem)
+		return StreamHeader{}, false
+	}
+
+	if err := h.validator.Struct(&header); err != nil {
+		h.writeProblem(w, r, validationProblem(err))
+		return StreamHeader{}, false
+	}
+
+	if !h.withinBatchSize(w, r, "/transfer_count", header.TransferCount) {
+		return StreamHeader{}, false
+	}
+
+	return header, true
+}
+
+// parseCreditTransfer decodes and validates the credit transfer at index. Its
+// errors point into the equivalent BulkTransferRequest.
+func (h Handler) parseCreditTransfer(line []byte, index int) (core.Transfer, []FieldError) {
+	pointer := fmt.Sprintf("/credit_transfers/%d", index)
+
+	var ct CreditTransfer
+	if err := json.Unmarshal(line, &ct); err != nil {
+		return core.Transfer{}, []FieldError{{Pointer: pointer, Code: CodeInvalidBody, Detail: err.Error()}}
+	}
+
+	if err := h.validator.Struct(&ct); err != nil {
+		fieldErrors := validationProblem(err).Errors
+		for i := range fieldErrors {
+			fieldErrors[i].Pointer = pointer + fieldErrors[i].Pointer
+		}
+		return core.Transfer{}, fieldErrors
+	}
+
+	transfer, err := ct.toDomain()
+	if err != nil {
+		return core.Transfer{}, []FieldError{amountError(index, ct.Amount, err)}
+	}
+
+	return transfer, nil
+}
+
+type transferStream struct {
+	handler              Handler
+	request              *http.Request
+	w                    http.ResponseWriter
+	controller           *http.ResponseController
+	header               StreamHeader
+	stagedBulkTransferID int64
+
+	chunk      []core.StagedTransfer
+	lineErrors map[int][]FieldError
+	received   int
+	staged     int
+}
+
+// ingest stages the credit transfers read from scanner and returns the
+// problem that ends the stream early, if any.
+func (s *transferStream) ingest(ctx context.Context, scanner *bufio.Scanner) *Problem {
+	partial := s.header.ExecutionMode == string(core.ExecutionModePartial)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		index := s.received
+		s.received++
+		if s.received > s.header.TransferCount {
+			return s.transferCountProblem()
+		}
+
+		transfer, fieldErrors := s.handler.parseCreditTransfer(line, index)
+		if len(fieldErrors) > 0 {
+			if !partial {
+				problem := validationProblem(&ValidationError{Errors: fieldErrors})
+				return &problem
+			}
+			s.lineErrors[index] = fieldErrors
+			continue
+		}
+
+		s.chunk = append(s.chunk, core.StagedTransfer{Position: index, Transfer: transfer})
+		s.staged++
+
+		if len(s.chunk) == streamChunkSize {
+			if problem := s.flush(ctx); problem != nil {
+				return problem
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		problem := newProblem(http.StatusBadRequest, CodeInvalidBody, "Invalid request body")
+		problem.Detail = fmt.Sprintf("after %d transfer(s): %s", s.received, err)
+		return &problem
+	}
+
+	if problem := s.flush(ctx); problem != nil {
+		return problem
+	}
+
+	if s.received != s.header.TransferCount {
+		return s.transferCountProblem()
+	}
+
+	if s.staged == 0 {
+		problem := validationProblem(&ValidationError{Errors: flattenLineErrors(s.lineErrors)})
+		return &problem
+	}
+
+	return nil
+}
+
+func (s *transferStream) flush(ctx context.Context) *Problem {
+	if len(s.chunk) == 0 {
+		return nil
+	}
+
+	err := s.handler.bulkTransferProcessor.StageTransfers(ctx, s.stagedBulkTransferID, s.chunk)
+	if err != nil {
+		problem := s.handler.processingProblem(ctx, err)
+		return &problem
+	}
+	s.chunk = s.chunk[:0]
+
+	s.send(StreamEvent{
+		Event:    StreamEventProgress,
+		Received: s.received,
+		Staged:   s.staged,
+		Total:    s.header.TransferCount,
+	})
+	extendDeadlines(s.controller)
+
+	return nil
+}
+
+func (s *transferStream) transferCountProblem() *Problem {
+	detail := fmt.Sprintf("declared %d transfer(s), received %d", s.header.TransferCount, s.received)
+	if s.received > s.header.TransferCount {
+		detail = fmt.Sprintf("declared %d transfer(s), received more", s.header.TransferCount)
+	}
+
+	problem := validationProblem(&ValidationError{Errors: []FieldError{{
+		Pointer: "/transfer_count",
+		Code:    "transfer_count",
+		Detail:  detail,
+	}}})
+
+	return &problem
+}
+
+func (s *transferStream) fail(problem Problem) {
+	problem = problem.forRequest(s.request)
+	s.send(StreamEvent{Event: StreamEventFailed, Problem: &problem})
+}
+
+func (s *transferStream) send(event StreamEvent) {
+	if err := json.NewEncoder(s.w).Encode(event); err != nil {
+		s.handler.logger.ErrorContext(s.request.Context(), "Failed to write stream event", "error", err)
+		return
+	}
+
+	if err := s.controller.Flush(); err != nil {
+		s.handler.logger.ErrorContext(s.request.Context(), "Failed to flush stream event", "error", err)
+	}
+}
+
+func extendDeadlines(controller *http.ResponseController) {
+	deadline := time.Now().Add(streamIdleTimeout)
+
+	// Writers without deadline support keep the server timeouts.
+	_ = controller.SetReadDeadline(deadline)
+	_ = controller.SetWriteDeadline(deadline)
+}
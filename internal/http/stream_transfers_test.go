@@ -0,0 +1,342 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"payment/internal/core"
+)
+
+func TestHandler_StreamTransfers(t *testing.T) {
+	t.Parallel()
+
+	const validLine = `{"amount":"1.00","currency":"EUR","counterparty_name":"A","counterparty_bic":"BIC","counterparty_iban":"IBAN","description":"D"}`
+	const invalidLine = `{"amount":"1.00","currency":"USD","counterparty_name":"A","counterparty_bic":"BIC","counterparty_iban":"IBAN","description":"D"}`
+
+	header := func(executionMode string, transferCount int) string {
+		return fmt.Sprintf(`{"organization_bic":"TESTBIC","organization_iban":"TESTIBAN","execution_mode":%q,"transfer_count":%d}`, executionMode, transferCount)
+	}
+	lines := func(count int) []string {
+		result := make([]string, count)
+		for i := range result {
+			result[i] = validLine
+		}
+		return result
+	}
+
+	tests := []struct {
+		name           string
+		body           []string
+		setupMock      func(mock *MockBulkTransferProcessor)
+		expectedStatus int
+		expectedCode   string
+		// expectedEvents lists the event names of a streamed response.
+		expectedEvents []string
+		check          func(t *testing.T, events []StreamEvent)
+	}{
+		{
+			name: "stages_in_chunks_and_executes",
+			body: append([]string{header("", 2500)}, lines(2500)...),
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().StageBulkTransfer(gomock.Any(), core.BulkTransfer{OrganizationBIC: "TESTBIC", OrganizationIBAN: "TESTIBAN"}).Return(int64(7), nil)
+				var staged []int
+				mock.EXPECT().
+					StageTransfers(gomock.Any(), int64(7), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int64, transfers []core.StagedTransfer) error {
+						staged = append(staged, len(transfers))
+						return nil
+					}).
+					Times(3)
+				mock.EXPECT().
+					ExecuteStagedBulkTransfer(gomock.Any(), int64(7)).
+					DoAndReturn(func(context.Context, int64) (core.BulkTransferResult, error) {
+						if fmt.Sprint(staged) != "[1000 1000 500]" {
+							return core.BulkTransferResult{}, fmt.Errorf("unexpected chunks %v", staged)
+						}
+						result := core.BulkTransferResult{BulkTransferID: 3, ExecutionMode: core.ExecutionModeAllOrNothing}
+						for i := range 2500 {
+							result.Transfers = append(result.Transfers, core.TransferResult{Index: i, Status: core.TransferAccepted})
+						}
+						return result, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+			expectedEvents: []string{StreamEventProgress, StreamEventProgress, StreamEventProgress, StreamEventCompleted},
+			check: func(t *testing.T, events []StreamEvent) {
+				require.Equal(t, StreamEvent{Event: StreamEventProgress, Received: 1000, Staged: 1000, Total: 2500}, events[0])
+				require.Equal(t, 2500, events[2].Staged)
+				require.Equal(t, int64(3), events[3].Result.BulkTransferID)
+				require.Equal(t, 2500, events[3].Result.AcceptedCount)
+			},
+		},
+		{
+			name:           "invalid_header_returns_400",
+			body:           []string{`{"organization_bic":"TESTBIC","transfer_count":1}`, validLine},
+			setupMock:      func(mock *MockBulkTransferProcessor) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeValidationFailed,
+		},
+		{
+			name:           "transfer_count_above_max_batch_size_returns_400",
+			body:           append([]string{header("", 5001)}, lines(5001)...),
+			setupMock:      func(mock *MockBulkTransferProcessor) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeValidationFailed,
+		},
+		{
+			name:           "missing_header_returns_400",
+			body:           []string{},
+			setupMock:      func(mock *MockBulkTransferProcessor) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeInvalidBody,
+		},
+		{
+			name: "invalid_line_fails_and_discards_in_all_or_nothing_mode",
+			body: []string{header("all_or_nothing", 2), validLine, invalidLine},
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().StageBulkTransfer(gomock.Any(), gomock.Any()).Return(int64(7), nil)
+				mock.EXPECT().DiscardStagedBulkTransfer(gomock.Any(), int64(7)).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedEvents: []string{StreamEventFailed},
+			check: func(t *testing.T, events []StreamEvent) {
+				require.Equal(t, CodeValidationFailed, events[0].Problem.Code)
+				require.Equal(t, "/credit_transfers/1/currency", events[0].Problem.Errors[0].Pointer)
+				require.Equal(t, "/transfers/bulk:stream", events[0].Problem.Instance)
+			},
+		},
+		{
+			name: "transfer_count_mismatch_fails",
+			body: []string{header("", 3), validLine, validLine},
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().StageBulkTransfer(gomock.Any(), gomock.Any()).Return(int64(7), nil)
+				mock.EXPECT().StageTransfers(gomock.Any(), int64(7), gomock.Len(2)).Return(nil)
+				mock.EXPECT().DiscardStagedBulkTransfer(gomock.Any(), int64(7)).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedEvents: []string{StreamEventProgress, StreamEventFailed},
+			check: func(t *testing.T, events []StreamEvent) {
+				require.Equal(t, "/transfer_count", events[1].Problem.Errors[0].Pointer)
+				require.Equal(t, "declared 3 transfer(s), received 2", events[1].Problem.Errors[0].Detail)
+			},
+		},
+		{
+			name: "partial_mode_rejects_invalid_lines",
+			body: []string{header("partial", 3), invalidLine, validLine, validLine},
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().StageBulkTransfer(gomock.Any(), gomock.Any()).Return(int64(7), nil)
+				mock.EXPECT().
+					StageTransfers(gomock.Any(), int64(7), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ int64, transfers []core.StagedTransfer) error {
+						positions := make([]int, len(transfers))
+						for i, transfer := range transfers {
+							positions[i] = transfer.Position
+						}
+						if fmt.Sprint(positions) != "[1 2]" {
+							return fmt.Errorf("unexpected positions %v", positions)
+						}
+						return nil
+					})
+				mock.EXPECT().
+					ExecuteStagedBulkTransfer(gomock.Any(), int64(7)).
+					Return(core.BulkTransferResult{
+						BulkTransferID: 3,
+						ExecutionMode:  core.ExecutionModePartial,
+						Transfers: []core.TransferResult{
+							{Index: 1, Status: core.TransferAccepted},
+							{Index: 2, Status: core.TransferRejected, Reason: core.RejectionInsufficientFunds},
+						},
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedEvents: []string{StreamEventProgress, StreamEventCompleted},
+			check: func(t *testing.T, events []StreamEvent) {
+				require.Equal(t, StreamEvent{Event: StreamEventProgress, Received: 3, Staged: 2, Total: 3}, events[0])
+
+				result := events[1].Result
+				require.Equal(t, 1, result.AcceptedCount)
+				require.Equal(t, 2, result.RejectedCount)
+				require.Equal(t, CodeValidationFailed, result.Transfers[0].Reason)
+				require.Equal(t, "accepted", result.Transfers[1].Status)
+				require.Equal(t, core.RejectionInsufficientFunds, result.Transfers[2].Reason)
+			},
+		},
+		{
+			name: "execution_error_fails",
+			body: []string{header("", 1), validLine},
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().StageBulkTransfer(gomock.Any(), gomock.Any()).Return(int64(7), nil)
+				mock.EXPECT().StageTransfers(gomock.Any(), int64(7), gomock.Len(1)).Return(nil)
+				mock.EXPECT().ExecuteStagedBulkTransfer(gomock.Any(), int64(7)).Return(core.BulkTransferResult{}, core.ErrInsufficientFunds)
+			},
+			expectedStatus: http.StatusOK,
+			expectedEvents: []string{StreamEventProgress, StreamEventFailed},
+			check: func(t *testing.T, events []StreamEvent) {
+				require.Equal(t, CodeInsufficientFunds, events[1].Problem.Code)
+				require.Equal(t, http.StatusUnprocessableEntity, events[1].Problem.Status)
+			},
+		},
+		{
+			name: "held_bulk_transfer_ends_with_a_held_event",
+			body: []string{header("", 1), validLine},
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().StageBulkTransfer(gomock.Any(), gomock.Any()).Return(int64(7), nil)
+				mock.EXPECT().StageTransfers(gomock.Any(), int64(7), gomock.Len(1)).Return(nil)
+				mock.EXPECT().ExecuteStagedBulkTransfer(gomock.Any(), int64(7)).Return(core.BulkTransferResult{}, &core.HeldError{
+					HeldBulkTransferID: 7,
+					Review:             core.ReviewKindSanctions,
+					Reason:             &core.SanctionsError{Decision: core.DecisionReview},
+				})
+			},
+			expectedStatus: http.StatusOK,
+			expectedEvents: []string{StreamEventProgress, StreamEventHeld},
+			check: func(t *testing.T, events []StreamEvent) {
+				require.Equal(t, &HeldBulkTransferResponse{HeldBulkTransferID: 7, Review: "sanctions"}, events[1].Held)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProcessor := NewMockBulkTransferProcessor(ctrl)
+			tt.setupMock(mockProcessor)
+
+			mockLimiter := allowingLimiter(ctrl)
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			handler := NewHandler(mockProcessor, mockLimiter, logger, 5000)
+
+			req := httptest.NewRequest(http.MethodPost, "/transfers/bulk:stream", strings.NewReader(strings.Join(tt.body, "\n")))
+			req.Header.Set("Content-Type", ndjsonContentType)
+			w := httptest.NewRecorder()
+
+			handler.StreamTransfers(w, req)
+			require.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedCode != "" {
+				var problem Problem
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+				require.Equal(t, tt.expectedCode, problem.Code)
+				return
+			}
+
+			require.Equal(t, ndjsonContentType, w.Header().Get("Content-Type"))
+
+			var events []StreamEvent
+			var names []string
+			scanner := bufio.NewScanner(w.Body)
+			scanner.Buffer(nil, 1<<20)
+			for scanner.Scan() {
+				var event StreamEvent
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+				events = append(events, event)
+				names = append(names, event.Event)
+			}
+			require.NoError(t, scanner.Err())
+			require.Equal(t, tt.expectedEvents, names)
+
+			if tt.check != nil {
+				tt.check(t, events)
+			}
+		})
+	}
+}
+
+// streamBody generates an NDJSON stream of count transfers as it is read, so
+// that the request body itself takes no memory.
+type streamBody struct {
+	pending []byte
+	line    []byte
+	left    int
+}
+
+func (b *streamBody) Read(p []byte) (int, error) {
+	for len(b.pending) == 0 {
+		if b.left == 0 {
+			return 0, io.EOF
+		}
+		b.pending = b.line
+		b.left--
+	}
+
+	n := copy(p, b.pending)
+	b.pending = b.pending[n:]
+
+	return n, nil
+}
+
+// Not parallel: it measures the heap of the whole process.
+func TestHandler_StreamTransfers_BoundedMemory(t *testing.T) {
+	const transferCount = 200000
+	const maxHeapGrowth = 8 << 20
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	heapAlloc := func() uint64 {
+		runtime.GC()
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		return stats.HeapAlloc
+	}
+
+	var peak uint64
+	measure := func() {
+		if heap := heapAlloc(); heap > peak {
+			peak = heap
+		}
+	}
+
+	mockProcessor := NewMockBulkTransferProcessor(ctrl)
+	mockProcessor.EXPECT().StageBulkTransfer(gomock.Any(), gomock.Any()).Return(int64(7), nil)
+	mockProcessor.EXPECT().
+		StageTransfers(gomock.Any(), int64(7), gomock.Len(streamChunkSize)).
+		DoAndReturn(func(context.Context, int64, []core.StagedTransfer) error {
+			measure()
+			return nil
+		}).
+		Times(transferCount / streamChunkSize)
+	mockProcessor.EXPECT().
+		ExecuteStagedBulkTransfer(gomock.Any(), int64(7)).
+		DoAndReturn(func(context.Context, int64) (core.BulkTransferResult, error) {
+			measure()
+			return core.BulkTransferResult{BulkTransferID: 3, ExecutionMode: core.ExecutionModeAllOrNothing}, nil
+		})
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewHandler(mockProcessor, allowingLimiter(ctrl), logger, transferCount)
+
+	header := fmt.Sprintf(`{"organization_bic":"TESTBIC","organization_iban":"TESTIBAN","transfer_count":%d}`+"\n", transferCount)
+	body := &streamBody{
+		pending: []byte(header),
+		line:    []byte(`{"amount":"1.00","currency":"EUR","counterparty_name":"A","counterparty_bic":"BIC","counterparty_iban":"IBAN","description":"D"}` + "\n"),
+		left:    transferCount,
+	}
+	req := httptest.NewRequest(http.MethodPost, "/transfers/bulk:stream", body)
+	req.Header.Set("Content-Type", ndjsonContentType)
+	w := httptest.NewRecorder()
+
+	baseline := heapAlloc()
+	handler.StreamTransfers(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"event":"completed"`)
+	require.Less(t, peak, baseline+maxHeapGrowth, "heap grew with the stream")
+}
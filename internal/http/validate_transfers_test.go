@@ -0,0 +1,137 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"payment/internal/core"
+	"payment/internal/ratelimit"
+)
+
+func TestHandler_ValidateTransfers(t *testing.T) {
+	t.Parallel()
+
+	requestBody := BulkTransferRequest{
+		OrganizationBIC:  "TESTBIC",
+		OrganizationIBAN: "TESTIBAN",
+		CreditTransfers: []CreditTransfer{
+			{Amount: "10.00", Currency: "EUR", CounterpartyName: "A", CounterpartyBIC: "BIC", CounterpartyIBAN: "IBAN1", Description: "D"},
+			{Amount: "20.50", Currency: "EUR", CounterpartyName: "B", CounterpartyBIC: "BIC", CounterpartyIBAN: "IBAN2", Description: "D"},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		setupMock        func(mock *MockBulkTransferProcessor)
+		expectedStatus   int
+		expectedResponse string
+	}{
+		{
+			name: "returns_quote_with_violations",
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().
+					ValidateBulkTransfer(gomock.Any(), gomock.Any()).
+					Return(core.BulkTransferQuote{
+						TransferCount:      2,
+						TotalAmountCents:   3050,
+						BalanceBeforeCents: 1000,
+						BalanceAfterCents:  -2050,
+						Violations: []core.Violation{
+							{TransferIndex: core.BatchViolation, Code: core.ViolationSanctionsReview, Message: "bulk transfer would be held for sanctions review"},
+							{TransferIndex: core.BatchViolation, Code: core.ViolationInsufficientFunds, Message: "balance does not cover the bulk transfer"},
+						},
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: `{
+				"valid": false,
+				"transfer_count": 2,
+				"total_amount": "30.50",
+				"fees": "0.00",
+				"balance_before": "10.00",
+				"balance_after": "-20.50",
+				"currency": "EUR",
+				"violations": [
+					{"code": "sanctions_review", "message": "bulk transfer would be held for sanctions review"},
+					{"code": "insufficient_funds", "message": "balance does not cover the bulk transfer"}
+				]
+			}`,
+		},
+		{
+			name: "returns_valid_quote",
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().
+					ValidateBulkTransfer(gomock.Any(), gomock.Any()).
+					Return(core.BulkTransferQuote{
+						TransferCount:      2,
+						TotalAmountCents:   3050,
+						BalanceBeforeCents: 10000,
+						BalanceAfterCents:  6950,
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedResponse: `{
+				"valid": true,
+				"transfer_count": 2,
+				"total_amount": "30.50",
+				"fees": "0.00",
+				"balance_before": "100.00",
+				"balance_after": "69.50",
+				"currency": "EUR",
+				"violations": []
+			}`,
+		},
+		{
+			name: "account_not_found_returns_404",
+			setupMock: func(mock *MockBulkTransferProcessor) {
+				mock.EXPECT().
+					ValidateBulkTransfer(gomock.Any(), gomock.Any()).
+					Return(core.BulkTransferQuote{}, core.ErrAccountNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProcessor := NewMockBulkTransferProcessor(ctrl)
+			tt.setupMock(mockProcessor)
+
+			// Dry runs do not count towards the daily transfer quota.
+			mockLimiter := NewMockRateLimiter(ctrl)
+			mockLimiter.EXPECT().
+				Allow(gomock.Any(), "", "192.0.2.1").
+				Return(ratelimit.Decision{Allowed: true, Key: "addr:192.0.2.1"}, nil)
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			server := NewServer(mockProcessor, mockLimiter, NewMockHealthChecker(ctrl), logger, 0, Config{})
+
+			body, err := json.Marshal(requestBody)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/transfers/bulk:validate", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			server.httpServer.Handler.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedResponse != "" {
+				require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+				require.JSONEq(t, tt.expectedResponse, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"payment/internal/core"
+)
+
+// AccountStore keeps accounts and their ledger in process memory. It has the
+// semantics of the SQLite store, tables included, but loses everything when
+// the process exits.
+type AccountStore struct {
+	db *database
+	tx *transaction
+}
+
+// database holds the committed state. Transactions run one at a time, like
+// writers behind BEGIN IMMEDIATE in the SQLite store.
+type database struct {
+	lock      chan struct{}
+	committed *state
+}
+
+func NewAccountStore() AccountStore {
+	return AccountStore{
+		db: &database{
+			lock:      make(chan struct{}, 1),
+			committed: &state{},
+		},
+	}
+}
+
+// state is one version of every table. A committed state is never modified:
+// a transaction copies it on its first write and replaces it on commit.
+type state struct {
+	accounts              []accountRow
+	bulkTransfers         []core.BulkTransferRecord
+	transactions          []core.Transaction
+	auditLog              []core.AuditRecord
+	stagedBulkTransfers   []stagedBulkTransfer
+	stagedTransfers       []stagedTransfer
+	holds                 []core.Hold
+	reconciliationReports []core.ReconciliationReport
+
+	// lastStagedBulkTransferID keeps the IDs of deleted staged bulk transfers
+	// from being reused, like AUTOINCREMENT.
+	lastStagedBulkTransferID int64
+}
+
+type accountRow struct {
+	core.Account
+	openingBalanceCents int64
+}
+
+// clone copies the tables that are modified in place. Append-only tables are
+// clipped instead: the first append then moves them to a new array.
+func (s *state) clone() *state {
+	return &state{
+		accounts:                 slices.Clone(s.accounts),
+		bulkTransfers:            slices.Clip(s.bulkTransfers),
+		transactions:             slices.Clip(s.transactions),
+		auditLog:                 slices.Clip(s.auditLog),
+		stagedBulkTransfers:      slices.Clone(s.stagedBulkTransfers),
+		stagedTransfers:          slices.Clone(s.stagedTransfers),
+		holds:                    slices.Clone(s.holds),
+		reconciliationReports:    slices.Clip(s.reconciliationReports),
+		lastStagedBulkTransferID: s.lastStagedBulkTransferID,
+	}
+}
+
+// transaction is the state seen by an Atomic callback.
+type transaction struct {
+	current *state
+	// copied is set once current is a copy that belongs to the transaction.
+	copied bool
+}
+
+func (t *transaction) read() *state {
+	return t.current
+}
+
+func (t *transaction) write() *state {
+	if !t.copied {
+		t.current = t.current.clone()
+		t.copied = true
+	}
+
+	return t.current
+}
+
+func (s AccountStore) GetAccountByID(ctx context.Context, iban string, bic string) (core.Account, error) {
+	if s.tx == nil {
+		return core.Account{}, errors.New("GetAccountByID must be called within Atomic transaction")
+	}
+
+	state := s.tx.read()
+	for _, account := range state.accounts {
+		if account.IBAN == iban && account.BIC == bic {
+			return state.withHeldCents(account.Account, time.Now()), nil
+		}
+	}
+
+	return core.Account{}, core.ErrAccountNotFound
+}
+
+func (s AccountStore) CreateAccount(ctx context.Context, account core.Account) (int64, error) {
+	if s.tx == nil {
+		return 0, errors.New("CreateAccount must be called within Atomic transaction")
+	}
+
+	if account.OverdraftLimitCents < 0 {
+		return 0, errors.New("failed to insert account: overdraft limit cannot be negative")
+	}
+
+	for _, existing := range s.tx.read().accounts {
+		if existing.IBAN == account.IBAN && existing.BIC == account.BIC {
+			return 0, core.ErrAccountExists
+		}
+	}
+
+	state := s.tx.write()
+	account.ID = int64(len(state.accounts) + 1)
+	account.HeldCents = 0
+	account.Version = 0
+	if account.Currency == "" {
+		account.Currency = core.DefaultCurrency
+	}
+	state.accounts = append(state.accounts, accountRow{Account: account, openingBalanceCents: account.BalanceCents})
+
+	return account.ID, nil
+}
+
+func (s AccountStore) ListAccounts(ctx context.Context) ([]core.Account, error) {
+	if s.tx == nil {
+		return nil, errors.New("ListAccounts must be called within Atomic transaction")
+	}
+
+	state := s.tx.read()
+	now := time.Now()
+
+	var accounts []core.Account
+	for _, account := range state.accounts {
+		accounts = append(accounts, state.withHeldCents(account.Account, now))
+	}
+
+	return accounts, nil
+}
+
+// account returns the stored account with the given ID, or nil.
+func (s *state) account(accountID int64) *accountRow {
+	for i := range s.accounts {
+		if s.accounts[i].ID == accountID {
+			return &s.accounts[i]
+		}
+	}
+
+	return nil
+}
+
+// updateAccount applies update to the stored account with the given ID.
+func (s AccountStore) updateAccount(accountID int64, update func(*accountRow)) error {
+	if s.tx.read().account(accountID) == nil {
+		return fmt.Errorf("no rows updated for account ID %d", accountID)
+	}
+
+	update(s.tx.write().account(accountID))
+	return nil
+}
+
+func (s AccountStore) SetAccountFrozen(ctx context.Context, accountID int64, frozen bool) error {
+	if s.tx == nil {
+		return errors.New("SetAccountFrozen must be called within Atomic transaction")
+	}
+
+	return s.updateAccount(accountID, func(a *accountRow) {
+		a.Frozen = frozen
+	})
+}
+
+func (s AccountStore) SetOverdraftLimit(ctx context.Context, accountID int64, limitCents int64) error {
+	if s.tx == nil {
+		return errors.New("SetOverdraftLimit must be called within Atomic transaction")
+	}
+
+	if limitCents < 0 {
+		return errors.New("failed to execute update: overdraft limit cannot be negative")
+	}
+
+	return s.updateAccount(accountID, func(a *accountRow) {
+		a.OverdraftLimitCents = limitCents
+	})
+}
+
+func (s AccountStore) SetPricingPlan(ctx context.Context, accountID int64, plan string) error {
+	if s.tx == nil {
+		return errors.New("SetPricingPlan must be called within Atomic transaction")
+	}
+
+	return s.updateAccount(accountID, func(a *accountRow) {
+		a.PricingPlan = plan
+	})
+}
+
+func (s AccountStore) UpdateBalance(ctx context.Context, account core.Account) error {
+	if s.tx == nil {
+		return errors.New("UpdateBalance must be called within Atomic transaction")
+	}
+
+	stored := s.tx.read().account(account.ID)
+	if stored == nil {
+		return core.ErrAccountNotFound
+	}
+	if stored.Version != account.Version {
+		return &core.VersionConflictError{AccountID: account.ID, Version: account.Version}
+	}
+
+	stored = s.tx.write().account(account.ID)
+	stored.BalanceCents = account.BalanceCents
+	stored.Version++
+
+	return nil
+}
+
+func (s AccountStore) AddTransfers(ctx context.Context, transfers []core.Transfer) error {
+	if s.tx == nil {
+		return errors.New("AddTransfers must be called within Atomic transaction")
+	}
+
+	for _, transfer := range transfers {
+		if transfer.BankAccountID == 0 {
+			return fmt.Errorf("transfer missing bank_account_id")
+		}
+	}
+
+	state := s.tx.write()
+	for _, transfer := range transfers {
+		state.addTransaction(core.Transaction{
+			AccountID:        transfer.BankAccountID,
+			BulkTransferID:   transfer.BulkTransferID,
+			Kind:             core.TransactionKindTransfer,
+			CounterpartyName: transfer.CounterpartyName,
+			CounterpartyIBAN: transfer.CounterpartyIBAN,
+			CounterpartyBIC:  transfer.CounterpartyBIC,
+			AmountCents:      -transfer.AmountCents,
+			Currency:         transfer.Currency,
+			Description:      transfer.Description,
+		})
+	}
+
+	return nil
+}
+
+// AddTransaction books a single transaction. Unlike AddTransfers, its amount
+// is stored as is.
+func (s AccountStore) AddTransaction(ctx context.Context, transaction core.Transaction) error {
+	if s.tx == nil {
+		return errors.New("AddTransaction must be called within Atomic transaction")
+	}
+
+	s.tx.write().addTransaction(transaction)
+	return nil
+}
+
+func (s *state) addTransaction(transaction core.Transaction) {
+	transaction.ID = int64(len(s.transactions) + 1)
+	s.transactions = append(s.transactions, transaction)
+}
+
+// Atomic runs cb in a transaction that works on its own copy of the tables:
+// the copy replaces the committed tables when cb succeeds and is dropped when
+// it fails. Transactions wait for each other, or for ctx to be done.
+//
+// Called on the repository passed to a callback, Atomic runs cb on a copy of
+// the enclosing transaction's tables instead, so that an error of cb only
+// undoes what cb did.
+func (s AccountStore) Atomic(ctx context.Context, cb func(core.AccountRepository) error) error {
+	if s.tx != nil {
+		nested := &transaction{current: s.tx.current}
+		if err := cb(AccountStore{db: s.db, tx: nested}); err != nil {
+			return err
+		}
+
+		if nested.copied {
+			s.tx.current = nested.current
+			s.tx.copied = true
+		}
+		return nil
+	}
+
+	select {
+	case s.db.lock <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("failed to begin transaction: %w", ctx.Err())
+	}
+	defer func() { <-s.db.lock }()
+
+	tx := &transaction{current: s.db.committed}
+	if err := cb(AccountStore{db: s.db, tx: tx}); err != nil {
+		return err
+	}
+
+	s.db.committed = tx.current
+	return nil
+}
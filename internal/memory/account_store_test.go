@@ -0,0 +1,17 @@
+package memory_test
+
+import (
+	"testing"
+
+	"payment/internal/core"
+	"payment/internal/memory"
+	"payment/test/conformance"
+)
+
+func TestAccountStore_Conformance(t *testing.T) {
+	t.Parallel()
+
+	conformance.RunAccountRepository(t, func(*testing.T) core.AccountRepository {
+		return memory.NewAccountStore()
+	})
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"payment/internal/core"
+)
+
+func (s AccountStore) AppendAuditRecord(ctx context.Context, record core.AuditRecord) error {
+	if s.tx == nil {
+		return errors.New("AppendAuditRecord must be called within Atomic transaction")
+	}
+
+	state := s.tx.write()
+
+	var prevHash string
+	if len(state.auditLog) > 0 {
+		prevHash = state.auditLog[len(state.auditLog)-1].Hash
+	}
+
+	record.CreatedAt = time.Now().UTC()
+	record = record.Seal(prevHash)
+	record.ID = int64(len(state.auditLog) + 1)
+	state.auditLog = append(state.auditLog, record)
+
+	return nil
+}
+
+// ForEachAuditRecord streams the committed audit log in insertion order.
+func (s AccountStore) ForEachAuditRecord(ctx context.Context, fn func(core.AuditRecord) error) error {
+	select {
+	case s.db.lock <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	auditLog := s.db.committed.auditLog
+	<-s.db.lock
+
+	// Committed records are never modified, so they can be read unlocked.
+	for _, record := range auditLog {
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"payment/internal/core"
+)
+
+func (s AccountStore) AddBulkTransfer(ctx context.Context, accountID int64, bulkTransfer core.BulkTransfer) (int64, error) {
+	if s.tx == nil {
+		return 0, errors.New("AddBulkTransfer must be called within Atomic transaction")
+	}
+
+	executionMode := bulkTransfer.ExecutionMode
+	if executionMode == "" {
+		executionMode = core.ExecutionModeAllOrNothing
+	}
+
+	state := s.tx.write()
+	record := core.BulkTransferRecord{
+		ID:            int64(len(state.bulkTransfers) + 1),
+		AccountID:     accountID,
+		ExecutionMode: executionMode,
+		TotalCents:    bulkTransfer.TotalAmount(),
+		TransferCount: len(bulkTransfer.Transfers),
+		RequestID:     bulkTransfer.RequestID,
+		CreatedAt:     time.Now().UTC(),
+	}
+	state.bulkTransfers = append(state.bulkTransfers, record)
+
+	return record.ID, nil
+}
+
+func (s AccountStore) GetAccountHistory(ctx context.Context, accountID int64, counterpartyIBANs []string) (core.AccountHistory, error) {
+	if s.tx == nil {
+		return core.AccountHistory{}, errors.New("GetAccountHistory must be called within Atomic transaction")
+	}
+
+	state := s.tx.read()
+	history := core.AccountHistory{
+		KnownCounterparties: make(map[string]bool),
+	}
+
+	// Like the SQLite store, the batches are read from the transfers the
+	// account paid.
+	batches := make(map[int64]bool)
+	var totalCents int64
+	for _, transaction := range state.transactions {
+		if transaction.AccountID != accountID || transaction.Kind != core.TransactionKindTransfer || transaction.BulkTransferID == 0 {
+			continue
+		}
+		batches[transaction.BulkTransferID] = true
+		totalCents -= transaction.AmountCents
+	}
+	history.BatchCount = len(batches)
+	if history.BatchCount > 0 {
+		history.AverageBatchTotalCents = totalCents / int64(history.BatchCount)
+	}
+
+	wanted := make(map[string]bool, len(counterpartyIBANs))
+	for _, iban := range counterpartyIBANs {
+		wanted[iban] = true
+	}
+	for _, transaction := range state.transactions {
+		if transaction.AccountID == accountID && wanted[transaction.CounterpartyIBAN] {
+			history.KnownCounterparties[transaction.CounterpartyIBAN] = true
+		}
+	}
+
+	return history, nil
+}
+
+func (s AccountStore) CountTransfersSince(ctx context.Context, accountID int64, since time.Time) (int, error) {
+	if s.tx == nil {
+		return 0, errors.New("CountTransfersSince must be called within Atomic transaction")
+	}
+
+	// The SQLite store compares at second precision.
+	since = since.Truncate(time.Second)
+
+	state := s.tx.read()
+	var count int
+	for _, transaction := range state.transactions {
+		if transaction.AccountID != accountID || transaction.Kind != core.TransactionKindTransfer {
+			continue
+		}
+
+		record := state.bulkTransfer(transaction.BulkTransferID)
+		if record != nil && !record.CreatedAt.Before(since) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// bulkTransfer returns the stored bulk transfer with the given ID, or nil.
+func (s *state) bulkTransfer(bulkTransferID int64) *core.BulkTransferRecord {
+	if bulkTransferID <= 0 || bulkTransferID > int64(len(s.bulkTransfers)) {
+		return nil
+	}
+
+	return &s.bulkTransfers[bulkTransferID-1]
+}
+
+// bulkTransferRecord completes record with the IBAN and BIC of its account.
+func (s *state) bulkTransferRecord(record core.BulkTransferRecord) (core.BulkTransferRecord, bool) {
+	account := s.account(record.AccountID)
+	if account == nil {
+		return core.BulkTransferRecord{}, false
+	}
+
+	record.OrganizationIBAN = account.IBAN
+	record.OrganizationBIC = account.BIC
+	return record, true
+}
+
+func (s AccountStore) GetBulkTransfer(ctx context.Context, bulkTransferID int64) (core.BulkTransferRecord, error) {
+	if s.tx == nil {
+		return core.BulkTransferRecord{}, errors.New("GetBulkTransfer must be called within Atomic transaction")
+	}
+
+	state := s.tx.read()
+	stored := state.bulkTransfer(bulkTransferID)
+	if stored == nil {
+		return core.BulkTransferRecord{}, core.ErrBulkTransferNotFound
+	}
+
+	record, ok := state.bulkTransferRecord(*stored)
+	if !ok {
+		return core.BulkTransferRecord{}, core.ErrBulkTransferNotFound
+	}
+
+	return record, nil
+}
+
+func (s AccountStore) ListBulkTransfers(ctx context.Context, accountID int64) ([]core.BulkTransferRecord, error) {
+	if s.tx == nil {
+		return nil, errors.New("ListBulkTransfers must be called within Atomic transaction")
+	}
+
+	state := s.tx.read()
+	var records []core.BulkTransferRecord
+	for _, stored := range state.bulkTransfers {
+		if stored.AccountID != accountID {
+			continue
+		}
+
+		if record, ok := state.bulkTransferRecord(stored); ok {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+func (s AccountStore) ListTransactions(ctx context.Context, accountID int64, query core.TransactionQuery) ([]core.Transaction, error) {
+	if s.tx == nil {
+		return nil, errors.New("ListTransactions must be called within Atomic transaction")
+	}
+
+	transactions := make([]core.Transaction, 0, max(query.Limit, 0))
+	for _, transaction := range s.tx.read().transactions {
+		if len(transactions) == query.Limit {
+			break
+		}
+
+		if transaction.AccountID != accountID || transaction.ID <= query.AfterID {
+			continue
+		}
+		if query.BulkTransferID != 0 && transaction.BulkTransferID != query.BulkTransferID {
+			continue
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,20 @@
+package memory
+
+import (
+	"context"
+)
+
+// The memory store has no connection, schema or journal to check: it is
+// ready as soon as it exists.
+
+func (s AccountStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (s AccountStore) CheckSchema(ctx context.Context) error {
+	return nil
+}
+
+func (s AccountStore) CheckJournalMode(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"payment/internal/core"
+)
+
+// withHeldCents sets the total of the pending holds on account that have not
+// expired at now.
+func (s *state) withHeldCents(account core.Account, now time.Time) core.Account {
+	account.HeldCents = 0
+	for _, hold := range s.holds {
+		if hold.AccountID == account.ID && hold.Status == core.HoldPending && hold.ExpiresAt.After(now) {
+			account.HeldCents += hold.AmountCents
+		}
+	}
+
+	return account
+}
+
+func (s AccountStore) AddHold(ctx context.Context, hold core.Hold) (int64, error) {
+	if s.tx == nil {
+		return 0, errors.New("AddHold must be called within Atomic transaction")
+	}
+
+	if hold.AmountCents <= 0 {
+		return 0, errors.New("failed to insert hold: amount must be positive")
+	}
+
+	state := s.tx.write()
+	hold.ID = int64(len(state.holds) + 1)
+	// Like the stored timestamps of the SQLite store, without monotonic clock
+	// reading or location.
+	hold.CreatedAt = hold.CreatedAt.UTC()
+	hold.ExpiresAt = hold.ExpiresAt.UTC()
+	state.holds = append(state.holds, hold)
+
+	return hold.ID, nil
+}
+
+func (s AccountStore) GetHold(ctx context.Context, holdID int64) (core.Hold, error) {
+	if s.tx == nil {
+		return core.Hold{}, errors.New("GetHold must be called within Atomic transaction")
+	}
+
+	for _, hold := range s.tx.read().holds {
+		if hold.ID == holdID {
+			return hold, nil
+		}
+	}
+
+	return core.Hold{}, core.ErrHoldNotFound
+}
+
+func (s AccountStore) ListHolds(ctx context.Context, accountID int64) ([]core.Hold, error) {
+	if s.tx == nil {
+		return nil, errors.New("ListHolds must be called within Atomic transaction")
+	}
+
+	var holds []core.Hold
+	for _, hold := range s.tx.read().holds {
+		if hold.AccountID == accountID {
+			holds = append(holds, hold)
+		}
+	}
+
+	return holds, nil
+}
+
+func (s AccountStore) SetHoldStatus(ctx context.Context, holdID int64, status core.HoldStatus) error {
+	if s.tx == nil {
+		return errors.New("SetHoldStatus must be called within Atomic transaction")
+	}
+
+	if _, err := s.GetHold(ctx, holdID); err != nil {
+		return err
+	}
+
+	state := s.tx.write()
+	for i := range state.holds {
+		if state.holds[i].ID == holdID {
+			state.holds[i].Status = status
+		}
+	}
+
+	return nil
+}
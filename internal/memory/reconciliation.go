@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"payment/internal/core"
+)
+
+func (s AccountStore) CheckBalances(ctx context.Context) ([]core.BalanceCheck, error) {
+	if s.tx == nil {
+		return nil, errors.New("CheckBalances must be called within Atomic transaction")
+	}
+
+	state := s.tx.read()
+	transactionsCents := make(map[int64]int64, len(state.accounts))
+	for _, transaction := range state.transactions {
+		transactionsCents[transaction.AccountID] += transaction.AmountCents
+	}
+
+	var checks []core.BalanceCheck
+	for _, account := range state.accounts {
+		checks = append(checks, core.BalanceCheck{
+			AccountID:           account.ID,
+			IBAN:                account.IBAN,
+			BIC:                 account.BIC,
+			OpeningBalanceCents: account.openingBalanceCents,
+			TransactionsCents:   transactionsCents[account.ID],
+			BalanceCents:        account.BalanceCents,
+		})
+	}
+
+	return checks, nil
+}
+
+func (s AccountStore) AddReconciliationReport(ctx context.Context, report core.ReconciliationReport) (int64, error) {
+	if s.tx == nil {
+		return 0, errors.New("AddReconciliationReport must be called within Atomic transaction")
+	}
+
+	state := s.tx.write()
+	report.ID = int64(len(state.reconciliationReports) + 1)
+	report.StartedAt = report.StartedAt.UTC()
+	report.FinishedAt = report.FinishedAt.UTC()
+	report.Discrepancies = slices.Clone(report.Discrepancies)
+	state.reconciliationReports = append(state.reconciliationReports, report)
+
+	return report.ID, nil
+}
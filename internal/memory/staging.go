@@ -0,0 +1,241 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"payment/internal/core"
+)
+
+type stagedBulkTransfer struct {
+	id               int64
+	organizationIBAN string
+	organizationBIC  string
+	executionMode    core.ExecutionMode
+	requestID        string
+	holdID           int64
+	createdAt        time.Time
+	// review is set while the staged bulk transfer is held.
+	review   core.ReviewKind
+	reason   string
+	approved []core.ReviewKind
+	heldAt   time.Time
+}
+
+type stagedTransfer struct {
+	stagedBulkTransferID int64
+	position             int
+	transfer             core.Transfer
+}
+
+func (s AccountStore) AddStagedBulkTransfer(ctx context.Context, bulkTransfer core.BulkTransfer) (int64, error) {
+	if s.tx == nil {
+		return 0, errors.New("AddStagedBulkTransfer must be called within Atomic transaction")
+	}
+
+	executionMode := bulkTransfer.ExecutionMode
+	if executionMode == "" {
+		executionMode = core.ExecutionModeAllOrNothing
+	}
+
+	state := s.tx.write()
+	state.lastStagedBulkTransferID++
+	state.stagedBulkTransfers = append(state.stagedBulkTransfers, stagedBulkTransfer{
+		id:               state.lastStagedBulkTransferID,
+		organizationIBAN: bulkTransfer.OrganizationIBAN,
+		organizationBIC:  bulkTransfer.OrganizationBIC,
+		executionMode:    executionMode,
+		requestID:        bulkTransfer.RequestID,
+		holdID:           bulkTransfer.HoldID,
+		createdAt:        time.Now(),
+	})
+
+	return state.lastStagedBulkTransferID, nil
+}
+
+func (s AccountStore) AddStagedTransfers(ctx context.Context, stagedBulkTransferID int64, transfers []core.StagedTransfer) error {
+	if s.tx == nil {
+		return errors.New("AddStagedTransfers must be called within Atomic transaction")
+	}
+
+	state := s.tx.write()
+	for _, staged := range transfers {
+		transfer := staged.Transfer
+		// Only the staged columns are kept.
+		state.stagedTransfers = append(state.stagedTransfers, stagedTransfer{
+			stagedBulkTransferID: stagedBulkTransferID,
+			position:             staged.Position,
+			transfer: core.Transfer{
+				CounterpartyName: transfer.CounterpartyName,
+				CounterpartyIBAN: transfer.CounterpartyIBAN,
+				CounterpartyBIC:  transfer.CounterpartyBIC,
+				AmountCents:      transfer.AmountCents,
+				Currency:         transfer.Currency,
+				Description:      transfer.Description,
+				DebtorIBAN:       transfer.DebtorIBAN,
+				DebtorBIC:        transfer.DebtorBIC,
+			},
+		})
+	}
+
+	return nil
+}
+
+func (s AccountStore) GetStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) (core.BulkTransfer, error) {
+	if s.tx == nil {
+		return core.BulkTransfer{}, errors.New("GetStagedBulkTransfer must be called within Atomic transaction")
+	}
+
+	state := s.tx.read()
+	index := slices.IndexFunc(state.stagedBulkTransfers, func(staged stagedBulkTransfer) bool {
+		return staged.id == stagedBulkTransferID
+	})
+	if index < 0 {
+		return core.BulkTransfer{}, core.ErrStagedBulkTransferNotFound
+	}
+
+	staged := state.stagedBulkTransfers[index]
+	bulkTransfer := core.BulkTransfer{
+		OrganizationIBAN: staged.organizationIBAN,
+		OrganizationBIC:  staged.organizationBIC,
+		ExecutionMode:    staged.executionMode,
+		RequestID:        staged.requestID,
+		HoldID:           staged.holdID,
+		ApprovedReviews:  slices.Clone(staged.approved),
+	}
+
+	return bulkTransfer, nil
+}
+
+func (s AccountStore) ListStagedTransfers(ctx context.Context, stagedBulkTransferID int64, afterPosition int, limit int) ([]core.StagedTransfer, error) {
+	if s.tx == nil {
+		return nil, errors.New("ListStagedTransfers must be called within Atomic transaction")
+	}
+
+	var transfers []core.StagedTransfer
+	for _, transfer := range s.tx.read().stagedTransfers {
+		if transfer.stagedBulkTransferID == stagedBulkTransferID && transfer.position > afterPosition {
+			transfers = append(transfers, core.StagedTransfer{Position: transfer.position, Transfer: transfer.transfer})
+		}
+	}
+
+	slices.SortFunc(transfers, func(a, b core.StagedTransfer) int {
+		return cmp.Compare(a.Position, b.Position)
+	})
+
+	return transfers[:min(limit, len(transfers))], nil
+}
+
+func (s AccountStore) DeleteStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) error {
+	if s.tx == nil {
+		return errors.New("DeleteStagedBulkTransfer must be called within Atomic transaction")
+	}
+
+	state := s.tx.write()
+	state.stagedTransfers = slices.DeleteFunc(state.stagedTransfers, func(transfer stagedTransfer) bool {
+		return transfer.stagedBulkTransferID == stagedBulkTransferID
+	})
+	state.stagedBulkTransfers = slices.DeleteFunc(state.stagedBulkTransfers, func(staged stagedBulkTransfer) bool {
+		return staged.id == stagedBulkTransferID
+	})
+
+	return nil
+}
+
+func (s AccountStore) DeleteStagedBulkTransfersBefore(ctx context.Context, createdBefore time.Time) (int, error) {
+	if s.tx == nil {
+		return 0, errors.New("DeleteStagedBulkTransfersBefore must be called within Atomic transaction")
+	}
+
+	state := s.tx.write()
+	count := len(state.stagedBulkTransfers)
+	state.stagedBulkTransfers = slices.DeleteFunc(state.stagedBulkTransfers, func(staged stagedBulkTransfer) bool {
+		return staged.review == "" && staged.createdAt.Before(createdBefore)
+	})
+	state.stagedTransfers = slices.DeleteFunc(state.stagedTransfers, func(transfer stagedTransfer) bool {
+		return !slices.ContainsFunc(state.stagedBulkTransfers, func(staged stagedBulkTransfer) bool {
+			return staged.id == transfer.stagedBulkTransferID
+		})
+	})
+
+	return count - len(state.stagedBulkTransfers), nil
+}
+
+func (s AccountStore) HoldStagedBulkTransfer(ctx context.Context, held core.HeldBulkTransfer) error {
+	if s.tx == nil {
+		return errors.New("HoldStagedBulkTransfer must be called within Atomic transaction")
+	}
+
+	state := s.tx.write()
+	index := slices.IndexFunc(state.stagedBulkTransfers, func(staged stagedBulkTransfer) bool {
+		return staged.id == held.ID
+	})
+	if index < 0 {
+		return core.ErrStagedBulkTransferNotFound
+	}
+
+	staged := &state.stagedBulkTransfers[index]
+	staged.review = held.Review
+	staged.reason = held.Reason
+	staged.approved = slices.Clone(held.Approved)
+	staged.heldAt = time.Now().UTC()
+
+	return nil
+}
+
+func (s AccountStore) GetHeldBulkTransfer(ctx context.Context, heldBulkTransferID int64) (core.HeldBulkTransfer, error) {
+	if s.tx == nil {
+		return core.HeldBulkTransfer{}, errors.New("GetHeldBulkTransfer must be called within Atomic transaction")
+	}
+
+	state := s.tx.read()
+	for _, staged := range state.stagedBulkTransfers {
+		if staged.id == heldBulkTransferID && staged.review != "" {
+			return state.heldBulkTransfer(staged), nil
+		}
+	}
+
+	return core.HeldBulkTransfer{}, core.ErrHeldBulkTransferNotFound
+}
+
+func (s AccountStore) ListHeldBulkTransfers(ctx context.Context) ([]core.HeldBulkTransfer, error) {
+	if s.tx == nil {
+		return nil, errors.New("ListHeldBulkTransfers must be called within Atomic transaction")
+	}
+
+	state := s.tx.read()
+	var held []core.HeldBulkTransfer
+	for _, staged := range state.stagedBulkTransfers {
+		if staged.review != "" {
+			held = append(held, state.heldBulkTransfer(staged))
+		}
+	}
+
+	return held, nil
+}
+
+func (s *state) heldBulkTransfer(staged stagedBulkTransfer) core.HeldBulkTransfer {
+	held := core.HeldBulkTransfer{
+		ID:               staged.id,
+		OrganizationIBAN: staged.organizationIBAN,
+		OrganizationBIC:  staged.organizationBIC,
+		ExecutionMode:    staged.executionMode,
+		RequestID:        staged.requestID,
+		Review:           staged.review,
+		Reason:           staged.reason,
+		Approved:         slices.Clone(staged.approved),
+		HeldAt:           staged.heldAt,
+	}
+
+	for _, transfer := range s.stagedTransfers {
+		if transfer.stagedBulkTransferID == staged.id {
+			held.TransferCount++
+			held.TotalCents += transfer.transfer.AmountCents
+		}
+	}
+
+	return held
+}
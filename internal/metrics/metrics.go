@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "payment"
+
+// Bulk transfer outcomes.
+const (
+	OutcomeAccepted          = "accepted"
+	OutcomeNotFound          = "not_found"
+	OutcomeInsufficientFunds = "insufficient_funds"
+	OutcomeAccountFrozen     = "account_frozen"
+	OutcomeSanctions         = "sanctions"
+	OutcomeFraud             = "fraud"
+	OutcomeHeld              = "held"
+	OutcomeInternal          = "internal"
+)
+
+// Reconciliation run results.
+const (
+	ReconciliationResultOK            = "ok"
+	ReconciliationResultDiscrepancies = "discrepancies"
+	ReconciliationResultError         = "error"
+)
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	BulkTransferOutcomes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bulk_transfer_outcomes_total",
+		Help:      "Processed bulk transfers by outcome.",
+	}, []string{"outcome"})
+
+	BulkTransferSize = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "bulk_transfer_size",
+		Help:      "Number of credit transfers per bulk transfer.",
+		Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+	})
+
+	BulkTransferAmount = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "bulk_transfer_amount_cents",
+		Help:      "Total amount per bulk transfer, in cents.",
+		Buckets:   prometheus.ExponentialBuckets(100, 10, 9),
+	})
+
+	SQLiteLockWait = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sqlite_lock_wait_seconds",
+		Help:      "Time spent acquiring the SQLite write lock in AccountStore.Atomic.",
+		Buckets:   []float64{.0005, .001, .005, .01, .05, .1, .5, 1, 5, 10, 30},
+	})
+
+	SQLiteTransactionRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sqlite_transaction_retries_total",
+		Help:      "Transactions rerun by AccountStore.Atomic, by SQLite error code.",
+	}, []string{"code"})
+
+	SQLiteTransientFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sqlite_transient_failures_total",
+		Help:      "Transactions that still failed on a transient SQLite error once their retries ran out, by error code.",
+	}, []string{"code"})
+
+	ReconciliationRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconciliation_runs_total",
+		Help:      "Balance reconciliation runs by result.",
+	}, []string{"result"})
+
+	ReconciliationDiscrepancies = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "reconciliation_discrepancies",
+		Help:      "Accounts whose balance did not match their ledger in the last reconciliation.",
+	})
+
+	ReconciliationLastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "reconciliation_last_success_timestamp_seconds",
+		Help:      "Unix time at which the last reconciliation completed.",
+	})
+)
+
+// Registry holds every collector of the service. A dedicated registry keeps
+// tests and tools that import the packages from colliding on the global one.
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		BulkTransferOutcomes,
+		BulkTransferSize,
+		BulkTransferAmount,
+		SQLiteLockWait,
+		SQLiteTransactionRetries,
+		SQLiteTransientFailures,
+		ReconciliationRuns,
+		ReconciliationDiscrepancies,
+		ReconciliationLastSuccess,
+	)
+}
+
+// RegisterDBStats exposes the connection pool statistics of db.
+func RegisterDBStats(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
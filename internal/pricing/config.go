@@ -0,0 +1,5 @@
+package pricing
+
+type Config struct {
+	Path string `envconfig:"PRICING_PLANS_PATH"` // JSON pricing plans file, no fees are charged when empty
+}
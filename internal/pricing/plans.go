@@ -0,0 +1,62 @@
+package pricing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"payment/internal/core"
+)
+
+type plansFile struct {
+	Plans []planSpec `json:"plans"`
+}
+
+type planSpec struct {
+	Name                 string `json:"name"`
+	SEPAFeeCents         int64  `json:"sepa_fee_cents"`
+	CrossBorderFeeBPS    int64  `json:"cross_border_fee_bps"`
+	MonthlyFreeTransfers int    `json:"monthly_free_transfers"`
+}
+
+// Load returns the pricing plans configured by cfg.
+func Load(cfg Config) (core.Pricing, error) {
+	if cfg.Path == "" {
+		return core.Pricing{}, nil
+	}
+
+	data, err := os.ReadFile(cfg.Path)
+	if err != nil {
+		return core.Pricing{}, fmt.Errorf("failed to read pricing plans: %w", err)
+	}
+
+	return ParsePlans(data)
+}
+
+func ParsePlans(data []byte) (core.Pricing, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var file plansFile
+	if err := decoder.Decode(&file); err != nil {
+		return core.Pricing{}, fmt.Errorf("failed to parse pricing plans: %w", err)
+	}
+
+	plans := make([]core.PricingPlan, 0, len(file.Plans))
+	for _, plan := range file.Plans {
+		plans = append(plans, core.PricingPlan{
+			Name:                      plan.Name,
+			SEPAFeeCents:              plan.SEPAFeeCents,
+			CrossBorderFeeBasisPoints: plan.CrossBorderFeeBPS,
+			MonthlyFreeTransfers:      plan.MonthlyFreeTransfers,
+		})
+	}
+
+	pricing, err := core.NewPricing(plans)
+	if err != nil {
+		return core.Pricing{}, fmt.Errorf("invalid pricing plans: %w", err)
+	}
+
+	return pricing, nil
+}
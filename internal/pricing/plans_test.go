@@ -0,0 +1,65 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"payment/internal/core"
+)
+
+func TestParsePlans(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		data          string
+		plan          string
+		expectedPlan  core.PricingPlan
+		expectedError string
+	}{
+		{
+			name: "parses every field",
+			data: `{"plans": [{"name": "business", "sepa_fee_cents": 20, "cross_border_fee_bps": 50, "monthly_free_transfers": 100}]}`,
+			plan: "business",
+			expectedPlan: core.PricingPlan{
+				Name:                      "business",
+				SEPAFeeCents:              20,
+				CrossBorderFeeBasisPoints: 50,
+				MonthlyFreeTransfers:      100,
+			},
+		},
+		{
+			name:          "rejects unknown fields",
+			data:          `{"plans": [{"name": "business", "sepa_fee": 20}]}`,
+			expectedError: "failed to parse pricing plans",
+		},
+		{
+			name:          "rejects duplicate plans",
+			data:          `{"plans": [{"name": "business"}, {"name": "business"}]}`,
+			expectedError: `invalid pricing plans: plan "business": defined twice`,
+		},
+		{
+			name:          "rejects negative fees",
+			data:          `{"plans": [{"name": "business", "sepa_fee_cents": -1}]}`,
+			expectedError: "fees and allowance cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pricing, err := ParsePlans([]byte(tt.data))
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			plan, ok := pricing.Plan(tt.plan)
+			require.True(t, ok)
+			require.Equal(t, tt.expectedPlan, plan)
+		})
+	}
+}
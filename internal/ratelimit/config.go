@@ -0,0 +1,14 @@
+package ratelimit
+
+type Config struct {
+	Enabled            bool    `envconfig:"RATE_LIMIT_ENABLED" default:"true"`
+	RequestsPerSecond  float64 `envconfig:"RATE_LIMIT_RPS" default:"5"`                  // Token refill rate per key
+	Burst              int     `envconfig:"RATE_LIMIT_BURST" default:"10"`               // Bucket capacity per key
+	DailyTransferQuota int64   `envconfig:"RATE_LIMIT_DAILY_TRANSFER_QUOTA" default:"0"` // Max transfers per organization per UTC day, 0 disables
+	// APIKeys are the API keys clients may send. When set, any other key is
+	// refused. Clients without a key are told apart by their address.
+	APIKeys []string `envconfig:"API_KEYS"`
+	// APIKeyOrganizations binds API keys to the IBAN of the organization they
+	// act for, as key:IBAN pairs. Bound keys are accepted like APIKeys.
+	APIKeyOrganizations map[string]string `envconfig:"API_KEY_ORGANIZATIONS"`
+}
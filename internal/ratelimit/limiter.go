@@ -0,0 +1,152 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	ReasonRateLimited   = "rate_limited"
+	ReasonQuotaExceeded = "quota_exceeded"
+)
+
+// ErrUnknownAPIKey is returned for API keys that are not configured.
+var ErrUnknownAPIKey = errors.New("unknown API key")
+
+type Decision struct {
+	Allowed    bool
+	RetryAfter time.Duration
+	Reason     string
+	// Key identifies the client in the limiter.
+	Key string
+	// Organization is the IBAN of the organization the client's API key is
+	// bound to, if any.
+	Organization string
+}
+
+// Reservation is daily quota taken for a bulk transfer. The part its
+// transfers did not use is given back with ReleaseQuota.
+type Reservation struct {
+	Key       string
+	Transfers int
+	At        time.Time
+}
+
+type Limiter struct {
+	store  Store
+	config Config
+	// apiKeys maps the hash of every accepted API key to the organization it
+	// is bound to, empty when it is not.
+	apiKeys map[string]string
+	now     func() time.Time
+}
+
+func NewLimiter(store Store, config Config) Limiter {
+	apiKeys := make(map[string]string, len(config.APIKeys)+len(config.APIKeyOrganizations))
+	for _, apiKey := range config.APIKeys {
+		apiKeys[hashAPIKey(apiKey)] = ""
+	}
+	for apiKey, organization := range config.APIKeyOrganizations {
+		apiKeys[hashAPIKey(apiKey)] = organization
+	}
+
+	return Limiter{
+		store:   store,
+		config:  config,
+		apiKeys: apiKeys,
+		now:     time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of the client that sent apiKey from
+// address. It reads nothing from the request body, so that a client over its
+// limit costs as little as possible.
+func (l Limiter) Allow(ctx context.Context, apiKey string, address string) (Decision, error) {
+	key, organization, err := l.clientKey(apiKey, address)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	if !l.config.Enabled || l.config.RequestsPerSecond <= 0 {
+		return Decision{Allowed: true, Key: key, Organization: organization}, nil
+	}
+
+	wait, err := l.store.TakeToken(ctx, key, l.config.RequestsPerSecond, l.config.Burst, l.now())
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to take token: %w", err)
+	}
+	if wait > 0 {
+		return Decision{RetryAfter: wait, Reason: ReasonRateLimited, Key: key, Organization: organization}, nil
+	}
+
+	return Decision{Allowed: true, Key: key, Organization: organization}, nil
+}
+
+// clientKey identifies a client by its API key when it sends one of the
+// configured keys, and by its network address otherwise. Other API keys are
+// refused so that a client cannot get a fresh bucket by making one up. When
+// no key is configured, API keys are ignored. Keys are hashed so that they
+// never end up in limiter stores or the audit log. The organization the key
+// is bound to is returned along.
+func (l Limiter) clientKey(apiKey string, address string) (string, string, error) {
+	if apiKey == "" || len(l.apiKeys) == 0 {
+		return "addr:" + address, "", nil
+	}
+
+	hash := hashAPIKey(apiKey)
+	organization, ok := l.apiKeys[hash]
+	if !ok {
+		return "", "", ErrUnknownAPIKey
+	}
+
+	return "api_key:" + hash[:16], organization, nil
+}
+
+func hashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// ReserveQuota charges transfers against the daily quota of key. Transfers
+// that end up not executing are given back with ReleaseQuota.
+func (l Limiter) ReserveQuota(ctx context.Context, key string, transfers int) (Decision, Reservation, error) {
+	if !l.config.Enabled || l.config.DailyTransferQuota <= 0 {
+		return Decision{Allowed: true, Key: key}, Reservation{}, nil
+	}
+
+	now := l.now()
+	ok, err := l.store.ConsumeQuota(ctx, key, int64(transfers), l.config.DailyTransferQuota, now)
+	if err != nil {
+		return Decision{}, Reservation{}, fmt.Errorf("failed to consume quota: %w", err)
+	}
+	if !ok {
+		return Decision{RetryAfter: untilNextDay(now), Reason: ReasonQuotaExceeded, Key: key}, Reservation{}, nil
+	}
+
+	return Decision{Allowed: true, Key: key}, Reservation{Key: key, Transfers: transfers, At: now}, nil
+}
+
+// ReleaseQuota gives transfers of reservation back to the quota of the day it
+// was taken on.
+func (l Limiter) ReleaseQuota(ctx context.Context, reservation Reservation, transfers int) error {
+	transfers = min(transfers, reservation.Transfers)
+	if transfers <= 0 {
+		return nil
+	}
+
+	if err := l.store.ReleaseQuota(ctx, reservation.Key, int64(transfers), reservation.At); err != nil {
+		return fmt.Errorf("failed to release quota: %w", err)
+	}
+
+	return nil
+}
+
+func untilNextDay(now time.Time) time.Duration {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	return next.Sub(now)
+}
@@ -0,0 +1,262 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 9, 30, 23, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		config           Config
+		requests         int
+		expectedDecision Decision
+	}{
+		{
+			name:             "disabled_always_allows",
+			config:           Config{Enabled: false, RequestsPerSecond: 1, Burst: 1},
+			requests:         3,
+			expectedDecision: Decision{Allowed: true, Key: "addr:192.0.2.1"},
+		},
+		{
+			name:             "within_burst_allows",
+			config:           Config{Enabled: true, RequestsPerSecond: 1, Burst: 3},
+			requests:         3,
+			expectedDecision: Decision{Allowed: true, Key: "addr:192.0.2.1"},
+		},
+		{
+			name:     "burst_exhausted_is_rate_limited",
+			config:   Config{Enabled: true, RequestsPerSecond: 2, Burst: 2},
+			requests: 3,
+			expectedDecision: Decision{
+				RetryAfter: 500 * time.Millisecond,
+				Reason:     ReasonRateLimited,
+				Key:        "addr:192.0.2.1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			limiter := NewLimiter(NewMemoryStore(), tt.config)
+			limiter.now = func() time.Time { return now }
+
+			var decision Decision
+			for range tt.requests {
+				var err error
+				decision, err = limiter.Allow(context.Background(), "", "192.0.2.1")
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tt.expectedDecision, decision)
+		})
+	}
+}
+
+func TestLimiter_Allow_APIKeys(t *testing.T) {
+	t.Parallel()
+
+	configured := NewLimiter(NewMemoryStore(), Config{Enabled: true, RequestsPerSecond: 1, Burst: 1, APIKeys: []string{"secret"}})
+
+	decision, err := configured.Allow(context.Background(), "secret", "192.0.2.1")
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+	require.Equal(t, "api_key:"+hashAPIKey("secret")[:16], decision.Key)
+
+	decision, err = configured.Allow(context.Background(), "secret", "192.0.2.2")
+	require.NoError(t, err)
+	require.False(t, decision.Allowed, "the bucket follows the key, not the address")
+
+	_, err = configured.Allow(context.Background(), "made-up", "192.0.2.1")
+	require.ErrorIs(t, err, ErrUnknownAPIKey)
+
+	decision, err = configured.Allow(context.Background(), "", "192.0.2.1")
+	require.NoError(t, err)
+	require.Equal(t, Decision{Allowed: true, Key: "addr:192.0.2.1"}, decision)
+
+	// Without configured keys, a made-up key does not get a fresh bucket.
+	unconfigured := NewLimiter(NewMemoryStore(), Config{Enabled: true, RequestsPerSecond: 1, Burst: 1})
+
+	decision, err = unconfigured.Allow(context.Background(), "made-up", "192.0.2.1")
+	require.NoError(t, err)
+	require.Equal(t, Decision{Allowed: true, Key: "addr:192.0.2.1"}, decision)
+
+	decision, err = unconfigured.Allow(context.Background(), "another", "192.0.2.1")
+	require.NoError(t, err)
+	require.False(t, decision.Allowed)
+}
+
+func TestLimiter_Allow_APIKeyOrganizations(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewLimiter(NewMemoryStore(), Config{
+		Enabled:             true,
+		RequestsPerSecond:   1,
+		Burst:               1,
+		APIKeys:             []string{"unbound"},
+		APIKeyOrganizations: map[string]string{"bound": "FR10474608000002006107XXXXX"},
+	})
+
+	decision, err := limiter.Allow(context.Background(), "bound", "192.0.2.1")
+	require.NoError(t, err)
+	require.Equal(t, Decision{Allowed: true, Key: "api_key:" + hashAPIKey("bound")[:16], Organization: "FR10474608000002006107XXXXX"}, decision)
+
+	decision, err = limiter.Allow(context.Background(), "unbound", "192.0.2.1")
+	require.NoError(t, err)
+	require.Equal(t, Decision{Allowed: true, Key: "api_key:" + hashAPIKey("unbound")[:16]}, decision)
+
+	decision, err = limiter.Allow(context.Background(), "", "192.0.2.1")
+	require.NoError(t, err)
+	require.Empty(t, decision.Organization)
+
+	_, err = limiter.Allow(context.Background(), "made-up", "192.0.2.1")
+	require.ErrorIs(t, err, ErrUnknownAPIKey)
+}
+
+func TestLimiter_ReserveQuota(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 9, 30, 23, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		config           Config
+		transfers        []int
+		expectedDecision Decision
+	}{
+		{
+			name:             "disabled_always_allows",
+			config:           Config{Enabled: false, DailyTransferQuota: 1},
+			transfers:        []int{5, 5},
+			expectedDecision: Decision{Allowed: true, Key: "org:TESTIBAN"},
+		},
+		{
+			name:      "quota_exceeded_waits_until_midnight",
+			config:    Config{Enabled: true, DailyTransferQuota: 100},
+			transfers: []int{60, 41},
+			expectedDecision: Decision{
+				RetryAfter: time.Hour,
+				Reason:     ReasonQuotaExceeded,
+				Key:        "org:TESTIBAN",
+			},
+		},
+		{
+			name:             "quota_reached_exactly_allows",
+			config:           Config{Enabled: true, DailyTransferQuota: 100},
+			transfers:        []int{60, 40},
+			expectedDecision: Decision{Allowed: true, Key: "org:TESTIBAN"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			limiter := NewLimiter(NewMemoryStore(), tt.config)
+			limiter.now = func() time.Time { return now }
+
+			var decision Decision
+			for _, transfers := range tt.transfers {
+				var err error
+				decision, _, err = limiter.ReserveQuota(context.Background(), "org:TESTIBAN", transfers)
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tt.expectedDecision, decision)
+		})
+	}
+}
+
+func TestLimiter_ReleaseQuota(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewLimiter(NewMemoryStore(), Config{Enabled: true, DailyTransferQuota: 100})
+
+	decision, reservation, err := limiter.ReserveQuota(context.Background(), "org:TESTIBAN", 100)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+
+	// Only the transfers that did not execute are given back.
+	require.NoError(t, limiter.ReleaseQuota(context.Background(), reservation, 30))
+
+	decision, _, err = limiter.ReserveQuota(context.Background(), "org:TESTIBAN", 31)
+	require.NoError(t, err)
+	require.False(t, decision.Allowed)
+
+	decision, _, err = limiter.ReserveQuota(context.Background(), "org:TESTIBAN", 30)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+}
+
+func TestMemoryStore_TakeToken_Refills(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryStore()
+	now := time.Date(2025, 9, 30, 12, 0, 0, 0, time.UTC)
+
+	wait, err := store.TakeToken(context.Background(), "key", 1, 1, now)
+	require.NoError(t, err)
+	require.Zero(t, wait)
+
+	wait, err = store.TakeToken(context.Background(), "key", 1, 1, now)
+	require.NoError(t, err)
+	require.Equal(t, time.Second, wait)
+
+	wait, err = store.TakeToken(context.Background(), "other", 1, 1, now)
+	require.NoError(t, err)
+	require.Zero(t, wait, "buckets are per key")
+
+	wait, err = store.TakeToken(context.Background(), "key", 1, 1, now.Add(time.Second))
+	require.NoError(t, err)
+	require.Zero(t, wait)
+}
+
+func TestMemoryStore_ConsumeQuota_ResetsDaily(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryStore()
+	now := time.Date(2025, 9, 30, 23, 59, 0, 0, time.UTC)
+
+	ok, err := store.ConsumeQuota(context.Background(), "key", 10, 10, now)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = store.ConsumeQuota(context.Background(), "key", 1, 10, now)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = store.ConsumeQuota(context.Background(), "key", 1, 10, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestMemoryStore_DropsIdleState(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryStore()
+	now := time.Date(2025, 9, 30, 23, 59, 0, 0, time.UTC)
+
+	_, err := store.TakeToken(context.Background(), "idle", 1, 2, now)
+	require.NoError(t, err)
+	// Refills one token every 100 seconds.
+	_, err = store.TakeToken(context.Background(), "refilling", 0.01, 1, now)
+	require.NoError(t, err)
+	_, err = store.ConsumeQuota(context.Background(), "yesterday", 1, 10, now)
+	require.NoError(t, err)
+
+	_, err = store.TakeToken(context.Background(), "new", 1, 2, now.Add(sweepInterval))
+	require.NoError(t, err)
+
+	require.NotContains(t, store.buckets, "idle")
+	require.Contains(t, store.buckets, "refilling")
+	require.NotContains(t, store.usages, "yesterday")
+}
@@ -0,0 +1,141 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store keeps the limiter state. Operations must be atomic per key so that a
+// shared implementation (e.g. Redis) can replace MemoryStore across instances.
+type Store interface {
+	// TakeToken consumes one token from the key's bucket and returns how long
+	// the caller has to wait before a token is available (0 when consumed).
+	TakeToken(ctx context.Context, key string, rate float64, burst int, now time.Time) (time.Duration, error)
+	// ConsumeQuota adds amount to the key's usage for the UTC day of now,
+	// unless it would exceed quota.
+	ConsumeQuota(ctx context.Context, key string, amount, quota int64, now time.Time) (bool, error)
+	// ReleaseQuota gives amount back to the key's usage for the UTC day of
+	// day, if that usage is still kept.
+	ReleaseQuota(ctx context.Context, key string, amount int64, day time.Time) error
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	rate      float64
+	burst     int
+}
+
+// full reports whether the bucket has refilled to its burst by now, in which
+// case it is no different from a new one.
+func (b *bucket) full(now time.Time) bool {
+	return b.tokens+now.Sub(b.updatedAt).Seconds()*b.rate >= float64(b.burst)
+}
+
+type usage struct {
+	day   string
+	count int64
+}
+
+// sweepInterval is how often MemoryStore drops the state it no longer needs.
+const sweepInterval = time.Minute
+
+// MemoryStore keeps the limiter state in process. Full buckets and the usages
+// of past days are dropped every sweepInterval, so that the maps do not grow
+// with every client ever seen.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	usages  map[string]*usage
+	sweptAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		usages:  make(map[string]*usage),
+	}
+}
+
+func (s *MemoryStore) TakeToken(_ context.Context, key string, rate float64, burst int, now time.Time) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), updatedAt: now}
+		s.buckets[key] = b
+	}
+	b.rate, b.burst = rate, burst
+
+	if elapsed := now.Sub(b.updatedAt).Seconds(); elapsed > 0 {
+		b.tokens = min(float64(burst), b.tokens+elapsed*rate)
+		b.updatedAt = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, nil
+	}
+
+	missing := 1 - b.tokens
+	return time.Duration(missing / rate * float64(time.Second)), nil
+}
+
+func (s *MemoryStore) ConsumeQuota(_ context.Context, key string, amount, quota int64, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	day := now.UTC().Format(time.DateOnly)
+
+	u, ok := s.usages[key]
+	if !ok || u.day != day {
+		u = &usage{day: day}
+		s.usages[key] = u
+	}
+
+	if u.count+amount > quota {
+		return false, nil
+	}
+
+	u.count += amount
+	return true, nil
+}
+
+func (s *MemoryStore) ReleaseQuota(_ context.Context, key string, amount int64, day time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.usages[key]; ok && u.day == day.UTC().Format(time.DateOnly) {
+		u.count = max(u.count-amount, 0)
+	}
+
+	return nil
+}
+
+// sweep drops the full buckets and the usages of past days, at most once per
+// sweepInterval. s.mu must be held.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.sweptAt) < sweepInterval {
+		return
+	}
+	s.sweptAt = now
+
+	for key, b := range s.buckets {
+		if b.full(now) {
+			delete(s.buckets, key)
+		}
+	}
+
+	day := now.UTC().Format(time.DateOnly)
+	for key, u := range s.usages {
+		if u.day != day {
+			delete(s.usages, key)
+		}
+	}
+}
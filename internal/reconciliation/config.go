@@ -0,0 +1,9 @@
+package reconciliation
+
+import (
+	"time"
+)
+
+type Config struct {
+	Interval time.Duration `envconfig:"RECONCILIATION_INTERVAL" default:"1h"` // How often balances are reconciled, never when 0
+}
@@ -0,0 +1,93 @@
+package reconciliation
+
+import (
+	"context"
+	"time"
+
+	"payment/internal/core"
+	"payment/internal/metrics"
+)
+
+//go:generate go tool go.uber.org/mock/mockgen -source=job.go -destination=reconciler_mock.go -package=reconciliation
+
+type Reconciler interface {
+	Reconcile(ctx context.Context) (core.ReconciliationReport, error)
+}
+
+type Logger interface {
+	InfoContext(ctx context.Context, msg string, args ...any)
+	ErrorContext(ctx context.Context, msg string, args ...any)
+}
+
+// Job reconciles the account balances periodically and reports
+// discrepancies as metrics and log events.
+type Job struct {
+	reconciler Reconciler
+	logger     Logger
+	config     Config
+}
+
+func NewJob(reconciler Reconciler, logger Logger, config Config) Job {
+	return Job{
+		reconciler: reconciler,
+		logger:     logger,
+		config:     config,
+	}
+}
+
+// Run reconciles every interval until ctx is done. Failed runs are logged and
+// retried at the next tick.
+func (j Job) Run(ctx context.Context) {
+	if j.config.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = j.RunOnce(ctx)
+		}
+	}
+}
+
+func (j Job) RunOnce(ctx context.Context) (core.ReconciliationReport, error) {
+	report, err := j.reconciler.Reconcile(ctx)
+	if err != nil {
+		metrics.ReconciliationRuns.WithLabelValues(metrics.ReconciliationResultError).Inc()
+		j.logger.ErrorContext(ctx, "Balance reconciliation failed", "error", err)
+		return core.ReconciliationReport{}, err
+	}
+
+	result := metrics.ReconciliationResultOK
+	if len(report.Discrepancies) > 0 {
+		result = metrics.ReconciliationResultDiscrepancies
+	}
+	metrics.ReconciliationRuns.WithLabelValues(result).Inc()
+	metrics.ReconciliationDiscrepancies.Set(float64(len(report.Discrepancies)))
+	metrics.ReconciliationLastSuccess.Set(float64(report.FinishedAt.Unix()))
+
+	for _, check := range report.Discrepancies {
+		j.logger.ErrorContext(ctx, "Balance does not match ledger",
+			"report_id", report.ID,
+			"account_id", check.AccountID,
+			"iban", check.IBAN,
+			"balance_cents", check.BalanceCents,
+			"expected_balance_cents", check.ExpectedBalanceCents(),
+			"discrepancy_cents", check.DiscrepancyCents(),
+		)
+	}
+
+	j.logger.InfoContext(ctx, "Balance reconciliation completed",
+		"report_id", report.ID,
+		"accounts", report.AccountCount,
+		"discrepancies", len(report.Discrepancies),
+		"duration", report.FinishedAt.Sub(report.StartedAt),
+	)
+
+	return report, nil
+}
@@ -0,0 +1,106 @@
+package reconciliation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"payment/internal/core"
+	"payment/internal/metrics"
+)
+
+// The tests share the global metrics and do not run in parallel.
+
+func TestJob_RunOnce(t *testing.T) {
+	finishedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                  string
+		report                core.ReconciliationReport
+		err                   error
+		expectedResult        string
+		expectedDiscrepancies float64
+		expectedLogs          []string
+	}{
+		{
+			name:                  "balanced",
+			report:                core.ReconciliationReport{ID: 1, AccountCount: 3, FinishedAt: finishedAt},
+			expectedResult:        metrics.ReconciliationResultOK,
+			expectedDiscrepancies: 0,
+			expectedLogs:          []string{"Balance reconciliation completed"},
+		},
+		{
+			name: "discrepancies",
+			report: core.ReconciliationReport{
+				ID:           2,
+				AccountCount: 3,
+				FinishedAt:   finishedAt,
+				Discrepancies: []core.BalanceCheck{
+					{AccountID: 7, IBAN: "FR76", OpeningBalanceCents: 1000, TransactionsCents: -200, BalanceCents: 900},
+				},
+			},
+			expectedResult:        metrics.ReconciliationResultDiscrepancies,
+			expectedDiscrepancies: 1,
+			expectedLogs: []string{
+				"Balance does not match ledger",
+				"account_id=7",
+				"discrepancy_cents=100",
+				"Balance reconciliation completed",
+			},
+		},
+		{
+			name:                  "failure",
+			err:                   errors.New("database is locked"),
+			expectedResult:        metrics.ReconciliationResultError,
+			expectedDiscrepancies: 1, // left from the previous run
+			expectedLogs:          []string{"Balance reconciliation failed", "database is locked"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			reconciler := NewMockReconciler(ctrl)
+			reconciler.EXPECT().Reconcile(gomock.Any()).Return(tt.report, tt.err)
+
+			var logs bytes.Buffer
+			job := NewJob(reconciler, slog.New(slog.NewTextHandler(&logs, nil)), Config{})
+
+			runs := testutil.ToFloat64(metrics.ReconciliationRuns.WithLabelValues(tt.expectedResult))
+
+			report, err := job.RunOnce(context.Background())
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.report, report)
+				require.Equal(t, float64(finishedAt.Unix()), testutil.ToFloat64(metrics.ReconciliationLastSuccess))
+			}
+
+			require.Equal(t, runs+1, testutil.ToFloat64(metrics.ReconciliationRuns.WithLabelValues(tt.expectedResult)))
+			require.Equal(t, tt.expectedDiscrepancies, testutil.ToFloat64(metrics.ReconciliationDiscrepancies))
+			for _, expected := range tt.expectedLogs {
+				require.Contains(t, logs.String(), expected)
+			}
+		})
+	}
+}
+
+func TestJob_Run_Disabled(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Without an interval, Run returns at once and never reconciles.
+	NewJob(NewMockReconciler(ctrl), slog.Default(), Config{}).Run(context.Background())
+}
@@ -0,0 +1,7 @@
+package sanctions
+
+type Config struct {
+	ListPath  string  `envconfig:"SANCTIONS_LIST_PATH"`                      // CSV or XML export, screening is disabled when empty
+	Threshold float64 `envconfig:"SANCTIONS_MATCH_THRESHOLD" default:"0.92"` // Minimum name similarity (0-1) reported as a hit
+	Action    string  `envconfig:"SANCTIONS_ACTION" default:"block"`         // "block" or "review"
+}
@@ -0,0 +1,132 @@
+package sanctions
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Entry struct {
+	Name string
+	IBAN string
+}
+
+// LoadList reads a watchlist export. CSV files need a header row with a name
+// column ("name", or "NameAlias_WholeName" in EU consolidated list exports)
+// and may have an "iban" column. XML files are read as EU consolidated list
+// exports, one entry per nameAlias.
+func LoadList(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sanctions list: %w", err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return parseCSV(file)
+	case ".xml":
+		return parseXML(file)
+	default:
+		return nil, fmt.Errorf("unsupported sanctions list format %q", filepath.Ext(path))
+	}
+}
+
+func parseCSV(r io.Reader) ([]Entry, error) {
+	buffered := bufio.NewReader(r)
+
+	// EU exports are semicolon separated, hand-made lists usually are not.
+	firstLine, err := buffered.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read sanctions list header: %w", err)
+	}
+
+	reader := csv.NewReader(io.MultiReader(strings.NewReader(firstLine), buffered))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sanctions list header: %w", err)
+	}
+
+	nameColumn, ibanColumn := -1, -1
+	for i, column := range header {
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case "name", "wholename", "namealias_wholename", "naal_wholename":
+			nameColumn = i
+		case "iban":
+			ibanColumn = i
+		}
+	}
+	if nameColumn < 0 && ibanColumn < 0 {
+		return nil, errors.New("sanctions list has neither a name nor an iban column")
+	}
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sanctions list: %w", err)
+		}
+
+		entry := Entry{
+			Name: column(record, nameColumn),
+			IBAN: column(record, ibanColumn),
+		}
+		if entry.Name == "" && entry.IBAN == "" {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func column(record []string, index int) string {
+	if index < 0 || index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
+
+func parseXML(r io.Reader) ([]Entry, error) {
+	decoder := xml.NewDecoder(r)
+
+	var entries []Entry
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sanctions list: %w", err)
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok || element.Name.Local != "nameAlias" {
+			continue
+		}
+
+		for _, attr := range element.Attr {
+			if attr.Name.Local == "wholeName" && strings.TrimSpace(attr.Value) != "" {
+				entries = append(entries, Entry{Name: strings.TrimSpace(attr.Value)})
+			}
+		}
+	}
+
+	return entries, nil
+}
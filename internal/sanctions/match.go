@@ -0,0 +1,108 @@
+package sanctions
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizeName lowercases, strips accents and punctuation and sorts the
+// tokens, so that "DOE, John" and "John Doé" compare equal.
+func normalizeName(name string) string {
+	stripAccents := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	if stripped, _, err := transform.String(stripAccents, name); err == nil {
+		name = stripped
+	}
+
+	tokens := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	slices.Sort(tokens)
+
+	return strings.Join(tokens, " ")
+}
+
+func normalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(iban), ""))
+}
+
+// maxJaroWinkler is an upper bound of jaroWinkler for strings of the given
+// lengths, used to skip comparisons that cannot reach the threshold.
+func maxJaroWinkler(len1, len2 int) float64 {
+	if len1 == 0 || len2 == 0 {
+		return 0
+	}
+
+	shorter, longer := float64(min(len1, len2)), float64(max(len1, len2))
+	jaro := (2 + shorter/longer) / 3
+
+	return jaro + 0.4*(1-jaro)
+}
+
+func jaroWinkler(s1, s2 string) float64 {
+	r1, r2 := []rune(s1), []rune(s2)
+
+	jaro := jaroSimilarity(r1, r2)
+
+	prefix := 0
+	for prefix < min(len(r1), len(r2), 4) && r1[prefix] == r2[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+func jaroSimilarity(r1, r2 []rune) float64 {
+	if len(r1) == 0 && len(r2) == 0 {
+		return 1
+	}
+	if len(r1) == 0 || len(r2) == 0 {
+		return 0
+	}
+
+	window := max(max(len(r1), len(r2))/2-1, 0)
+
+	matched1 := make([]bool, len(r1))
+	matched2 := make([]bool, len(r2))
+
+	matches := 0
+	for i := range r1 {
+		start := max(0, i-window)
+		end := min(len(r2), i+window+1)
+
+		for j := start; j < end; j++ {
+			if matched2[j] || r1[i] != r2[j] {
+				continue
+			}
+			matched1[i], matched2[j] = true, true
+			matches++
+			break
+		}
+	}
+
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range r1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[j] {
+			j++
+		}
+		if r1[i] != r2[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	return (m/float64(len(r1)) + m/float64(len(r2)) + (m-float64(transpositions)/2)/m) / 3
+}
@@ -0,0 +1,58 @@
+package sanctions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "lowercases", input: "ACME", expected: "acme"},
+		{name: "strips_accents", input: "Tänk Ögon", expected: "ogon tank"},
+		{name: "sorts_tokens", input: "Doe, John", expected: "doe john"},
+		{name: "drops_punctuation", input: "Al-Tikriti (Saddam)", expected: "al saddam tikriti"},
+		{name: "only_punctuation", input: " -- ", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, normalizeName(tt.input))
+		})
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		s1       string
+		s2       string
+		expected float64
+	}{
+		{name: "identical", s1: "martha", s2: "martha", expected: 1},
+		{name: "transposition", s1: "martha", s2: "marhta", expected: 0.961},
+		{name: "common_prefix", s1: "dwayne", s2: "duane", expected: 0.84},
+		{name: "nothing_in_common", s1: "abc", s2: "xyz", expected: 0},
+		{name: "empty", s1: "", s2: "abc", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			score := jaroWinkler(tt.s1, tt.s2)
+			require.InDelta(t, tt.expected, score, 0.001)
+			require.LessOrEqual(t, score, maxJaroWinkler(len(tt.s1), len(tt.s2))+1e-9)
+		})
+	}
+}
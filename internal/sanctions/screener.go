@@ -0,0 +1,109 @@
+package sanctions
+
+import (
+	"context"
+	"fmt"
+
+	"payment/internal/core"
+)
+
+type listEntry struct {
+	name       string
+	normalized string
+}
+
+type Screener struct {
+	entries   []listEntry
+	ibans     map[string]string
+	threshold float64
+	decision  core.Decision
+}
+
+func NewScreener(config Config) (Screener, error) {
+	var entries []Entry
+	if config.ListPath != "" {
+		var err error
+		entries, err = LoadList(config.ListPath)
+		if err != nil {
+			return Screener{}, err
+		}
+	}
+
+	return newScreener(entries, config)
+}
+
+func newScreener(entries []Entry, config Config) (Screener, error) {
+	decision := core.Decision(config.Action)
+	if decision != core.DecisionBlock && decision != core.DecisionReview {
+		return Screener{}, fmt.Errorf("invalid sanctions action %q, expected %q or %q", config.Action, core.DecisionBlock, core.DecisionReview)
+	}
+
+	screener := Screener{
+		ibans:     make(map[string]string),
+		threshold: config.Threshold,
+		decision:  decision,
+	}
+
+	for _, entry := range entries {
+		if entry.IBAN != "" {
+			screener.ibans[normalizeIBAN(entry.IBAN)] = entry.Name
+		}
+		if normalized := normalizeName(entry.Name); normalized != "" {
+			screener.entries = append(screener.entries, listEntry{name: entry.Name, normalized: normalized})
+		}
+	}
+
+	return screener, nil
+}
+
+func (s Screener) Size() int {
+	return len(s.entries) + len(s.ibans)
+}
+
+func (s Screener) Screen(ctx context.Context, transfers []core.Transfer) (core.ScreeningResult, error) {
+	var hits []core.SanctionsHit
+
+	for i, transfer := range transfers {
+		if err := ctx.Err(); err != nil {
+			return core.ScreeningResult{}, err
+		}
+
+		if name, ok := s.ibans[normalizeIBAN(transfer.CounterpartyIBAN)]; ok {
+			hits = append(hits, core.SanctionsHit{TransferIndex: i, MatchedOn: "iban", ListEntry: name, Score: 1})
+			continue
+		}
+
+		if entry, score, ok := s.matchName(transfer.CounterpartyName); ok {
+			hits = append(hits, core.SanctionsHit{TransferIndex: i, MatchedOn: "name", ListEntry: entry.name, Score: score})
+		}
+	}
+
+	if len(hits) == 0 {
+		return core.ScreeningResult{Decision: core.DecisionAllow}, nil
+	}
+
+	return core.ScreeningResult{Decision: s.decision, Hits: hits}, nil
+}
+
+func (s Screener) matchName(name string) (listEntry, float64, bool) {
+	normalized := normalizeName(name)
+	if normalized == "" {
+		return listEntry{}, 0, false
+	}
+
+	var (
+		best      listEntry
+		bestScore float64
+	)
+	for _, entry := range s.entries {
+		if maxJaroWinkler(len(normalized), len(entry.normalized)) < s.threshold {
+			continue
+		}
+
+		if score := jaroWinkler(normalized, entry.normalized); score > bestScore {
+			best, bestScore = entry, score
+		}
+	}
+
+	return best, bestScore, bestScore >= s.threshold
+}
@@ -0,0 +1,148 @@
+package sanctions
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"payment/internal/core"
+)
+
+func TestLoadList(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		file          string
+		expected      []Entry
+		expectedError bool
+	}{
+		{
+			name: "eu_csv_export",
+			file: "eu_consolidated.csv",
+			expected: []Entry{
+				{Name: "Saddam Hussein Al-Tikriti"},
+				{Name: "Abu Ali"},
+				{Name: "Tänk Ögon Trading GmbH"},
+			},
+		},
+		{
+			name: "eu_xml_export",
+			file: "eu_consolidated.xml",
+			expected: []Entry{
+				{Name: "Saddam Hussein Al-Tikriti"},
+				{Name: "Abu Ali"},
+				{Name: "Tänk Ögon Trading GmbH"},
+			},
+		},
+		{
+			name: "csv_with_iban",
+			file: "internal.csv",
+			expected: []Entry{
+				{Name: "Evil Corp Holdings", IBAN: "DE00 1234 5678 9012 3456 78"},
+			},
+		},
+		{
+			name:          "missing_file",
+			file:          "missing.csv",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			entries, err := LoadList(filepath.Join("testdata", tt.file))
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, entries)
+		})
+	}
+}
+
+func TestScreener_Screen(t *testing.T) {
+	t.Parallel()
+
+	entries := []Entry{
+		{Name: "Saddam Hussein Al-Tikriti"},
+		{Name: "Tänk Ögon Trading GmbH"},
+		{Name: "Evil Corp Holdings", IBAN: "DE00 1234 5678 9012 3456 78"},
+	}
+
+	tests := []struct {
+		name         string
+		action       string
+		counterparty core.Transfer
+		expected     core.ScreeningResult
+	}{
+		{
+			name:         "unrelated_name_is_allowed",
+			action:       "block",
+			counterparty: core.Transfer{CounterpartyName: "Bugs Bunny", CounterpartyIBAN: "FR0010009380540930414023042"},
+			expected:     core.ScreeningResult{Decision: core.DecisionAllow},
+		},
+		{
+			name:         "reordered_unaccented_name_is_blocked",
+			action:       "block",
+			counterparty: core.Transfer{CounterpartyName: "TANK OGON TRADING GMBH", CounterpartyIBAN: "FR0010009380540930414023042"},
+			expected: core.ScreeningResult{
+				Decision: core.DecisionBlock,
+				Hits:     []core.SanctionsHit{{MatchedOn: "name", ListEntry: "Tänk Ögon Trading GmbH", Score: 1}},
+			},
+		},
+		{
+			name:         "iban_match_is_held_for_review",
+			action:       "review",
+			counterparty: core.Transfer{CounterpartyName: "Totally Legit Ltd", CounterpartyIBAN: "de00123456789012345678"},
+			expected: core.ScreeningResult{
+				Decision: core.DecisionReview,
+				Hits:     []core.SanctionsHit{{MatchedOn: "iban", ListEntry: "Evil Corp Holdings", Score: 1}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			screener, err := newScreener(entries, Config{Threshold: 0.92, Action: tt.action})
+			require.NoError(t, err)
+
+			result, err := screener.Screen(context.Background(), []core.Transfer{tt.counterparty})
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestScreener_Screen_FuzzyName(t *testing.T) {
+	t.Parallel()
+
+	screener, err := newScreener([]Entry{{Name: "Saddam Hussein Al-Tikriti"}}, Config{Threshold: 0.92, Action: "block"})
+	require.NoError(t, err)
+
+	result, err := screener.Screen(context.Background(), []core.Transfer{
+		{CounterpartyName: "Sadam Hussein al Tikriti"},
+		{CounterpartyName: "Hussein Bolt"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, core.DecisionBlock, result.Decision)
+	require.Len(t, result.Hits, 1)
+	require.Equal(t, 0, result.Hits[0].TransferIndex)
+	require.GreaterOrEqual(t, result.Hits[0].Score, 0.92)
+}
+
+func TestNewScreener_InvalidAction(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewScreener(Config{Threshold: 0.92, Action: "ignore"})
+	require.Error(t, err)
+}
@@ -5,49 +5,219 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"go.opentelemetry.io/otel"
 
 	"payment/internal/core"
+	"payment/internal/metrics"
+	"payment/internal/telemetry"
 )
 
+var tracer = otel.Tracer("payment/internal/sqlite")
+
 type AccountStore struct {
-	db *sql.DB
-	tx *sql.Tx
+	db          *sql.DB
+	tx          *sql.Tx
+	retryPolicy RetryPolicy
+	// depth counts the savepoints open on tx.
+	depth int
 }
 
 func NewAccountStore(db *sql.DB) AccountStore {
 	return AccountStore{
-		db: db,
+		db:          db,
+		retryPolicy: DefaultRetryPolicy,
 	}
 }
 
+// WithRetryPolicy returns a copy of the store whose Atomic retries transient
+// errors according to policy.
+func (s AccountStore) WithRetryPolicy(policy RetryPolicy) AccountStore {
+	s.retryPolicy = policy
+	return s
+}
+
 func (s AccountStore) GetAccountByID(ctx context.Context, iban string, bic string) (core.Account, error) {
 	if s.tx == nil {
 		return core.Account{}, errors.New("GetAccountByID must be called within Atomic transaction")
 	}
 
 	query := `
-			SELECT id, organization_name, balance_cents, iban, bic
+			SELECT id, organization_name, organization_id, balance_cents, iban, bic, currency, frozen, overdraft_limit_cents, pricing_plan, version, ` + heldCentsColumn + `
 			FROM bank_accounts
 			WHERE iban = ? AND bic = ?
 		`
 
+	account, err := scanAccount(s.tx.QueryRowContext(ctx, query, formatHoldTime(time.Now()), iban, bic))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return core.Account{}, core.ErrAccountNotFound
+		}
+
+		return core.Account{}, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	return account, nil
+}
+
+func (s AccountStore) CreateAccount(ctx context.Context, account core.Account) (int64, error) {
+	if s.tx == nil {
+		return 0, errors.New("CreateAccount must be called within Atomic transaction")
+	}
+
+	currency := account.Currency
+	if currency == "" {
+		currency = core.DefaultCurrency
+	}
+
+	query := `
+		INSERT INTO bank_accounts (organization_name, organization_id, balance_cents, opening_balance_cents, iban, bic, currency, frozen, overdraft_limit_cents, pricing_plan)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := s.tx.ExecContext(ctx, query,
+		account.OrganizationName,
+		account.OrganizationID,
+		account.BalanceCents,
+		account.BalanceCents,
+		account.IBAN,
+		account.BIC,
+		currency,
+		account.Frozen,
+		account.OverdraftLimitCents,
+		account.PricingPlan,
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, core.ErrAccountExists
+		}
+
+		return 0, fmt.Errorf("failed to insert account: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get account id: %w", err)
+	}
+
+	return id, nil
+}
+
+func (s AccountStore) ListAccounts(ctx context.Context) ([]core.Account, error) {
+	if s.tx == nil {
+		return nil, errors.New("ListAccounts must be called within Atomic transaction")
+	}
+
+	rows, err := s.tx.QueryContext(ctx, `
+		SELECT id, organization_name, organization_id, balance_cents, iban, bic, currency, frozen, overdraft_limit_cents, pricing_plan, version, `+heldCentsColumn+`
+		FROM bank_accounts
+		ORDER BY id
+	`, formatHoldTime(time.Now()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []core.Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+func scanAccount(row interface{ Scan(dest ...any) error }) (core.Account, error) {
 	var account core.Account
-	err := s.tx.QueryRowContext(ctx, query, iban, bic).Scan(
+	err := row.Scan(
 		&account.ID,
 		&account.OrganizationName,
+		&account.OrganizationID,
 		&account.BalanceCents,
 		&account.IBAN,
 		&account.BIC,
+		&account.Currency,
+		&account.Frozen,
+		&account.OverdraftLimitCents,
+		&account.PricingPlan,
+		&account.Version,
+		&account.HeldCents,
 	)
+	return account, err
+}
+
+func (s AccountStore) SetAccountFrozen(ctx context.Context, accountID int64, frozen bool) error {
+	if s.tx == nil {
+		return errors.New("SetAccountFrozen must be called within Atomic transaction")
+	}
+
+	result, err := s.tx.ExecContext(ctx, "UPDATE bank_accounts SET frozen = ? WHERE id = ?", frozen, accountID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return core.Account{}, core.ErrAccountNotFound
-		}
+		return fmt.Errorf("failed to execute update: %w", err)
+	}
 
-		return core.Account{}, fmt.Errorf("failed to get account: %w", err)
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no rows updated for account ID %d", accountID)
 	}
 
-	return account, nil
+	return nil
+}
+
+func (s AccountStore) SetOverdraftLimit(ctx context.Context, accountID int64, limitCents int64) error {
+	if s.tx == nil {
+		return errors.New("SetOverdraftLimit must be called within Atomic transaction")
+	}
+
+	result, err := s.tx.ExecContext(ctx, "UPDATE bank_accounts SET overdraft_limit_cents = ? WHERE id = ?", limitCents, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to execute update: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no rows updated for account ID %d", accountID)
+	}
+
+	return nil
+}
+
+func (s AccountStore) SetPricingPlan(ctx context.Context, accountID int64, plan string) error {
+	if s.tx == nil {
+		return errors.New("SetPricingPlan must be called within Atomic transaction")
+	}
+
+	result, err := s.tx.ExecContext(ctx, "UPDATE bank_accounts SET pricing_plan = ? WHERE id = ?", plan, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to execute update: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no rows updated for account ID %d", accountID)
+	}
+
+	return nil
 }
 
 func (s AccountStore) UpdateBalance(ctx context.Context, account core.Account) error {
@@ -57,11 +227,11 @@ func (s AccountStore) UpdateBalance(ctx context.Context, account core.Account) e
 
 	query := `
 		UPDATE bank_accounts
-		SET balance_cents = ?
-		WHERE id = ?
+		SET balance_cents = ?, version = version + 1
+		WHERE id = ? AND version = ?
 	`
 
-	result, err := s.tx.ExecContext(ctx, query, account.BalanceCents, account.ID)
+	result, err := s.tx.ExecContext(ctx, query, account.BalanceCents, account.ID, account.Version)
 	if err != nil {
 		return fmt.Errorf("failed to execute update: %w", err)
 	}
@@ -71,18 +241,98 @@ func (s AccountStore) UpdateBalance(ctx context.Context, account core.Account) e
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("no rows updated for account ID %d", account.ID)
+		return s.updateBalanceMiss(ctx, account)
 	}
 
 	return nil
 }
 
+// updateBalanceMiss tells why UpdateBalance updated no row: the account does
+// not exist, or it is no longer at the version that was read.
+func (s AccountStore) updateBalanceMiss(ctx context.Context, account core.Account) error {
+	var exists bool
+	err := s.tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM bank_accounts WHERE id = ?)`, account.ID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to query account: %w", err)
+	}
+	if !exists {
+		return core.ErrAccountNotFound
+	}
+
+	return &core.VersionConflictError{AccountID: account.ID, Version: account.Version}
+}
+
+// transferChunkSize bounds the rows per INSERT so that the bound parameters
+// stay below SQLITE_MAX_VARIABLE_NUMBER, which is 999 on older builds.
+const transferChunkSize = 100
+
+const transferColumns = 8
+
 func (s AccountStore) AddTransfers(ctx context.Context, transfers []core.Transfer) error {
 	if s.tx == nil {
 		return errors.New("AddTransfers must be called within Atomic transaction")
 	}
 
-	baseQuery := `
+	for _, transfer := range transfers {
+		if transfer.BankAccountID == 0 {
+			return fmt.Errorf("transfer missing bank_account_id")
+		}
+	}
+
+	// Full chunks share one prepared statement; the remainder gets its own.
+	full := len(transfers) / transferChunkSize * transferChunkSize
+	if full > 0 {
+		if err := s.insertTransfers(ctx, transfers[:full], transferChunkSize); err != nil {
+			return err
+		}
+	}
+
+	if rest := transfers[full:]; len(rest) > 0 {
+		if err := s.insertTransfers(ctx, rest, len(rest)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertTransfers inserts transfers, whose length is a multiple of chunkSize,
+// with one prepared statement of chunkSize rows.
+func (s AccountStore) insertTransfers(ctx context.Context, transfers []core.Transfer, chunkSize int) error {
+	stmt, err := s.tx.PrepareContext(ctx, insertTransfersQuery(chunkSize))
+	if err != nil {
+		return fmt.Errorf("failed to prepare transfers insert: %w", err)
+	}
+	defer stmt.Close()
+
+	args := make([]interface{}, 0, chunkSize*transferColumns)
+	for start := 0; start < len(transfers); start += chunkSize {
+		args = args[:0]
+		for _, transfer := range transfers[start : start+chunkSize] {
+			args = append(args,
+				transfer.CounterpartyName,
+				transfer.CounterpartyIBAN,
+				transfer.CounterpartyBIC,
+				-transfer.AmountCents,
+				transfer.Currency,
+				transfer.BankAccountID,
+				sql.NullInt64{Int64: transfer.BulkTransferID, Valid: transfer.BulkTransferID != 0},
+				transfer.Description,
+			)
+		}
+
+		if _, err = stmt.ExecContext(ctx, args...); err != nil {
+			return fmt.Errorf("failed to bulk insert transfers: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func insertTransfersQuery(rows int) string {
+	valuePlaceholder := "(?" + strings.Repeat(", ?", transferColumns-1) + ")"
+
+	return `
 		INSERT INTO transactions (
 			counterparty_name,
 			counterparty_iban,
@@ -90,44 +340,71 @@ func (s AccountStore) AddTransfers(ctx context.Context, transfers []core.Transfe
 			amount_cents,
 			amount_currency,
 			bank_account_id,
+			bulk_transfer_id,
 			description
-		) VALUES `
-
-	valuePlaceholder := "(?, ?, ?, ?, ?, ?, ?)"
+		) VALUES ` + valuePlaceholder + strings.Repeat(", "+valuePlaceholder, rows-1)
+}
 
-	query := baseQuery + valuePlaceholder
-	for i := 1; i < len(transfers); i++ {
-		query += ", " + valuePlaceholder
+// AddTransaction books a single transaction. Unlike AddTransfers, its amount
+// is stored as is.
+func (s AccountStore) AddTransaction(ctx context.Context, transaction core.Transaction) error {
+	if s.tx == nil {
+		return errors.New("AddTransaction must be called within Atomic transaction")
 	}
 
-	args := make([]interface{}, 0, len(transfers)*7)
-	for _, transfer := range transfers {
-		if transfer.BankAccountID == 0 {
-			return fmt.Errorf("transfer missing bank_account_id")
-		}
-
-		amountCents := -transfer.AmountCents
-
-		args = append(args,
-			transfer.CounterpartyName,
-			transfer.CounterpartyIBAN,
-			transfer.CounterpartyBIC,
-			amountCents,
-			transfer.Currency,
-			transfer.BankAccountID,
-			transfer.Description,
-		)
-	}
+	query := `
+		INSERT INTO transactions (
+			counterparty_name,
+			counterparty_iban,
+			counterparty_bic,
+			amount_cents,
+			amount_currency,
+			bank_account_id,
+			bulk_transfer_id,
+			kind,
+			description
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
 
-	_, err := s.tx.ExecContext(ctx, query, args...)
+	_, err := s.tx.ExecContext(ctx, query,
+		transaction.CounterpartyName,
+		transaction.CounterpartyIBAN,
+		transaction.CounterpartyBIC,
+		transaction.AmountCents,
+		transaction.Currency,
+		transaction.AccountID,
+		sql.NullInt64{Int64: transaction.BulkTransferID, Valid: transaction.BulkTransferID != 0},
+		string(transaction.Kind),
+		transaction.Description,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to bulk insert transfers: %w", err)
+		return fmt.Errorf("failed to insert transaction: %w", err)
 	}
 
 	return nil
 }
 
+// Atomic runs cb in a transaction. When the transaction fails on
+// SQLITE_BUSY, SQLITE_LOCKED or an I/O error, at any point up to and
+// including the commit, it is rolled back and cb is run again from the start
+// after a jittered backoff. Whatever cb wrote to captured variables during the
+// failed run is therefore overwritten or repeated by the next one.
+//
+// Called on the repository passed to a callback, Atomic runs cb within a
+// savepoint of the enclosing transaction instead: an error of cb only undoes
+// what cb did, and is left to the enclosing callback to handle. Nested calls
+// are not retried on their own.
 func (s AccountStore) Atomic(ctx context.Context, cb func(core.AccountRepository) error) error {
+	if s.tx != nil {
+		return s.savepoint(ctx, cb)
+	}
+
+	return s.retry(ctx, func() error {
+		return s.atomic(ctx, cb)
+	})
+}
+
+func (s AccountStore) atomic(ctx context.Context, cb func(core.AccountRepository) error) error {
 	// SQLite doesn't support SELECT FOR UPDATE, but we use BEGIN IMMEDIATE instead
 	// (configured via _txlock=immediate in DSN)
 	//
@@ -137,9 +414,11 @@ func (s AccountStore) Atomic(ctx context.Context, cb func(core.AccountRepository
 	// - No race window between SELECT and UPDATE
 	//
 	// This is NOT BEGIN EXCLUSIVE (which would block all reads unnecessarily)
+	lockStart := time.Now()
 	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelDefault,
 	})
+	metrics.SQLiteLockWait.Observe(time.Since(lockStart).Seconds())
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -155,9 +434,40 @@ func (s AccountStore) Atomic(ctx context.Context, cb func(core.AccountRepository
 		return err
 	}
 
-	if err = tx.Commit(); err != nil {
+	_, span := tracer.Start(ctx, "sqlite.Commit")
+	err = tx.Commit()
+	telemetry.End(span, err)
+	if err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
+
+func (s AccountStore) savepoint(ctx context.Context, cb func(core.AccountRepository) error) error {
+	// Savepoints of sibling calls are released before the next one is
+	// created, so the depth is enough to keep names unique.
+	name := fmt.Sprintf("atomic_%d", s.depth)
+	if _, err := s.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	nested := AccountStore{
+		tx:    s.tx,
+		depth: s.depth + 1,
+	}
+
+	if err := cb(nested); err != nil {
+		// ROLLBACK TO leaves the savepoint open, so it is released as well.
+		if _, rbErr := s.tx.ExecContext(ctx, "ROLLBACK TO "+name+"; RELEASE "+name); rbErr != nil {
+			return fmt.Errorf("transaction error: %w, rollback error: %w", err, rbErr)
+		}
+		return err
+	}
+
+	if _, err := s.tx.ExecContext(ctx, "RELEASE "+name); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+
+	return nil
+}
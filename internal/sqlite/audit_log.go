@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"payment/internal/core"
+)
+
+func (s AccountStore) AppendAuditRecord(ctx context.Context, record core.AuditRecord) error {
+	if s.tx == nil {
+		return errors.New("AppendAuditRecord must be called within Atomic transaction")
+	}
+
+	// BEGIN IMMEDIATE serializes writers, so the last hash cannot change
+	// between this read and the insert below.
+	var prevHash string
+	err := s.tx.QueryRowContext(ctx, `SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1`).Scan(&prevHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get last audit hash: %w", err)
+	}
+
+	record.CreatedAt = time.Now().UTC()
+	record = record.Seal(prevHash)
+
+	query := `
+		INSERT INTO audit_log (
+			event_type,
+			actor,
+			request_id,
+			bank_account_id,
+			balance_before_cents,
+			balance_after_cents,
+			amount_cents,
+			transfer_count,
+			reason,
+			created_at,
+			prev_hash,
+			hash
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = s.tx.ExecContext(ctx, query,
+		record.EventType,
+		record.Actor,
+		record.RequestID,
+		record.AccountID,
+		record.BalanceBeforeCents,
+		record.BalanceAfterCents,
+		record.AmountCents,
+		record.TransferCount,
+		record.Reason,
+		record.CreatedAt.Format(time.RFC3339Nano),
+		record.PrevHash,
+		record.Hash,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit record: %w", err)
+	}
+
+	return nil
+}
+
+// ForEachAuditRecord streams the audit log in insertion order. It reads outside
+// of Atomic so that verifying the chain does not block writers.
+func (s AccountStore) ForEachAuditRecord(ctx context.Context, fn func(core.AuditRecord) error) error {
+	query := `
+		SELECT id, event_type, actor, request_id, bank_account_id,
+		       balance_before_cents, balance_after_cents, amount_cents,
+		       transfer_count, reason, created_at, prev_hash, hash
+		FROM audit_log
+		ORDER BY id
+	`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			record    core.AuditRecord
+			createdAt string
+		)
+
+		err = rows.Scan(
+			&record.ID,
+			&record.EventType,
+			&record.Actor,
+			&record.RequestID,
+			&record.AccountID,
+			&record.BalanceBeforeCents,
+			&record.BalanceAfterCents,
+			&record.AmountCents,
+			&record.TransferCount,
+			&record.Reason,
+			&createdAt,
+			&record.PrevHash,
+			&record.Hash,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan audit record: %w", err)
+		}
+
+		record.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt)
+		if err != nil {
+			return fmt.Errorf("failed to parse audit record %d timestamp: %w", record.ID, err)
+		}
+
+		if err = fn(record); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate audit log: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,266 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"payment/internal/core"
+)
+
+// counterpartyChunkSize keeps IN lists well below SQLite's bound parameter limit.
+const counterpartyChunkSize = 500
+
+func (s AccountStore) AddBulkTransfer(ctx context.Context, accountID int64, bulkTransfer core.BulkTransfer) (int64, error) {
+	if s.tx == nil {
+		return 0, errors.New("AddBulkTransfer must be called within Atomic transaction")
+	}
+
+	query := `
+		INSERT INTO bulk_transfers (bank_account_id, total_cents, transfer_count, request_id, execution_mode, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	executionMode := bulkTransfer.ExecutionMode
+	if executionMode == "" {
+		executionMode = core.ExecutionModeAllOrNothing
+	}
+
+	result, err := s.tx.ExecContext(ctx, query,
+		accountID,
+		bulkTransfer.TotalAmount(),
+		len(bulkTransfer.Transfers),
+		sql.NullString{String: bulkTransfer.RequestID, Valid: bulkTransfer.RequestID != ""},
+		string(executionMode),
+		time.Now().UTC().Format(time.RFC3339Nano),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert bulk transfer: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get bulk transfer ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (s AccountStore) GetAccountHistory(ctx context.Context, accountID int64, counterpartyIBANs []string) (core.AccountHistory, error) {
+	if s.tx == nil {
+		return core.AccountHistory{}, errors.New("GetAccountHistory must be called within Atomic transaction")
+	}
+
+	history := core.AccountHistory{
+		KnownCounterparties: make(map[string]bool),
+	}
+
+	// The batches are those the account paid transfers of, whichever account
+	// submitted them, and their totals the part the account paid.
+	query := `
+		SELECT COUNT(*), COALESCE(CAST(AVG(total_cents) AS INTEGER), 0)
+		FROM (
+			SELECT -SUM(amount_cents) AS total_cents
+			FROM transactions
+			WHERE bank_account_id = ? AND kind = 'transfer' AND bulk_transfer_id IS NOT NULL
+			GROUP BY bulk_transfer_id
+		)
+	`
+
+	err := s.tx.QueryRowContext(ctx, query, accountID).Scan(&history.BatchCount, &history.AverageBatchTotalCents)
+	if err != nil {
+		return core.AccountHistory{}, fmt.Errorf("failed to get batch history: %w", err)
+	}
+
+	for start := 0; start < len(counterpartyIBANs); start += counterpartyChunkSize {
+		chunk := counterpartyIBANs[start:min(start+counterpartyChunkSize, len(counterpartyIBANs))]
+		if err = s.addKnownCounterparties(ctx, accountID, chunk, history.KnownCounterparties); err != nil {
+			return core.AccountHistory{}, err
+		}
+	}
+
+	return history, nil
+}
+
+func (s AccountStore) CountTransfersSince(ctx context.Context, accountID int64, since time.Time) (int, error) {
+	if s.tx == nil {
+		return 0, errors.New("CountTransfersSince must be called within Atomic transaction")
+	}
+
+	// created_at is RFC 3339 with a variable number of fractional digits:
+	// without them and the zone, since sorts before every time in its second.
+	query := `
+		SELECT COUNT(*)
+		FROM transactions t
+		JOIN bulk_transfers bt ON bt.id = t.bulk_transfer_id
+		WHERE t.bank_account_id = ? AND t.kind = 'transfer' AND bt.created_at >= ?
+	`
+
+	var count int
+	err := s.tx.QueryRowContext(ctx, query, accountID, since.UTC().Format("2006-01-02T15:04:05")).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count transfers: %w", err)
+	}
+
+	return count, nil
+}
+
+func (s AccountStore) addKnownCounterparties(ctx context.Context, accountID int64, ibans []string, known map[string]bool) error {
+	query := `
+		SELECT DISTINCT counterparty_iban
+		FROM transactions
+		WHERE bank_account_id = ? AND counterparty_iban IN (?` + strings.Repeat(", ?", len(ibans)-1) + `)
+	`
+
+	args := make([]interface{}, 0, len(ibans)+1)
+	args = append(args, accountID)
+	for _, iban := range ibans {
+		args = append(args, iban)
+	}
+
+	rows, err := s.tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query known counterparties: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var iban string
+		if err = rows.Scan(&iban); err != nil {
+			return fmt.Errorf("failed to scan known counterparty: %w", err)
+		}
+		known[iban] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate known counterparties: %w", err)
+	}
+
+	return nil
+}
+
+const bulkTransferRecordQuery = `
+	SELECT bt.id, bt.bank_account_id, a.iban, a.bic, bt.execution_mode, bt.total_cents, bt.transfer_count,
+		COALESCE(bt.request_id, ''), bt.created_at
+	FROM bulk_transfers bt
+	JOIN bank_accounts a ON a.id = bt.bank_account_id
+`
+
+func (s AccountStore) GetBulkTransfer(ctx context.Context, bulkTransferID int64) (core.BulkTransferRecord, error) {
+	if s.tx == nil {
+		return core.BulkTransferRecord{}, errors.New("GetBulkTransfer must be called within Atomic transaction")
+	}
+
+	record, err := scanBulkTransferRecord(s.tx.QueryRowContext(ctx, bulkTransferRecordQuery+"WHERE bt.id = ?", bulkTransferID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return core.BulkTransferRecord{}, core.ErrBulkTransferNotFound
+		}
+
+		return core.BulkTransferRecord{}, fmt.Errorf("failed to get bulk transfer: %w", err)
+	}
+
+	return record, nil
+}
+
+func (s AccountStore) ListBulkTransfers(ctx context.Context, accountID int64) ([]core.BulkTransferRecord, error) {
+	if s.tx == nil {
+		return nil, errors.New("ListBulkTransfers must be called within Atomic transaction")
+	}
+
+	rows, err := s.tx.QueryContext(ctx, bulkTransferRecordQuery+"WHERE bt.bank_account_id = ? ORDER BY bt.id", accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bulk transfers: %w", err)
+	}
+	defer rows.Close()
+
+	var records []core.BulkTransferRecord
+	for rows.Next() {
+		record, err := scanBulkTransferRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bulk transfer: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate bulk transfers: %w", err)
+	}
+
+	return records, nil
+}
+
+func scanBulkTransferRecord(row interface{ Scan(dest ...any) error }) (core.BulkTransferRecord, error) {
+	var record core.BulkTransferRecord
+	var createdAt string
+	err := row.Scan(
+		&record.ID,
+		&record.AccountID,
+		&record.OrganizationIBAN,
+		&record.OrganizationBIC,
+		&record.ExecutionMode,
+		&record.TotalCents,
+		&record.TransferCount,
+		&record.RequestID,
+		&createdAt,
+	)
+	if err != nil {
+		return core.BulkTransferRecord{}, err
+	}
+
+	record.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return core.BulkTransferRecord{}, fmt.Errorf("failed to parse bulk transfer created_at: %w", err)
+	}
+
+	return record, nil
+}
+
+func (s AccountStore) ListTransactions(ctx context.Context, accountID int64, query core.TransactionQuery) ([]core.Transaction, error) {
+	if s.tx == nil {
+		return nil, errors.New("ListTransactions must be called within Atomic transaction")
+	}
+
+	rows, err := s.tx.QueryContext(ctx, `
+		SELECT id, bank_account_id, COALESCE(bulk_transfer_id, 0), kind, counterparty_name, counterparty_iban,
+			counterparty_bic, amount_cents, amount_currency, COALESCE(description, '')
+		FROM transactions
+		WHERE bank_account_id = ? AND id > ? AND (? = 0 OR bulk_transfer_id = ?)
+		ORDER BY id
+		LIMIT ?
+	`, accountID, query.AfterID, query.BulkTransferID, query.BulkTransferID, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transactions: %w", err)
+	}
+	defer rows.Close()
+
+	transactions := make([]core.Transaction, 0, query.Limit)
+	for rows.Next() {
+		var transaction core.Transaction
+		err = rows.Scan(
+			&transaction.ID,
+			&transaction.AccountID,
+			&transaction.BulkTransferID,
+			&transaction.Kind,
+			&transaction.CounterpartyName,
+			&transaction.CounterpartyIBAN,
+			&transaction.CounterpartyBIC,
+			&transaction.AmountCents,
+			&transaction.Currency,
+			&transaction.Description,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate transactions: %w", err)
+	}
+
+	return transactions, nil
+}
@@ -13,8 +13,16 @@ type Client struct {
 }
 
 func NewClient(config Config) (*Client, error) {
-	dsn := buildDSN(config)
+	return newClient(config, buildDSN(config))
+}
+
+// NewReadOnlyClient opens the database for reads only. Its transactions are
+// deferred, so with WAL they read a snapshot without blocking writers.
+func NewReadOnlyClient(config Config) (*Client, error) {
+	return newClient(config, buildReadOnlyDSN(config))
+}
 
+func newClient(config Config, dsn string) (*Client, error) {
 	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
@@ -52,6 +60,11 @@ func buildDSN(config Config) string {
 	return dsn
 }
 
+func buildReadOnlyDSN(config Config) string {
+	return fmt.Sprintf("file:%s?mode=ro&_busy_timeout=%d&_txlock=deferred",
+		config.DatabasePath, int(config.BusyTimeout.Milliseconds()))
+}
+
 func (c *Client) DB() *sql.DB {
 	return c.db
 }
@@ -12,4 +12,16 @@ type Config struct {
 	ConnMaxIdleTime time.Duration `envconfig:"CONN_MAX_IDLE_TIME" default:"1m"`
 	BusyTimeout     time.Duration `envconfig:"BUSY_TIMEOUT" default:"30s"` // Time to wait for lock acquisition
 	EnableWAL       bool          `envconfig:"ENABLE_WAL" default:"true"`  // Allows concurrent reads while writing
+
+	TxMaxRetries     int           `envconfig:"TX_MAX_RETRIES" default:"5"`
+	TxRetryBaseDelay time.Duration `envconfig:"TX_RETRY_BASE_DELAY" default:"10ms"`
+	TxRetryMaxDelay  time.Duration `envconfig:"TX_RETRY_MAX_DELAY" default:"1s"`
+}
+
+func (c Config) RetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: c.TxMaxRetries,
+		BaseDelay:  c.TxRetryBaseDelay,
+		MaxDelay:   c.TxRetryMaxDelay,
+	}
 }
@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+func (c *Client) Ping(ctx context.Context) error {
+	if err := c.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return nil
+}
+
+// CheckSchema fails when the database is not at the version of the embedded
+// migrations, e.g. while a newer release is still migrating it.
+func (c *Client) CheckSchema(ctx context.Context) error {
+	expected, err := LatestSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	current, err := c.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if current != expected {
+		return fmt.Errorf("schema version is %d, expected %d", current, expected)
+	}
+
+	return nil
+}
+
+// CheckJournalMode fails when WAL is enabled in the config but not active on
+// the database.
+func (c *Client) CheckJournalMode(ctx context.Context) error {
+	var mode string
+	if err := c.db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&mode); err != nil {
+		return fmt.Errorf("failed to read journal mode: %w", err)
+	}
+
+	if c.config.EnableWAL && !strings.EqualFold(mode, "wal") {
+		return fmt.Errorf("journal mode is %q, expected \"wal\"", mode)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"payment/internal/core"
+)
+
+// holdTimeLayout has a fixed width so that stored timestamps sort like the
+// times they represent.
+const holdTimeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+func formatHoldTime(t time.Time) string {
+	return t.UTC().Format(holdTimeLayout)
+}
+
+// heldCentsColumn sums the pending holds of bank_accounts that have not
+// expired at the time bound to its parameter.
+const heldCentsColumn = `COALESCE((
+	SELECT SUM(h.amount_cents)
+	FROM holds h
+	WHERE h.bank_account_id = bank_accounts.id AND h.status = 'pending' AND h.expires_at > ?1
+), 0)`
+
+func (s AccountStore) AddHold(ctx context.Context, hold core.Hold) (int64, error) {
+	if s.tx == nil {
+		return 0, errors.New("AddHold must be called within Atomic transaction")
+	}
+
+	query := `
+		INSERT INTO holds (bank_account_id, amount_cents, reason, status, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := s.tx.ExecContext(ctx, query,
+		hold.AccountID,
+		hold.AmountCents,
+		hold.Reason,
+		string(hold.Status),
+		formatHoldTime(hold.CreatedAt),
+		formatHoldTime(hold.ExpiresAt),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert hold: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get hold ID: %w", err)
+	}
+
+	return id, nil
+}
+
+const holdQuery = `
+	SELECT id, bank_account_id, amount_cents, reason, status, created_at, expires_at
+	FROM holds
+`
+
+func (s AccountStore) GetHold(ctx context.Context, holdID int64) (core.Hold, error) {
+	if s.tx == nil {
+		return core.Hold{}, errors.New("GetHold must be called within Atomic transaction")
+	}
+
+	hold, err := scanHold(s.tx.QueryRowContext(ctx, holdQuery+"WHERE id = ?", holdID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return core.Hold{}, core.ErrHoldNotFound
+		}
+
+		return core.Hold{}, fmt.Errorf("failed to get hold: %w", err)
+	}
+
+	return hold, nil
+}
+
+func (s AccountStore) ListHolds(ctx context.Context, accountID int64) ([]core.Hold, error) {
+	if s.tx == nil {
+		return nil, errors.New("ListHolds must be called within Atomic transaction")
+	}
+
+	rows, err := s.tx.QueryContext(ctx, holdQuery+"WHERE bank_account_id = ? ORDER BY id", accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holds: %w", err)
+	}
+	defer rows.Close()
+
+	var holds []core.Hold
+	for rows.Next() {
+		hold, err := scanHold(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan hold: %w", err)
+		}
+		holds = append(holds, hold)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate holds: %w", err)
+	}
+
+	return holds, nil
+}
+
+func scanHold(row interface{ Scan(dest ...any) error }) (core.Hold, error) {
+	var hold core.Hold
+	var createdAt, expiresAt string
+	err := row.Scan(
+		&hold.ID,
+		&hold.AccountID,
+		&hold.AmountCents,
+		&hold.Reason,
+		&hold.Status,
+		&createdAt,
+		&expiresAt,
+	)
+	if err != nil {
+		return core.Hold{}, err
+	}
+
+	hold.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return core.Hold{}, fmt.Errorf("failed to parse hold created_at: %w", err)
+	}
+
+	hold.ExpiresAt, err = time.Parse(time.RFC3339Nano, expiresAt)
+	if err != nil {
+		return core.Hold{}, fmt.Errorf("failed to parse hold expires_at: %w", err)
+	}
+
+	return hold, nil
+}
+
+func (s AccountStore) SetHoldStatus(ctx context.Context, holdID int64, status core.HoldStatus) error {
+	if s.tx == nil {
+		return errors.New("SetHoldStatus must be called within Atomic transaction")
+	}
+
+	result, err := s.tx.ExecContext(ctx, "UPDATE holds SET status = ? WHERE id = ?", string(status), holdID)
+	if err != nil {
+		return fmt.Errorf("failed to execute update: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return core.ErrHoldNotFound
+	}
+
+	return nil
+}
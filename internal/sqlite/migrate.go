@@ -0,0 +1,107 @@
+package sqlite
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrate applies the embedded migrations that are newer than the database's
+// user_version, each one in its own transaction.
+func (c *Client) Migrate(ctx context.Context) error {
+	latest, err := LatestSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	return c.MigrateTo(ctx, latest)
+}
+
+// MigrateTo applies the embedded migrations that are newer than the database's
+// user_version, up to and including the given version.
+func (c *Client) MigrateTo(ctx context.Context, target int) error {
+	migrations, err := migrationNames()
+	if err != nil {
+		return err
+	}
+
+	current, err := c.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i, name := range migrations {
+		version := i + 1
+		if version <= current || version > target {
+			continue
+		}
+
+		if err = c.applyMigration(ctx, name, version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SchemaVersion returns the version of the last migration applied to the
+// database.
+func (c *Client) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	if err := c.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	return version, nil
+}
+
+// LatestSchemaVersion returns the version the embedded migrations lead to.
+func LatestSchemaVersion() (int, error) {
+	migrations, err := migrationNames()
+	if err != nil {
+		return 0, err
+	}
+
+	return len(migrations), nil
+}
+
+func migrationNames() ([]string, error) {
+	migrations, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	return migrations, nil
+}
+
+func (c *Client) applyMigration(ctx context.Context, name string, version int) error {
+	script, err := migrationFiles.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("failed to read migration %s: %w", name, err)
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %s: %w", name, err)
+	}
+
+	// PRAGMA does not accept bound parameters.
+	statements := string(script) + fmt.Sprintf("\nPRAGMA user_version = %d;", version)
+
+	if _, err = tx.ExecContext(ctx, statements); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("failed to apply migration %s: %w, rollback error: %w", name, err, rbErr)
+		}
+		return fmt.Errorf("failed to apply migration %s: %w", name, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS bank_accounts (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	organization_name TEXT NOT NULL,
+	balance_cents INTEGER NOT NULL DEFAULT 0,
+	iban TEXT NOT NULL,
+	bic TEXT NOT NULL,
+	UNIQUE(iban, bic)
+);
+
+CREATE TABLE IF NOT EXISTS transactions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	counterparty_name TEXT NOT NULL,
+	counterparty_iban TEXT NOT NULL,
+	counterparty_bic TEXT NOT NULL,
+	amount_cents INTEGER NOT NULL,
+	amount_currency TEXT NOT NULL DEFAULT 'EUR',
+	bank_account_id INTEGER NOT NULL,
+	description TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_bank_account
+ON transactions(bank_account_id);
@@ -0,0 +1,27 @@
+CREATE TABLE audit_log (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	event_type TEXT NOT NULL,
+	actor TEXT NOT NULL,
+	request_id TEXT NOT NULL,
+	bank_account_id INTEGER NOT NULL,
+	balance_before_cents INTEGER NOT NULL,
+	balance_after_cents INTEGER NOT NULL,
+	amount_cents INTEGER NOT NULL,
+	transfer_count INTEGER NOT NULL,
+	reason TEXT NOT NULL,
+	created_at TEXT NOT NULL,
+	prev_hash TEXT NOT NULL,
+	hash TEXT NOT NULL UNIQUE
+);
+
+CREATE TRIGGER audit_log_no_update
+BEFORE UPDATE ON audit_log
+BEGIN
+	SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
+
+CREATE TRIGGER audit_log_no_delete
+BEFORE DELETE ON audit_log
+BEGIN
+	SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
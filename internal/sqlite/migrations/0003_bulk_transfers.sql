@@ -0,0 +1,15 @@
+CREATE TABLE bulk_transfers (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	bank_account_id INTEGER NOT NULL,
+	total_cents INTEGER NOT NULL,
+	transfer_count INTEGER NOT NULL,
+	created_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_bulk_transfers_bank_account
+ON bulk_transfers(bank_account_id);
+
+ALTER TABLE transactions ADD COLUMN bulk_transfer_id INTEGER REFERENCES bulk_transfers(id);
+
+CREATE INDEX idx_transactions_counterparty
+ON transactions(bank_account_id, counterparty_iban);
@@ -0,0 +1,4 @@
+ALTER TABLE bulk_transfers ADD COLUMN request_id TEXT;
+
+CREATE INDEX idx_bulk_transfers_request_id
+ON bulk_transfers(request_id);
@@ -0,0 +1 @@
+ALTER TABLE bulk_transfers ADD COLUMN execution_mode TEXT NOT NULL DEFAULT 'all_or_nothing';
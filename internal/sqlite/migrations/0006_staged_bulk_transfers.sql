@@ -0,0 +1,22 @@
+CREATE TABLE staged_bulk_transfers (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	organization_iban TEXT NOT NULL,
+	organization_bic TEXT NOT NULL,
+	execution_mode TEXT NOT NULL,
+	request_id TEXT,
+	created_at TEXT NOT NULL
+);
+
+CREATE TABLE staged_transfers (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	staged_bulk_transfer_id INTEGER NOT NULL REFERENCES staged_bulk_transfers(id),
+	counterparty_name TEXT NOT NULL,
+	counterparty_iban TEXT NOT NULL,
+	counterparty_bic TEXT NOT NULL,
+	amount_cents INTEGER NOT NULL,
+	amount_currency TEXT NOT NULL,
+	description TEXT NOT NULL
+);
+
+CREATE INDEX idx_staged_transfers_bulk_transfer
+ON staged_transfers(staged_bulk_transfer_id, id);
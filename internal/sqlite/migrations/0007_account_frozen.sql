@@ -0,0 +1 @@
+ALTER TABLE bank_accounts ADD COLUMN frozen INTEGER NOT NULL DEFAULT 0;
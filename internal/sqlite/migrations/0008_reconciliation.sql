@@ -0,0 +1,30 @@
+ALTER TABLE bank_accounts ADD COLUMN opening_balance_cents INTEGER NOT NULL DEFAULT 0;
+
+-- Existing balances are taken as matching their ledger: only discrepancies
+-- introduced from now on are reported.
+UPDATE bank_accounts
+SET opening_balance_cents = balance_cents - COALESCE(
+	(SELECT SUM(amount_cents) FROM transactions WHERE transactions.bank_account_id = bank_accounts.id),
+	0
+);
+
+CREATE TABLE reconciliation_reports (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at TEXT NOT NULL,
+	finished_at TEXT NOT NULL,
+	account_count INTEGER NOT NULL,
+	discrepancy_count INTEGER NOT NULL
+);
+
+CREATE TABLE reconciliation_discrepancies (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	report_id INTEGER NOT NULL REFERENCES reconciliation_reports(id),
+	bank_account_id INTEGER NOT NULL,
+	opening_balance_cents INTEGER NOT NULL,
+	transactions_cents INTEGER NOT NULL,
+	balance_cents INTEGER NOT NULL,
+	discrepancy_cents INTEGER NOT NULL
+);
+
+CREATE INDEX idx_reconciliation_discrepancies_report
+ON reconciliation_discrepancies(report_id);
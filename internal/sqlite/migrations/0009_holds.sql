@@ -0,0 +1,14 @@
+CREATE TABLE holds (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	bank_account_id INTEGER NOT NULL REFERENCES bank_accounts(id),
+	amount_cents INTEGER NOT NULL CHECK (amount_cents > 0),
+	reason TEXT NOT NULL,
+	status TEXT NOT NULL,
+	created_at TEXT NOT NULL,
+	-- Fixed-width UTC timestamps, so that pending holds can be compared with
+	-- the current time as strings.
+	expires_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_holds_pending
+ON holds(bank_account_id, expires_at) WHERE status = 'pending';
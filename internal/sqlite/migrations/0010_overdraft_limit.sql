@@ -0,0 +1 @@
+ALTER TABLE bank_accounts ADD COLUMN overdraft_limit_cents INTEGER NOT NULL DEFAULT 0 CHECK (overdraft_limit_cents >= 0);
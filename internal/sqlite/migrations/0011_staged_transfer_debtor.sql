@@ -0,0 +1,2 @@
+ALTER TABLE staged_transfers ADD COLUMN debtor_iban TEXT NOT NULL DEFAULT '';
+ALTER TABLE staged_transfers ADD COLUMN debtor_bic TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+ALTER TABLE bank_accounts ADD COLUMN pricing_plan TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE transactions ADD COLUMN kind TEXT NOT NULL DEFAULT 'transfer';
+
+-- Manual credits are the only transactions booked outside a bulk transfer.
+UPDATE transactions SET kind = 'credit' WHERE bulk_transfer_id IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE bank_accounts ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
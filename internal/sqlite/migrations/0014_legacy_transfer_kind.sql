@@ -0,0 +1,4 @@
+-- 0012_pricing.sql labeled every transaction booked outside a bulk transfer as
+-- a credit, including the transfers booked before bulk transfers were recorded.
+-- Those have a negative amount.
+UPDATE transactions SET kind = 'transfer' WHERE kind = 'credit' AND bulk_transfer_id IS NULL AND amount_cents < 0;
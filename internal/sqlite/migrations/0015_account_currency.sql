@@ -0,0 +1 @@
+ALTER TABLE bank_accounts ADD COLUMN currency TEXT NOT NULL DEFAULT 'EUR';
@@ -0,0 +1 @@
+ALTER TABLE bank_accounts ADD COLUMN organization_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE staged_bulk_transfers ADD COLUMN hold_id INTEGER REFERENCES holds(id);
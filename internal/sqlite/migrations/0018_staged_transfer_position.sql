@@ -0,0 +1,15 @@
+-- Staged transfers keep their index in the request, so that a stream with
+-- invalid lines left out can still be reported line by line. Batches staged
+-- before the upgrade belong to uploads the restart interrupted.
+DELETE FROM staged_transfers;
+DELETE FROM staged_bulk_transfers;
+
+ALTER TABLE staged_transfers ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+DROP INDEX idx_staged_transfers_bulk_transfer;
+
+CREATE UNIQUE INDEX idx_staged_transfers_position
+ON staged_transfers(staged_bulk_transfer_id, position);
+
+CREATE INDEX idx_staged_bulk_transfers_created_at
+ON staged_bulk_transfers(created_at);
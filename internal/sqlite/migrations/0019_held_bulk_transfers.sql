@@ -0,0 +1,7 @@
+-- Bulk transfers held for a manual review are kept as staged bulk transfers
+-- until they are released or rejected. review is NULL for the others.
+ALTER TABLE staged_bulk_transfers ADD COLUMN review TEXT;
+ALTER TABLE staged_bulk_transfers ADD COLUMN review_reason TEXT NOT NULL DEFAULT '';
+-- Comma-separated reviews the bulk transfer passed before it was held again.
+ALTER TABLE staged_bulk_transfers ADD COLUMN approved_reviews TEXT NOT NULL DEFAULT '';
+ALTER TABLE staged_bulk_transfers ADD COLUMN held_at TEXT;
@@ -0,0 +1,92 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"payment/internal/core"
+)
+
+func (s AccountStore) CheckBalances(ctx context.Context) ([]core.BalanceCheck, error) {
+	if s.tx == nil {
+		return nil, errors.New("CheckBalances must be called within Atomic transaction")
+	}
+
+	rows, err := s.tx.QueryContext(ctx, `
+		SELECT a.id, a.iban, a.bic, a.opening_balance_cents, a.balance_cents,
+			COALESCE((SELECT SUM(t.amount_cents) FROM transactions t WHERE t.bank_account_id = a.id), 0)
+		FROM bank_accounts a
+		ORDER BY a.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balances: %w", err)
+	}
+	defer rows.Close()
+
+	var checks []core.BalanceCheck
+	for rows.Next() {
+		var check core.BalanceCheck
+		err = rows.Scan(
+			&check.AccountID,
+			&check.IBAN,
+			&check.BIC,
+			&check.OpeningBalanceCents,
+			&check.BalanceCents,
+			&check.TransactionsCents,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan balance: %w", err)
+		}
+		checks = append(checks, check)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate balances: %w", err)
+	}
+
+	return checks, nil
+}
+
+func (s AccountStore) AddReconciliationReport(ctx context.Context, report core.ReconciliationReport) (int64, error) {
+	if s.tx == nil {
+		return 0, errors.New("AddReconciliationReport must be called within Atomic transaction")
+	}
+
+	result, err := s.tx.ExecContext(ctx, `
+		INSERT INTO reconciliation_reports (started_at, finished_at, account_count, discrepancy_count)
+		VALUES (?, ?, ?, ?)
+	`,
+		report.StartedAt.UTC().Format(time.RFC3339Nano),
+		report.FinishedAt.UTC().Format(time.RFC3339Nano),
+		report.AccountCount,
+		len(report.Discrepancies),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert reconciliation report: %w", err)
+	}
+
+	reportID, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get reconciliation report ID: %w", err)
+	}
+
+	for _, check := range report.Discrepancies {
+		_, err = s.tx.ExecContext(ctx, `
+			INSERT INTO reconciliation_discrepancies (
+				report_id,
+				bank_account_id,
+				opening_balance_cents,
+				transactions_cents,
+				balance_cents,
+				discrepancy_cents
+			) VALUES (?, ?, ?, ?, ?, ?)
+		`, reportID, check.AccountID, check.OpeningBalanceCents, check.TransactionsCents, check.BalanceCents, check.DiscrepancyCents())
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert reconciliation discrepancy: %w", err)
+		}
+	}
+
+	return reportID, nil
+}
@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"payment/internal/metrics"
+)
+
+// RetryPolicy bounds how Atomic reruns a transaction that failed on a
+// transient SQLite error.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 5,
+	BaseDelay:  10 * time.Millisecond,
+	MaxDelay:   time.Second,
+}
+
+// backoff returns the delay before the given retry, counting from 1. The
+// ceiling doubles with every retry up to MaxDelay, and the delay is drawn
+// between half the ceiling and the ceiling so that contending transactions
+// do not retry in lockstep.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	ceiling := p.MaxDelay
+	if shift := retry - 1; shift < 32 && p.BaseDelay<<shift < p.MaxDelay {
+		ceiling = p.BaseDelay << shift
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return ceiling/2 + rand.N(ceiling/2+1)
+}
+
+// transientCode returns the metrics label of err when it is a SQLite error
+// that may go away on its own: a lock held by another connection, or an I/O
+// error.
+func transientCode(err error) (string, bool) {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return "", false
+	}
+
+	switch sqliteErr.Code {
+	case sqlite3.ErrBusy:
+		return "busy", true
+	case sqlite3.ErrLocked:
+		return "locked", true
+	case sqlite3.ErrIoErr:
+		return "ioerr", true
+	default:
+		return "", false
+	}
+}
+
+// retry runs the transaction again while it fails on a transient error, as
+// long as the retries left by the policy and the context deadline allow.
+func (s AccountStore) retry(ctx context.Context, transaction func() error) error {
+	err := transaction()
+	for retry := 1; retry <= s.retryPolicy.MaxRetries; retry++ {
+		code, ok := transientCode(err)
+		if !ok {
+			return err
+		}
+
+		delay := s.retryPolicy.backoff(retry)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			break
+		}
+
+		metrics.SQLiteTransactionRetries.WithLabelValues(code).Inc()
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		err = transaction()
+	}
+
+	if code, ok := transientCode(err); ok {
+		metrics.SQLiteTransientFailures.WithLabelValues(code).Inc()
+	}
+
+	return err
+}
@@ -0,0 +1,354 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"payment/internal/core"
+)
+
+func (s AccountStore) AddStagedBulkTransfer(ctx context.Context, bulkTransfer core.BulkTransfer) (int64, error) {
+	if s.tx == nil {
+		return 0, errors.New("AddStagedBulkTransfer must be called within Atomic transaction")
+	}
+
+	query := `
+		INSERT INTO staged_bulk_transfers (organization_iban, organization_bic, execution_mode, request_id, hold_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	executionMode := bulkTransfer.ExecutionMode
+	if executionMode == "" {
+		executionMode = core.ExecutionModeAllOrNothing
+	}
+
+	result, err := s.tx.ExecContext(ctx, query,
+		bulkTransfer.OrganizationIBAN,
+		bulkTransfer.OrganizationBIC,
+		string(executionMode),
+		sql.NullString{String: bulkTransfer.RequestID, Valid: bulkTransfer.RequestID != ""},
+		sql.NullInt64{Int64: bulkTransfer.HoldID, Valid: bulkTransfer.HoldID != 0},
+		time.Now().UTC().Format(time.RFC3339Nano),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert staged bulk transfer: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get staged bulk transfer ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (s AccountStore) AddStagedTransfers(ctx context.Context, stagedBulkTransferID int64, transfers []core.StagedTransfer) error {
+	if s.tx == nil {
+		return errors.New("AddStagedTransfers must be called within Atomic transaction")
+	}
+
+	query := `
+		INSERT INTO staged_transfers (
+			staged_bulk_transfer_id,
+			position,
+			counterparty_name,
+			counterparty_iban,
+			counterparty_bic,
+			amount_cents,
+			amount_currency,
+			description,
+			debtor_iban,
+			debtor_bic
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	stmt, err := s.tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare staged transfer insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, staged := range transfers {
+		transfer := staged.Transfer
+		_, err = stmt.ExecContext(ctx,
+			stagedBulkTransferID,
+			staged.Position,
+			transfer.CounterpartyName,
+			transfer.CounterpartyIBAN,
+			transfer.CounterpartyBIC,
+			transfer.AmountCents,
+			transfer.Currency,
+			transfer.Description,
+			transfer.DebtorIBAN,
+			transfer.DebtorBIC,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert staged transfer: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s AccountStore) GetStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) (core.BulkTransfer, error) {
+	if s.tx == nil {
+		return core.BulkTransfer{}, errors.New("GetStagedBulkTransfer must be called within Atomic transaction")
+	}
+
+	query := `
+		SELECT organization_iban, organization_bic, execution_mode, COALESCE(request_id, ''), COALESCE(hold_id, 0),
+			approved_reviews
+		FROM staged_bulk_transfers
+		WHERE id = ?
+	`
+
+	var bulkTransfer core.BulkTransfer
+	var approvedReviews string
+	err := s.tx.QueryRowContext(ctx, query, stagedBulkTransferID).Scan(
+		&bulkTransfer.OrganizationIBAN,
+		&bulkTransfer.OrganizationBIC,
+		&bulkTransfer.ExecutionMode,
+		&bulkTransfer.RequestID,
+		&bulkTransfer.HoldID,
+		&approvedReviews,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return core.BulkTransfer{}, core.ErrStagedBulkTransferNotFound
+		}
+
+		return core.BulkTransfer{}, fmt.Errorf("failed to get staged bulk transfer: %w", err)
+	}
+	bulkTransfer.ApprovedReviews = parseReviews(approvedReviews)
+
+	return bulkTransfer, nil
+}
+
+func (s AccountStore) ListStagedTransfers(ctx context.Context, stagedBulkTransferID int64, afterPosition int, limit int) ([]core.StagedTransfer, error) {
+	if s.tx == nil {
+		return nil, errors.New("ListStagedTransfers must be called within Atomic transaction")
+	}
+
+	rows, err := s.tx.QueryContext(ctx, `
+		SELECT position, counterparty_name, counterparty_iban, counterparty_bic, amount_cents, amount_currency,
+			description, debtor_iban, debtor_bic
+		FROM staged_transfers
+		WHERE staged_bulk_transfer_id = ? AND position > ?
+		ORDER BY position
+		LIMIT ?
+	`, stagedBulkTransferID, afterPosition, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query staged transfers: %w", err)
+	}
+	defer rows.Close()
+
+	var transfers []core.StagedTransfer
+	for rows.Next() {
+		var staged core.StagedTransfer
+		err = rows.Scan(
+			&staged.Position,
+			&staged.Transfer.CounterpartyName,
+			&staged.Transfer.CounterpartyIBAN,
+			&staged.Transfer.CounterpartyBIC,
+			&staged.Transfer.AmountCents,
+			&staged.Transfer.Currency,
+			&staged.Transfer.Description,
+			&staged.Transfer.DebtorIBAN,
+			&staged.Transfer.DebtorBIC,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan staged transfer: %w", err)
+		}
+		transfers = append(transfers, staged)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate staged transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+func (s AccountStore) DeleteStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) error {
+	if s.tx == nil {
+		return errors.New("DeleteStagedBulkTransfer must be called within Atomic transaction")
+	}
+
+	_, err := s.tx.ExecContext(ctx, `DELETE FROM staged_transfers WHERE staged_bulk_transfer_id = ?`, stagedBulkTransferID)
+	if err != nil {
+		return fmt.Errorf("failed to delete staged transfers: %w", err)
+	}
+
+	_, err = s.tx.ExecContext(ctx, `DELETE FROM staged_bulk_transfers WHERE id = ?`, stagedBulkTransferID)
+	if err != nil {
+		return fmt.Errorf("failed to delete staged bulk transfer: %w", err)
+	}
+
+	return nil
+}
+
+func (s AccountStore) DeleteStagedBulkTransfersBefore(ctx context.Context, createdBefore time.Time) (int, error) {
+	if s.tx == nil {
+		return 0, errors.New("DeleteStagedBulkTransfersBefore must be called within Atomic transaction")
+	}
+
+	// created_at is RFC 3339 with a variable number of fractional digits:
+	// without them and the zone, createdBefore sorts before every time in its
+	// second, which keeps the batches staged during that second.
+	result, err := s.tx.ExecContext(ctx, `DELETE FROM staged_bulk_transfers WHERE created_at < ? AND review IS NULL`,
+		createdBefore.UTC().Format("2006-01-02T15:04:05"),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete staged bulk transfers: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count deleted staged bulk transfers: %w", err)
+	}
+
+	_, err = s.tx.ExecContext(ctx, `
+		DELETE FROM staged_transfers
+		WHERE staged_bulk_transfer_id NOT IN (SELECT id FROM staged_bulk_transfers)
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete orphaned staged transfers: %w", err)
+	}
+
+	return int(deleted), nil
+}
+
+func (s AccountStore) HoldStagedBulkTransfer(ctx context.Context, held core.HeldBulkTransfer) error {
+	if s.tx == nil {
+		return errors.New("HoldStagedBulkTransfer must be called within Atomic transaction")
+	}
+
+	approved := make([]string, len(held.Approved))
+	for i, review := range held.Approved {
+		approved[i] = string(review)
+	}
+
+	result, err := s.tx.ExecContext(ctx, `
+		UPDATE staged_bulk_transfers
+		SET review = ?, review_reason = ?, approved_reviews = ?, held_at = ?
+		WHERE id = ?
+	`,
+		string(held.Review),
+		held.Reason,
+		strings.Join(approved, ","),
+		time.Now().UTC().Format(time.RFC3339Nano),
+		held.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to hold staged bulk transfer: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to count held staged bulk transfers: %w", err)
+	}
+	if updated == 0 {
+		return core.ErrStagedBulkTransferNotFound
+	}
+
+	return nil
+}
+
+const heldBulkTransferQuery = `
+	SELECT b.id, b.organization_iban, b.organization_bic, b.execution_mode, COALESCE(b.request_id, ''),
+		COUNT(t.id), COALESCE(SUM(t.amount_cents), 0), b.review, b.review_reason, b.approved_reviews, b.held_at
+	FROM staged_bulk_transfers b
+	LEFT JOIN staged_transfers t ON t.staged_bulk_transfer_id = b.id
+	WHERE b.review IS NOT NULL
+`
+
+func (s AccountStore) GetHeldBulkTransfer(ctx context.Context, heldBulkTransferID int64) (core.HeldBulkTransfer, error) {
+	if s.tx == nil {
+		return core.HeldBulkTransfer{}, errors.New("GetHeldBulkTransfer must be called within Atomic transaction")
+	}
+
+	held, err := scanHeldBulkTransfer(s.tx.QueryRowContext(ctx, heldBulkTransferQuery+"AND b.id = ? GROUP BY b.id", heldBulkTransferID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return core.HeldBulkTransfer{}, core.ErrHeldBulkTransferNotFound
+		}
+
+		return core.HeldBulkTransfer{}, fmt.Errorf("failed to get held bulk transfer: %w", err)
+	}
+
+	return held, nil
+}
+
+func (s AccountStore) ListHeldBulkTransfers(ctx context.Context) ([]core.HeldBulkTransfer, error) {
+	if s.tx == nil {
+		return nil, errors.New("ListHeldBulkTransfers must be called within Atomic transaction")
+	}
+
+	rows, err := s.tx.QueryContext(ctx, heldBulkTransferQuery+"GROUP BY b.id ORDER BY b.id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query held bulk transfers: %w", err)
+	}
+	defer rows.Close()
+
+	var held []core.HeldBulkTransfer
+	for rows.Next() {
+		heldBulkTransfer, err := scanHeldBulkTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan held bulk transfer: %w", err)
+		}
+		held = append(held, heldBulkTransfer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate held bulk transfers: %w", err)
+	}
+
+	return held, nil
+}
+
+func scanHeldBulkTransfer(row interface{ Scan(dest ...any) error }) (core.HeldBulkTransfer, error) {
+	var held core.HeldBulkTransfer
+	var approvedReviews, heldAt string
+	err := row.Scan(
+		&held.ID,
+		&held.OrganizationIBAN,
+		&held.OrganizationBIC,
+		&held.ExecutionMode,
+		&held.RequestID,
+		&held.TransferCount,
+		&held.TotalCents,
+		&held.Review,
+		&held.Reason,
+		&approvedReviews,
+		&heldAt,
+	)
+	if err != nil {
+		return core.HeldBulkTransfer{}, err
+	}
+	held.Approved = parseReviews(approvedReviews)
+
+	held.HeldAt, err = time.Parse(time.RFC3339Nano, heldAt)
+	if err != nil {
+		return core.HeldBulkTransfer{}, fmt.Errorf("failed to parse held bulk transfer held_at: %w", err)
+	}
+
+	return held, nil
+}
+
+// parseReviews reads a comma-separated list of reviews.
+func parseReviews(value string) []core.ReviewKind {
+	if value == "" {
+		return nil
+	}
+
+	var reviews []core.ReviewKind
+	for review := range strings.SplitSeq(value, ",") {
+		reviews = append(reviews, core.ReviewKind(review))
+	}
+
+	return reviews
+}
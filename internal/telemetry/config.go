@@ -0,0 +1,16 @@
+package telemetry
+
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+	ExporterFile = "file"
+)
+
+// Config selects where spans are exported. The OTLP exporter is configured
+// through the standard OTEL_EXPORTER_OTLP_* environment variables.
+type Config struct {
+	Exporter    string  `envconfig:"TRACING_EXPORTER" default:"none"`
+	FilePath    string  `envconfig:"TRACING_FILE_PATH" default:"traces.jsonl"`
+	ServiceName string  `envconfig:"TRACING_SERVICE_NAME" default:"payment"`
+	SampleRatio float64 `envconfig:"TRACING_SAMPLE_RATIO" default:"1"`
+}
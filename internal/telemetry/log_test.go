@@ -0,0 +1,68 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"payment/internal/core"
+)
+
+func TestLogHandler(t *testing.T) {
+	t.Parallel()
+
+	traceID := trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	spanID := trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	tests := []struct {
+		name              string
+		ctx               context.Context
+		expectedTraceID   any
+		expectedSpanID    any
+		expectedRequestID any
+	}{
+		{
+			name:            "adds_ids_of_current_span",
+			ctx:             trace.ContextWithSpanContext(context.Background(), spanContext),
+			expectedTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectedSpanID:  "00f067aa0ba902b7",
+		},
+		{
+			name:              "adds_request_id",
+			ctx:               core.WithRequestID(context.Background(), "req-1"),
+			expectedRequestID: "req-1",
+		},
+		{
+			name: "no_span",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			logger := slog.New(NewLogHandler(slog.NewJSONHandler(&buf, nil))).With("component", "test")
+
+			logger.InfoContext(tt.ctx, "hello")
+
+			var record map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+			require.Equal(t, "test", record["component"])
+			require.Equal(t, tt.expectedTraceID, record["trace_id"])
+			require.Equal(t, tt.expectedSpanID, record["span_id"])
+			require.Equal(t, tt.expectedRequestID, record["request_id"])
+		})
+	}
+}
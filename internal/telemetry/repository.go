@@ -0,0 +1,307 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"payment/internal/core"
+)
+
+var tracer = otel.Tracer("payment/internal/telemetry")
+
+// TracingRepository starts a span for every call to the wrapped repository,
+// including the calls made inside Atomic.
+type TracingRepository struct {
+	next core.AccountRepository
+}
+
+func NewTracingRepository(next core.AccountRepository) TracingRepository {
+	return TracingRepository{next: next}
+}
+
+func (r TracingRepository) GetAccountByID(ctx context.Context, iban string, bic string) (core.Account, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.GetAccountByID")
+	account, err := r.next.GetAccountByID(ctx, iban, bic)
+	End(span, err)
+	return account, err
+}
+
+func (r TracingRepository) CreateAccount(ctx context.Context, account core.Account) (int64, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.CreateAccount")
+	id, err := r.next.CreateAccount(ctx, account)
+	End(span, err)
+	return id, err
+}
+
+func (r TracingRepository) ListAccounts(ctx context.Context) ([]core.Account, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.ListAccounts")
+	accounts, err := r.next.ListAccounts(ctx)
+	End(span, err)
+	return accounts, err
+}
+
+func (r TracingRepository) SetAccountFrozen(ctx context.Context, accountID int64, frozen bool) error {
+	ctx, span := tracer.Start(ctx, "AccountRepository.SetAccountFrozen", trace.WithAttributes(
+		attribute.Int64("account.id", accountID),
+		attribute.Bool("account.frozen", frozen),
+	))
+	err := r.next.SetAccountFrozen(ctx, accountID, frozen)
+	End(span, err)
+	return err
+}
+
+func (r TracingRepository) SetOverdraftLimit(ctx context.Context, accountID int64, limitCents int64) error {
+	ctx, span := tracer.Start(ctx, "AccountRepository.SetOverdraftLimit", trace.WithAttributes(
+		attribute.Int64("account.id", accountID),
+	))
+	err := r.next.SetOverdraftLimit(ctx, accountID, limitCents)
+	End(span, err)
+	return err
+}
+
+func (r TracingRepository) SetPricingPlan(ctx context.Context, accountID int64, plan string) error {
+	ctx, span := tracer.Start(ctx, "AccountRepository.SetPricingPlan", trace.WithAttributes(
+		attribute.Int64("account.id", accountID),
+		attribute.String("account.pricing_plan", plan),
+	))
+	err := r.next.SetPricingPlan(ctx, accountID, plan)
+	End(span, err)
+	return err
+}
+
+func (r TracingRepository) GetAccountHistory(ctx context.Context, accountID int64, counterpartyIBANs []string) (core.AccountHistory, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.GetAccountHistory", trace.WithAttributes(
+		attribute.Int64("account.id", accountID),
+		attribute.Int("counterparty.count", len(counterpartyIBANs)),
+	))
+	history, err := r.next.GetAccountHistory(ctx, accountID, counterpartyIBANs)
+	End(span, err)
+	return history, err
+}
+
+func (r TracingRepository) AddBulkTransfer(ctx context.Context, accountID int64, bulkTransfer core.BulkTransfer) (int64, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.AddBulkTransfer", trace.WithAttributes(
+		attribute.Int64("account.id", accountID),
+	))
+	id, err := r.next.AddBulkTransfer(ctx, accountID, bulkTransfer)
+	End(span, err)
+	return id, err
+}
+
+func (r TracingRepository) AddTransfers(ctx context.Context, transfers []core.Transfer) error {
+	ctx, span := tracer.Start(ctx, "AccountRepository.AddTransfers", trace.WithAttributes(
+		attribute.Int("transfer.count", len(transfers)),
+	))
+	err := r.next.AddTransfers(ctx, transfers)
+	End(span, err)
+	return err
+}
+
+func (r TracingRepository) CountTransfersSince(ctx context.Context, accountID int64, since time.Time) (int, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.CountTransfersSince", trace.WithAttributes(
+		attribute.Int64("account.id", accountID),
+	))
+	count, err := r.next.CountTransfersSince(ctx, accountID, since)
+	End(span, err)
+	return count, err
+}
+
+func (r TracingRepository) UpdateBalance(ctx context.Context, account core.Account) error {
+	ctx, span := tracer.Start(ctx, "AccountRepository.UpdateBalance", trace.WithAttributes(
+		attribute.Int64("account.id", account.ID),
+	))
+	err := r.next.UpdateBalance(ctx, account)
+	End(span, err)
+	return err
+}
+
+func (r TracingRepository) AppendAuditRecord(ctx context.Context, record core.AuditRecord) error {
+	ctx, span := tracer.Start(ctx, "AccountRepository.AppendAuditRecord", trace.WithAttributes(
+		attribute.String("audit.event_type", string(record.EventType)),
+	))
+	err := r.next.AppendAuditRecord(ctx, record)
+	End(span, err)
+	return err
+}
+
+func (r TracingRepository) AddStagedBulkTransfer(ctx context.Context, bulkTransfer core.BulkTransfer) (int64, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.AddStagedBulkTransfer")
+	id, err := r.next.AddStagedBulkTransfer(ctx, bulkTransfer)
+	End(span, err)
+	return id, err
+}
+
+func (r TracingRepository) AddStagedTransfers(ctx context.Context, stagedBulkTransferID int64, transfers []core.StagedTransfer) error {
+	ctx, span := tracer.Start(ctx, "AccountRepository.AddStagedTransfers", trace.WithAttributes(
+		attribute.Int64("staged_bulk_transfer.id", stagedBulkTransferID),
+		attribute.Int("transfer.count", len(transfers)),
+	))
+	err := r.next.AddStagedTransfers(ctx, stagedBulkTransferID, transfers)
+	End(span, err)
+	return err
+}
+
+func (r TracingRepository) GetStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) (core.BulkTransfer, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.GetStagedBulkTransfer", trace.WithAttributes(
+		attribute.Int64("staged_bulk_transfer.id", stagedBulkTransferID),
+	))
+	bulkTransfer, err := r.next.GetStagedBulkTransfer(ctx, stagedBulkTransferID)
+	End(span, err)
+	return bulkTransfer, err
+}
+
+func (r TracingRepository) ListStagedTransfers(ctx context.Context, stagedBulkTransferID int64, afterPosition int, limit int) ([]core.StagedTransfer, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.ListStagedTransfers", trace.WithAttributes(
+		attribute.Int64("staged_bulk_transfer.id", stagedBulkTransferID),
+		attribute.Int("staged_transfer.after_position", afterPosition),
+	))
+	transfers, err := r.next.ListStagedTransfers(ctx, stagedBulkTransferID, afterPosition, limit)
+	End(span, err)
+	return transfers, err
+}
+
+func (r TracingRepository) DeleteStagedBulkTransfer(ctx context.Context, stagedBulkTransferID int64) error {
+	ctx, span := tracer.Start(ctx, "AccountRepository.DeleteStagedBulkTransfer", trace.WithAttributes(
+		attribute.Int64("staged_bulk_transfer.id", stagedBulkTransferID),
+	))
+	err := r.next.DeleteStagedBulkTransfer(ctx, stagedBulkTransferID)
+	End(span, err)
+	return err
+}
+
+func (r TracingRepository) DeleteStagedBulkTransfersBefore(ctx context.Context, createdBefore time.Time) (int, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.DeleteStagedBulkTransfersBefore")
+	deleted, err := r.next.DeleteStagedBulkTransfersBefore(ctx, createdBefore)
+	End(span, err)
+	return deleted, err
+}
+
+func (r TracingRepository) HoldStagedBulkTransfer(ctx context.Context, held core.HeldBulkTransfer) error {
+	ctx, span := tracer.Start(ctx, "AccountRepository.HoldStagedBulkTransfer", trace.WithAttributes(
+		attribute.Int64("staged_bulk_transfer.id", held.ID),
+		attribute.String("held_bulk_transfer.review", string(held.Review)),
+	))
+	err := r.next.HoldStagedBulkTransfer(ctx, held)
+	End(span, err)
+	return err
+}
+
+func (r TracingRepository) GetHeldBulkTransfer(ctx context.Context, heldBulkTransferID int64) (core.HeldBulkTransfer, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.GetHeldBulkTransfer", trace.WithAttributes(
+		attribute.Int64("held_bulk_transfer.id", heldBulkTransferID),
+	))
+	held, err := r.next.GetHeldBulkTransfer(ctx, heldBulkTransferID)
+	End(span, err)
+	return held, err
+}
+
+func (r TracingRepository) ListHeldBulkTransfers(ctx context.Context) ([]core.HeldBulkTransfer, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.ListHeldBulkTransfers")
+	held, err := r.next.ListHeldBulkTransfers(ctx)
+	End(span, err)
+	return held, err
+}
+
+func (r TracingRepository) GetBulkTransfer(ctx context.Context, bulkTransferID int64) (core.BulkTransferRecord, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.GetBulkTransfer", trace.WithAttributes(
+		attribute.Int64("bulk_transfer.id", bulkTransferID),
+	))
+	record, err := r.next.GetBulkTransfer(ctx, bulkTransferID)
+	End(span, err)
+	return record, err
+}
+
+func (r TracingRepository) ListBulkTransfers(ctx context.Context, accountID int64) ([]core.BulkTransferRecord, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.ListBulkTransfers", trace.WithAttributes(
+		attribute.Int64("account.id", accountID),
+	))
+	records, err := r.next.ListBulkTransfers(ctx, accountID)
+	End(span, err)
+	return records, err
+}
+
+func (r TracingRepository) ListTransactions(ctx context.Context, accountID int64, query core.TransactionQuery) ([]core.Transaction, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.ListTransactions", trace.WithAttributes(
+		attribute.Int64("account.id", accountID),
+		attribute.Int("transaction.limit", query.Limit),
+	))
+	transactions, err := r.next.ListTransactions(ctx, accountID, query)
+	End(span, err)
+	return transactions, err
+}
+
+func (r TracingRepository) AddTransaction(ctx context.Context, transaction core.Transaction) error {
+	ctx, span := tracer.Start(ctx, "AccountRepository.AddTransaction", trace.WithAttributes(
+		attribute.Int64("account.id", transaction.AccountID),
+	))
+	err := r.next.AddTransaction(ctx, transaction)
+	End(span, err)
+	return err
+}
+
+func (r TracingRepository) CheckBalances(ctx context.Context) ([]core.BalanceCheck, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.CheckBalances")
+	checks, err := r.next.CheckBalances(ctx)
+	End(span, err)
+	return checks, err
+}
+
+func (r TracingRepository) AddReconciliationReport(ctx context.Context, report core.ReconciliationReport) (int64, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.AddReconciliationReport", trace.WithAttributes(
+		attribute.Int("reconciliation.discrepancy_count", len(report.Discrepancies)),
+	))
+	id, err := r.next.AddReconciliationReport(ctx, report)
+	End(span, err)
+	return id, err
+}
+
+func (r TracingRepository) AddHold(ctx context.Context, hold core.Hold) (int64, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.AddHold", trace.WithAttributes(
+		attribute.Int64("account.id", hold.AccountID),
+		attribute.Int64("hold.amount_cents", hold.AmountCents),
+	))
+	id, err := r.next.AddHold(ctx, hold)
+	End(span, err)
+	return id, err
+}
+
+func (r TracingRepository) GetHold(ctx context.Context, holdID int64) (core.Hold, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.GetHold", trace.WithAttributes(
+		attribute.Int64("hold.id", holdID),
+	))
+	hold, err := r.next.GetHold(ctx, holdID)
+	End(span, err)
+	return hold, err
+}
+
+func (r TracingRepository) ListHolds(ctx context.Context, accountID int64) ([]core.Hold, error) {
+	ctx, span := tracer.Start(ctx, "AccountRepository.ListHolds", trace.WithAttributes(
+		attribute.Int64("account.id", accountID),
+	))
+	holds, err := r.next.ListHolds(ctx, accountID)
+	End(span, err)
+	return holds, err
+}
+
+func (r TracingRepository) SetHoldStatus(ctx context.Context, holdID int64, status core.HoldStatus) error {
+	ctx, span := tracer.Start(ctx, "AccountRepository.SetHoldStatus", trace.WithAttributes(
+		attribute.Int64("hold.id", holdID),
+		attribute.String("hold.status", string(status)),
+	))
+	err := r.next.SetHoldStatus(ctx, holdID, status)
+	End(span, err)
+	return err
+}
+
+func (r TracingRepository) Atomic(ctx context.Context, cb func(core.AccountRepository) error) error {
+	ctx, span := tracer.Start(ctx, "AccountRepository.Atomic")
+	err := r.next.Atomic(ctx, func(txRepo core.AccountRepository) error {
+		return cb(TracingRepository{next: txRepo})
+	})
+	End(span, err)
+	return err
+}
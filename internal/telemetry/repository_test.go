@@ -0,0 +1,66 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/mock/gomock"
+
+	"payment/internal/core"
+)
+
+func TestTracingRepository(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	errUpdate := errors.New("disk full")
+
+	mockRepo := core.NewMockAccountRepository(ctrl)
+	mockRepo.EXPECT().
+		Atomic(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, cb func(core.AccountRepository) error) error {
+			return cb(mockRepo)
+		})
+	mockRepo.EXPECT().
+		GetAccountByID(gomock.Any(), "IBAN", "BIC").
+		Return(core.Account{ID: 1}, nil)
+	mockRepo.EXPECT().
+		UpdateBalance(gomock.Any(), core.Account{ID: 1}).
+		Return(errUpdate)
+
+	repo := NewTracingRepository(mockRepo)
+	err := repo.Atomic(context.Background(), func(r core.AccountRepository) error {
+		account, err := r.GetAccountByID(context.Background(), "IBAN", "BIC")
+		if err != nil {
+			return err
+		}
+		return r.UpdateBalance(context.Background(), account)
+	})
+	require.ErrorIs(t, err, errUpdate)
+
+	spans := exporter.GetSpans()
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name
+	}
+	require.Equal(t, []string{
+		"AccountRepository.GetAccountByID",
+		"AccountRepository.UpdateBalance",
+		"AccountRepository.Atomic",
+	}, names)
+
+	require.Equal(t, codes.Unset, spans[0].Status.Code)
+	require.Equal(t, codes.Error, spans[1].Status.Code)
+	require.Equal(t, codes.Error, spans[2].Status.Code)
+}
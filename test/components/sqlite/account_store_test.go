@@ -3,11 +3,15 @@ package integration
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/require"
 
 	"payment/internal/core"
+	"payment/internal/sanctions"
 	"payment/internal/sqlite"
 )
 
@@ -77,6 +81,139 @@ func TestAccountStore_GetAccountByID(t *testing.T) {
 	}
 }
 
+func TestAccountStore_CreateAccount(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	ctx := context.Background()
+
+	existingID := suite.SeedAccount(t, "Existing Org", "FR1420041010050500013M02606", "PSSTFRPPMON", 500)
+
+	account := core.Account{OrganizationName: "New Org", BalanceCents: 1000, IBAN: "DE89370400440532013000", BIC: "COBADEFFXXX"}
+	err := store.Atomic(ctx, func(r core.AccountRepository) error {
+		var err error
+		account.ID, err = r.CreateAccount(ctx, account)
+		return err
+	})
+	require.NoError(t, err)
+	require.NotZero(t, account.ID)
+	account.Currency = core.DefaultCurrency
+
+	err = store.Atomic(ctx, func(r core.AccountRepository) error {
+		_, err := r.CreateAccount(ctx, core.Account{OrganizationName: "Duplicate", IBAN: account.IBAN, BIC: account.BIC})
+		return err
+	})
+	require.ErrorIs(t, err, core.ErrAccountExists)
+
+	err = store.Atomic(ctx, func(r core.AccountRepository) error {
+		accounts, err := r.ListAccounts(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []core.Account{
+			{ID: existingID, OrganizationName: "Existing Org", BalanceCents: 500, IBAN: "FR1420041010050500013M02606", BIC: "PSSTFRPPMON", Currency: "EUR"},
+			account,
+		}, accounts)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestAccountStore_SetAccountFrozen(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	ctx := context.Background()
+
+	iban := "FR1420041010050500013M02606"
+	bic := "PSSTFRPPMON"
+	accountID := suite.SeedAccount(t, "Test Org", iban, bic, 1000)
+
+	for _, frozen := range []bool{true, false} {
+		err := store.Atomic(ctx, func(r core.AccountRepository) error {
+			return r.SetAccountFrozen(ctx, accountID, frozen)
+		})
+		require.NoError(t, err)
+
+		err = store.Atomic(ctx, func(r core.AccountRepository) error {
+			account, err := r.GetAccountByID(ctx, iban, bic)
+			require.NoError(t, err)
+			require.Equal(t, frozen, account.Frozen)
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	err := store.Atomic(ctx, func(r core.AccountRepository) error {
+		return r.SetAccountFrozen(ctx, accountID+1, true)
+	})
+	require.Error(t, err)
+}
+
+func TestAccountStore_SetOverdraftLimit(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	ctx := context.Background()
+
+	iban := "FR1420041010050500013M02606"
+	bic := "PSSTFRPPMON"
+	accountID := suite.SeedAccount(t, "Test Org", iban, bic, 1000)
+
+	err := store.Atomic(ctx, func(r core.AccountRepository) error {
+		return r.SetOverdraftLimit(ctx, accountID, 50000)
+	})
+	require.NoError(t, err)
+
+	err = store.Atomic(ctx, func(r core.AccountRepository) error {
+		account, err := r.GetAccountByID(ctx, iban, bic)
+		require.NoError(t, err)
+		require.Equal(t, int64(50000), account.OverdraftLimitCents)
+		require.Equal(t, int64(51000), account.AvailableCents())
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = store.Atomic(ctx, func(r core.AccountRepository) error {
+		return r.SetOverdraftLimit(ctx, accountID, -1)
+	})
+	require.Error(t, err, "negative limits are refused by the schema")
+}
+
+func TestAccountStore_SetPricingPlan(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	ctx := context.Background()
+
+	iban := "FR1420041010050500013M02606"
+	bic := "PSSTFRPPMON"
+	accountID := suite.SeedAccount(t, "Test Org", iban, bic, 1000)
+
+	err := store.Atomic(ctx, func(r core.AccountRepository) error {
+		return r.SetPricingPlan(ctx, accountID, "business")
+	})
+	require.NoError(t, err)
+
+	err = store.Atomic(ctx, func(r core.AccountRepository) error {
+		account, err := r.GetAccountByID(ctx, iban, bic)
+		require.NoError(t, err)
+		require.Equal(t, "business", account.PricingPlan)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
 func TestAccountStore_UpdateBalance(t *testing.T) {
 	t.Parallel()
 
@@ -137,6 +274,51 @@ func TestAccountStore_UpdateBalance(t *testing.T) {
 	}
 }
 
+func TestAccountStore_UpdateBalance_VersionConflict(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+
+	iban := "FR1420041010050500013M02606"
+	bic := "PSSTFRPPMON"
+	accountID := suite.SeedAccount(t, "Test Org", iban, bic, 1000000)
+
+	var stale core.Account
+	err := store.Atomic(context.Background(), func(r core.AccountRepository) error {
+		var err error
+		stale, err = r.GetAccountByID(context.Background(), iban, bic)
+		if err != nil {
+			return err
+		}
+
+		account := stale
+		account.BalanceCents = 900000
+		if err = r.UpdateBalance(context.Background(), account); err != nil {
+			return err
+		}
+
+		account, err = r.GetAccountByID(context.Background(), iban, bic)
+		require.NoError(t, err)
+		require.Equal(t, stale.Version+1, account.Version)
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = store.Atomic(context.Background(), func(r core.AccountRepository) error {
+		stale.BalanceCents = 800000
+		return r.UpdateBalance(context.Background(), stale)
+	})
+
+	var conflictErr *core.VersionConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	require.Equal(t, accountID, conflictErr.AccountID)
+	require.ErrorIs(t, err, core.ErrVersionConflict)
+	require.Equal(t, int64(900000), suite.GetAccountBalance(t, accountID))
+}
+
 func TestAccountStore_AddTransfers(t *testing.T) {
 	t.Parallel()
 
@@ -159,6 +341,21 @@ func TestAccountStore_AddTransfers(t *testing.T) {
 				return -t.AmountCents
 			},
 		},
+		{
+			name:          "whole_chunks",
+			transferCount: 300,
+			expectedDBAmount: func(t core.Transfer) int64 {
+				return -t.AmountCents
+			},
+		},
+		{
+			// Far more bound parameters than SQLITE_MAX_VARIABLE_NUMBER.
+			name:          "large_batch_with_partial_chunk",
+			transferCount: 12345,
+			expectedDBAmount: func(t core.Transfer) int64 {
+				return -t.AmountCents
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -181,7 +378,7 @@ func TestAccountStore_AddTransfers(t *testing.T) {
 					CounterpartyName: "Recipient",
 					CounterpartyIBAN: "GB33BUKB20201555555555",
 					CounterpartyBIC:  "BUKBGB22",
-					AmountCents:      10000,
+					AmountCents:      int64(10000 + i),
 					Currency:         "EUR",
 					Description:      "Payment",
 				}
@@ -328,3 +525,269 @@ func TestAccountStore_Atomic_RaceConditionPrevention(t *testing.T) {
 	count := suite.CountTransactions(t, accountID)
 	require.Equal(t, 1, count, "Should have exactly one transfer record")
 }
+
+func TestAccountStore_Atomic_RetriesTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	busy := fmt.Errorf("failed to execute update: %w", sqlite3.Error{Code: sqlite3.ErrBusy})
+	policy := sqlite.RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+	tests := []struct {
+		name          string
+		failures      []error
+		expectedCalls int
+		expectedError error
+	}{
+		{
+			name:          "busy_then_success",
+			failures:      []error{busy, busy},
+			expectedCalls: 3,
+		},
+		{
+			name:          "locked_and_ioerr_are_retried",
+			failures:      []error{sqlite3.Error{Code: sqlite3.ErrLocked}, sqlite3.Error{Code: sqlite3.ErrIoErr}},
+			expectedCalls: 3,
+		},
+		{
+			name:          "retries_run_out",
+			failures:      []error{busy, busy, busy, busy, busy},
+			expectedCalls: 4,
+			expectedError: busy,
+		},
+		{
+			name:          "other_errors_are_not_retried",
+			failures:      []error{core.ErrInsufficientFunds},
+			expectedCalls: 1,
+			expectedError: core.ErrInsufficientFunds,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			suite := NewTestSuite(t)
+			defer suite.Teardown()
+
+			store := sqlite.NewAccountStore(suite.DB).WithRetryPolicy(policy)
+
+			iban := "FR1420041010050500013M02606"
+			bic := "PSSTFRPPMON"
+			accountID := suite.SeedAccount(t, "Test Org", iban, bic, 1000000)
+
+			calls := 0
+			err := store.Atomic(context.Background(), func(r core.AccountRepository) error {
+				calls++
+				account, err := r.GetAccountByID(context.Background(), iban, bic)
+				if err != nil {
+					return err
+				}
+
+				account.BalanceCents -= 100
+				if err = r.UpdateBalance(context.Background(), account); err != nil {
+					return err
+				}
+
+				if calls <= len(tt.failures) {
+					return tt.failures[calls-1]
+				}
+				return nil
+			})
+
+			require.Equal(t, tt.expectedCalls, calls)
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				require.Equal(t, int64(1000000), suite.GetAccountBalance(t, accountID))
+				return
+			}
+
+			require.NoError(t, err)
+			// Only the last run is committed.
+			require.Equal(t, int64(999900), suite.GetAccountBalance(t, accountID))
+		})
+	}
+}
+
+func TestAccountStore_Atomic_StopsRetryingAtDeadline(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB).WithRetryPolicy(sqlite.RetryPolicy{
+		MaxRetries: 5,
+		BaseDelay:  time.Second,
+		MaxDelay:   time.Second,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	err := store.Atomic(ctx, func(core.AccountRepository) error {
+		calls++
+		return sqlite3.Error{Code: sqlite3.ErrBusy}
+	})
+
+	var sqliteErr sqlite3.Error
+	require.ErrorAs(t, err, &sqliteErr)
+	require.Equal(t, sqlite3.ErrBusy, sqliteErr.Code)
+	require.Equal(t, 1, calls)
+}
+
+// busyOnceRepository fails the first account history read of its
+// transactions with SQLITE_BUSY, after the dry run has quoted the balances and
+// fees, so that Atomic runs the callback again.
+type busyOnceRepository struct {
+	core.AccountRepository
+	failures *int
+}
+
+func (r busyOnceRepository) Atomic(ctx context.Context, cb func(core.AccountRepository) error) error {
+	return r.AccountRepository.Atomic(ctx, func(tx core.AccountRepository) error {
+		return cb(busyOnceRepository{AccountRepository: tx, failures: r.failures})
+	})
+}
+
+func (r busyOnceRepository) GetAccountHistory(ctx context.Context, accountID int64, counterpartyIBANs []string) (core.AccountHistory, error) {
+	*r.failures++
+	if *r.failures == 1 {
+		return core.AccountHistory{}, sqlite3.Error{Code: sqlite3.ErrBusy}
+	}
+
+	return r.AccountRepository.GetAccountHistory(ctx, accountID, counterpartyIBANs)
+}
+
+func TestAccountStore_Atomic_RetriedDryRunQuotesOnce(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	ctx := context.Background()
+	iban := "FR1420041010050500013M02606"
+	bic := "PSSTFRPPMON"
+	accountID := suite.SeedAccount(t, "Test Org", iban, bic, 3000)
+
+	store := sqlite.NewAccountStore(suite.DB).WithRetryPolicy(sqlite.RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	require.NoError(t, store.Atomic(ctx, func(r core.AccountRepository) error {
+		return r.SetPricingPlan(ctx, accountID, "business")
+	}))
+
+	pricing, err := core.NewPricing([]core.PricingPlan{{Name: "business", SEPAFeeCents: 20}})
+	require.NoError(t, err)
+	screener, err := sanctions.NewScreener(sanctions.Config{Threshold: 0.92, Action: "block"})
+	require.NoError(t, err)
+
+	var failures int
+	service := core.NewService(busyOnceRepository{AccountRepository: store, failures: &failures}, screener, core.NewFraudEngine(nil), pricing)
+	quote, err := service.ValidateBulkTransfer(ctx, core.BulkTransfer{
+		OrganizationIBAN: iban,
+		OrganizationBIC:  bic,
+		Transfers: []core.Transfer{
+			{CounterpartyName: "Bip Bip", CounterpartyIBAN: "EE383680981021245685", AmountCents: 1000, Currency: "EUR"},
+			{CounterpartyName: "Bip Bip", CounterpartyIBAN: "EE383680981021245685", AmountCents: 1990, Currency: "EUR"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, failures, "the dry run must have been retried once")
+
+	require.Equal(t, core.BulkTransferQuote{
+		TransferCount:      2,
+		TotalAmountCents:   2990,
+		FeesCents:          40,
+		BalanceBeforeCents: 3000,
+		BalanceAfterCents:  -30,
+		Violations: []core.Violation{
+			{TransferIndex: core.BatchViolation, Code: core.ViolationInsufficientFunds, Message: "balance does not cover the bulk transfer"},
+		},
+	}, quote)
+}
+
+func TestAccountStore_Atomic_Nested(t *testing.T) {
+	t.Parallel()
+
+	errInner := errors.New("inner failure")
+	errOuter := errors.New("outer failure")
+
+	tests := []struct {
+		name            string
+		innerError      error
+		outerError      error
+		expectedError   error
+		expectedBalance int64
+	}{
+		{
+			name:            "inner_changes_commit_with_outer",
+			expectedBalance: 700,
+		},
+		{
+			name:            "inner_failure_only_undoes_inner_changes",
+			innerError:      errInner,
+			expectedBalance: 900,
+		},
+		{
+			name:            "outer_failure_undoes_released_inner_changes",
+			outerError:      errOuter,
+			expectedError:   errOuter,
+			expectedBalance: 1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			suite := NewTestSuite(t)
+			defer suite.Teardown()
+
+			store := sqlite.NewAccountStore(suite.DB)
+
+			iban := "FR1420041010050500013M02606"
+			bic := "PSSTFRPPMON"
+			accountID := suite.SeedAccount(t, "Test Org", iban, bic, 1000)
+
+			debit := func(r core.AccountRepository, amountCents int64) error {
+				account, err := r.GetAccountByID(context.Background(), iban, bic)
+				if err != nil {
+					return err
+				}
+
+				account.BalanceCents -= amountCents
+				return r.UpdateBalance(context.Background(), account)
+			}
+
+			err := store.Atomic(context.Background(), func(r core.AccountRepository) error {
+				if err := debit(r, 100); err != nil {
+					return err
+				}
+
+				err := r.Atomic(context.Background(), func(nested core.AccountRepository) error {
+					if err := debit(nested, 200); err != nil {
+						return err
+					}
+					return tt.innerError
+				})
+				require.ErrorIs(t, err, tt.innerError)
+
+				// The savepoint is released either way, so the outer
+				// transaction can go on, even with another nested call.
+				err = r.Atomic(context.Background(), func(nested core.AccountRepository) error {
+					return nested.Atomic(context.Background(), func(core.AccountRepository) error {
+						return nil
+					})
+				})
+				require.NoError(t, err)
+
+				return tt.outerError
+			})
+
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.expectedBalance, suite.GetAccountBalance(t, accountID))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"payment/internal/core"
+	"payment/internal/sqlite"
+)
+
+func TestAccountStore_AppendAuditRecord_ChainsRecords(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	ctx := core.WithRequestID(core.WithActor(context.Background(), "org:TESTIBAN"), "req-1")
+
+	for _, eventType := range []core.AuditEventType{core.AuditEventBalanceChanged, core.AuditEventBulkTransferAccepted} {
+		err := store.Atomic(ctx, func(r core.AccountRepository) error {
+			return r.AppendAuditRecord(ctx, core.NewAuditRecord(ctx, eventType, core.Account{ID: 1, BalanceCents: 500}, 1000))
+		})
+		require.NoError(t, err)
+	}
+
+	var (
+		verifier core.AuditChainVerifier
+		records  []core.AuditRecord
+	)
+	err := store.ForEachAuditRecord(context.Background(), func(record core.AuditRecord) error {
+		records = append(records, record)
+		return verifier.Verify(record)
+	})
+	require.NoError(t, err)
+
+	require.Len(t, records, 2)
+	require.Empty(t, records[0].PrevHash)
+	require.Equal(t, records[0].Hash, records[1].PrevHash)
+	require.Equal(t, "org:TESTIBAN", records[1].Actor)
+	require.Equal(t, "req-1", records[1].RequestID)
+	require.Equal(t, int64(1000), records[1].BalanceBeforeCents)
+	require.Equal(t, int64(500), records[1].BalanceAfterCents)
+}
+
+func TestAccountStore_AppendAuditRecord_RolledBackWithTransaction(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	errAbort := errors.New("abort")
+
+	err := store.Atomic(context.Background(), func(r core.AccountRepository) error {
+		if err := r.AppendAuditRecord(context.Background(), core.AuditRecord{EventType: core.AuditEventBalanceChanged}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+
+	count := 0
+	err = store.ForEachAuditRecord(context.Background(), func(core.AuditRecord) error {
+		count++
+		return nil
+	})
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func TestAuditLog_IsAppendOnly(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+
+	err := store.Atomic(context.Background(), func(r core.AccountRepository) error {
+		return r.AppendAuditRecord(context.Background(), core.AuditRecord{EventType: core.AuditEventBalanceChanged, BalanceAfterCents: 100})
+	})
+	require.NoError(t, err)
+
+	_, err = suite.DB.Exec("UPDATE audit_log SET balance_after_cents = 1000000")
+	require.ErrorContains(t, err, "append-only")
+
+	_, err = suite.DB.Exec("DELETE FROM audit_log")
+	require.ErrorContains(t, err, "append-only")
+}
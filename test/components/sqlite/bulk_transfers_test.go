@@ -0,0 +1,425 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"payment/internal/core"
+	"payment/internal/sqlite"
+)
+
+func TestAccountStore_GetAccountHistory(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+
+	accountID := suite.SeedAccount(t, "Test Org", "FR1420041010050500013M02606", "PSSTFRPPMON", 10000000)
+	otherAccountID := suite.SeedAccount(t, "Other Org", "FR2220041010050500013M02607", "PSSTFRPPMON", 10000000)
+
+	addBatch := func(accountID int64, ibans ...string) {
+		transfers := make([]core.Transfer, len(ibans))
+		for i, iban := range ibans {
+			transfers[i] = core.Transfer{
+				BankAccountID:    accountID,
+				CounterpartyName: "Recipient",
+				CounterpartyIBAN: iban,
+				CounterpartyBIC:  "BUKBGB22",
+				AmountCents:      1000,
+				Currency:         "EUR",
+				Description:      "Payment",
+			}
+		}
+
+		err := store.Atomic(context.Background(), func(r core.AccountRepository) error {
+			batchID, err := r.AddBulkTransfer(context.Background(), accountID, core.BulkTransfer{Transfers: transfers})
+			if err != nil {
+				return err
+			}
+			for i := range transfers {
+				transfers[i].BulkTransferID = batchID
+			}
+			return r.AddTransfers(context.Background(), transfers)
+		})
+		require.NoError(t, err)
+	}
+
+	addBatch(accountID, "IBAN1")
+	addBatch(accountID, "IBAN1", "IBAN2", "IBAN3")
+	addBatch(otherAccountID, "IBAN4")
+
+	// More counterparties than a single IN list chunk.
+	queried := []string{"IBAN2", "IBAN4", "IBAN5"}
+	for i := 0; i < 1000; i++ {
+		queried = append(queried, fmt.Sprintf("UNKNOWN%d", i))
+	}
+	queried = append(queried, "IBAN3")
+
+	var history core.AccountHistory
+	err := store.Atomic(context.Background(), func(r core.AccountRepository) error {
+		var err error
+		history, err = r.GetAccountHistory(context.Background(), accountID, queried)
+		return err
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 2, history.BatchCount)
+	require.Equal(t, int64(2000), history.AverageBatchTotalCents)
+	require.Equal(t, map[string]bool{"IBAN2": true, "IBAN3": true}, history.KnownCounterparties)
+}
+
+func TestAccountStore_GetAccountHistory_NoHistory(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	accountID := suite.SeedAccount(t, "Test Org", "FR1420041010050500013M02606", "PSSTFRPPMON", 10000000)
+
+	var history core.AccountHistory
+	err := store.Atomic(context.Background(), func(r core.AccountRepository) error {
+		var err error
+		history, err = r.GetAccountHistory(context.Background(), accountID, nil)
+		return err
+	})
+	require.NoError(t, err)
+
+	require.Zero(t, history.BatchCount)
+	require.Zero(t, history.AverageBatchTotalCents)
+	require.Empty(t, history.KnownCounterparties)
+}
+
+func TestAccountStore_CountTransfersSince(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	ctx := context.Background()
+
+	accountID := suite.SeedAccount(t, "Test Org", "FR1420041010050500013M02606", "PSSTFRPPMON", 10000000)
+	otherAccountID := suite.SeedAccount(t, "Test Org", "FR2220041010050500013M02607", "PSSTFRPPMON", 10000000)
+
+	// addBatch books a batch paying count transfers from accountID and one
+	// from otherAccountID, with its fees.
+	addBatch := func(count int) int64 {
+		var batchID int64
+		err := store.Atomic(ctx, func(r core.AccountRepository) error {
+			var err error
+			batchID, err = r.AddBulkTransfer(ctx, accountID, core.BulkTransfer{})
+			if err != nil {
+				return err
+			}
+
+			transfers := make([]core.Transfer, count+1)
+			for i := range transfers {
+				transfers[i] = core.Transfer{
+					BankAccountID:    accountID,
+					BulkTransferID:   batchID,
+					CounterpartyName: "Recipient",
+					CounterpartyIBAN: "IBAN1",
+					CounterpartyBIC:  "BUKBGB22",
+					AmountCents:      1000,
+					Currency:         "EUR",
+				}
+			}
+			transfers[count].BankAccountID = otherAccountID
+			if err = r.AddTransfers(ctx, transfers); err != nil {
+				return err
+			}
+
+			return r.AddTransaction(ctx, core.Transaction{
+				AccountID:      accountID,
+				BulkTransferID: batchID,
+				Kind:           core.TransactionKindFee,
+				AmountCents:    -20,
+				Currency:       "EUR",
+			})
+		})
+		require.NoError(t, err)
+		return batchID
+	}
+
+	lastMonth := addBatch(5)
+	addBatch(2)
+	addBatch(1)
+
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	_, err := suite.DB.Exec("UPDATE bulk_transfers SET created_at = ? WHERE id = ?",
+		month.Add(-time.Millisecond).Format(time.RFC3339Nano), lastMonth)
+	require.NoError(t, err)
+
+	count := func(accountID int64, since time.Time) int {
+		var count int
+		err := store.Atomic(ctx, func(r core.AccountRepository) error {
+			var err error
+			count, err = r.CountTransfersSince(ctx, accountID, since)
+			return err
+		})
+		require.NoError(t, err)
+		return count
+	}
+
+	require.Equal(t, 3, count(accountID, month))
+	require.Equal(t, 8, count(accountID, month.AddDate(0, -1, 0)))
+	require.Equal(t, 2, count(otherAccountID, month))
+}
+
+func TestAccountStore_AddBulkTransfer_PersistsRequestID(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	accountID := suite.SeedAccount(t, "Test Org", "FR1420041010050500013M02606", "PSSTFRPPMON", 10000000)
+
+	addBatch := func(requestID string) int64 {
+		var batchID int64
+		err := store.Atomic(context.Background(), func(r core.AccountRepository) error {
+			var err error
+			batchID, err = r.AddBulkTransfer(context.Background(), accountID, core.BulkTransfer{
+				RequestID: requestID,
+				Transfers: []core.Transfer{{AmountCents: 1000}},
+			})
+			return err
+		})
+		require.NoError(t, err)
+		return batchID
+	}
+
+	withID := addBatch("req-42")
+	withoutID := addBatch("")
+
+	var requestID sql.NullString
+	err := suite.DB.QueryRow("SELECT request_id FROM bulk_transfers WHERE id = ?", withID).Scan(&requestID)
+	require.NoError(t, err)
+	require.Equal(t, sql.NullString{String: "req-42", Valid: true}, requestID)
+
+	err = suite.DB.QueryRow("SELECT request_id FROM bulk_transfers WHERE id = ?", withoutID).Scan(&requestID)
+	require.NoError(t, err)
+	require.False(t, requestID.Valid)
+}
+
+func TestAccountStore_AddBulkTransfer_PersistsExecutionMode(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	accountID := suite.SeedAccount(t, "Test Org", "FR1420041010050500013M02606", "PSSTFRPPMON", 10000000)
+
+	tests := []struct {
+		name          string
+		executionMode core.ExecutionMode
+		expected      string
+	}{
+		{name: "defaults_to_all_or_nothing", executionMode: "", expected: "all_or_nothing"},
+		{name: "partial", executionMode: core.ExecutionModePartial, expected: "partial"},
+	}
+
+	for _, tt := range tests {
+		var batchID int64
+		err := store.Atomic(context.Background(), func(r core.AccountRepository) error {
+			var err error
+			batchID, err = r.AddBulkTransfer(context.Background(), accountID, core.BulkTransfer{
+				ExecutionMode: tt.executionMode,
+				Transfers:     []core.Transfer{{AmountCents: 1000}},
+			})
+			return err
+		})
+		require.NoError(t, err, tt.name)
+
+		var executionMode string
+		err = suite.DB.QueryRow("SELECT execution_mode FROM bulk_transfers WHERE id = ?", batchID).Scan(&executionMode)
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.expected, executionMode, tt.name)
+	}
+}
+
+func TestAccountStore_GetBulkTransfer(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	ctx := context.Background()
+
+	accountID := suite.SeedAccount(t, "Test Org", "FR1420041010050500013M02606", "PSSTFRPPMON", 10000000)
+
+	var bulkTransferID int64
+	err := store.Atomic(ctx, func(r core.AccountRepository) error {
+		var err error
+		bulkTransferID, err = r.AddBulkTransfer(ctx, accountID, core.BulkTransfer{
+			RequestID:     "req-1",
+			ExecutionMode: core.ExecutionModePartial,
+			Transfers:     []core.Transfer{{AmountCents: 1000}, {AmountCents: 2500}},
+		})
+		return err
+	})
+	require.NoError(t, err)
+
+	err = store.Atomic(ctx, func(r core.AccountRepository) error {
+		record, err := r.GetBulkTransfer(ctx, bulkTransferID)
+		require.NoError(t, err)
+
+		require.NotZero(t, record.CreatedAt)
+		record.CreatedAt = time.Time{}
+		require.Equal(t, core.BulkTransferRecord{
+			ID:               bulkTransferID,
+			AccountID:        accountID,
+			OrganizationIBAN: "FR1420041010050500013M02606",
+			OrganizationBIC:  "PSSTFRPPMON",
+			ExecutionMode:    core.ExecutionModePartial,
+			TotalCents:       3500,
+			TransferCount:    2,
+			RequestID:        "req-1",
+		}, record)
+
+		_, err = r.GetBulkTransfer(ctx, bulkTransferID+1)
+		require.ErrorIs(t, err, core.ErrBulkTransferNotFound)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestAccountStore_ListBulkTransfers(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	ctx := context.Background()
+
+	accountID := suite.SeedAccount(t, "Test Org", "FR1420041010050500013M02606", "PSSTFRPPMON", 10000000)
+	otherAccountID := suite.SeedAccount(t, "Other Org", "DE89370400440532013000", "COBADEFFXXX", 10000000)
+
+	var ids []int64
+	err := store.Atomic(ctx, func(r core.AccountRepository) error {
+		for _, id := range []int64{accountID, otherAccountID, accountID} {
+			bulkTransferID, err := r.AddBulkTransfer(ctx, id, core.BulkTransfer{
+				ExecutionMode: core.ExecutionModeAllOrNothing,
+				Transfers:     []core.Transfer{{AmountCents: 1000}},
+			})
+			if err != nil {
+				return err
+			}
+			ids = append(ids, bulkTransferID)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = store.Atomic(ctx, func(r core.AccountRepository) error {
+		records, err := r.ListBulkTransfers(ctx, accountID)
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		require.Equal(t, []int64{ids[0], ids[2]}, []int64{records[0].ID, records[1].ID})
+		require.Equal(t, "PSSTFRPPMON", records[0].OrganizationBIC)
+		require.NotZero(t, records[1].CreatedAt)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestAccountStore_ListTransactions(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	ctx := context.Background()
+
+	accountID := suite.SeedAccount(t, "Test Org", "FR1420041010050500013M02606", "PSSTFRPPMON", 10000000)
+	otherAccountID := suite.SeedAccount(t, "Other Org", "FR2220041010050500013M02607", "PSSTFRPPMON", 10000000)
+
+	addBatch := func(accountID int64, count int) int64 {
+		var bulkTransferID int64
+		err := store.Atomic(ctx, func(r core.AccountRepository) error {
+			var err error
+			bulkTransferID, err = r.AddBulkTransfer(ctx, accountID, core.BulkTransfer{})
+			if err != nil {
+				return err
+			}
+
+			transfers := make([]core.Transfer, count)
+			for i := range transfers {
+				transfers[i] = core.Transfer{
+					BankAccountID:    accountID,
+					BulkTransferID:   bulkTransferID,
+					CounterpartyName: "Recipient",
+					CounterpartyIBAN: fmt.Sprintf("IBAN%d", i),
+					CounterpartyBIC:  "BUKBGB22",
+					AmountCents:      int64(100 * (i + 1)),
+					Currency:         "EUR",
+					Description:      "Payment",
+				}
+			}
+			return r.AddTransfers(ctx, transfers)
+		})
+		require.NoError(t, err)
+		return bulkTransferID
+	}
+
+	firstBatch := addBatch(accountID, 3)
+	addBatch(otherAccountID, 2)
+	secondBatch := addBatch(accountID, 2)
+
+	// Transactions without a bulk transfer or description are listed too.
+	_, err := suite.DB.Exec(`
+		INSERT INTO transactions (counterparty_name, counterparty_iban, counterparty_bic, amount_cents, amount_currency, bank_account_id)
+		VALUES ('Top-up', 'IBANX', 'BICX', 5000, 'EUR', ?)
+	`, accountID)
+	require.NoError(t, err)
+
+	list := func(query core.TransactionQuery) []core.Transaction {
+		var transactions []core.Transaction
+		err := store.Atomic(ctx, func(r core.AccountRepository) error {
+			var err error
+			transactions, err = r.ListTransactions(ctx, accountID, query)
+			return err
+		})
+		require.NoError(t, err)
+		return transactions
+	}
+
+	firstPage := list(core.TransactionQuery{Limit: 4})
+	require.Len(t, firstPage, 4)
+	for _, transaction := range firstPage {
+		require.Equal(t, accountID, transaction.AccountID)
+	}
+	require.Equal(t, firstBatch, firstPage[0].BulkTransferID)
+	require.Equal(t, int64(-100), firstPage[0].AmountCents)
+	require.Equal(t, secondBatch, firstPage[3].BulkTransferID)
+
+	lastPage := list(core.TransactionQuery{AfterID: firstPage[3].ID, Limit: 4})
+	require.Len(t, lastPage, 2)
+	require.Equal(t, core.Transaction{
+		ID:               lastPage[1].ID,
+		AccountID:        accountID,
+		Kind:             core.TransactionKindTransfer,
+		CounterpartyName: "Top-up",
+		CounterpartyIBAN: "IBANX",
+		CounterpartyBIC:  "BICX",
+		AmountCents:      5000,
+		Currency:         "EUR",
+	}, lastPage[1])
+
+	batchPage := list(core.TransactionQuery{BulkTransferID: secondBatch, Limit: 10})
+	require.Len(t, batchPage, 2)
+	require.Equal(t, secondBatch, batchPage[1].BulkTransferID)
+}
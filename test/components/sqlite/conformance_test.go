@@ -0,0 +1,20 @@
+package integration
+
+import (
+	"testing"
+
+	"payment/internal/core"
+	"payment/internal/sqlite"
+	"payment/test/conformance"
+)
+
+func TestAccountStore_Conformance(t *testing.T) {
+	t.Parallel()
+
+	conformance.RunAccountRepository(t, func(t *testing.T) core.AccountRepository {
+		suite := NewTestSuite(t)
+		t.Cleanup(suite.Teardown)
+
+		return sqlite.NewAccountStore(suite.DB)
+	})
+}
@@ -0,0 +1,62 @@
+package integration
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"payment/internal/sqlite"
+)
+
+func TestClient_HealthChecks(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	ctx := context.Background()
+
+	require.NoError(t, suite.Client.Ping(ctx))
+	require.NoError(t, suite.Client.CheckSchema(ctx))
+	require.NoError(t, suite.Client.CheckJournalMode(ctx))
+
+	latest, err := sqlite.LatestSchemaVersion()
+	require.NoError(t, err)
+
+	version, err := suite.Client.SchemaVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, latest, version)
+}
+
+func TestClient_HealthChecks_UnmigratedDatabase(t *testing.T) {
+	t.Parallel()
+
+	client, err := sqlite.NewClient(sqlite.Config{
+		DatabasePath: filepath.Join(t.TempDir(), "unmigrated.db"),
+		MaxOpenConns: 1,
+		BusyTimeout:  time.Second,
+		EnableWAL:    false,
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := context.Background()
+
+	require.NoError(t, client.Ping(ctx))
+	require.ErrorContains(t, client.CheckSchema(ctx), "schema version is 0")
+	require.NoError(t, client.CheckJournalMode(ctx))
+}
+
+func TestClient_CheckJournalMode_WALNotActive(t *testing.T) {
+	t.Parallel()
+
+	// In-memory databases cannot use WAL and stay in "memory" journal mode.
+	client, err := sqlite.NewClient(sqlite.Config{DatabasePath: ":memory:", MaxOpenConns: 1, BusyTimeout: time.Second, EnableWAL: true})
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.ErrorContains(t, client.CheckJournalMode(context.Background()), `journal mode is "memory"`)
+}
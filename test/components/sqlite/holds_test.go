@@ -0,0 +1,71 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"payment/internal/core"
+	"payment/internal/sqlite"
+)
+
+func TestAccountStore_Holds(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	ctx := context.Background()
+
+	accountID := suite.SeedAccount(t, "Test Org", "FR1420041010050500013M02606", "PSSTFRPPMON", 10000)
+	now := time.Now()
+
+	holds := []core.Hold{
+		{AccountID: accountID, AmountCents: 1000, Reason: "pending", Status: core.HoldPending, CreatedAt: now, ExpiresAt: now.Add(time.Hour)},
+		{AccountID: accountID, AmountCents: 2000, Reason: "expired", Status: core.HoldPending, CreatedAt: now, ExpiresAt: now.Add(-time.Second)},
+		{AccountID: accountID, AmountCents: 4000, Reason: "released", Status: core.HoldPending, CreatedAt: now, ExpiresAt: now.Add(time.Hour)},
+	}
+
+	err := store.Atomic(ctx, func(r core.AccountRepository) error {
+		for i := range holds {
+			var err error
+			holds[i].ID, err = r.AddHold(ctx, holds[i])
+			require.NoError(t, err)
+		}
+
+		return r.SetHoldStatus(ctx, holds[2].ID, core.HoldReleased)
+	})
+	require.NoError(t, err)
+	holds[2].Status = core.HoldReleased
+
+	err = store.Atomic(ctx, func(r core.AccountRepository) error {
+		account, err := r.GetAccountByID(ctx, "FR1420041010050500013M02606", "PSSTFRPPMON")
+		require.NoError(t, err)
+		require.Equal(t, int64(1000), account.HeldCents, "only pending holds that have not expired are held")
+		require.Equal(t, int64(9000), account.AvailableCents())
+
+		accounts, err := r.ListAccounts(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(1000), accounts[0].HeldCents)
+
+		listed, err := r.ListHolds(ctx, accountID)
+		require.NoError(t, err)
+		require.Len(t, listed, 3)
+		for i, hold := range listed {
+			require.Equal(t, holds[i].ID, hold.ID)
+			require.Equal(t, holds[i].Status, hold.Status)
+			require.Equal(t, holds[i].Reason, hold.Reason)
+			require.True(t, holds[i].ExpiresAt.Equal(hold.ExpiresAt))
+		}
+		require.Equal(t, core.HoldExpired, listed[1].StatusAt(time.Now()))
+
+		_, err = r.GetHold(ctx, 999)
+		require.ErrorIs(t, err, core.ErrHoldNotFound)
+		require.ErrorIs(t, r.SetHoldStatus(ctx, 999, core.HoldCaptured), core.ErrHoldNotFound)
+		return nil
+	})
+	require.NoError(t, err)
+}
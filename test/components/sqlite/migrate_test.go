@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"payment/internal/sqlite"
+)
+
+func newUnmigratedClient(t *testing.T) *sqlite.Client {
+	t.Helper()
+
+	client, err := sqlite.NewClient(sqlite.Config{
+		DatabasePath: filepath.Join(t.TempDir(), "legacy.db"),
+		MaxOpenConns: 1,
+		BusyTimeout:  time.Second,
+		EnableWAL:    false,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func transactionKinds(t *testing.T, client *sqlite.Client) map[int64]string {
+	t.Helper()
+
+	rows, err := client.DB().Query(`SELECT amount_cents, kind FROM transactions`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	kinds := make(map[int64]string)
+	for rows.Next() {
+		var amountCents int64
+		var kind string
+		require.NoError(t, rows.Scan(&amountCents, &kind))
+		kinds[amountCents] = kind
+	}
+	require.NoError(t, rows.Err())
+
+	return kinds
+}
+
+func TestMigrate_KeepsLegacyTransfersOutOfCredits(t *testing.T) {
+	t.Parallel()
+
+	client := newUnmigratedClient(t)
+	ctx := context.Background()
+
+	// Before 0003_bulk_transfers.sql, transfers were booked without a bulk
+	// transfer.
+	require.NoError(t, client.MigrateTo(ctx, 2))
+	_, err := client.DB().Exec(`
+		INSERT INTO bank_accounts (organization_name, balance_cents, iban, bic)
+		VALUES ('ACME Corp', 1000, 'FR10474608000002006107XXXXX', 'OIVUSCLQXXX')
+	`)
+	require.NoError(t, err)
+	_, err = client.DB().Exec(`
+		INSERT INTO transactions (counterparty_name, counterparty_iban, counterparty_bic, amount_cents, bank_account_id, description)
+		VALUES ('Bip Bip', 'EE383680981021245685', 'CRLYFRPPTOU', -500, 1, 'legacy transfer'),
+		       ('ACME Corp', 'FR10474608000002006107XXXXX', 'OIVUSCLQXXX', 300, 1, 'manual credit')
+	`)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Migrate(ctx))
+
+	require.Equal(t, map[int64]string{-500: "transfer", 300: "credit"}, transactionKinds(t, client))
+}
+
+func TestMigrate_RelabelsLegacyTransfersBookedAsCredits(t *testing.T) {
+	t.Parallel()
+
+	client := newUnmigratedClient(t)
+	ctx := context.Background()
+
+	// Databases already at version 13 hold the credits 0012_pricing.sql mislabeled.
+	require.NoError(t, client.MigrateTo(ctx, 13))
+	_, err := client.DB().Exec(`
+		INSERT INTO bank_accounts (organization_name, balance_cents, iban, bic)
+		VALUES ('ACME Corp', 1000, 'FR10474608000002006107XXXXX', 'OIVUSCLQXXX')
+	`)
+	require.NoError(t, err)
+	_, err = client.DB().Exec(`
+		INSERT INTO transactions (counterparty_name, counterparty_iban, counterparty_bic, amount_cents, bank_account_id, description, kind)
+		VALUES ('Bip Bip', 'EE383680981021245685', 'CRLYFRPPTOU', -500, 1, 'legacy transfer', 'credit'),
+		       ('ACME Corp', 'FR10474608000002006107XXXXX', 'OIVUSCLQXXX', 300, 1, 'manual credit', 'credit')
+	`)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Migrate(ctx))
+
+	require.Equal(t, map[int64]string{-500: "transfer", 300: "credit"}, transactionKinds(t, client))
+}
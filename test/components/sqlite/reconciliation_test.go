@@ -0,0 +1,136 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"payment/internal/core"
+	"payment/internal/sqlite"
+)
+
+func TestAccountStore_CheckBalances(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	ctx := context.Background()
+
+	balancedID := suite.SeedAccount(t, "Balanced Org", "FR1420041010050500013M02606", "PSSTFRPPMON", 10000)
+	tamperedID := suite.SeedAccount(t, "Tampered Org", "DE89370400440532013000", "COBADEFFXXX", 10000)
+
+	err := store.Atomic(ctx, func(r core.AccountRepository) error {
+		for _, accountID := range []int64{balancedID, tamperedID} {
+			account := core.Account{ID: accountID, BalanceCents: 9000}
+			if err := r.UpdateBalance(ctx, account); err != nil {
+				return err
+			}
+
+			err := r.AddTransfers(ctx, []core.Transfer{{
+				BankAccountID:    accountID,
+				CounterpartyName: "Bip Bip",
+				CounterpartyIBAN: "EE383680981021245685",
+				CounterpartyBIC:  "CRLYFRPPTOU",
+				AmountCents:      1000,
+				Currency:         "EUR",
+				Description:      "Test",
+			}})
+			if err != nil {
+				return err
+			}
+		}
+
+		return r.AddTransaction(ctx, core.Transaction{
+			AccountID:        balancedID,
+			CounterpartyName: "Balanced Org",
+			CounterpartyIBAN: "FR1420041010050500013M02606",
+			CounterpartyBIC:  "PSSTFRPPMON",
+			AmountCents:      500,
+			Currency:         "EUR",
+			Description:      "Manual credit",
+		})
+	})
+	require.NoError(t, err)
+
+	_, err = suite.DB.Exec("UPDATE bank_accounts SET balance_cents = balance_cents + 500 WHERE id IN (?, ?)", balancedID, tamperedID)
+	require.NoError(t, err)
+
+	var report core.ReconciliationReport
+	err = store.Atomic(ctx, func(r core.AccountRepository) error {
+		checks, err := r.CheckBalances(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []core.BalanceCheck{
+			{AccountID: balancedID, IBAN: "FR1420041010050500013M02606", BIC: "PSSTFRPPMON", OpeningBalanceCents: 10000, TransactionsCents: -500, BalanceCents: 9500},
+			{AccountID: tamperedID, IBAN: "DE89370400440532013000", BIC: "COBADEFFXXX", OpeningBalanceCents: 10000, TransactionsCents: -1000, BalanceCents: 9500},
+		}, checks)
+
+		report = core.ReconciliationReport{
+			StartedAt:     time.Now(),
+			FinishedAt:    time.Now(),
+			AccountCount:  len(checks),
+			Discrepancies: checks[1:],
+		}
+		report.ID, err = r.AddReconciliationReport(ctx, report)
+		return err
+	})
+	require.NoError(t, err)
+
+	var discrepancyCount int
+	err = suite.DB.QueryRow("SELECT discrepancy_count FROM reconciliation_reports WHERE id = ?", report.ID).Scan(&discrepancyCount)
+	require.NoError(t, err)
+	require.Equal(t, 1, discrepancyCount)
+
+	var accountID, discrepancyCents int64
+	err = suite.DB.QueryRow("SELECT bank_account_id, discrepancy_cents FROM reconciliation_discrepancies WHERE report_id = ?", report.ID).
+		Scan(&accountID, &discrepancyCents)
+	require.NoError(t, err)
+	require.Equal(t, tamperedID, accountID)
+	require.Equal(t, int64(500), discrepancyCents)
+}
+
+func TestReconciler_ReadOnlyClientDoesNotWaitForWriters(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	accountID := suite.SeedAccount(t, "Test Org", "FR1420041010050500013M02606", "PSSTFRPPMON", 10000)
+
+	readOnlyClient, err := sqlite.NewReadOnlyClient(sqlite.Config{DatabasePath: suite.DBPath, BusyTimeout: 30 * time.Second})
+	require.NoError(t, err)
+	defer readOnlyClient.Close()
+
+	// A writer holds the write lock with an uncommitted change.
+	tx, err := suite.DB.Begin()
+	require.NoError(t, err)
+	defer tx.Rollback()
+	_, err = tx.Exec("UPDATE bank_accounts SET balance_cents = 0 WHERE id = ?", accountID)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reconciler := core.NewReconciler(sqlite.NewAccountStore(readOnlyClient.DB()), sqlite.NewAccountStore(suite.DB))
+	err = sqlite.NewAccountStore(readOnlyClient.DB()).Atomic(ctx, func(r core.AccountRepository) error {
+		checks, err := r.CheckBalances(ctx)
+		require.NoError(t, err)
+		require.Len(t, checks, 1)
+		require.Equal(t, int64(10000), checks[0].BalanceCents, "uncommitted changes must not be visible")
+
+		_, err = r.CreateAccount(ctx, core.Account{OrganizationName: "New Org", IBAN: "DE89370400440532013000", BIC: "COBADEFFXXX"})
+		require.Error(t, err, "the connection must be read-only")
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, tx.Rollback())
+
+	report, err := reconciler.Reconcile(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.AccountCount)
+	require.Empty(t, report.Discrepancies)
+}
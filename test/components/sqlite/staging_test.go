@@ -0,0 +1,162 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"payment/internal/core"
+	"payment/internal/sqlite"
+)
+
+func TestAccountStore_StagedBulkTransfer(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	ctx := context.Background()
+
+	var stagedID int64
+	err := store.Atomic(ctx, func(r core.AccountRepository) error {
+		var err error
+		stagedID, err = r.AddStagedBulkTransfer(ctx, core.BulkTransfer{
+			OrganizationIBAN: "FR1420041010050500013M02606",
+			OrganizationBIC:  "PSSTFRPPMON",
+			ExecutionMode:    core.ExecutionModePartial,
+			RequestID:        "req-1",
+		})
+		return err
+	})
+	require.NoError(t, err)
+
+	// Chunks are staged in transactions of their own and read back in order of
+	// position, after a cursor. Every tenth position is missing, as for an
+	// invalid line.
+	var expected []core.StagedTransfer
+	position := 0
+	for chunk := range 3 {
+		transfers := make([]core.StagedTransfer, 700)
+		for i := range transfers {
+			if position%10 == 9 {
+				position++
+			}
+			transfers[i] = core.StagedTransfer{Position: position, Transfer: core.Transfer{
+				CounterpartyName: "Recipient",
+				CounterpartyIBAN: fmt.Sprintf("IBAN%d-%d", chunk, i),
+				CounterpartyBIC:  "BUKBGB22",
+				AmountCents:      int64(chunk*1000 + i + 1),
+				Currency:         "EUR",
+				Description:      "Payment",
+			}}
+			if i%2 == 1 {
+				transfers[i].Transfer.DebtorIBAN = "FR7630006000011234567890189"
+				transfers[i].Transfer.DebtorBIC = "AGRIFRPPXXX"
+			}
+			position++
+		}
+		expected = append(expected, transfers...)
+
+		err = store.Atomic(ctx, func(r core.AccountRepository) error {
+			return r.AddStagedTransfers(ctx, stagedID, transfers)
+		})
+		require.NoError(t, err)
+	}
+
+	err = store.Atomic(ctx, func(r core.AccountRepository) error {
+		staged, err := r.GetStagedBulkTransfer(ctx, stagedID)
+		require.NoError(t, err)
+		require.Equal(t, core.BulkTransfer{
+			OrganizationIBAN: "FR1420041010050500013M02606",
+			OrganizationBIC:  "PSSTFRPPMON",
+			ExecutionMode:    core.ExecutionModePartial,
+			RequestID:        "req-1",
+		}, staged)
+
+		var listed []core.StagedTransfer
+		for afterPosition := -1; ; {
+			chunk, err := r.ListStagedTransfers(ctx, stagedID, afterPosition, 1000)
+			require.NoError(t, err)
+			if len(chunk) == 0 {
+				break
+			}
+			listed = append(listed, chunk...)
+			afterPosition = chunk[len(chunk)-1].Position
+		}
+		require.Equal(t, expected, listed)
+
+		return r.DeleteStagedBulkTransfer(ctx, stagedID)
+	})
+	require.NoError(t, err)
+
+	err = store.Atomic(ctx, func(r core.AccountRepository) error {
+		_, err := r.GetStagedBulkTransfer(ctx, stagedID)
+		return err
+	})
+	require.ErrorIs(t, err, core.ErrStagedBulkTransferNotFound)
+
+	var remaining int
+	require.NoError(t, suite.DB.QueryRow("SELECT COUNT(*) FROM staged_transfers").Scan(&remaining))
+	require.Zero(t, remaining)
+}
+
+func TestAccountStore_DeleteStagedBulkTransfersBefore(t *testing.T) {
+	t.Parallel()
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	store := sqlite.NewAccountStore(suite.DB)
+	ctx := context.Background()
+	transfer := core.Transfer{CounterpartyName: "Recipient", CounterpartyIBAN: "IBAN", AmountCents: 100, Currency: "EUR"}
+
+	var abandonedID, currentID int64
+	err := store.Atomic(ctx, func(r core.AccountRepository) error {
+		var err error
+		abandonedID, err = r.AddStagedBulkTransfer(ctx, core.BulkTransfer{OrganizationIBAN: "IBAN", OrganizationBIC: "BIC"})
+		if err != nil {
+			return err
+		}
+		currentID, err = r.AddStagedBulkTransfer(ctx, core.BulkTransfer{OrganizationIBAN: "IBAN", OrganizationBIC: "BIC"})
+		if err != nil {
+			return err
+		}
+
+		for _, id := range []int64{abandonedID, currentID, currentID + 1} {
+			if err = r.AddStagedTransfers(ctx, id, []core.StagedTransfer{{Position: 0, Transfer: transfer}}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = suite.DB.Exec("UPDATE staged_bulk_transfers SET created_at = '2025-01-01T00:00:00Z' WHERE id = ?", abandonedID)
+	require.NoError(t, err)
+
+	var deleted int
+	err = store.Atomic(ctx, func(r core.AccountRepository) error {
+		var err error
+		deleted, err = r.DeleteStagedBulkTransfersBefore(ctx, time.Now().Add(-time.Hour))
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+
+	// The transfers of the abandoned batch and the orphaned one are gone.
+	var remaining []int64
+	rows, err := suite.DB.Query("SELECT staged_bulk_transfer_id FROM staged_transfers")
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		require.NoError(t, rows.Scan(&id))
+		remaining = append(remaining, id)
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, []int64{currentID}, remaining)
+}
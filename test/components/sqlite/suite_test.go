@@ -1,6 +1,7 @@
 package integration
 
 import (
+	"context"
 	"database/sql"
 	"os"
 	"path/filepath"
@@ -36,30 +37,8 @@ func NewTestSuite(t *testing.T) *TestSuite {
 	client, err := sqlite.NewClient(config)
 	require.NoError(t, err, "failed to create test client")
 
-	schema := `
-		CREATE TABLE IF NOT EXISTS bank_accounts (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			organization_name TEXT NOT NULL,
-			balance_cents INTEGER NOT NULL DEFAULT 0,
-			iban TEXT NOT NULL,
-			bic TEXT NOT NULL,
-			UNIQUE(iban, bic)
-		);
-
-		CREATE TABLE IF NOT EXISTS transactions (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			counterparty_name TEXT NOT NULL,
-			counterparty_iban TEXT NOT NULL,
-			counterparty_bic TEXT NOT NULL,
-			amount_cents INTEGER NOT NULL,
-			amount_currency TEXT NOT NULL DEFAULT 'EUR',
-			bank_account_id INTEGER NOT NULL,
-			description TEXT
-		);
-	`
-
-	_, err = client.DB().Exec(schema)
-	require.NoError(t, err, "failed to create schema")
+	err = client.Migrate(context.Background())
+	require.NoError(t, err, "failed to migrate schema")
 
 	suite := &TestSuite{
 		DB:     client.DB(),
@@ -82,11 +61,11 @@ func (s *TestSuite) SeedAccount(t *testing.T, orgName, iban, bic string, balance
 	t.Helper()
 
 	query := `
-		INSERT INTO bank_accounts (organization_name, iban, bic, balance_cents)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO bank_accounts (organization_name, iban, bic, balance_cents, opening_balance_cents)
+		VALUES (?, ?, ?, ?, ?)
 	`
 
-	result, err := s.DB.Exec(query, orgName, iban, bic, balanceCents)
+	result, err := s.DB.Exec(query, orgName, iban, bic, balanceCents, balanceCents)
 	require.NoError(t, err, "failed to seed account")
 
 	id, err := result.LastInsertId()
@@ -0,0 +1,813 @@
+// Package conformance checks that an AccountRepository implementation behaves
+// like the others, through the port alone.
+package conformance
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"payment/internal/core"
+)
+
+const (
+	iban = "FR1420041010050500013M02606"
+	bic  = "PSSTFRPPMON"
+)
+
+// AuditLogReader is implemented by repositories whose audit log can be read
+// back.
+type AuditLogReader interface {
+	ForEachAuditRecord(ctx context.Context, fn func(core.AuditRecord) error) error
+}
+
+// RunAccountRepository runs the conformance tests against the repositories
+// returned by newRepository, which must be empty and migrated.
+func RunAccountRepository(t *testing.T, newRepository func(t *testing.T) core.AccountRepository) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repo core.AccountRepository)
+	}{
+		{"requires_a_transaction", testRequiresTransaction},
+		{"accounts", testAccounts},
+		{"account_settings", testAccountSettings},
+		{"update_balance_checks_the_version", testUpdateBalanceVersion},
+		{"atomic_commits_on_success", testAtomicCommit},
+		{"atomic_rolls_back_on_error", testAtomicRollback},
+		{"nested_atomic", testNestedAtomic},
+		{"atomic_serializes_writers", testAtomicSerializesWriters},
+		{"bulk_transfers_and_transactions", testBulkTransfers},
+		{"account_history", testAccountHistory},
+		{"staged_bulk_transfers", testStagedBulkTransfers},
+		{"held_bulk_transfers", testHeldBulkTransfers},
+		{"holds", testHolds},
+		{"reconciliation", testReconciliation},
+		{"audit_log", testAuditLog},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.test(t, newRepository(t))
+		})
+	}
+}
+
+// atomic runs fn in a transaction of repo and fails the test on error.
+func atomic(t *testing.T, repo core.AccountRepository, fn func(r core.AccountRepository) error) {
+	t.Helper()
+
+	require.NoError(t, repo.Atomic(context.Background(), fn))
+}
+
+func createAccount(t *testing.T, repo core.AccountRepository, account core.Account) int64 {
+	t.Helper()
+
+	var accountID int64
+	atomic(t, repo, func(r core.AccountRepository) error {
+		var err error
+		accountID, err = r.CreateAccount(context.Background(), account)
+		return err
+	})
+
+	return accountID
+}
+
+func getAccount(t *testing.T, repo core.AccountRepository, iban string, bic string) core.Account {
+	t.Helper()
+
+	var account core.Account
+	atomic(t, repo, func(r core.AccountRepository) error {
+		var err error
+		account, err = r.GetAccountByID(context.Background(), iban, bic)
+		return err
+	})
+
+	return account
+}
+
+func testRequiresTransaction(t *testing.T, repo core.AccountRepository) {
+	_, err := repo.GetAccountByID(context.Background(), iban, bic)
+	require.EqualError(t, err, "GetAccountByID must be called within Atomic transaction")
+
+	err = repo.UpdateBalance(context.Background(), core.Account{ID: 1})
+	require.EqualError(t, err, "UpdateBalance must be called within Atomic transaction")
+}
+
+func testAccounts(t *testing.T, repo core.AccountRepository) {
+	ctx := context.Background()
+
+	accountID := createAccount(t, repo, core.Account{
+		OrganizationName:    "ACME Corp",
+		BalanceCents:        100000,
+		IBAN:                iban,
+		BIC:                 bic,
+		OverdraftLimitCents: 5000,
+		PricingPlan:         "business",
+		OrganizationID:      "org-acme",
+	})
+	otherID := createAccount(t, repo, core.Account{OrganizationName: "ACME Corp", IBAN: iban, BIC: "OTHERBIC", Currency: "CHF"})
+	require.NotEqual(t, accountID, otherID)
+	require.Equal(t, "CHF", getAccount(t, repo, iban, "OTHERBIC").Currency)
+
+	expected := core.Account{
+		ID:                  accountID,
+		OrganizationName:    "ACME Corp",
+		BalanceCents:        100000,
+		IBAN:                iban,
+		BIC:                 bic,
+		Currency:            core.DefaultCurrency,
+		OverdraftLimitCents: 5000,
+		PricingPlan:         "business",
+		OrganizationID:      "org-acme",
+	}
+	require.Equal(t, expected, getAccount(t, repo, iban, bic))
+
+	atomic(t, repo, func(r core.AccountRepository) error {
+		_, err := r.CreateAccount(ctx, core.Account{OrganizationName: "Copycat", IBAN: iban, BIC: bic})
+		require.ErrorIs(t, err, core.ErrAccountExists)
+
+		_, err = r.GetAccountByID(ctx, "DE89370400440532013000", bic)
+		require.ErrorIs(t, err, core.ErrAccountNotFound)
+
+		accounts, err := r.ListAccounts(ctx)
+		require.NoError(t, err)
+		require.Len(t, accounts, 2)
+		require.Equal(t, expected, accounts[0])
+		require.Equal(t, otherID, accounts[1].ID)
+		return nil
+	})
+}
+
+func testAccountSettings(t *testing.T, repo core.AccountRepository) {
+	ctx := context.Background()
+	accountID := createAccount(t, repo, core.Account{OrganizationName: "ACME Corp", IBAN: iban, BIC: bic})
+
+	atomic(t, repo, func(r core.AccountRepository) error {
+		require.NoError(t, r.SetAccountFrozen(ctx, accountID, true))
+		require.NoError(t, r.SetOverdraftLimit(ctx, accountID, 50000))
+		require.NoError(t, r.SetPricingPlan(ctx, accountID, "business"))
+
+		require.Error(t, r.SetAccountFrozen(ctx, accountID+100, true))
+		require.Error(t, r.SetOverdraftLimit(ctx, accountID+100, 0))
+		require.Error(t, r.SetPricingPlan(ctx, accountID+100, ""))
+		return nil
+	})
+
+	account := getAccount(t, repo, iban, bic)
+	require.True(t, account.Frozen)
+	require.Equal(t, int64(50000), account.OverdraftLimitCents)
+	require.Equal(t, "business", account.PricingPlan)
+
+	err := repo.Atomic(ctx, func(r core.AccountRepository) error {
+		return r.SetOverdraftLimit(ctx, accountID, -1)
+	})
+	require.Error(t, err)
+}
+
+func testUpdateBalanceVersion(t *testing.T, repo core.AccountRepository) {
+	ctx := context.Background()
+	accountID := createAccount(t, repo, core.Account{OrganizationName: "ACME Corp", BalanceCents: 1000, IBAN: iban, BIC: bic})
+
+	stale := getAccount(t, repo, iban, bic)
+	atomic(t, repo, func(r core.AccountRepository) error {
+		account := stale
+		account.BalanceCents = 900
+		return r.UpdateBalance(ctx, account)
+	})
+
+	account := getAccount(t, repo, iban, bic)
+	require.Equal(t, int64(900), account.BalanceCents)
+	require.Equal(t, stale.Version+1, account.Version)
+
+	err := repo.Atomic(ctx, func(r core.AccountRepository) error {
+		stale.BalanceCents = 800
+		return r.UpdateBalance(ctx, stale)
+	})
+
+	var conflictErr *core.VersionConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	require.Equal(t, accountID, conflictErr.AccountID)
+	require.Equal(t, int64(900), getAccount(t, repo, iban, bic).BalanceCents)
+
+	err = repo.Atomic(ctx, func(r core.AccountRepository) error {
+		return r.UpdateBalance(ctx, core.Account{ID: accountID + 1, BalanceCents: 800})
+	})
+	require.ErrorIs(t, err, core.ErrAccountNotFound)
+	require.NotErrorIs(t, err, core.ErrVersionConflict)
+}
+
+func debit(r core.AccountRepository, amountCents int64) error {
+	account, err := r.GetAccountByID(context.Background(), iban, bic)
+	if err != nil {
+		return err
+	}
+
+	if err = account.Debit(amountCents); err != nil {
+		return err
+	}
+
+	return r.UpdateBalance(context.Background(), account)
+}
+
+func testAtomicCommit(t *testing.T, repo core.AccountRepository) {
+	createAccount(t, repo, core.Account{OrganizationName: "ACME Corp", BalanceCents: 1000, IBAN: iban, BIC: bic})
+
+	atomic(t, repo, func(r core.AccountRepository) error {
+		if err := debit(r, 100); err != nil {
+			return err
+		}
+
+		// Writes are visible within the transaction.
+		account, err := r.GetAccountByID(context.Background(), iban, bic)
+		require.NoError(t, err)
+		require.Equal(t, int64(900), account.BalanceCents)
+		return nil
+	})
+
+	require.Equal(t, int64(900), getAccount(t, repo, iban, bic).BalanceCents)
+}
+
+func testAtomicRollback(t *testing.T, repo core.AccountRepository) {
+	ctx := context.Background()
+	createAccount(t, repo, core.Account{OrganizationName: "ACME Corp", BalanceCents: 1000, IBAN: iban, BIC: bic})
+
+	errAbort := errors.New("abort")
+	err := repo.Atomic(ctx, func(r core.AccountRepository) error {
+		if err := debit(r, 100); err != nil {
+			return err
+		}
+
+		_, err := r.CreateAccount(ctx, core.Account{OrganizationName: "ACME Corp", IBAN: iban, BIC: "OTHERBIC"})
+		require.NoError(t, err)
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+
+	require.Equal(t, int64(1000), getAccount(t, repo, iban, bic).BalanceCents)
+	atomic(t, repo, func(r core.AccountRepository) error {
+		_, err := r.GetAccountByID(ctx, iban, "OTHERBIC")
+		require.ErrorIs(t, err, core.ErrAccountNotFound)
+		return nil
+	})
+}
+
+func testNestedAtomic(t *testing.T, repo core.AccountRepository) {
+	ctx := context.Background()
+	createAccount(t, repo, core.Account{OrganizationName: "ACME Corp", BalanceCents: 1000, IBAN: iban, BIC: bic})
+
+	errInner := errors.New("inner failure")
+	atomic(t, repo, func(r core.AccountRepository) error {
+		if err := debit(r, 100); err != nil {
+			return err
+		}
+
+		err := r.Atomic(ctx, func(nested core.AccountRepository) error {
+			if err := debit(nested, 200); err != nil {
+				return err
+			}
+			return errInner
+		})
+		require.ErrorIs(t, err, errInner)
+
+		return r.Atomic(ctx, func(nested core.AccountRepository) error {
+			return debit(nested, 300)
+		})
+	})
+
+	require.Equal(t, int64(600), getAccount(t, repo, iban, bic).BalanceCents)
+
+	errOuter := errors.New("outer failure")
+	err := repo.Atomic(ctx, func(r core.AccountRepository) error {
+		require.NoError(t, r.Atomic(ctx, func(nested core.AccountRepository) error {
+			return debit(nested, 100)
+		}))
+		return errOuter
+	})
+	require.ErrorIs(t, err, errOuter)
+	require.Equal(t, int64(600), getAccount(t, repo, iban, bic).BalanceCents)
+}
+
+func testAtomicSerializesWriters(t *testing.T, repo core.AccountRepository) {
+	createAccount(t, repo, core.Account{OrganizationName: "ACME Corp", BalanceCents: 1000, IBAN: iban, BIC: bic})
+
+	// Each writer reads, then debits: without serialization, several would
+	// pass the funds check on the same balance.
+	const writers = 8
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = repo.Atomic(context.Background(), func(r core.AccountRepository) error {
+				return debit(r, 300)
+			})
+		}()
+	}
+	wg.Wait()
+
+	var succeeded int
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, core.ErrInsufficientFunds)
+	}
+
+	require.Equal(t, 3, succeeded)
+	require.Equal(t, int64(100), getAccount(t, repo, iban, bic).BalanceCents)
+}
+
+func testBulkTransfers(t *testing.T, repo core.AccountRepository) {
+	ctx := context.Background()
+	accountID := createAccount(t, repo, core.Account{OrganizationName: "ACME Corp", BalanceCents: 100000, IBAN: iban, BIC: bic})
+	since := time.Now().Add(-time.Minute)
+
+	bulkTransfer := core.BulkTransfer{
+		OrganizationIBAN: iban,
+		OrganizationBIC:  bic,
+		RequestID:        "req-1",
+		Transfers: []core.Transfer{
+			{CounterpartyName: "Bip Bip", CounterpartyIBAN: "EE383680981021245685", CounterpartyBIC: "CRLYFRPPTOU", AmountCents: 1000, Currency: "EUR", Description: "Wonderland"},
+			{CounterpartyName: "Wile E", CounterpartyIBAN: "IT60X0542811101000000123456", CounterpartyBIC: "RNJZNTMC", AmountCents: 2000, Currency: "EUR", Description: "Anvils"},
+		},
+	}
+
+	var bulkTransferID int64
+	atomic(t, repo, func(r core.AccountRepository) error {
+		var err error
+		bulkTransferID, err = r.AddBulkTransfer(ctx, accountID, bulkTransfer)
+		if err != nil {
+			return err
+		}
+
+		transfers := make([]core.Transfer, 0, len(bulkTransfer.Transfers))
+		for _, transfer := range bulkTransfer.Transfers {
+			transfer.BankAccountID = accountID
+			transfer.BulkTransferID = bulkTransferID
+			transfers = append(transfers, transfer)
+		}
+		if err = r.AddTransfers(ctx, transfers); err != nil {
+			return err
+		}
+
+		require.Error(t, r.AddTransfers(ctx, []core.Transfer{{AmountCents: 100}}))
+
+		return r.AddTransaction(ctx, core.Transaction{
+			AccountID:        accountID,
+			BulkTransferID:   bulkTransferID,
+			Kind:             core.TransactionKindFee,
+			CounterpartyName: "ACME Corp",
+			CounterpartyIBAN: iban,
+			CounterpartyBIC:  bic,
+			AmountCents:      -40,
+			Currency:         "EUR",
+			Description:      "Transfer fees",
+		})
+	})
+
+	atomic(t, repo, func(r core.AccountRepository) error {
+		record, err := r.GetBulkTransfer(ctx, bulkTransferID)
+		require.NoError(t, err)
+		require.Equal(t, accountID, record.AccountID)
+		require.Equal(t, iban, record.OrganizationIBAN)
+		require.Equal(t, bic, record.OrganizationBIC)
+		require.Equal(t, core.ExecutionModeAllOrNothing, record.ExecutionMode)
+		require.Equal(t, int64(3000), record.TotalCents)
+		require.Equal(t, 2, record.TransferCount)
+		require.Equal(t, "req-1", record.RequestID)
+		require.WithinDuration(t, time.Now(), record.CreatedAt, time.Minute)
+
+		_, err = r.GetBulkTransfer(ctx, bulkTransferID+100)
+		require.ErrorIs(t, err, core.ErrBulkTransferNotFound)
+
+		records, err := r.ListBulkTransfers(ctx, accountID)
+		require.NoError(t, err)
+		require.Equal(t, []core.BulkTransferRecord{record}, records)
+
+		count, err := r.CountTransfersSince(ctx, accountID, since)
+		require.NoError(t, err)
+		require.Equal(t, 2, count, "fees are not transfers")
+
+		count, err = r.CountTransfersSince(ctx, accountID, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.Zero(t, count)
+
+		transactions, err := r.ListTransactions(ctx, accountID, core.TransactionQuery{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, transactions, 3)
+		require.Equal(t, core.Transaction{
+			ID:               transactions[0].ID,
+			AccountID:        accountID,
+			BulkTransferID:   bulkTransferID,
+			Kind:             core.TransactionKindTransfer,
+			CounterpartyName: "Bip Bip",
+			CounterpartyIBAN: "EE383680981021245685",
+			CounterpartyBIC:  "CRLYFRPPTOU",
+			AmountCents:      -1000,
+			Currency:         "EUR",
+			Description:      "Wonderland",
+		}, transactions[0])
+		require.Equal(t, int64(-2000), transactions[1].AmountCents)
+		require.Equal(t, core.TransactionKindFee, transactions[2].Kind)
+		require.Equal(t, int64(-40), transactions[2].AmountCents)
+
+		page, err := r.ListTransactions(ctx, accountID, core.TransactionQuery{AfterID: transactions[0].ID, Limit: 1})
+		require.NoError(t, err)
+		require.Equal(t, transactions[1:2], page)
+
+		page, err = r.ListTransactions(ctx, accountID, core.TransactionQuery{BulkTransferID: bulkTransferID + 100, Limit: 10})
+		require.NoError(t, err)
+		require.Empty(t, page)
+		return nil
+	})
+}
+
+func testAccountHistory(t *testing.T, repo core.AccountRepository) {
+	ctx := context.Background()
+	accountID := createAccount(t, repo, core.Account{OrganizationName: "ACME Corp", BalanceCents: 100000, IBAN: iban, BIC: bic})
+
+	atomic(t, repo, func(r core.AccountRepository) error {
+		history, err := r.GetAccountHistory(ctx, accountID, []string{"EE383680981021245685"})
+		require.NoError(t, err)
+		require.Equal(t, core.AccountHistory{KnownCounterparties: map[string]bool{}}, history)
+
+		for _, amountCents := range []int64{1000, 2001} {
+			bulkTransferID, err := r.AddBulkTransfer(ctx, accountID, core.BulkTransfer{
+				Transfers: []core.Transfer{{AmountCents: amountCents}},
+			})
+			if err != nil {
+				return err
+			}
+
+			err = r.AddTransfers(ctx, []core.Transfer{{
+				BankAccountID:    accountID,
+				BulkTransferID:   bulkTransferID,
+				CounterpartyName: "Bip Bip",
+				CounterpartyIBAN: "EE383680981021245685",
+				CounterpartyBIC:  "CRLYFRPPTOU",
+				AmountCents:      amountCents,
+				Currency:         "EUR",
+			}})
+			if err != nil {
+				return err
+			}
+		}
+
+		history, err = r.GetAccountHistory(ctx, accountID, []string{"EE383680981021245685", "IT60X0542811101000000123456"})
+		require.NoError(t, err)
+		require.Equal(t, core.AccountHistory{
+			BatchCount:             2,
+			AverageBatchTotalCents: 1500,
+			KnownCounterparties:    map[string]bool{"EE383680981021245685": true},
+		}, history)
+		return nil
+	})
+
+	// A batch of another account that this account pays part of counts with
+	// that part only, for both of them.
+	otherID := createAccount(t, repo, core.Account{OrganizationName: "ACME Corp", BalanceCents: 100000, IBAN: iban, BIC: "OTHERBIC"})
+	atomic(t, repo, func(r core.AccountRepository) error {
+		bulkTransferID, err := r.AddBulkTransfer(ctx, otherID, core.BulkTransfer{
+			Transfers: []core.Transfer{{AmountCents: 3000}, {AmountCents: 5000}},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = r.AddTransfers(ctx, []core.Transfer{
+			{BankAccountID: otherID, BulkTransferID: bulkTransferID, CounterpartyIBAN: "EE383680981021245685", AmountCents: 3000, Currency: "EUR"},
+			{BankAccountID: accountID, BulkTransferID: bulkTransferID, CounterpartyIBAN: "EE383680981021245685", AmountCents: 5000, Currency: "EUR"},
+		})
+		if err != nil {
+			return err
+		}
+
+		history, err := r.GetAccountHistory(ctx, accountID, nil)
+		require.NoError(t, err)
+		require.Equal(t, 3, history.BatchCount)
+		require.Equal(t, int64(2667), history.AverageBatchTotalCents)
+
+		history, err = r.GetAccountHistory(ctx, otherID, nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, history.BatchCount)
+		require.Equal(t, int64(3000), history.AverageBatchTotalCents)
+		return nil
+	})
+}
+
+func testStagedBulkTransfers(t *testing.T, repo core.AccountRepository) {
+	ctx := context.Background()
+
+	staged := core.BulkTransfer{
+		OrganizationIBAN: iban,
+		OrganizationBIC:  bic,
+		RequestID:        "req-1",
+		ExecutionMode:    core.ExecutionModePartial,
+		HoldID:           3,
+	}
+	transfers := []core.StagedTransfer{
+		{Position: 0, Transfer: core.Transfer{CounterpartyName: "Bip Bip", CounterpartyIBAN: "EE383680981021245685", CounterpartyBIC: "CRLYFRPPTOU", AmountCents: 1000, Currency: "EUR", Description: "First"}},
+		{Position: 2, Transfer: core.Transfer{CounterpartyName: "Wile E", CounterpartyIBAN: "IT60X0542811101000000123456", CounterpartyBIC: "RNJZNTMC", AmountCents: 2000, Currency: "EUR", Description: "Second", DebtorIBAN: iban, DebtorBIC: "OTHERBIC"}},
+		{Position: 3, Transfer: core.Transfer{CounterpartyName: "Road Runner", CounterpartyIBAN: "DE89370400440532013000", CounterpartyBIC: "COBADEFFXXX", AmountCents: 3000, Currency: "EUR", Description: "Third"}},
+	}
+
+	var firstID, secondID int64
+	atomic(t, repo, func(r core.AccountRepository) error {
+		var err error
+		firstID, err = r.AddStagedBulkTransfer(ctx, staged)
+		if err != nil {
+			return err
+		}
+		// Positions, not insertion order, give the order of the transfers.
+		if err = r.AddStagedTransfers(ctx, firstID, transfers[2:]); err != nil {
+			return err
+		}
+		if err = r.AddStagedTransfers(ctx, firstID, transfers[:2]); err != nil {
+			return err
+		}
+
+		secondID, err = r.AddStagedBulkTransfer(ctx, core.BulkTransfer{OrganizationIBAN: iban, OrganizationBIC: bic})
+		return err
+	})
+	require.NotEqual(t, firstID, secondID)
+
+	atomic(t, repo, func(r core.AccountRepository) error {
+		bulkTransfer, err := r.GetStagedBulkTransfer(ctx, firstID)
+		require.NoError(t, err)
+		require.Equal(t, staged, bulkTransfer)
+
+		chunk, err := r.ListStagedTransfers(ctx, firstID, -1, 2)
+		require.NoError(t, err)
+		require.Equal(t, transfers[:2], chunk)
+
+		chunk, err = r.ListStagedTransfers(ctx, firstID, 2, 2)
+		require.NoError(t, err)
+		require.Equal(t, transfers[2:], chunk)
+
+		bulkTransfer, err = r.GetStagedBulkTransfer(ctx, secondID)
+		require.NoError(t, err)
+		require.Equal(t, core.ExecutionModeAllOrNothing, bulkTransfer.ExecutionMode)
+
+		chunk, err = r.ListStagedTransfers(ctx, secondID, -1, 2)
+		require.NoError(t, err)
+		require.Empty(t, chunk)
+
+		return r.DeleteStagedBulkTransfer(ctx, firstID)
+	})
+
+	atomic(t, repo, func(r core.AccountRepository) error {
+		_, err := r.GetStagedBulkTransfer(ctx, firstID)
+		require.ErrorIs(t, err, core.ErrStagedBulkTransferNotFound)
+
+		chunk, err := r.ListStagedTransfers(ctx, firstID, -1, 2)
+		require.NoError(t, err)
+		require.Empty(t, chunk)
+
+		// IDs of deleted batches are not reused.
+		thirdID, err := r.AddStagedBulkTransfer(ctx, core.BulkTransfer{OrganizationIBAN: iban, OrganizationBIC: bic})
+		require.NoError(t, err)
+		require.Greater(t, thirdID, secondID)
+		return nil
+	})
+
+	atomic(t, repo, func(r core.AccountRepository) error {
+		deleted, err := r.DeleteStagedBulkTransfersBefore(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		require.Zero(t, deleted)
+
+		deleted, err = r.DeleteStagedBulkTransfersBefore(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, 2, deleted)
+
+		_, err = r.GetStagedBulkTransfer(ctx, secondID)
+		require.ErrorIs(t, err, core.ErrStagedBulkTransferNotFound)
+		return nil
+	})
+}
+
+func testHeldBulkTransfers(t *testing.T, repo core.AccountRepository) {
+	ctx := context.Background()
+
+	transfers := []core.StagedTransfer{
+		{Position: 0, Transfer: core.Transfer{CounterpartyName: "Ivan Sanctionedov", CounterpartyIBAN: "EE383680981021245685", AmountCents: 1000, Currency: "EUR"}},
+		{Position: 1, Transfer: core.Transfer{CounterpartyName: "Bip Bip", CounterpartyIBAN: "IT60X0542811101000000123456", AmountCents: 2500, Currency: "EUR"}},
+	}
+
+	var heldID, stagedID int64
+	atomic(t, repo, func(r core.AccountRepository) error {
+		var err error
+		heldID, err = r.AddStagedBulkTransfer(ctx, core.BulkTransfer{OrganizationIBAN: iban, OrganizationBIC: bic, RequestID: "req-1"})
+		if err != nil {
+			return err
+		}
+		if err = r.AddStagedTransfers(ctx, heldID, transfers); err != nil {
+			return err
+		}
+
+		stagedID, err = r.AddStagedBulkTransfer(ctx, core.BulkTransfer{OrganizationIBAN: iban, OrganizationBIC: bic})
+		if err != nil {
+			return err
+		}
+
+		err = r.HoldStagedBulkTransfer(ctx, core.HeldBulkTransfer{
+			ID:       heldID,
+			Review:   core.ReviewKind("fraud"),
+			Reason:   "fraud rule review",
+			Approved: []core.ReviewKind{core.ReviewKindSanctions},
+		})
+		require.NoError(t, err)
+
+		err = r.HoldStagedBulkTransfer(ctx, core.HeldBulkTransfer{ID: stagedID + 1, Review: core.ReviewKindSanctions})
+		require.ErrorIs(t, err, core.ErrStagedBulkTransferNotFound)
+		return nil
+	})
+
+	atomic(t, repo, func(r core.AccountRepository) error {
+		held, err := r.GetHeldBulkTransfer(ctx, heldID)
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now(), held.HeldAt, time.Minute)
+		held.HeldAt = time.Time{}
+		require.Equal(t, core.HeldBulkTransfer{
+			ID:               heldID,
+			OrganizationIBAN: iban,
+			OrganizationBIC:  bic,
+			ExecutionMode:    core.ExecutionModeAllOrNothing,
+			RequestID:        "req-1",
+			TransferCount:    2,
+			TotalCents:       3500,
+			Review:           core.ReviewKind("fraud"),
+			Reason:           "fraud rule review",
+			Approved:         []core.ReviewKind{core.ReviewKindSanctions},
+		}, held)
+
+		// The staged bulk transfer keeps the reviews it passed.
+		bulkTransfer, err := r.GetStagedBulkTransfer(ctx, heldID)
+		require.NoError(t, err)
+		require.Equal(t, []core.ReviewKind{core.ReviewKindSanctions}, bulkTransfer.ApprovedReviews)
+
+		_, err = r.GetHeldBulkTransfer(ctx, stagedID)
+		require.ErrorIs(t, err, core.ErrHeldBulkTransferNotFound)
+
+		list, err := r.ListHeldBulkTransfers(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, heldID, list[0].ID)
+		return nil
+	})
+
+	// Held bulk transfers outlive their upload.
+	atomic(t, repo, func(r core.AccountRepository) error {
+		deleted, err := r.DeleteStagedBulkTransfersBefore(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, 1, deleted)
+
+		chunk, err := r.ListStagedTransfers(ctx, heldID, -1, 10)
+		require.NoError(t, err)
+		require.Equal(t, transfers, chunk)
+
+		return r.DeleteStagedBulkTransfer(ctx, heldID)
+	})
+
+	atomic(t, repo, func(r core.AccountRepository) error {
+		_, err := r.GetHeldBulkTransfer(ctx, heldID)
+		require.ErrorIs(t, err, core.ErrHeldBulkTransferNotFound)
+
+		list, err := r.ListHeldBulkTransfers(ctx)
+		require.NoError(t, err)
+		require.Empty(t, list)
+		return nil
+	})
+}
+
+func testHolds(t *testing.T, repo core.AccountRepository) {
+	ctx := context.Background()
+	accountID := createAccount(t, repo, core.Account{OrganizationName: "ACME Corp", BalanceCents: 100000, IBAN: iban, BIC: bic})
+	now := time.Now()
+
+	holds := []core.Hold{
+		{AccountID: accountID, AmountCents: 1000, Reason: "pending", Status: core.HoldPending, CreatedAt: now, ExpiresAt: now.Add(time.Hour)},
+		{AccountID: accountID, AmountCents: 2000, Reason: "expired", Status: core.HoldPending, CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)},
+		{AccountID: accountID, AmountCents: 4000, Reason: "released", Status: core.HoldPending, CreatedAt: now, ExpiresAt: now.Add(time.Hour)},
+	}
+
+	atomic(t, repo, func(r core.AccountRepository) error {
+		for i, hold := range holds {
+			holdID, err := r.AddHold(ctx, hold)
+			if err != nil {
+				return err
+			}
+			holds[i].ID = holdID
+		}
+
+		_, err := r.AddHold(ctx, core.Hold{AccountID: accountID, Status: core.HoldPending, CreatedAt: now, ExpiresAt: now})
+		require.Error(t, err, "amounts must be positive")
+
+		require.ErrorIs(t, r.SetHoldStatus(ctx, holds[2].ID+100, core.HoldReleased), core.ErrHoldNotFound)
+		return r.SetHoldStatus(ctx, holds[2].ID, core.HoldReleased)
+	})
+
+	require.Equal(t, int64(1000), getAccount(t, repo, iban, bic).HeldCents)
+
+	atomic(t, repo, func(r core.AccountRepository) error {
+		hold, err := r.GetHold(ctx, holds[0].ID)
+		require.NoError(t, err)
+		require.Equal(t, accountID, hold.AccountID)
+		require.Equal(t, int64(1000), hold.AmountCents)
+		require.Equal(t, "pending", hold.Reason)
+		require.Equal(t, core.HoldPending, hold.Status)
+		require.True(t, hold.CreatedAt.Equal(now))
+		require.True(t, hold.ExpiresAt.Equal(now.Add(time.Hour)))
+
+		_, err = r.GetHold(ctx, holds[2].ID+100)
+		require.ErrorIs(t, err, core.ErrHoldNotFound)
+
+		listed, err := r.ListHolds(ctx, accountID)
+		require.NoError(t, err)
+		require.Len(t, listed, 3)
+		require.Equal(t, core.HoldReleased, listed[2].Status)
+		return nil
+	})
+}
+
+func testReconciliation(t *testing.T, repo core.AccountRepository) {
+	ctx := context.Background()
+	accountID := createAccount(t, repo, core.Account{OrganizationName: "ACME Corp", BalanceCents: 1000, IBAN: iban, BIC: bic})
+
+	atomic(t, repo, func(r core.AccountRepository) error {
+		if err := debit(r, 300); err != nil {
+			return err
+		}
+
+		return r.AddTransaction(ctx, core.Transaction{
+			AccountID:        accountID,
+			Kind:             core.TransactionKindCredit,
+			CounterpartyName: "ACME Corp",
+			CounterpartyIBAN: iban,
+			CounterpartyBIC:  bic,
+			AmountCents:      200,
+			Currency:         "EUR",
+		})
+	})
+
+	atomic(t, repo, func(r core.AccountRepository) error {
+		checks, err := r.CheckBalances(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []core.BalanceCheck{{
+			AccountID:           accountID,
+			IBAN:                iban,
+			BIC:                 bic,
+			OpeningBalanceCents: 1000,
+			TransactionsCents:   200,
+			BalanceCents:        700,
+		}}, checks)
+
+		first, err := r.AddReconciliationReport(ctx, core.ReconciliationReport{
+			StartedAt:     time.Now(),
+			FinishedAt:    time.Now(),
+			AccountCount:  1,
+			Discrepancies: checks,
+		})
+		require.NoError(t, err)
+
+		second, err := r.AddReconciliationReport(ctx, core.ReconciliationReport{StartedAt: time.Now(), FinishedAt: time.Now()})
+		require.NoError(t, err)
+		require.NotEqual(t, first, second)
+		return nil
+	})
+}
+
+func testAuditLog(t *testing.T, repo core.AccountRepository) {
+	ctx := context.Background()
+	accountID := createAccount(t, repo, core.Account{OrganizationName: "ACME Corp", BalanceCents: 1000, IBAN: iban, BIC: bic})
+
+	record := core.AuditRecord{EventType: core.AuditEventBalanceChanged, AccountID: accountID, BalanceBeforeCents: 1000, BalanceAfterCents: 900}
+	atomic(t, repo, func(r core.AccountRepository) error {
+		if err := r.AppendAuditRecord(ctx, record); err != nil {
+			return err
+		}
+		return r.AppendAuditRecord(ctx, record)
+	})
+
+	err := repo.Atomic(ctx, func(r core.AccountRepository) error {
+		require.NoError(t, r.AppendAuditRecord(ctx, record))
+		return errors.New("abort")
+	})
+	require.Error(t, err)
+
+	reader, ok := repo.(AuditLogReader)
+	if !ok {
+		return
+	}
+
+	var verifier core.AuditChainVerifier
+	require.NoError(t, reader.ForEachAuditRecord(ctx, verifier.Verify))
+	require.Equal(t, 2, verifier.Count())
+}
@@ -2,13 +2,18 @@ package integration
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"payment/internal/core"
 	httpHandler "payment/internal/http"
 )
 
@@ -97,3 +102,779 @@ func TestBulkTransfer_E2E_HappyPath(t *testing.T) {
 		require.Equal(t, expected.description, tx.Description, "transaction %d: description mismatch", i)
 	}
 }
+
+func TestBulkTransfer_E2E_AuditTrail(t *testing.T) {
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	const (
+		orgIBAN = "FR10474608000002006107XXXXX"
+		orgBIC  = "OIVUSCLQXXX"
+	)
+
+	suite.SeedAccount(t, "Test Organization", orgIBAN, orgBIC, 10000)
+
+	post := func(amount string) int {
+		requestBody := httpHandler.BulkTransferRequest{
+			OrganizationBIC:  orgBIC,
+			OrganizationIBAN: orgIBAN,
+			CreditTransfers: []httpHandler.CreditTransfer{
+				{
+					Amount:           amount,
+					Currency:         "EUR",
+					CounterpartyName: "Alice Smith",
+					CounterpartyBIC:  "CRLYFRPPTOU",
+					CounterpartyIBAN: "EE383680981021245685",
+					Description:      "Payment to Alice",
+				},
+			},
+		}
+
+		bodyBytes, err := json.Marshal(requestBody)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/transfers/bulk", bytes.NewReader(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		suite.Handler.PostTransfers(w, req)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusCreated, post("60"))
+	require.Equal(t, http.StatusUnprocessableEntity, post("60"))
+
+	require.Equal(t, []string{
+		"balance_changed",
+		"bulk_transfer_accepted",
+		"bulk_transfer_rejected",
+	}, suite.GetAuditEventTypes(t))
+}
+
+func TestBulkTransfer_E2E_SanctionsHitBlocksBatch(t *testing.T) {
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	const (
+		orgIBAN        = "FR10474608000002006107XXXXX"
+		orgBIC         = "OIVUSCLQXXX"
+		initialBalance = 1000000
+	)
+
+	accountID := suite.SeedAccount(t, "Test Organization", orgIBAN, orgBIC, initialBalance)
+
+	requestBody := httpHandler.BulkTransferRequest{
+		OrganizationBIC:  orgBIC,
+		OrganizationIBAN: orgIBAN,
+		CreditTransfers: []httpHandler.CreditTransfer{
+			{
+				Amount:           "100.50",
+				Currency:         "EUR",
+				CounterpartyName: "Alice Smith",
+				CounterpartyBIC:  "CRLYFRPPTOU",
+				CounterpartyIBAN: "EE383680981021245685",
+				Description:      "Payment to Alice",
+			},
+			{
+				Amount:           "250.75",
+				Currency:         "EUR",
+				CounterpartyName: "SANCTIONEDOV, Ivan",
+				CounterpartyBIC:  "DEUTDEFF",
+				CounterpartyIBAN: "DE89370400440532013000",
+				Description:      "Consulting",
+			},
+		},
+	}
+
+	bodyBytes, err := json.Marshal(requestBody)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/transfers/bulk", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.Handler.PostTransfers(w, req)
+
+	require.Equal(t, http.StatusUnavailableForLegalReasons, w.Code, "expected 451, got: %s", w.Body.String())
+	require.Equal(t, int64(initialBalance), suite.GetAccountBalance(t, accountID), "nothing should be debited")
+	require.Empty(t, suite.GetTransactions(t, accountID))
+	require.Equal(t, []string{"bulk_transfer_rejected"}, suite.GetAuditEventTypes(t))
+}
+
+func TestBulkTransfer_E2E_FraudReviewHoldsBatchUntilReleased(t *testing.T) {
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	const (
+		orgIBAN        = "FR10474608000002006107XXXXX"
+		orgBIC         = "OIVUSCLQXXX"
+		initialBalance = 1000000
+	)
+
+	accountID := suite.SeedAccount(t, "Test Organization", orgIBAN, orgBIC, initialBalance)
+
+	rules, err := core.BuildFraudRules([]core.FraudRuleSpec{
+		{Type: core.FraudRuleNewCounterpartyLargeAmount, Decision: core.DecisionReview, MinAmountCents: 50000},
+	})
+	require.NoError(t, err)
+	suite.FraudEngine.SetRules(rules)
+
+	bodyBytes, err := json.Marshal(httpHandler.BulkTransferRequest{
+		OrganizationBIC:  orgBIC,
+		OrganizationIBAN: orgIBAN,
+		CreditTransfers: []httpHandler.CreditTransfer{
+			{
+				Amount:           "1000.00",
+				Currency:         "EUR",
+				CounterpartyName: "Alice Smith",
+				CounterpartyBIC:  "CRLYFRPPTOU",
+				CounterpartyIBAN: "EE383680981021245685",
+				Description:      "First payment to Alice",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/transfers/bulk", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.Handler.PostTransfers(w, req)
+
+	require.Equal(t, http.StatusAccepted, w.Code, "expected 202, got: %s", w.Body.String())
+	var held httpHandler.HeldBulkTransferResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &held))
+	require.Equal(t, "fraud", held.Review)
+	require.Equal(t, int64(initialBalance), suite.GetAccountBalance(t, accountID), "nothing should be debited while held")
+	require.Empty(t, suite.GetTransactions(t, accountID))
+
+	ctx := context.Background()
+	heldBulkTransfers, err := suite.Service.ListHeldBulkTransfers(ctx)
+	require.NoError(t, err)
+	require.Len(t, heldBulkTransfers, 1)
+	require.Equal(t, held.HeldBulkTransferID, heldBulkTransfers[0].ID)
+
+	// The rule still asks for a review: only the approval lets the batch through.
+	result, err := suite.Service.ReleaseHeldBulkTransfer(ctx, held.HeldBulkTransferID, "called the customer")
+	require.NoError(t, err)
+	require.Equal(t, 1, result.AcceptedCount())
+
+	require.Equal(t, int64(initialBalance-100000-result.FeesCents()), suite.GetAccountBalance(t, accountID))
+	require.Len(t, suite.GetTransactions(t, accountID), 1)
+
+	heldBulkTransfers, err = suite.Service.ListHeldBulkTransfers(ctx)
+	require.NoError(t, err)
+	require.Empty(t, heldBulkTransfers)
+	require.Equal(t, []string{"bulk_transfer_held", "bulk_transfer_released", "balance_changed", "bulk_transfer_accepted"}, suite.GetAuditEventTypes(t))
+
+	_, err = suite.Service.ReleaseHeldBulkTransfer(ctx, held.HeldBulkTransferID, "released twice")
+	require.ErrorIs(t, err, core.ErrHeldBulkTransferNotFound)
+}
+
+func TestBulkTransfer_E2E_FraudBlockIsNotHeld(t *testing.T) {
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	const (
+		orgIBAN        = "FR10474608000002006107XXXXX"
+		orgBIC         = "OIVUSCLQXXX"
+		initialBalance = 1000000
+	)
+
+	accountID := suite.SeedAccount(t, "Test Organization", orgIBAN, orgBIC, initialBalance)
+
+	rules, err := core.BuildFraudRules([]core.FraudRuleSpec{
+		{Type: core.FraudRuleNewCounterpartyLargeAmount, Decision: core.DecisionBlock, MinAmountCents: 50000},
+	})
+	require.NoError(t, err)
+	suite.FraudEngine.SetRules(rules)
+
+	bodyBytes, err := json.Marshal(httpHandler.BulkTransferRequest{
+		OrganizationBIC:  orgBIC,
+		OrganizationIBAN: orgIBAN,
+		CreditTransfers: []httpHandler.CreditTransfer{
+			{
+				Amount:           "1000.00",
+				Currency:         "EUR",
+				CounterpartyName: "Alice Smith",
+				CounterpartyBIC:  "CRLYFRPPTOU",
+				CounterpartyIBAN: "EE383680981021245685",
+				Description:      "First payment to Alice",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/transfers/bulk", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.Handler.PostTransfers(w, req)
+
+	require.Equal(t, http.StatusForbidden, w.Code, "expected 403, got: %s", w.Body.String())
+	require.Contains(t, w.Body.String(), httpHandler.CodeFraudBlocked)
+	require.Equal(t, int64(initialBalance), suite.GetAccountBalance(t, accountID))
+
+	heldBulkTransfers, err := suite.Service.ListHeldBulkTransfers(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, heldBulkTransfers)
+	require.Equal(t, []string{"bulk_transfer_rejected"}, suite.GetAuditEventTypes(t))
+}
+
+func TestBulkTransfer_E2E_FrozenAccountIsRefused(t *testing.T) {
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	const (
+		orgIBAN        = "FR10474608000002006107XXXXX"
+		orgBIC         = "OIVUSCLQXXX"
+		initialBalance = 1000000
+	)
+
+	accountID := suite.SeedAccount(t, "Test Organization", orgIBAN, orgBIC, initialBalance)
+
+	_, err := suite.Service.SetAccountFrozen(context.Background(), orgIBAN, orgBIC, true, "investigation")
+	require.NoError(t, err)
+
+	requestBody := httpHandler.BulkTransferRequest{
+		OrganizationBIC:  orgBIC,
+		OrganizationIBAN: orgIBAN,
+		CreditTransfers: []httpHandler.CreditTransfer{
+			{
+				Amount:           "100.50",
+				Currency:         "EUR",
+				CounterpartyName: "Alice Smith",
+				CounterpartyBIC:  "CRLYFRPPTOU",
+				CounterpartyIBAN: "EE383680981021245685",
+				Description:      "Payment to Alice",
+			},
+		},
+	}
+
+	bodyBytes, err := json.Marshal(requestBody)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/transfers/bulk", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.Handler.PostTransfers(w, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, "expected 422, got: %s", w.Body.String())
+	require.Contains(t, w.Body.String(), httpHandler.CodeAccountFrozen)
+	require.Equal(t, int64(initialBalance), suite.GetAccountBalance(t, accountID), "nothing should be debited")
+	require.Equal(t, []string{"account_frozen", "bulk_transfer_rejected"}, suite.GetAuditEventTypes(t))
+}
+
+func TestBulkTransfer_E2E_OverdraftIsUsed(t *testing.T) {
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	const (
+		orgIBAN        = "FR10474608000002006107XXXXX"
+		orgBIC         = "OIVUSCLQXXX"
+		initialBalance = 1000
+	)
+
+	accountID := suite.SeedAccount(t, "Test Organization", orgIBAN, orgBIC, initialBalance)
+
+	_, err := suite.Service.SetOverdraftLimit(context.Background(), orgIBAN, orgBIC, 5000, "agreed facility")
+	require.NoError(t, err)
+
+	requestBody := httpHandler.BulkTransferRequest{
+		OrganizationBIC:  orgBIC,
+		OrganizationIBAN: orgIBAN,
+		CreditTransfers: []httpHandler.CreditTransfer{
+			{
+				Amount:           "30.00",
+				Currency:         "EUR",
+				CounterpartyName: "Alice Smith",
+				CounterpartyBIC:  "CRLYFRPPTOU",
+				CounterpartyIBAN: "EE383680981021245685",
+				Description:      "Payment to Alice",
+			},
+		},
+	}
+
+	bodyBytes, err := json.Marshal(requestBody)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/transfers/bulk", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.Handler.PostTransfers(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code, "expected 201, got: %s", w.Body.String())
+	require.Equal(t, int64(-2000), suite.GetAccountBalance(t, accountID))
+	require.Equal(t, []string{"overdraft_limit_set", "balance_changed", "overdraft_entered", "bulk_transfer_accepted"}, suite.GetAuditEventTypes(t))
+
+	req = httptest.NewRequest(http.MethodGet, "/accounts/"+orgIBAN+"/"+orgBIC, nil)
+	req.SetPathValue("iban", orgIBAN)
+	req.SetPathValue("bic", orgBIC)
+	w = httptest.NewRecorder()
+
+	suite.Handler.GetAccount(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var account httpHandler.AccountResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &account))
+	require.Equal(t, "-20.00", account.Balance)
+	require.Equal(t, "20.00", account.OverdraftUsed)
+	require.Equal(t, "30.00", account.AvailableBalance)
+}
+
+func TestBulkTransfer_E2E_CapturesHold(t *testing.T) {
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	const (
+		orgIBAN = "FR10474608000002006107XXXXX"
+		orgBIC  = "OIVUSCLQXXX"
+	)
+
+	accountID := suite.SeedAccount(t, "Test Organization", orgIBAN, orgBIC, 5000)
+
+	// Only 10.00 is left available once the hold is placed.
+	hold, err := suite.Service.PlaceHold(context.Background(), orgIBAN, orgBIC, 4000, time.Now().Add(time.Hour), "awaiting approval")
+	require.NoError(t, err)
+
+	post := func() *httptest.ResponseRecorder {
+		bodyBytes, err := json.Marshal(httpHandler.BulkTransferRequest{
+			OrganizationBIC:  orgBIC,
+			OrganizationIBAN: orgIBAN,
+			HoldID:           hold.ID,
+			CreditTransfers: []httpHandler.CreditTransfer{
+				{
+					Amount:           "30.00",
+					Currency:         "EUR",
+					CounterpartyName: "Alice Smith",
+					CounterpartyBIC:  "CRLYFRPPTOU",
+					CounterpartyIBAN: "EE383680981021245685",
+					Description:      "Payment to Alice",
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/transfers/bulk", bytes.NewReader(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		suite.Handler.PostTransfers(w, req)
+		return w
+	}
+
+	w := post()
+
+	require.Equal(t, http.StatusCreated, w.Code, "expected 201, got: %s", w.Body.String())
+	require.Equal(t, int64(2000), suite.GetAccountBalance(t, accountID))
+	require.Equal(t, []string{"hold_placed", "hold_captured", "balance_changed", "bulk_transfer_accepted"}, suite.GetAuditEventTypes(t))
+
+	holds, err := suite.Service.ListHolds(context.Background(), orgIBAN, orgBIC)
+	require.NoError(t, err)
+	require.Len(t, holds, 1)
+	require.Equal(t, core.HoldCaptured, holds[0].Status)
+
+	account, err := suite.Service.GetAccount(context.Background(), orgIBAN, orgBIC)
+	require.NoError(t, err)
+	require.Zero(t, account.HeldCents)
+	require.Equal(t, int64(2000), account.AvailableCents())
+
+	// A captured hold cannot be captured again.
+	w = post()
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	require.Contains(t, w.Body.String(), httpHandler.CodeHoldNotPending)
+	require.Equal(t, int64(2000), suite.GetAccountBalance(t, accountID))
+}
+
+func TestBulkTransfer_E2E_MultipleDebtorAccounts(t *testing.T) {
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	const (
+		orgIBAN    = "FR10474608000002006107XXXXX"
+		orgBIC     = "OIVUSCLQXXX"
+		debtorIBAN = "FR7630006000011234567890189"
+		debtorBIC  = "AGRIFRPPXXX"
+	)
+
+	orgAccountID := suite.SeedAccount(t, "Test Organization", orgIBAN, orgBIC, 10000)
+	debtorAccountID := suite.SeedAccount(t, "Test Organization", debtorIBAN, debtorBIC, 5000)
+	// Another organization that happens to have the same name.
+	otherAccountID := suite.SeedAccount(t, "Test Organization", "DE89370400440532013000", "COBADEFFXXX", 5000)
+	suite.SetOrganizationID(t, orgAccountID, "org-test")
+	suite.SetOrganizationID(t, debtorAccountID, "org-test")
+	suite.SetOrganizationID(t, otherAccountID, "org-other")
+
+	transfer := func(amount, debtorIBAN, debtorBIC string) httpHandler.CreditTransfer {
+		return httpHandler.CreditTransfer{
+			Amount:           amount,
+			Currency:         "EUR",
+			CounterpartyName: "Alice Smith",
+			CounterpartyBIC:  "CRLYFRPPTOU",
+			CounterpartyIBAN: "EE383680981021245685",
+			Description:      "Payment to Alice",
+			DebtorIBAN:       debtorIBAN,
+			DebtorBIC:        debtorBIC,
+		}
+	}
+
+	post := func(requestBody httpHandler.BulkTransferRequest) *httptest.ResponseRecorder {
+		bodyBytes, err := json.Marshal(requestBody)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/transfers/bulk", bytes.NewReader(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		suite.Handler.PostTransfers(w, req)
+		return w
+	}
+
+	w := post(httpHandler.BulkTransferRequest{
+		OrganizationBIC:  orgBIC,
+		OrganizationIBAN: orgIBAN,
+		CreditTransfers: []httpHandler.CreditTransfer{
+			transfer("30.00", "", ""),
+			transfer("20.00", debtorIBAN, debtorBIC),
+			transfer("10.00", debtorIBAN, debtorBIC),
+		},
+	})
+
+	require.Equal(t, http.StatusCreated, w.Code, "expected 201, got: %s", w.Body.String())
+	require.Equal(t, int64(7000), suite.GetAccountBalance(t, orgAccountID))
+	require.Equal(t, int64(2000), suite.GetAccountBalance(t, debtorAccountID))
+	require.Equal(t, []string{"balance_changed", "balance_changed", "bulk_transfer_accepted"}, suite.GetAuditEventTypes(t))
+
+	// Accounts of another organization cannot be debited.
+	w = post(httpHandler.BulkTransferRequest{
+		OrganizationBIC:  orgBIC,
+		OrganizationIBAN: orgIBAN,
+		CreditTransfers: []httpHandler.CreditTransfer{
+			transfer("10.00", "DE89370400440532013000", "COBADEFFXXX"),
+		},
+	})
+
+	require.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	require.Equal(t, int64(7000), suite.GetAccountBalance(t, orgAccountID))
+	require.Equal(t, int64(5000), suite.GetAccountBalance(t, otherAccountID))
+}
+
+func TestBulkTransfer_E2E_ChargesFees(t *testing.T) {
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	const (
+		orgIBAN = "FR10474608000002006107XXXXX"
+		orgBIC  = "OIVUSCLQXXX"
+	)
+
+	accountID := suite.SeedAccount(t, "Test Organization", orgIBAN, orgBIC, 100000)
+
+	// The business plan of testdata/pricing.json charges 0.20 per SEPA
+	// transfer and 1% across borders, after one free transfer a month.
+	_, err := suite.Service.SetPricingPlan(context.Background(), orgIBAN, orgBIC, "business", "contract signed")
+	require.NoError(t, err)
+
+	transfer := func(amount, iban string) httpHandler.CreditTransfer {
+		return httpHandler.CreditTransfer{
+			Amount:           amount,
+			Currency:         "EUR",
+			CounterpartyName: "Alice Smith",
+			CounterpartyBIC:  "CRLYFRPPTOU",
+			CounterpartyIBAN: iban,
+			Description:      "Payment to Alice",
+		}
+	}
+
+	requestBody := httpHandler.BulkTransferRequest{
+		OrganizationBIC:  orgBIC,
+		OrganizationIBAN: orgIBAN,
+		CreditTransfers: []httpHandler.CreditTransfer{
+			transfer("10.00", "EE383680981021245685"),
+			transfer("10.00", "EE383680981021245685"),
+			transfer("100.00", "US12345678901234567890"),
+		},
+	}
+
+	bodyBytes, err := json.Marshal(requestBody)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/transfers/bulk", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.Handler.PostTransfers(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code, "expected 201, got: %s", w.Body.String())
+	var response httpHandler.BulkTransferResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Equal(t, "1.20", response.Fees)
+	require.Equal(t, []string{"0.00", "0.20", "1.00"}, []string{
+		response.Transfers[0].Fee, response.Transfers[1].Fee, response.Transfers[2].Fee,
+	})
+	require.Equal(t, int64(100000-12000-120), suite.GetAccountBalance(t, accountID))
+
+	transactions, err := suite.Service.ListTransactions(context.Background(), orgIBAN, orgBIC, core.TransactionQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, transactions, 4)
+	require.Equal(t, core.TransactionKindFee, transactions[3].Kind)
+	require.Equal(t, int64(-120), transactions[3].AmountCents)
+	require.Equal(t, response.BulkTransferID, transactions[3].BulkTransferID)
+}
+
+func TestBulkTransfer_E2E_DryRunRollsBack(t *testing.T) {
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	const (
+		orgIBAN        = "FR10474608000002006107XXXXX"
+		orgBIC         = "OIVUSCLQXXX"
+		initialBalance = 30000
+	)
+
+	accountID := suite.SeedAccount(t, "Test Organization", orgIBAN, orgBIC, initialBalance)
+
+	requestBody := httpHandler.BulkTransferRequest{
+		OrganizationBIC:  orgBIC,
+		OrganizationIBAN: orgIBAN,
+		CreditTransfers: []httpHandler.CreditTransfer{
+			{
+				Amount:           "100.50",
+				Currency:         "EUR",
+				CounterpartyName: "Alice Smith",
+				CounterpartyBIC:  "CRLYFRPPTOU",
+				CounterpartyIBAN: "EE383680981021245685",
+				Description:      "Payment to Alice",
+			},
+			{
+				Amount:           "250.75",
+				Currency:         "EUR",
+				CounterpartyName: "SANCTIONEDOV, Ivan",
+				CounterpartyBIC:  "DEUTDEFF",
+				CounterpartyIBAN: "DE89370400440532013000",
+				Description:      "Consulting",
+			},
+		},
+	}
+
+	bodyBytes, err := json.Marshal(requestBody)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/transfers/bulk:validate", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.Handler.ValidateTransfers(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, "expected 200, got: %s", w.Body.String())
+
+	var quote httpHandler.BulkTransferQuoteResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &quote))
+
+	require.False(t, quote.Valid)
+	require.Equal(t, "351.25", quote.TotalAmount)
+	require.Equal(t, "300.00", quote.BalanceBefore)
+	require.Equal(t, "-51.25", quote.BalanceAfter)
+
+	codes := make([]string, len(quote.Violations))
+	for i, violation := range quote.Violations {
+		codes[i] = violation.Code
+	}
+	require.Equal(t, []string{"sanctions_blocked", "insufficient_funds"}, codes)
+	require.Nil(t, quote.Violations[0].TransferIndex, "sanctions hits must not point at the matching transfer")
+
+	require.Equal(t, int64(initialBalance), suite.GetAccountBalance(t, accountID), "dry runs must not debit")
+	require.Empty(t, suite.GetTransactions(t, accountID))
+	require.Equal(t, []string{"dry_run_screening_hit"}, suite.GetAuditEventTypes(t), "only the screening hit of a dry run is audited")
+}
+
+func TestBulkTransfer_E2E_PartialModeAcceptsWhatFits(t *testing.T) {
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	const (
+		orgIBAN        = "FR10474608000002006107XXXXX"
+		orgBIC         = "OIVUSCLQXXX"
+		initialBalance = 30000
+	)
+
+	accountID := suite.SeedAccount(t, "Test Organization", orgIBAN, orgBIC, initialBalance)
+
+	requestBody := httpHandler.BulkTransferRequest{
+		OrganizationBIC:  orgBIC,
+		OrganizationIBAN: orgIBAN,
+		ExecutionMode:    "partial",
+		CreditTransfers: []httpHandler.CreditTransfer{
+			{
+				Amount:           "100.50",
+				Currency:         "EUR",
+				CounterpartyName: "Alice Smith",
+				CounterpartyBIC:  "CRLYFRPPTOU",
+				CounterpartyIBAN: "EE383680981021245685",
+				Description:      "Payment to Alice",
+			},
+			{
+				Amount:           "20.00",
+				Currency:         "USD",
+				CounterpartyName: "Bob Jones",
+				CounterpartyBIC:  "BNPAFRPP",
+				CounterpartyIBAN: "FR7630006000011234567890189",
+				Description:      "Payment to Bob",
+			},
+			{
+				Amount:           "250.75",
+				Currency:         "EUR",
+				CounterpartyName: "Carol White",
+				CounterpartyBIC:  "DEUTDEFF",
+				CounterpartyIBAN: "DE89370400440532013000",
+				Description:      "Payment to Carol",
+			},
+		},
+	}
+
+	bodyBytes, err := json.Marshal(requestBody)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/transfers/bulk", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.Handler.PostTransfers(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code, "expected 201, got: %s", w.Body.String())
+
+	var response httpHandler.BulkTransferResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	require.Equal(t, "partial", response.ExecutionMode)
+	require.Equal(t, 1, response.AcceptedCount)
+	require.Equal(t, 2, response.RejectedCount)
+	require.Len(t, response.Transfers, 3)
+	require.Equal(t, "accepted", response.Transfers[0].Status)
+	require.Equal(t, "validation_failed", response.Transfers[1].Reason)
+	require.Equal(t, "insufficient_funds", response.Transfers[2].Reason)
+
+	require.Equal(t, int64(initialBalance-10050), suite.GetAccountBalance(t, accountID))
+	transactions := suite.GetTransactions(t, accountID)
+	require.Len(t, transactions, 1)
+	require.Equal(t, "Alice Smith", transactions[0].CounterpartyName)
+	require.Equal(t, []string{"balance_changed", "bulk_transfer_accepted"}, suite.GetAuditEventTypes(t))
+}
+
+func TestBulkTransfer_E2E_StreamedBatch(t *testing.T) {
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	const (
+		orgIBAN        = "FR10474608000002006107XXXXX"
+		orgBIC         = "OIVUSCLQXXX"
+		initialBalance = 100000000
+		transferCount  = 12000
+		// invalidLine is rejected in the stream and never staged.
+		invalidLine = 5000
+	)
+
+	accountID := suite.SeedAccount(t, "Test Organization", orgIBAN, orgBIC, initialBalance)
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	require.NoError(t, encoder.Encode(httpHandler.StreamHeader{
+		OrganizationBIC:  orgBIC,
+		OrganizationIBAN: orgIBAN,
+		ExecutionMode:    string(core.ExecutionModePartial),
+		TransferCount:    transferCount,
+	}))
+	for i := range transferCount {
+		currency := "EUR"
+		if i == invalidLine {
+			currency = "USD"
+		}
+		require.NoError(t, encoder.Encode(httpHandler.CreditTransfer{
+			Amount:           "1.25",
+			Currency:         currency,
+			CounterpartyName: "Alice Smith",
+			CounterpartyBIC:  "CRLYFRPPTOU",
+			CounterpartyIBAN: fmt.Sprintf("EE3836809810212456%04d", i),
+			Description:      "Payroll",
+		}))
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/transfers/bulk:stream", &body)
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w := httptest.NewRecorder()
+
+	suite.Handler.StreamTransfers(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var events []httpHandler.StreamEvent
+	decoder := json.NewDecoder(w.Body)
+	for decoder.More() {
+		var event httpHandler.StreamEvent
+		require.NoError(t, decoder.Decode(&event))
+		events = append(events, event)
+	}
+
+	require.Len(t, events, 13, "twelve progress events and the outcome")
+	last := events[len(events)-1]
+	require.Equal(t, httpHandler.StreamEventCompleted, last.Event, "unexpected outcome: %+v", last.Problem)
+	require.Equal(t, transferCount-1, last.Result.AcceptedCount)
+	for i, transfer := range last.Result.Transfers {
+		require.Equal(t, i, transfer.Index)
+	}
+	require.Equal(t, httpHandler.CodeValidationFailed, last.Result.Transfers[invalidLine].Reason)
+	require.Equal(t, "accepted", last.Result.Transfers[invalidLine+1].Status)
+
+	require.Equal(t, int64(initialBalance-(transferCount-1)*125), suite.GetAccountBalance(t, accountID))
+	require.Len(t, suite.GetTransactions(t, accountID), transferCount-1)
+
+	var staged int
+	require.NoError(t, suite.DB.QueryRow("SELECT COUNT(*) FROM staged_bulk_transfers").Scan(&staged))
+	require.Zero(t, staged, "executed batches are no longer staged")
+}
+
+func TestBulkTransfer_E2E_LargeBatch(t *testing.T) {
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	const (
+		orgIBAN        = "FR10474608000002006107XXXXX"
+		orgBIC         = "OIVUSCLQXXX"
+		initialBalance = 100000000
+		transferCount  = 10001
+	)
+
+	accountID := suite.SeedAccount(t, "Test Organization", orgIBAN, orgBIC, initialBalance)
+
+	requestBody := httpHandler.BulkTransferRequest{
+		OrganizationBIC:  orgBIC,
+		OrganizationIBAN: orgIBAN,
+		CreditTransfers:  make([]httpHandler.CreditTransfer, transferCount),
+	}
+	for i := range requestBody.CreditTransfers {
+		requestBody.CreditTransfers[i] = httpHandler.CreditTransfer{
+			Amount:           "2.50",
+			Currency:         "EUR",
+			CounterpartyName: "Alice Smith",
+			CounterpartyBIC:  "CRLYFRPPTOU",
+			CounterpartyIBAN: fmt.Sprintf("EE38368098102124%06d", i),
+			Description:      "Payroll",
+		}
+	}
+
+	bodyBytes, err := json.Marshal(requestBody)
+	require.NoError(t, err)
+
+	// Through the server, so that the body limit and the request validator
+	// see the whole batch.
+	resp, err := http.Post(suite.URL+"/transfers/bulk", "application/json", bytes.NewReader(bodyBytes))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, "expected 201, got: %s", responseBody)
+	require.Equal(t, int64(initialBalance-transferCount*250), suite.GetAccountBalance(t, accountID))
+	require.Len(t, suite.GetTransactions(t, accountID), transferCount)
+}
@@ -1,9 +1,11 @@
 package integration
 
 import (
+	"context"
 	"database/sql"
 	"io"
 	"log/slog"
+	"net"
 	"os"
 	"path/filepath"
 	"testing"
@@ -11,19 +13,27 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"payment/internal/admission"
 	"payment/internal/core"
 	"payment/internal/http"
+	"payment/internal/pricing"
+	"payment/internal/ratelimit"
+	"payment/internal/sanctions"
 	"payment/internal/sqlite"
+	"payment/internal/telemetry"
 )
 
 type TestSuite struct {
-	DB       *sql.DB
-	DBPath   string
-	Client   *sqlite.Client
-	Handler  http.Handler
-	Service  core.Service
-	Logger   *slog.Logger
-	teardown func()
+	DB      *sql.DB
+	DBPath  string
+	Client  *sqlite.Client
+	Handler http.Handler
+	URL     string
+	Service core.Service
+	// FraudEngine starts without rules.
+	FraudEngine *core.FraudEngine
+	Logger      *slog.Logger
+	teardown    func()
 }
 
 func NewTestSuite(t *testing.T) *TestSuite {
@@ -43,48 +53,47 @@ func NewTestSuite(t *testing.T) *TestSuite {
 	client, err := sqlite.NewClient(config)
 	require.NoError(t, err, "failed to create test client")
 
-	schema := `
-		CREATE TABLE IF NOT EXISTS bank_accounts (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			organization_name TEXT NOT NULL,
-			balance_cents INTEGER NOT NULL DEFAULT 0,
-			iban TEXT NOT NULL,
-			bic TEXT NOT NULL,
-			UNIQUE(iban, bic)
-		);
-
-		CREATE TABLE IF NOT EXISTS transactions (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			counterparty_name TEXT NOT NULL,
-			counterparty_iban TEXT NOT NULL,
-			counterparty_bic TEXT NOT NULL,
-			amount_cents INTEGER NOT NULL,
-			amount_currency TEXT NOT NULL DEFAULT 'EUR',
-			bank_account_id INTEGER NOT NULL,
-			description TEXT
-		);
-
-		CREATE INDEX IF NOT EXISTS idx_transactions_bank_account 
-		ON transactions(bank_account_id);
-	`
-
-	_, err = client.DB().Exec(schema)
-	require.NoError(t, err, "failed to create schema")
+	err = client.Migrate(context.Background())
+	require.NoError(t, err, "failed to migrate schema")
 
 	// Initialize application components
-	accountRepository := sqlite.NewAccountStore(client.DB())
-	service := core.NewService(accountRepository)
+	screener, err := sanctions.NewScreener(sanctions.Config{
+		ListPath:  filepath.Join("testdata", "sanctions.csv"),
+		Threshold: 0.92,
+		Action:    "block",
+	})
+	require.NoError(t, err, "failed to load sanctions list")
+
+	pricingPlans, err := pricing.Load(pricing.Config{Path: filepath.Join("testdata", "pricing.json")})
+	require.NoError(t, err, "failed to load pricing plans")
+
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
-	handler := http.NewHandler(service, logger)
+	rateLimiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Config{})
+	accountRepository := telemetry.NewTracingRepository(sqlite.NewAccountStore(client.DB()))
+	fraudEngine := core.NewFraudEngine(nil)
+	service := core.NewService(accountRepository, screener, fraudEngine, pricingPlans).
+		WithAdmission(admission.New(rateLimiter, logger, admission.Config{MaxBatchSize: 50000}))
+	handler := http.NewHandler(service, rateLimiter, logger, 0)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err, "failed to listen")
+
+	server := http.NewServer(service, rateLimiter, client, logger, 50000, http.Config{
+		Timeout: time.Minute,
+	})
+	go server.Serve(context.Background(), listener)
 
 	suite := &TestSuite{
-		DB:      client.DB(),
-		DBPath:  dbPath,
-		Client:  client,
-		Handler: handler,
-		Service: service,
-		Logger:  logger,
+		DB:          client.DB(),
+		DBPath:      dbPath,
+		Client:      client,
+		Handler:     handler,
+		URL:         "http://" + listener.Addr().String(),
+		Service:     service,
+		FraudEngine: fraudEngine,
+		Logger:      logger,
 		teardown: func() {
+			_ = server.Stop(context.Background())
 			client.Close()
 			os.Remove(dbPath)
 		},
@@ -101,11 +110,11 @@ func (s *TestSuite) SeedAccount(t *testing.T, orgName, iban, bic string, balance
 	t.Helper()
 
 	query := `
-		INSERT INTO bank_accounts (organization_name, iban, bic, balance_cents)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO bank_accounts (organization_name, iban, bic, balance_cents, opening_balance_cents)
+		VALUES (?, ?, ?, ?, ?)
 	`
 
-	result, err := s.DB.Exec(query, orgName, iban, bic, balanceCents)
+	result, err := s.DB.Exec(query, orgName, iban, bic, balanceCents, balanceCents)
 	require.NoError(t, err, "failed to seed account")
 
 	id, err := result.LastInsertId()
@@ -114,6 +123,13 @@ func (s *TestSuite) SeedAccount(t *testing.T, orgName, iban, bic string, balance
 	return id
 }
 
+func (s *TestSuite) SetOrganizationID(t *testing.T, accountID int64, organizationID string) {
+	t.Helper()
+
+	_, err := s.DB.Exec("UPDATE bank_accounts SET organization_id = ? WHERE id = ?", organizationID, accountID)
+	require.NoError(t, err, "failed to set organization ID")
+}
+
 func (s *TestSuite) GetAccountBalance(t *testing.T, accountID int64) int64 {
 	t.Helper()
 
@@ -168,3 +184,21 @@ func (s *TestSuite) GetTransactions(t *testing.T, accountID int64) []Transaction
 	require.NoError(t, rows.Err(), "error iterating transactions")
 	return transactions
 }
+
+func (s *TestSuite) GetAuditEventTypes(t *testing.T) []string {
+	t.Helper()
+
+	rows, err := s.DB.Query("SELECT event_type FROM audit_log ORDER BY id")
+	require.NoError(t, err, "failed to query audit log")
+	defer rows.Close()
+
+	var eventTypes []string
+	for rows.Next() {
+		var eventType string
+		require.NoError(t, rows.Scan(&eventType), "failed to scan audit event type")
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	require.NoError(t, rows.Err(), "error iterating audit log")
+	return eventTypes
+}
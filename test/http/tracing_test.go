@@ -0,0 +1,100 @@
+package integration
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	httpHandler "payment/internal/http"
+	"payment/internal/telemetry"
+)
+
+func TestBulkTransfer_E2E_TracesExportedToFile(t *testing.T) {
+	tracePath := filepath.Join(t.TempDir(), "traces.jsonl")
+	shutdown, err := telemetry.Setup(context.Background(), telemetry.Config{
+		Exporter:    telemetry.ExporterFile,
+		FilePath:    tracePath,
+		ServiceName: "payment-test",
+		SampleRatio: 1,
+	})
+	require.NoError(t, err)
+
+	suite := NewTestSuite(t)
+	defer suite.Teardown()
+
+	const (
+		orgIBAN = "FR10474608000002006107XXXXX"
+		orgBIC  = "OIVUSCLQXXX"
+	)
+
+	suite.SeedAccount(t, "Test Organization", orgIBAN, orgBIC, 10000)
+
+	requestBody := httpHandler.BulkTransferRequest{
+		OrganizationBIC:  orgBIC,
+		OrganizationIBAN: orgIBAN,
+		CreditTransfers: []httpHandler.CreditTransfer{
+			{
+				Amount:           "10",
+				Currency:         "EUR",
+				CounterpartyName: "Alice Smith",
+				CounterpartyBIC:  "CRLYFRPPTOU",
+				CounterpartyIBAN: "EE383680981021245685",
+				Description:      "Payment to Alice",
+			},
+		},
+	}
+
+	bodyBytes, err := json.Marshal(requestBody)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/transfers/bulk", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.Handler.PostTransfers(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	require.NoError(t, shutdown(context.Background()))
+
+	file, err := os.Open(tracePath)
+	require.NoError(t, err)
+	defer file.Close()
+
+	spansByTrace := make(map[string][]string)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var span struct {
+			Name        string
+			SpanContext struct {
+				TraceID string
+			}
+		}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &span))
+		spansByTrace[span.SpanContext.TraceID] = append(spansByTrace[span.SpanContext.TraceID], span.Name)
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, spansByTrace, 1)
+	for traceID, names := range spansByTrace {
+		require.NotEqual(t, trace.TraceID{}.String(), traceID)
+		require.Subset(t, names, []string{
+			"PostTransfers",
+			"ProcessBulkTransfer",
+			"AccountRepository.Atomic",
+			"AccountRepository.GetAccountByID",
+			"AccountRepository.UpdateBalance",
+			"AccountRepository.AddTransfers",
+			"sqlite.Commit",
+		})
+	}
+}